@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/metrics"
+)
+
+// runDashboard prints the Grafana dashboard of the metrics of the kinds given, each with the types of its conditions:
+//
+//	kondition dashboard -title Storage Bucket=Bucket,DNS Quota=Quota > dashboard.json
+func runDashboard(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("dashboard", flag.ContinueOnError)
+	title := flags.String("title", "Conditions", "Title of the dashboard")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition dashboard [-title title] <kind>=<type>,<type>...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no kind given")
+	}
+
+	kinds := make([]metrics.Kind, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		name, types, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || types == "" {
+			return fmt.Errorf("invalid kind %q, expected <kind>=<type>,<type>...", arg)
+		}
+
+		kind := metrics.Kind{Name: name}
+		for _, ct := range splitList(types) {
+			kind.Types = append(kind.Types, konditions.ConditionType(ct))
+		}
+		kinds = append(kinds, kind)
+	}
+
+	data, err := metrics.NewDashboard(*title, kinds...).JSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, string(data))
+	return err
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestRunDashboard(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"dashboard", "-title", "Storage", "Bucket=Bucket,DNS", "Quota=Quota"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	var dashboard struct {
+		Title  string
+		Panels []struct{ Title string }
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &dashboard); err != nil {
+		t.Fatal(err)
+	}
+
+	if dashboard.Title != "Storage" || len(dashboard.Panels) != 14 {
+		t.Errorf("Unexpected dashboard %q with %d panels", dashboard.Title, len(dashboard.Panels))
+	}
+
+	for _, arg := range []string{"Bucket", "=Bucket", "Bucket="} {
+		stderr.Reset()
+		if code := run(context.Background(), []string{"dashboard", arg}, &stdout, &stderr); code != 1 {
+			t.Errorf("Expected %q to be refused, got %d", arg, code)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions/diagram"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/policy"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// runDiagram renders the state machine of every KonditionPolicy found in the YAML files given. Documents of other
+// kinds are skipped, so the manifests of an operator can be passed as they are.
+func runDiagram(ctx context.Context, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("diagram", flag.ContinueOnError)
+	format := flags.String("format", string(diagram.Mermaid), "Format of the diagrams, mermaid or dot")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition diagram [-format mermaid|dot] policy.yaml...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no file given")
+	}
+
+	rendered := 0
+	for _, path := range flags.Args() {
+		policies, err := readPolicies(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, p := range policies {
+			out, err := diagram.FromPolicy(p).Export(diagram.Format(*format))
+			if err != nil {
+				return err
+			}
+
+			if rendered > 0 {
+				fmt.Fprintln(stdout)
+			}
+			if _, err := stdout.Write(out); err != nil {
+				return err
+			}
+			rendered++
+		}
+	}
+
+	if rendered == 0 {
+		return errors.New("no KonditionPolicy found")
+	}
+
+	return nil
+}
+
+func readPolicies(path string) ([]*policy.KonditionPolicy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var policies []*policy.KonditionPolicy
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(file))
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return policies, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var p policy.KonditionPolicy
+		if err := yaml.Unmarshal(document, &p); err != nil {
+			return nil, err
+		}
+
+		if p.Kind != "KonditionPolicy" || p.APIVersion != policy.GroupVersion.String() {
+			continue
+		}
+
+		policies = append(policies, &p)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const manifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+---
+apiVersion: konditionner.io/v1alpha1
+kind: KonditionPolicy
+metadata:
+  name: buckets
+spec:
+  target:
+    apiVersion: example.com/v1
+    kind: Bucket
+  transitions:
+  - from: Initialized
+    to: [Locked]
+  - from: Locked
+    to: [Completed, Error]
+`
+
+func TestRunDiagram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	if err := os.WriteFile(path, []byte(manifests), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"diagram", "-format", "dot", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.HasPrefix(stdout.String(), `digraph "buckets" {`) || !strings.Contains(stdout.String(), `"Locked" -> "Completed";`) {
+		t.Error("Unexpected output: ", stdout.String())
+	}
+}
+
+func TestRunDiagramWithoutPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "configmap.yaml")
+	if err := os.WriteFile(path, []byte(strings.Split(manifests, "---")[0]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"diagram", path}, &stdout, &stderr); code != 1 {
+		t.Error("Expected the command to fail without a policy, got: ", code)
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"teleport"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "diagram") {
+		t.Error("Expected the usage to be printed, got: ", code, stderr.String())
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// editFlags are the flags shared by the commands that change the conditions of a resource.
+type editFlags struct {
+	kubeFlags
+
+	reason string
+	by     string
+	dryRun bool
+}
+
+func (e *editFlags) register(flags *flag.FlagSet) {
+	e.kubeFlags.register(flags)
+	flags.StringVar(&e.reason, "reason", "", "Why the condition is changed, recorded in its reason (required)")
+	flags.StringVar(&e.by, "by", os.Getenv("USER"), "Who changes the condition, recorded in its reason")
+	flags.BoolVar(&e.dryRun, "dry-run", false, "Send the change to the API server without persisting it")
+}
+
+// validate returns an error if the flags can't be used to change a condition.
+func (e *editFlags) validate() error {
+	if e.reason == "" {
+		return errors.New("a reason is required, see -reason")
+	}
+
+	return nil
+}
+
+// auditReason returns the reason stored in the condition changed, which records the action, who made it and why.
+func (e *editFlags) auditReason(action string) string {
+	if e.by == "" {
+		return fmt.Sprintf("%s with kondition: %s", action, e.reason)
+	}
+
+	return fmt.Sprintf("%s by %s with kondition: %s", action, e.by, e.reason)
+}
+
+// editConditions fetches the resource, lets edit change its conditions and updates the resource. The conditions are
+// changed through the library, the same validations apply as when a controller changes them.
+//
+// The update is made with the resource version of the resource fetched: when another writer updates the resource in
+// the meantime, the resource is fetched again and edit is called with the new conditions. The checks edit makes are
+// always made against the conditions that are persisted.
+func editConditions(ctx context.Context, e *editFlags, kind, name string, edit func(*konditions.Conditions) error) (*unstructured.Unstructured, error) {
+	c, err := newClient(&e.kubeFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk, err := resolveKind(c, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, err := e.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	var obj *unstructured.Unstructured
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj = &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+			return err
+		}
+
+		res, err := konditions.NewUnstructuredConditions(obj, e.fields()...)
+		if err != nil {
+			return err
+		}
+
+		if err := edit(res.Conditions()); err != nil {
+			return err
+		}
+
+		if _, err := res.Encode(); err != nil {
+			return err
+		}
+
+		return e.update(ctx, c, obj)
+	})
+
+	return obj, err
+}
+
+// update persists the object, through the status subresource when the conditions are stored in the status.
+func (e *editFlags) update(ctx context.Context, c client.Client, obj client.Object) error {
+	if e.fields()[0] == "status" {
+		var opts []client.SubResourceUpdateOption
+		if e.dryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+
+		return c.Status().Update(ctx, obj, opts...)
+	}
+
+	var opts []client.UpdateOption
+	if e.dryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+
+	return c.Update(ctx, obj, opts...)
+}
+
+// printEdit prints the condition as it is after the change.
+func printEdit(w io.Writer, e *editFlags, obj *unstructured.Unstructured, condition konditions.Condition, action string) {
+	suffix := ""
+	if e.dryRun {
+		suffix = " (dry run)"
+	}
+
+	fmt.Fprintf(w, "%s %s/%s: %s %s, now %s%s\n", obj.GetKind(), obj.GetNamespace(), obj.GetName(), condition.Type, action, condition.Status, suffix)
+}
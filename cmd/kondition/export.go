@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/export"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// runExport dumps the conditions of every resource of the kinds given as flat records, one per condition. The
+// resources of all namespaces are exported, unless a namespace is given with -n.
+func runExport(ctx context.Context, args []string, stdout io.Writer) error {
+	var kube kubeFlags
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	kube.register(flags)
+	format := flags.String("format", string(export.CSV), "Format of the records: csv, json or jsonl")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition export [flags] <kind>...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no kind given")
+	}
+
+	c, err := newClient(&kube)
+	if err != nil {
+		return err
+	}
+
+	var opts []client.ListOption
+	if kube.namespace != "" {
+		opts = append(opts, client.InNamespace(kube.namespace))
+	}
+
+	records := []export.Record{}
+	for _, kind := range flags.Args() {
+		gvk, err := resolveKind(c, kind)
+		if err != nil {
+			return err
+		}
+
+		r, err := export.List(ctx, c, gvk, kube.fields(), opts...)
+		var normalized *konditions.NormalizedError
+		if errors.As(err, &normalized) {
+			fmt.Fprintln(flags.Output(), "Warning:", err)
+		} else if err != nil {
+			return err
+		}
+		records = append(records, r...)
+	}
+
+	return export.Write(stdout, export.Format(*format), records)
+}
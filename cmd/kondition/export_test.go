@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+)
+
+func TestRunExport(t *testing.T) {
+	logs := newBucket("logs", map[string]interface{}{"type": "Bucket", "status": "Completed", "reason": "Bucket created", "lastTransitionTime": "2024-01-01T09:00:00Z"})
+	images := newBucket("images", map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T09:00:00Z"})
+	images.SetNamespace("staging")
+	useFakeClient(t, logs, images)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"export", "buckets"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	rows, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatal("Expected the records of all namespaces, got: ", rows)
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"export", "-n", "staging", "-format", "jsonl", "buckets"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	if lines := bytes.Count(stdout.Bytes(), []byte("\n")); lines != 1 || !bytes.Contains(stdout.Bytes(), []byte(`"namespace":"staging"`)) {
+		t.Error("Expected the records of the namespace, got: ", stdout.String())
+	}
+
+	if code := run(context.Background(), []string{"export", "-format", "xml", "buckets"}, &stdout, &stderr); code != 1 {
+		t.Error("Expected an unknown format to fail, got: ", code)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// now is the clock of the commands, tests replace it.
+var now = time.Now
+
+// runInspect prints the conditions of a resource as a table, ordered by their last transition: the timeline of the
+// resource. Conditions in progress for longer than -stuck-after are flagged, and the manager of locked conditions is
+// shown as the holder of the lock.
+func runInspect(ctx context.Context, args []string, stdout io.Writer) error {
+	var kube kubeFlags
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	kube.register(flags)
+	stuckAfter := flags.Duration("stuck-after", 15*time.Minute, "Flag the conditions in progress without a transition, or a heartbeat, for longer than this, 0 to disable")
+	color := flags.String("color", "auto", "Color the output: auto, always or never")
+	timeline := flags.Bool("timeline", false, "Also print the transitions reconstructed from the events of the resource")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition inspect [flags] <kind> <name>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("expected a kind and a name")
+	}
+
+	colors, err := newColorizer(*color, stdout)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(&kube)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := resolveKind(c, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	namespace, err := kube.Namespace()
+	if err != nil {
+		return err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: flags.Arg(1)}, obj); err != nil {
+		return err
+	}
+
+	conditions, _, err := konditions.NestedConditions(obj, kube.fields()...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s %s/%s, generation %d\n\n", gvk.Kind, namespace, obj.GetName(), obj.GetGeneration())
+	if len(conditions) == 0 {
+		fmt.Fprintln(stdout, "No conditions.")
+	} else {
+		printConditions(stdout, colors, conditions, obj.GetGeneration(), *stuckAfter)
+	}
+
+	if !*timeline {
+		return nil
+	}
+
+	entries, err := konditions.Timeline(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout)
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "No events, Kubernetes may have expired them.")
+		return nil
+	}
+
+	printTimeline(stdout, colors, entries)
+	return nil
+}
+
+// printConditions prints the conditions as a table, oldest transition first.
+func printConditions(w io.Writer, colors colorizer, conditions konditions.Conditions, generation int64, stuckAfter time.Duration) {
+	conditions = conditions.DeepCopy()
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return conditions[i].LastTransitionTime.Before(&conditions[j].LastTransitionTime)
+	})
+
+	current := now()
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, colors.header("TYPE\tSTATUS\tAGE\tHEARTBEAT\tLOCKED BY\tREASON\t"))
+
+	for _, condition := range conditions {
+		heartbeat := "-"
+		if condition.LastHeartbeatTime != nil {
+			heartbeat = age(current, condition.LastHeartbeatTime.Time)
+		}
+
+		holder := "-"
+		if condition.Status == konditions.ConditionLocked {
+			holder = condition.Manager
+			if holder == "" {
+				holder = "<unknown>"
+			}
+		}
+
+		var warnings []string
+		stuck := condition.IsStuck(current, stuckAfter)
+		if stuck {
+			warnings = append(warnings, fmt.Sprintf("stuck for %s", age(current, current.Add(-condition.SilentFor(current)))))
+		}
+
+		if condition.ObservedGeneration != 0 && condition.ObservedGeneration < generation {
+			warnings = append(warnings, fmt.Sprintf("observed generation %d", condition.ObservedGeneration))
+		}
+
+		reason := condition.Reason
+		if len(warnings) > 0 {
+			reason = fmt.Sprintf("%s (%s)", reason, strings.Join(warnings, ", "))
+		}
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t", condition.Type, condition.Status, age(current, condition.LastTransitionTime.Time), heartbeat, holder, reason)
+		fmt.Fprintln(table, colors.row(condition, stuck, line))
+	}
+
+	table.Flush()
+}
+
+// printTimeline prints the entries of the timeline as a table, oldest first.
+func printTimeline(w io.Writer, colors colorizer, entries []konditions.TimelineEntry) {
+	current := now()
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, colors.header("AGE\tTYPE\tTRANSITION\tDETAILS\t"))
+
+	for _, entry := range entries {
+		ct := string(entry.Type)
+		if ct == "" {
+			ct = "-"
+		}
+
+		transition := entry.EventReason
+		details := entry.Message
+		if entry.To != "" {
+			transition = string(entry.To)
+			if entry.From != "" {
+				transition = fmt.Sprintf("%s -> %s", entry.From, entry.To)
+			}
+
+			details = ""
+			if entry.Window > 0 {
+				details = fmt.Sprintf("within %s", entry.Window)
+			}
+		}
+
+		if entry.Count > 1 {
+			details = strings.TrimSpace(fmt.Sprintf("%s (x%d)", details, entry.Count))
+		}
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t", age(current, entry.Time), ct, transition, details)
+		fmt.Fprintln(table, colors.row(konditions.Condition{Status: entry.To}, false, line))
+	}
+
+	table.Flush()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func freezeTime(t *testing.T, at time.Time) {
+	t.Helper()
+
+	previous := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestRunInspect(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "DNS", "status": "Locked", "reason": "Resource locked", "manager": "dns-controller", "lastTransitionTime": "2024-01-01T09:00:00Z"},
+		map[string]interface{}{"type": "Bucket", "status": "Completed", "reason": "Bucket created", "lastTransitionTime": "2024-01-01T08:00:00Z", "observedGeneration": int64(1)},
+	))
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"inspect", "-n", "default", "-color", "never", "bucket", "logs"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 5 || lines[0] != "Bucket default/logs, generation 2" {
+		t.Fatalf("Unexpected output:\n%s", stdout.String())
+	}
+
+	if !strings.HasPrefix(lines[3], "Bucket") || !strings.Contains(lines[3], "observed generation 1") {
+		t.Error("Expected the oldest transition first, with a stale generation warning, got: ", lines[3])
+	}
+
+	if !strings.HasPrefix(lines[4], "DNS") || !strings.Contains(lines[4], "dns-controller") || !strings.Contains(lines[4], "stuck for 3h") {
+		t.Error("Expected the locked condition to show its holder and to be stuck, got: ", lines[4])
+	}
+}
+
+func TestRunInspectColors(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T11:59:00Z"},
+	))
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"inspect", "-n", "default", "-color", "always", "bucket", "logs"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), colorRed+"Bucket") {
+		t.Error("Expected the condition in error to be red, got: ", stdout.String())
+	}
+}
+
+func TestRunInspectNotFound(t *testing.T) {
+	useFakeClient(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"inspect", "-n", "default", "bucket", "missing"}, &stdout, &stderr); code != 1 {
+		t.Error("Expected the command to fail, got: ", code)
+	}
+}
+
+func TestRunInspectTimeline(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	freezeTime(t, at)
+
+	bucket := newBucket("logs", map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T11:00:00Z"})
+	bucket.SetUID("1234")
+
+	event := func(name, reason, message string, ago time.Duration, count int32) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     meta.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Namespace: "default", Name: "logs", UID: "1234"},
+			Reason:         reason,
+			Message:        message,
+			LastTimestamp:  meta.NewTime(at.Add(-ago)),
+			Count:          count,
+		}
+	}
+
+	useFakeClient(t, bucket,
+		event("a", konditions.TransitionsEventReason, "Bucket: Initialized→Locked over 0s", 2*time.Hour, 1),
+		event("b", konditions.TransitionsEventReason, "Bucket: Locked→Error over 3s", time.Hour, 1),
+		event("c", konditions.DegradedEventReason, "Bucket failed 3 times in a row: Access denied", 30*time.Minute, 2),
+	)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"inspect", "-n", "default", "-color", "never", "-timeline", "bucket", "logs"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 9 || !strings.HasPrefix(lines[5], "AGE") {
+		t.Fatalf("Unexpected output:\n%s", stdout.String())
+	}
+
+	if !strings.Contains(lines[6], "Initialized -> Locked") || !strings.Contains(lines[7], "Locked -> Error") || !strings.Contains(lines[7], "within 3s") {
+		t.Errorf("Expected the transitions in order, got:\n%s", stdout.String())
+	}
+
+	if !strings.Contains(lines[8], konditions.DegradedEventReason) || !strings.Contains(lines[8], "(x2)") {
+		t.Error("Expected the degraded event with its count, got: ", lines[8])
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubeFlags are the flags shared by the commands that talk to a cluster.
+type kubeFlags struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	path       string
+}
+
+func (k *kubeFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&k.kubeconfig, "kubeconfig", "", "Path to the kubeconfig, $KUBECONFIG or ~/.kube/config otherwise")
+	flags.StringVar(&k.context, "context", "", "Context of the kubeconfig to use, the current context otherwise")
+	flags.StringVar(&k.namespace, "n", "", "Namespace of the resources, the namespace of the context otherwise")
+	flags.StringVar(&k.path, "path", "status.conditions", "Path of the conditions in the resources")
+}
+
+func (k *kubeFlags) fields() []string {
+	return strings.Split(k.path, ".")
+}
+
+func (k *kubeFlags) loader() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = k.kubeconfig
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: k.context})
+}
+
+// Namespace returns the namespace given with -n, or the namespace of the context.
+func (k *kubeFlags) Namespace() (string, error) {
+	if k.namespace != "" {
+		return k.namespace, nil
+	}
+
+	namespace, _, err := k.loader().Namespace()
+	return namespace, err
+}
+
+// newClient returns the client the commands use to talk to the cluster. Tests replace it with a fake client.
+var newClient = func(k *kubeFlags) (client.WithWatch, error) {
+	config, err := k.loader().ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewWithWatch(config, client.Options{})
+}
+
+// resolveKind returns the kind of the resources named by the argument, which can be a kind, a resource, singular
+// or plural, optionally qualified by its group: Bucket, buckets, buckets.example.com, etc.
+func resolveKind(c client.Client, arg string) (schema.GroupVersionKind, error) {
+	resource := schema.ParseGroupResource(strings.ToLower(arg))
+	gvk, err := c.RESTMapper().KindFor(resource.WithVersion(""))
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("could not find the kind of %q: %w", arg, err)
+	}
+
+	return gvk, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var bucketGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"}
+
+// useFakeClient makes the commands use a fake client holding the objects given, which knows about the Bucket kind.
+func useFakeClient(t *testing.T, objs ...client.Object) client.WithWatch {
+	t.Helper()
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{bucketGVK.GroupVersion()})
+	mapper.Add(bucketGVK, meta.RESTScopeNamespace)
+
+	c := fake.NewClientBuilder().
+		WithRESTMapper(mapper).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithIndex(&corev1.Event{}, "involvedObject.uid", func(obj client.Object) []string {
+			return []string{string(obj.(*corev1.Event).InvolvedObject.UID)}
+		}).
+		Build()
+
+	previous := newClient
+	newClient = func(k *kubeFlags) (client.WithWatch, error) { return c, nil }
+	t.Cleanup(func() { newClient = previous })
+
+	return c
+}
+
+func newBucket(name string, conditions ...interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "default", "generation": int64(2)},
+		"status":   map[string]interface{}{"conditions": conditions},
+	}}
+	obj.SetGroupVersionKind(bucketGVK)
+
+	return obj
+}
+
+func TestResolveKind(t *testing.T) {
+	c := useFakeClient(t)
+
+	for _, arg := range []string{"Bucket", "bucket", "buckets", "buckets.example.com"} {
+		gvk, err := resolveKind(c, arg)
+		if err != nil || gvk != bucketGVK {
+			t.Errorf("Expected %q to resolve to %s, got %s: %v", arg, bucketGVK, gvk, err)
+		}
+	}
+
+	if _, err := resolveKind(c, "teleporters"); err == nil {
+		t.Error("Expected an unknown kind to fail")
+	}
+}
@@ -0,0 +1,82 @@
+// Command kondition is a companion tool for the operators built with Konditionner.
+//
+//	kondition dashboard [-title title] <kind>=<type>,<type>...
+//	kondition diagram [-format mermaid|dot] policy.yaml...
+//	kondition export [-format csv|json|jsonl] [-n namespace] <kind>...
+//	kondition inspect [-n namespace] [-timeline] <kind> <name>
+//	kondition unlock [-older-than duration] [-holder manager] -reason <reason> <kind> <name> <type>
+//	kondition reset -reason <reason> <kind> <name> <type>
+//	kondition watch [-type types] [-status statuses] [-since duration] <kind> <name|-all>
+//
+// Each command prints its own usage with -h.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+)
+
+// command is a subcommand of kondition.
+type command struct {
+	summary string
+	run     func(ctx context.Context, args []string, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"dashboard": {summary: "Generate the Grafana dashboard of the metrics of conditions", run: runDashboard},
+	"diagram":   {summary: "Render the state machines declared by KonditionPolicies", run: runDiagram},
+	"export":    {summary: "Dump the conditions of every resource of kinds as CSV or JSON records", run: runExport},
+	"inspect":   {summary: "Print the conditions of a resource", run: runInspect},
+	"reset":     {summary: "Set a condition back to Initialized so it's reconciled again", run: runReset},
+	"unlock":    {summary: "Release a condition left locked by a task", run: runUnlock},
+	"watch":     {summary: "Stream the transitions of the conditions of resources", run: runWatch},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "kondition: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	if err := cmd.run(ctx, args[1:], stdout); err != nil {
+		fmt.Fprintf(stderr, "kondition %s: %s\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: kondition <command> [arguments]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
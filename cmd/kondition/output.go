@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// ANSI colors of the statuses. They all have the same length, a row starts with one of them so the columns of the
+// table stay aligned.
+const (
+	colorDefault = "\x1b[39m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorReset   = "\x1b[0m"
+)
+
+// colorizer colors the rows of the tables printed by the commands.
+type colorizer struct {
+	enabled bool
+}
+
+// newColorizer returns a colorizer for the mode given: always, never or auto, in which case colors are enabled
+// when the output is a terminal and NO_COLOR isn't set.
+func newColorizer(mode string, w io.Writer) (colorizer, error) {
+	switch mode {
+	case "always":
+		return colorizer{enabled: true}, nil
+	case "never":
+		return colorizer{}, nil
+	case "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return colorizer{}, nil
+		}
+
+		file, ok := w.(*os.File)
+		if !ok {
+			return colorizer{}, nil
+		}
+
+		info, err := file.Stat()
+		return colorizer{enabled: err == nil && info.Mode()&os.ModeCharDevice != 0}, nil
+	default:
+		return colorizer{}, fmt.Errorf("invalid color mode %q, expected auto, always or never", mode)
+	}
+}
+
+// row returns the line given, colored for the condition.
+func (c colorizer) row(condition konditions.Condition, stuck bool, line string) string {
+	if !c.enabled {
+		return line
+	}
+
+	color := colorDefault
+	switch {
+	case condition.Class() == konditions.ClassError:
+		color = colorRed
+	case stuck, condition.Status == konditions.ConditionLocked:
+		color = colorYellow
+	case condition.Class() == konditions.ClassCompleted, condition.Class() == konditions.ClassTerminal:
+		color = colorGreen
+	}
+
+	return color + line + colorReset
+}
+
+// header returns the header line, prefixed so it aligns with the colored rows.
+func (c colorizer) header(line string) string {
+	if !c.enabled {
+		return line
+	}
+
+	return colorDefault + line + colorReset
+}
+
+// age returns how long ago the time was, the way kubectl prints ages.
+func age(now time.Time, t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(now.Sub(t))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// runReset sets a condition back to ConditionInitialized, whatever its status, so the controller runs its task
+// again: after the cause of a terminal error was fixed by hand for instance. See Conditions.Reset.
+//
+// A locked condition isn't reset, its task may still be running. The unlock command checks the lock is abandoned
+// before releasing it.
+func runReset(ctx context.Context, args []string, stdout io.Writer) error {
+	var edit editFlags
+	flags := flag.NewFlagSet("reset", flag.ContinueOnError)
+	edit.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition reset [flags] -reason <reason> <kind> <name> <type>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 3 {
+		flags.Usage()
+		return errors.New("expected a kind, a name and a condition type")
+	}
+
+	if err := edit.validate(); err != nil {
+		return err
+	}
+
+	ct := konditions.ConditionType(flags.Arg(2))
+	var reset konditions.Condition
+	obj, err := editConditions(ctx, &edit, flags.Arg(0), flags.Arg(1), func(conditions *konditions.Conditions) error {
+		condition, ok := conditions.GetType(ct)
+		if !ok {
+			return fmt.Errorf("%w: %s", konditions.ConditionNotFoundErr, ct)
+		}
+
+		if condition.Status == konditions.ConditionLocked {
+			return fmt.Errorf("condition %s is locked, see kondition unlock", ct)
+		}
+
+		if err := conditions.Reset(ct, edit.auditReason("Reset")); err != nil {
+			return err
+		}
+
+		reset = conditions.MustType(ct)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	printEdit(stdout, &edit, obj, reset, "reset")
+	return nil
+}
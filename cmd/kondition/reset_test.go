@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+func TestRunReset(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T08:00:00Z", "attributes": map[string]interface{}{konditions.ConsecutiveErrorsAttribute: "3"}},
+		map[string]interface{}{"type": "DNS", "status": "Locked", "reason": "Resource locked", "lastTransitionTime": "2024-01-01T09:00:00Z"},
+	))
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"reset", "-n", "default", "-by", "alice", "-reason", "credentials fixed", "bucket", "logs", "Bucket"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	condition := bucketCondition(t, c, "logs", "Bucket")
+	if condition.Status != konditions.ConditionInitialized || condition.Reason != "Reset by alice with kondition: credentials fixed" {
+		t.Errorf("Expected the condition to be reset with an audit reason, got %s: %s", condition.Status, condition.Reason)
+	}
+
+	if value, _ := condition.GetAttr(konditions.ConsecutiveErrorsAttribute); value != "3" {
+		t.Error("Expected the retained attributes to be kept, got: ", value)
+	}
+
+	for _, ct := range []string{"DNS", "Missing"} {
+		stdout.Reset()
+		stderr.Reset()
+		if code := run(context.Background(), []string{"reset", "-n", "default", "-reason", "retry", "bucket", "logs", ct}, &stdout, &stderr); code != 1 {
+			t.Errorf("Expected resetting %s to fail, got %d", ct, code)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// runUnlock releases a condition left locked by a task that didn't release it, a controller that crashed or was
+// scaled down while the task ran for instance. The condition is reset to ConditionInitialized so the controller
+// runs the task again.
+//
+// The lock can only be released when it looks abandoned: -older-than refuses locks that transitioned, or heartbeat,
+// more recently than the duration given, and -holder refuses locks held by another manager than the one given.
+// When the task still runs, its lock fails to release the condition if it's configured with fencing, or to commit
+// with the resource version.
+func runUnlock(ctx context.Context, args []string, stdout io.Writer) error {
+	var edit editFlags
+	flags := flag.NewFlagSet("unlock", flag.ContinueOnError)
+	edit.register(flags)
+	olderThan := flags.Duration("older-than", 0, "Only unlock the condition if it's been locked, without a heartbeat, for longer than this")
+	holder := flags.String("holder", "", "Only unlock the condition if it's locked by this manager")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition unlock [flags] -reason <reason> <kind> <name> <type>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 3 {
+		flags.Usage()
+		return errors.New("expected a kind, a name and a condition type")
+	}
+
+	if err := edit.validate(); err != nil {
+		return err
+	}
+
+	ct := konditions.ConditionType(flags.Arg(2))
+	var unlocked konditions.Condition
+	obj, err := editConditions(ctx, &edit, flags.Arg(0), flags.Arg(1), func(conditions *konditions.Conditions) error {
+		condition, ok := conditions.GetType(ct)
+		if !ok || condition.Status != konditions.ConditionLocked {
+			return fmt.Errorf("condition %s is not locked", ct)
+		}
+
+		if *holder != "" && condition.Manager != *holder {
+			return fmt.Errorf("condition %s is locked by %q, not %q", ct, condition.Manager, *holder)
+		}
+
+		if silent := condition.SilentFor(now()); silent < *olderThan {
+			return fmt.Errorf("condition %s has been locked for %s, less than %s", ct, silent.Round(time.Second), *olderThan)
+		}
+
+		if err := conditions.Reset(ct, edit.auditReason("Unlocked")); err != nil {
+			return err
+		}
+
+		unlocked = conditions.MustType(ct)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	printEdit(stdout, &edit, obj, unlocked, "unlocked")
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bucketCondition fetches the bucket and returns its condition of the given type.
+func bucketCondition(t *testing.T, c client.Client, name string, ct konditions.ConditionType) konditions.Condition {
+	t.Helper()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(bucketGVK)
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	conditions, _, err := konditions.NestedConditions(obj, "status", "conditions")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conditions.MustType(ct)
+}
+
+func TestRunUnlock(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "DNS", "status": "Locked", "reason": "Resource locked", "manager": "dns-controller", "lastTransitionTime": "2024-01-01T09:00:00Z", "attributes": map[string]interface{}{konditions.FencingTokenAttribute: "abc"}},
+	))
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"unlock", "-n", "default", "-by", "alice", "-reason", "controller crashed", "-older-than", "1h", "-holder", "dns-controller", "bucket", "logs", "DNS"}
+	if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	condition := bucketCondition(t, c, "logs", "DNS")
+	if condition.Status != konditions.ConditionInitialized {
+		t.Error("Expected the condition to be initialized, got: ", condition.Status)
+	}
+
+	if condition.Reason != "Unlocked by alice with kondition: controller crashed" {
+		t.Error("Expected the reason to record who unlocked the condition, got: ", condition.Reason)
+	}
+
+	if _, ok := condition.GetAttr(konditions.FencingTokenAttribute); ok {
+		t.Error("Expected the fencing token to be removed so the holder is fenced out")
+	}
+
+	if !strings.Contains(stdout.String(), "DNS unlocked, now Initialized") {
+		t.Error("Unexpected output: ", stdout.String())
+	}
+}
+
+func TestRunUnlockRefused(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "DNS", "status": "Locked", "reason": "Resource locked", "manager": "dns-controller", "lastTransitionTime": "2024-01-01T09:00:00Z", "lastHeartbeatTime": "2024-01-01T11:55:00Z"},
+		map[string]interface{}{"type": "Bucket", "status": "Completed", "reason": "Bucket created", "lastTransitionTime": "2024-01-01T08:00:00Z"},
+	))
+
+	cases := map[string][]string{
+		"recent heartbeat": {"-older-than", "10m", "bucket", "logs", "DNS"},
+		"other holder":     {"-holder", "bucket-controller", "bucket", "logs", "DNS"},
+		"not locked":       {"bucket", "logs", "Bucket"},
+		"missing reason":   {"-reason", "", "bucket", "logs", "DNS"},
+	}
+
+	for name, args := range cases {
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"unlock", "-n", "default", "-reason", "stale"}, args...)
+		if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+			t.Errorf("%s: expected the command to fail, got %d: %s", name, code, stderr.String())
+		}
+	}
+
+	if condition := bucketCondition(t, c, "logs", "DNS"); condition.Status != konditions.ConditionLocked {
+		t.Error("Expected the condition to still be locked, got: ", condition.Status)
+	}
+}
+
+func TestRunUnlockDryRun(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := useFakeClient(t, newBucket("logs",
+		map[string]interface{}{"type": "DNS", "status": "Locked", "reason": "Resource locked", "lastTransitionTime": "2024-01-01T09:00:00Z"},
+	))
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"unlock", "-n", "default", "-reason", "stale", "-dry-run", "bucket", "logs", "DNS"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the command to succeed, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "(dry run)") {
+		t.Error("Expected the output to mention the dry run, got: ", stdout.String())
+	}
+
+	if condition := bucketCondition(t, c, "logs", "DNS"); condition.Status != konditions.ConditionLocked {
+		t.Error("Expected the dry run to leave the condition locked, got: ", condition.Status)
+	}
+}
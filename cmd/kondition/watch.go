@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// runWatch streams the transitions of the conditions of a resource, or of all the resources of a kind with -all,
+// until it's interrupted. With -since, the transitions that happened in that window before the command started are
+// printed first, in the order they happened.
+//
+// The resources are watched with an informer: the transitions are computed by comparing the conditions of a resource
+// with the conditions it had on the previous event, a condition that transitioned more than once between two events is
+// only printed once.
+func runWatch(ctx context.Context, args []string, stdout io.Writer) error {
+	var kube kubeFlags
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	kube.register(flags)
+	all := flags.Bool("all", false, "Watch all the resources of the kind")
+	allNamespaces := flags.Bool("A", false, "Watch the resources of all namespaces")
+	types := flags.String("type", "", "Only print the transitions of these condition types, separated by commas")
+	statuses := flags.String("status", "", "Only print the transitions to these statuses, separated by commas")
+	since := flags.Duration("since", 0, "Also print the transitions that happened this long before the command started")
+	color := flags.String("color", "auto", "Color the output: auto, always or never")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: kondition watch [flags] <kind> <name|-all>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if (*all && flags.NArg() != 1) || (!*all && flags.NArg() != 2) {
+		flags.Usage()
+		return errors.New("expected a kind and a name, or a kind with -all")
+	}
+
+	colors, err := newColorizer(*color, stdout)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(&kube)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := resolveKind(c, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	namespace := ""
+	if !*allNamespaces {
+		if namespace, err = kube.Namespace(); err != nil {
+			return err
+		}
+	}
+
+	w := &watcher{
+		out:      stdout,
+		colors:   colors,
+		fields:   kube.fields(),
+		name:     flags.Arg(1),
+		types:    splitList(*types),
+		statuses: splitList(*statuses),
+		since:    now().Add(-*since),
+	}
+
+	informer := cache.NewSharedIndexInformer(listWatch(ctx, c, gvk, namespace), &unstructured.Unstructured{}, 0, cache.Indexers{})
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc:    w.add,
+		UpdateFunc: w.update,
+		DeleteFunc: w.delete,
+	})
+	if err != nil {
+		return err
+	}
+
+	go informer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		return ctx.Err()
+	}
+	w.synced()
+
+	<-ctx.Done()
+	return nil
+}
+
+// listWatch lists and watches the resources of the kind with the client.
+func listWatch(ctx context.Context, c client.WithWatch, gvk schema.GroupVersionKind, namespace string) *cache.ListWatch {
+	newList := func() *unstructured.UnstructuredList {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		return list
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(options meta.ListOptions) (runtime.Object, error) {
+			list := newList()
+			err := c.List(ctx, list, client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+			return list, err
+		},
+		WatchFunc: func(options meta.ListOptions) (watch.Interface, error) {
+			return c.Watch(ctx, newList(), client.InNamespace(namespace), &client.ListOptions{Raw: &options})
+		},
+	}
+}
+
+// splitList returns the values of a comma separated flag.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
+// transition is a change of a condition of a resource, from one status to another. From is empty when the
+// condition was added to the resource.
+type transition struct {
+	obj       *unstructured.Unstructured
+	from      konditions.ConditionStatus
+	condition konditions.Condition
+	deleted   bool
+	at        time.Time
+}
+
+// watcher prints the transitions of the resources received from the informer. The transitions of the initial list
+// are buffered until the informer is synced, so they can be printed in the order they happened.
+type watcher struct {
+	out      io.Writer
+	colors   colorizer
+	fields   []string
+	name     string
+	types    []string
+	statuses []string
+	since    time.Time
+
+	mu       sync.Mutex
+	buffered []transition
+	live     bool
+}
+
+func (w *watcher) add(obj interface{}, isInInitialList bool) {
+	res, ok := w.resource(obj)
+	if !ok {
+		return
+	}
+
+	var transitions []transition
+	for _, condition := range w.conditions(res) {
+		if condition.LastTransitionTime.Time.Before(w.since) {
+			continue
+		}
+
+		transitions = append(transitions, transition{obj: res, condition: condition, at: condition.LastTransitionTime.Time})
+	}
+
+	w.emit(transitions...)
+}
+
+func (w *watcher) update(oldObj, newObj interface{}) {
+	previous, ok := w.resource(oldObj)
+	if !ok {
+		return
+	}
+
+	res, ok := w.resource(newObj)
+	if !ok {
+		return
+	}
+
+	old := w.conditions(previous)
+
+	var transitions []transition
+	for _, condition := range w.conditions(res) {
+		existing, found := old.GetType(condition.Type)
+		if found && existing.Status == condition.Status && existing.LastTransitionTime.Equal(&condition.LastTransitionTime) {
+			continue
+		}
+
+		t := transition{obj: res, condition: condition, at: condition.LastTransitionTime.Time}
+		if found {
+			t.from = existing.Status
+		}
+		transitions = append(transitions, t)
+	}
+
+	w.emit(transitions...)
+}
+
+func (w *watcher) delete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	res, ok := w.resource(obj)
+	if !ok {
+		return
+	}
+
+	w.emit(transition{obj: res, deleted: true, at: now()})
+}
+
+// resource returns the object received from the informer if it's one of the resources watched.
+func (w *watcher) resource(obj interface{}) (*unstructured.Unstructured, bool) {
+	res, ok := obj.(*unstructured.Unstructured)
+	if !ok || (w.name != "" && res.GetName() != w.name) {
+		return nil, false
+	}
+
+	return res, true
+}
+
+// conditions returns the conditions of the resource that pass the -type and -status filters.
+func (w *watcher) conditions(res *unstructured.Unstructured) konditions.Conditions {
+	conditions, _, err := konditions.NestedConditions(res, w.fields...)
+	if err != nil {
+		return nil
+	}
+
+	filtered := konditions.Conditions{}
+	for _, condition := range conditions {
+		if len(w.types) > 0 && !contains(w.types, string(condition.Type)) {
+			continue
+		}
+
+		if len(w.statuses) > 0 && !contains(w.statuses, string(condition.Status)) {
+			continue
+		}
+
+		filtered = append(filtered, condition)
+	}
+
+	return filtered
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emit prints the transitions, or buffers them while the informer isn't synced.
+func (w *watcher) emit(transitions ...transition) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.live {
+		w.buffered = append(w.buffered, transitions...)
+		return
+	}
+
+	for _, t := range transitions {
+		w.print(t)
+	}
+}
+
+// synced prints the transitions buffered, oldest first, and prints the transitions as they are received from then on.
+func (w *watcher) synced() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sort.SliceStable(w.buffered, func(i, j int) bool {
+		return w.buffered[i].at.Before(w.buffered[j].at)
+	})
+
+	for _, t := range w.buffered {
+		w.print(t)
+	}
+
+	w.buffered = nil
+	w.live = true
+}
+
+func (w *watcher) print(t transition) {
+	name := fmt.Sprintf("%s %s/%s", t.obj.GetKind(), t.obj.GetNamespace(), t.obj.GetName())
+	if t.obj.GetNamespace() == "" {
+		name = fmt.Sprintf("%s %s", t.obj.GetKind(), t.obj.GetName())
+	}
+
+	timestamp := t.at.UTC().Format(time.RFC3339)
+	if t.deleted {
+		fmt.Fprintf(w.out, "%s %s deleted\n", timestamp, name)
+		return
+	}
+
+	status := string(t.condition.Status)
+	if t.from != "" {
+		status = fmt.Sprintf("%s -> %s", t.from, t.condition.Status)
+	}
+
+	line := fmt.Sprintf("%s %s %s: %s", timestamp, name, t.condition.Type, status)
+	if t.condition.Reason != "" {
+		line = fmt.Sprintf("%s (%s)", line, t.condition.Reason)
+	}
+
+	fmt.Fprintln(w.out, w.colors.row(t.condition, false, line))
+}
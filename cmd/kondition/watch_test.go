@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncBuffer is a buffer the test can read while the command writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitForOutput fails the test if the output doesn't contain the text given within a few seconds.
+func waitForOutput(t *testing.T, out *syncBuffer, text string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), text) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the output to contain %q, got:\n%s", text, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// startWatch runs the watch command in the background until the test ends.
+func startWatch(t *testing.T, args ...string) *syncBuffer {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan int)
+	go func() {
+		var stderr bytes.Buffer
+		code := run(ctx, append([]string{"watch", "-color", "never"}, args...), out, &stderr)
+		if code != 0 {
+			t.Errorf("Expected the command to succeed, got %d: %s", code, stderr.String())
+		}
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return out
+}
+
+func TestRunWatch(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := useFakeClient(t,
+		newBucket("logs",
+			map[string]interface{}{"type": "DNS", "status": "Completed", "reason": "Record created", "lastTransitionTime": "2024-01-01T11:30:00Z"},
+			map[string]interface{}{"type": "Bucket", "status": "Locked", "reason": "Resource locked", "lastTransitionTime": "2024-01-01T11:00:00Z"},
+			map[string]interface{}{"type": "Quota", "status": "Completed", "reason": "Quota set", "lastTransitionTime": "2024-01-01T08:00:00Z"},
+		),
+		newBucket("images",
+			map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T11:45:00Z"},
+		),
+	)
+
+	out := startWatch(t, "-n", "default", "-since", "2h", "-all", "bucket")
+	waitForOutput(t, out, "images")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{
+		"2024-01-01T11:00:00Z Bucket default/logs Bucket: Locked (Resource locked)",
+		"2024-01-01T11:30:00Z Bucket default/logs DNS: Completed (Record created)",
+		"2024-01-01T11:45:00Z Bucket default/images Bucket: Error (Access denied)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected the transitions since 10:00 in order, got:\n%s", out.String())
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(bucketGVK)
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "logs"}, obj); err != nil {
+		t.Fatal(err)
+	}
+
+	conditions := []interface{}{
+		map[string]interface{}{"type": "DNS", "status": "Completed", "reason": "Record created", "lastTransitionTime": "2024-01-01T11:30:00Z"},
+		map[string]interface{}{"type": "Bucket", "status": "Completed", "reason": "Bucket created", "lastTransitionTime": "2024-01-01T12:00:00Z"},
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, conditions, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Status().Update(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForOutput(t, out, "2024-01-01T12:00:00Z Bucket default/logs Bucket: Locked -> Completed (Bucket created)")
+	if strings.Count(out.String(), "DNS") != 1 {
+		t.Error("Expected the conditions that didn't transition to be left out, got:\n", out.String())
+	}
+
+	if err := c.Delete(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForOutput(t, out, "Bucket default/logs deleted")
+}
+
+func TestRunWatchFilters(t *testing.T) {
+	freezeTime(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	useFakeClient(t,
+		newBucket("logs",
+			map[string]interface{}{"type": "DNS", "status": "Error", "reason": "Zone missing", "lastTransitionTime": "2024-01-01T11:30:00Z"},
+			map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T11:00:00Z"},
+			map[string]interface{}{"type": "Quota", "status": "Completed", "reason": "Quota set", "lastTransitionTime": "2024-01-01T11:10:00Z"},
+		),
+		newBucket("images",
+			map[string]interface{}{"type": "Bucket", "status": "Error", "reason": "Access denied", "lastTransitionTime": "2024-01-01T11:45:00Z"},
+		),
+	)
+
+	out := startWatch(t, "-n", "default", "-since", "2h", "-type", "Bucket,Quota", "-status", "error", "bucket", "logs")
+	waitForOutput(t, out, "Access denied")
+
+	// Leaves time for the other transitions to show up, if they weren't filtered out.
+	time.Sleep(50 * time.Millisecond)
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 1 || !strings.Contains(lines[0], "default/logs Bucket: Error") {
+		t.Error("Expected only the Bucket error of logs, got:\n", out.String())
+	}
+}
+
+func TestRunWatchArguments(t *testing.T) {
+	useFakeClient(t)
+
+	for _, args := range [][]string{{"watch", "bucket"}, {"watch", "-all", "bucket", "logs"}} {
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+			t.Errorf("Expected %v to fail, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package konditions
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Snapshot is returned when a condition is acquired. It holds what the task of a lock would
+// receive: a copy of the condition *before* it was locked, along with the generation of the
+// resource at the time the condition was acquired.
+type Snapshot struct {
+	Condition  Condition
+	Generation int64
+}
+
+// AcquireCondition locks the condition with the given type and persists the resource. It is the first
+// half of Lock.Execute, exposed for advanced flows where the work on a condition doesn't fit in a
+// single function call: the condition can be acquired in one controller and committed in another, once
+// an external system calls back, for instance.
+//
+// The same rules as Execute apply: a condition that is already locked returns LockNotReleasedErr,
+// a terminal condition returns TerminalConditionErr and a paused condition is suspended and returns
+// PausedConditionErr. Lock options are supported as well.
+//
+//	snapshot, err := konditions.AcquireCondition(ctx, reconciler.Client, &res, ConditionType("Bucket"))
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	// ... Start the work, somewhere else ...
+//
+//	condition := snapshot.Condition
+//	condition.Status = konditions.ConditionCompleted
+//	err = konditions.CommitCondition(ctx, reconciler.Client, &res, condition)
+//
+// Whoever acquires a condition is responsible for committing it. Prefer Execute whenever possible.
+func AcquireCondition(ctx context.Context, c client.Client, obj ConditionalResource, ct ConditionType, opts ...LockOption) (Snapshot, error) {
+	lock := NewLock(obj, c, ct, opts...)
+	if err := lock.checkClient(); err != nil {
+		return Snapshot{}, err
+	}
+
+	return lock.acquire(ctx, "")
+}
+
+// CommitCondition stores the condition, releasing the lock acquired with AcquireCondition, and persists
+// the resource. The resource doesn't need to be the same instance that was used to acquire the condition,
+// it can be fetched again.
+//
+// If the condition given is still ConditionLocked, it is set to ConditionError and LockNotReleasedErr
+// is returned. Errors from the Kubernetes API are returned first. The ObservedGeneration of the condition
+// is set to the generation of the resource if it isn't set.
+//
+// Committing a condition the resource already has, with the same status and reason, doesn't write the resource.
+func CommitCondition(ctx context.Context, c client.Client, obj ConditionalResource, condition Condition, opts ...LockOption) error {
+	if condition.ObservedGeneration == 0 {
+		condition.ObservedGeneration = obj.GetGeneration()
+	}
+
+	return NewLock(obj, c, condition.Type, opts...).commit(ctx, condition)
+}
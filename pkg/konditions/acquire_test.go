@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestAcquireAndCommitCondition(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("acquire")
+	res.SetGeneration(2)
+	c := newTestClient(res)
+
+	snapshot, err := AcquireCondition(ctx, c, res, ConditionType("Bucket"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Condition.Status != ConditionInitialized || snapshot.Generation != 2 {
+		t.Error("Unexpected snapshot: ", snapshot)
+	}
+
+	if _, err := AcquireCondition(ctx, c, res, ConditionType("Bucket")); !errors.Is(err, LockNotReleasedErr) {
+		t.Error("Expected the condition to be locked, got: ", err)
+	}
+
+	// Commit from a fresh copy of the resource, like another controller would.
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := snapshot.Condition
+	condition.Status = ConditionCompleted
+	if err := CommitCondition(ctx, c, &stored, condition); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	committed := stored.Conditions().FindType(ConditionType("Bucket"))
+	if committed.Status != ConditionCompleted || committed.ObservedGeneration != 2 {
+		t.Error("Unexpected condition: ", committed)
+	}
+}
+
+func TestCommitConditionStillLocked(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("acquire")
+	c := newTestClient(res)
+
+	snapshot, err := AcquireCondition(ctx, c, res, ConditionType("Bucket"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := snapshot.Condition
+	condition.Status = ConditionLocked
+	if err := CommitCondition(ctx, c, res, condition); !errors.Is(err, LockNotReleasedErr) {
+		t.Error("Expected LockNotReleasedErr, got: ", err)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionError) {
+		t.Error("Expected the condition to be errored")
+	}
+}
+
+func TestCommitConditionTwice(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("acquire")
+
+	var writes int
+	c := newBatchTestClient(res, &writes)
+
+	snapshot, err := AcquireCondition(ctx, c, res, ConditionType("Bucket"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := snapshot.Condition
+	condition.Status = ConditionCompleted
+	condition.Reason = "Bucket created"
+	if err := CommitCondition(ctx, c, res, condition); err != nil {
+		t.Fatal(err)
+	}
+
+	// The worker commits again, it didn't find out the first commit went through.
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CommitCondition(ctx, c, &stored, condition); err != nil {
+		t.Fatal(err)
+	}
+
+	if writes != 2 {
+		t.Error("Expected the commit that doesn't change the conditions to be skipped, got writes: ", writes)
+	}
+}
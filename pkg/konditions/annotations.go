@@ -0,0 +1,173 @@
+package konditions
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultConditionsAnnotation is the annotation used by NewAnnotationConditions when no
+// annotation key is provided.
+const DefaultConditionsAnnotation = "konditionner.io/conditions"
+
+// AnnotationConditions stores a Conditions set, serialized as JSON, in a single annotation of
+// an object. This is useful to track work on objects your operator doesn't define, and as such,
+// can't add a Conditions field to: Namespaces, Nodes, resources owned by another operator, etc.
+//
+// AnnotationConditions is a ConditionalResource, which means it can be used with a Lock. Since
+// annotations are part of the object's metadata, the lock needs to update the whole object
+// through the ObjectPersister:
+//
+//	var namespace corev1.Namespace
+//	if err := reconciler.Get(ctx, req.NamespacedName, &namespace); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	res, err := konditions.NewAnnotationConditions(&namespace, "")
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Quotas"), konditions.WithPersister(konditions.ObjectPersister{Client: reconciler.Client}))
+//
+// The methods used to find, set and remove conditions are also available on AnnotationConditions
+// directly. Changes are only serialized into the annotation when the object is persisted, or
+// when Encode() is called.
+type AnnotationConditions struct {
+	client.Object
+
+	key        string
+	conditions Conditions
+	anomalies  error
+}
+
+// NewAnnotationConditions decodes the conditions stored in the annotation `key` of the object.
+// If the key is empty, DefaultConditionsAnnotation is used. An object without the annotation
+// starts with an empty Conditions set.
+//
+// An error is returned if the annotation exists but can't be decoded. Conditions with anomalies are
+// normalized, the anomalies are available with Anomalies.
+func NewAnnotationConditions(obj client.Object, key string) (*AnnotationConditions, error) {
+	if key == "" {
+		key = DefaultConditionsAnnotation
+	}
+
+	a := &AnnotationConditions{
+		Object:     obj,
+		key:        key,
+		conditions: Conditions{},
+	}
+
+	if value, ok := obj.GetAnnotations()[key]; ok && value != "" {
+		if err := json.Unmarshal([]byte(value), &a.conditions); err != nil {
+			return nil, fmt.Errorf("could not decode conditions from annotation %s: %w", key, err)
+		}
+
+		a.anomalies = normalizeDecoded(&a.conditions)
+	}
+
+	return a, nil
+}
+
+// Anomalies returns the *NormalizedError of the conditions decoded from the annotation, nil if they were
+// valid.
+func (a *AnnotationConditions) Anomalies() error {
+	return a.anomalies
+}
+
+// Conditions returns the conditions decoded from the annotation. Changes made to the conditions
+// are kept in memory until the object is persisted.
+func (a *AnnotationConditions) Conditions() *Conditions {
+	return &a.conditions
+}
+
+// Encode serializes the conditions into the annotation of the underlying object and returns
+// that object. If the conditions set is empty, the annotation is removed.
+func (a *AnnotationConditions) Encode() (client.Object, error) {
+	annotations := a.Object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if len(a.conditions) == 0 {
+		delete(annotations, a.key)
+	} else {
+		data, err := json.Marshal(a.conditions)
+		if err != nil {
+			return nil, err
+		}
+		annotations[a.key] = string(data)
+	}
+
+	a.Object.SetAnnotations(annotations)
+
+	return a.Object, nil
+}
+
+// See Conditions.FindOrInitializeFor
+func (a *AnnotationConditions) FindOrInitializeFor(ct ConditionType) Condition {
+	return a.conditions.FindOrInitializeFor(ct)
+}
+
+// See Conditions.FindStatus
+func (a *AnnotationConditions) FindStatus(status ConditionStatus) *Condition {
+	return a.conditions.FindStatus(status)
+}
+
+// See Conditions.FindType
+//
+// Deprecated: Use GetType.
+func (a *AnnotationConditions) FindType(ct ConditionType) *Condition {
+	return a.conditions.FindType(ct)
+}
+
+// See Conditions.GetType
+func (a *AnnotationConditions) GetType(ct ConditionType) (Condition, bool) {
+	return a.conditions.GetType(ct)
+}
+
+// See Conditions.MustType
+func (a *AnnotationConditions) MustType(ct ConditionType) Condition {
+	return a.conditions.MustType(ct)
+}
+
+// See Conditions.TypeHasStatus
+func (a *AnnotationConditions) TypeHasStatus(ct ConditionType, status ConditionStatus) bool {
+	return a.conditions.TypeHasStatus(ct, status)
+}
+
+// See Conditions.AnyWithStatus
+func (a *AnnotationConditions) AnyWithStatus(status ConditionStatus) bool {
+	return a.conditions.AnyWithStatus(status)
+}
+
+// See Conditions.SetCondition
+func (a *AnnotationConditions) SetCondition(condition Condition) error {
+	return a.conditions.SetCondition(condition)
+}
+
+// See Conditions.RenameType
+func (a *AnnotationConditions) RenameType(oldType, newType ConditionType) (bool, error) {
+	return a.conditions.RenameType(oldType, newType)
+}
+
+// See Conditions.RemoveConditionWith
+func (a *AnnotationConditions) RemoveConditionWith(ct ConditionType) bool {
+	return a.conditions.RemoveConditionWith(ct)
+}
+
+// See Conditions.RemoveAllWithStatus
+func (a *AnnotationConditions) RemoveAllWithStatus(status ConditionStatus) int {
+	return a.conditions.RemoveAllWithStatus(status)
+}
+
+// See Conditions.RemoveWhere
+func (a *AnnotationConditions) RemoveWhere(predicate func(Condition) bool) int {
+	return a.conditions.RemoveWhere(predicate)
+}
+
+// See Conditions.Clear
+func (a *AnnotationConditions) Clear() int {
+	return a.conditions.Clear()
+}
@@ -0,0 +1,102 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewAnnotationConditions(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "tenant",
+			Annotations: map[string]string{
+				DefaultConditionsAnnotation: `[{"type":"Quotas","status":"Completed","lastTransitionTime":null}]`,
+				"invalid":                   `{`,
+			},
+		},
+	}
+
+	res, err := NewAnnotationConditions(namespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.TypeHasStatus(ConditionType("Quotas"), ConditionCompleted) {
+		t.Error("Expected the conditions to be decoded from the annotation")
+	}
+
+	if _, err := NewAnnotationConditions(namespace, "invalid"); err == nil {
+		t.Error("Expected an error decoding an invalid annotation")
+	}
+
+	empty, err := NewAnnotationConditions(namespace, "konditionner.io/missing")
+	if err != nil || empty.Conditions() == nil || len(*empty.Conditions()) != 0 {
+		t.Error("Expected empty conditions, got: ", empty, err)
+	}
+}
+
+func TestAnnotationConditionsEncode(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "tenant"}}
+
+	res, err := NewAnnotationConditions(namespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res.SetCondition(Condition{Type: ConditionType("Quotas"), Status: ConditionCreated})
+	if _, err := res.Encode(); err != nil {
+		t.Fatal(err)
+	}
+
+	if namespace.Annotations[DefaultConditionsAnnotation] == "" {
+		t.Error("Expected the annotation to be set")
+	}
+
+	res.RemoveConditionWith(ConditionType("Quotas"))
+	if _, err := res.Encode(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := namespace.Annotations[DefaultConditionsAnnotation]; ok {
+		t.Error("Expected the annotation to be removed")
+	}
+}
+
+func TestAnnotationConditionsLock(t *testing.T) {
+	ctx := context.Background()
+	namespace := &corev1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "tenant"}}
+	c := fake.NewClientBuilder().WithObjects(namespace).Build()
+
+	res, err := NewAnnotationConditions(namespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLock(res, c, ConditionType("Quotas"), WithPersister(ObjectPersister{Client: c})).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKeyFromObject(namespace), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := NewAnnotationConditions(&stored, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.TypeHasStatus(ConditionType("Quotas"), ConditionCompleted) {
+		t.Error("Expected the condition to be persisted, got: ", decoded.Conditions())
+	}
+}
@@ -0,0 +1,258 @@
+// Package apply provides the server-side apply configurations of konditions.Conditions. With server-side apply,
+// a controller only sends the conditions it owns and the API server merges them, entry by entry, with the conditions
+// owned by other field managers. This is what makes it safe for multiple controllers to write the conditions of the
+// same resource.
+//
+// The conditions need to be declared as a list map keyed by type in the schema of the CRD for the API server to merge
+// them, see the openapi package.
+//
+//	conditions := apply.Conditions(
+//		apply.Condition().
+//			WithType(ConditionType("Bucket")).
+//			WithStatus(konditions.ConditionCompleted).
+//			WithReason("Bucket created"),
+//	)
+//
+//	err := apply.ApplyStatus(ctx, reconciler.Client, res, "bucket-controller", conditions)
+//
+// Conditions that were applied by the field manager before but aren't part of the configuration anymore are removed
+// by the API server: the configuration describes exactly the conditions the field manager owns.
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var MissingTypeErr = errors.New("Condition apply configuration has no type")
+
+// ConditionApplyConfiguration represents a declarative configuration of a konditions.Condition. Fields
+// that aren't set aren't owned by the field manager.
+type ConditionApplyConfiguration struct {
+	Type               *konditions.ConditionType   `json:"type,omitempty"`
+	Status             *konditions.ConditionStatus `json:"status,omitempty"`
+	LastTransitionTime *meta.Time                  `json:"lastTransitionTime,omitempty"`
+	Reason             *string                     `json:"reason,omitempty"`
+	InputHash          *string                     `json:"inputHash,omitempty"`
+	ResumeStatus       *konditions.ConditionStatus `json:"resumeStatus,omitempty"`
+	Ref                *string                     `json:"ref,omitempty"`
+	Attributes         map[string]string           `json:"attributes,omitempty"`
+	ObservedGeneration *int64                      `json:"observedGeneration,omitempty"`
+	Manager            *string                     `json:"manager,omitempty"`
+	LastHeartbeatTime  *meta.Time                  `json:"lastHeartbeatTime,omitempty"`
+}
+
+// Condition returns an empty ConditionApplyConfiguration, to be configured with the With functions.
+func Condition() *ConditionApplyConfiguration {
+	return &ConditionApplyConfiguration{}
+}
+
+// FromCondition returns a ConditionApplyConfiguration owning every field set on the condition given.
+func FromCondition(condition konditions.Condition) *ConditionApplyConfiguration {
+	c := Condition().
+		WithType(condition.Type).
+		WithStatus(condition.Status).
+		WithAttributes(condition.Attributes)
+
+	if !condition.LastTransitionTime.IsZero() {
+		c.WithLastTransitionTime(condition.LastTransitionTime)
+	}
+	if condition.Reason != "" {
+		c.WithReason(condition.Reason)
+	}
+	if condition.InputHash != "" {
+		c.WithInputHash(condition.InputHash)
+	}
+	if condition.ResumeStatus != "" {
+		c.WithResumeStatus(condition.ResumeStatus)
+	}
+	if condition.Ref != "" {
+		c.WithRef(condition.Ref)
+	}
+	if condition.ObservedGeneration != 0 {
+		c.WithObservedGeneration(condition.ObservedGeneration)
+	}
+	if condition.Manager != "" {
+		c.WithManager(condition.Manager)
+	}
+	if condition.LastHeartbeatTime != nil {
+		c.WithLastHeartbeatTime(*condition.LastHeartbeatTime)
+	}
+
+	return c
+}
+
+// WithType sets the Type field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithType(value konditions.ConditionType) *ConditionApplyConfiguration {
+	c.Type = &value
+	return c
+}
+
+// WithStatus sets the Status field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithStatus(value konditions.ConditionStatus) *ConditionApplyConfiguration {
+	c.Status = &value
+	return c
+}
+
+// WithLastTransitionTime sets the LastTransitionTime field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithLastTransitionTime(value meta.Time) *ConditionApplyConfiguration {
+	c.LastTransitionTime = &value
+	return c
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithReason(value string) *ConditionApplyConfiguration {
+	c.Reason = &value
+	return c
+}
+
+// WithInputHash sets the InputHash field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithInputHash(value string) *ConditionApplyConfiguration {
+	c.InputHash = &value
+	return c
+}
+
+// WithResumeStatus sets the ResumeStatus field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithResumeStatus(value konditions.ConditionStatus) *ConditionApplyConfiguration {
+	c.ResumeStatus = &value
+	return c
+}
+
+// WithRef sets the Ref field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithRef(value string) *ConditionApplyConfiguration {
+	c.Ref = &value
+	return c
+}
+
+// WithAttributes puts the entries into the Attributes field in the declarative configuration, entries
+// already set are overwritten.
+func (c *ConditionApplyConfiguration) WithAttributes(entries map[string]string) *ConditionApplyConfiguration {
+	if len(entries) == 0 {
+		return c
+	}
+
+	if c.Attributes == nil {
+		c.Attributes = make(map[string]string, len(entries))
+	}
+
+	for key, value := range entries {
+		c.Attributes[key] = value
+	}
+	return c
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithObservedGeneration(value int64) *ConditionApplyConfiguration {
+	c.ObservedGeneration = &value
+	return c
+}
+
+// WithManager sets the Manager field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithManager(value string) *ConditionApplyConfiguration {
+	c.Manager = &value
+	return c
+}
+
+// WithLastHeartbeatTime sets the LastHeartbeatTime field in the declarative configuration to the given value.
+func (c *ConditionApplyConfiguration) WithLastHeartbeatTime(value meta.Time) *ConditionApplyConfiguration {
+	c.LastHeartbeatTime = &value
+	return c
+}
+
+// ConditionsApplyConfiguration represents a declarative configuration of konditions.Conditions: the
+// condition entries a field manager owns.
+type ConditionsApplyConfiguration []*ConditionApplyConfiguration
+
+// Conditions returns a ConditionsApplyConfiguration holding the conditions given.
+func Conditions(conditions ...*ConditionApplyConfiguration) ConditionsApplyConfiguration {
+	return ConditionsApplyConfiguration(conditions)
+}
+
+// FromConditions returns a ConditionsApplyConfiguration owning the conditions of the types given, as they are
+// in the conditions. Types that aren't in the conditions are ignored.
+//
+//	conditions := apply.FromConditions(*res.Conditions(), ConditionType("Bucket"), ConditionType("DNS"))
+func FromConditions(conditions konditions.Conditions, types ...konditions.ConditionType) ConditionsApplyConfiguration {
+	configurations := ConditionsApplyConfiguration{}
+	for _, ct := range types {
+		if condition, ok := conditions.GetType(ct); ok {
+			configurations = append(configurations, FromCondition(condition))
+		}
+	}
+
+	return configurations
+}
+
+// ApplyStatus applies the conditions to the status of the resource with server-side apply, as the field
+// manager given. The conflicts with other field managers are forced: the field manager takes ownership of
+// the conditions it applies.
+//
+// The LastTransitionTime is required by the schema of the conditions. Conditions that don't have one get the
+// LastTransitionTime of the condition in the resource when their status didn't change, the current time otherwise.
+//
+// The resource is updated with the response of the API server.
+func ApplyStatus(ctx context.Context, c client.Client, obj konditions.ConditionalResource, fieldManager string, conditions ConditionsApplyConfiguration) error {
+	patch, err := applyObject(obj, c.Scheme(), conditions)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Status().Patch(ctx, patch, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(patch.Object, obj)
+}
+
+// Builds the object sent to the API server: the identity of the resource and the conditions in its status.
+func applyObject(obj konditions.ConditionalResource, scheme *runtime.Scheme, conditions ConditionsApplyConfiguration) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]any, 0, len(conditions))
+	for _, configuration := range conditions {
+		if configuration.Type == nil {
+			return nil, MissingTypeErr
+		}
+
+		condition := *configuration
+		if condition.LastTransitionTime == nil {
+			existing, ok := obj.Conditions().GetType(*condition.Type)
+			if ok && condition.Status != nil && existing.Status == *condition.Status {
+				condition.LastTransitionTime = &existing.LastTransitionTime
+			} else {
+				t := meta.NewTime(time.Now().Truncate(konditions.TimestampPrecision))
+				condition.LastTransitionTime = &t
+			}
+		}
+
+		entry, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condition)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert %s: %w", *condition.Type, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	patch := &unstructured.Unstructured{}
+	patch.SetGroupVersionKind(gvk)
+	patch.SetName(obj.GetName())
+	patch.SetNamespace(obj.GetNamespace())
+
+	if err := unstructured.SetNestedSlice(patch.Object, entries, "status", "conditions"); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
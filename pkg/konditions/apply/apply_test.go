@@ -0,0 +1,100 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest/envtest"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestFromConditions(t *testing.T) {
+	conditions := konditions.Conditions{
+		{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCompleted, Reason: "Bucket created", Attributes: map[string]string{"konditionner.io/etag": "abc"}},
+		{Type: konditions.ConditionType("DNS"), Status: konditions.ConditionInitialized},
+	}
+
+	configurations := FromConditions(conditions, konditions.ConditionType("Bucket"), konditions.ConditionType("Missing"))
+	if len(configurations) != 1 {
+		t.Fatal("Expected only the existing conditions to be owned, got: ", configurations)
+	}
+
+	data, err := json.Marshal(configurations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"type":"Bucket","status":"Completed","reason":"Bucket created","attributes":{"konditionner.io/etag":"abc"}}]`
+	if string(data) != expected {
+		t.Errorf("Expected only the fields set to be serialized, got: %s", data)
+	}
+}
+
+func TestApplyStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := envtest.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	transitioned := meta.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	sample := &envtest.Sample{ObjectMeta: meta.ObjectMeta{Name: "sample", Namespace: "default"}}
+	sample.Status.Conditions = konditions.Conditions{
+		{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCompleted, LastTransitionTime: transitioned},
+	}
+
+	var patchType types.PatchType
+	var owner string
+	var applied map[string]any
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			options := &client.SubResourcePatchOptions{}
+			options.ApplyOptions(opts)
+			owner = options.FieldManager
+			patchType = patch.Type()
+
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, &applied)
+		},
+	}).Build()
+
+	err := ApplyStatus(ctx, c, sample, "bucket-controller", Conditions(
+		Condition().WithType(konditions.ConditionType("Bucket")).WithStatus(konditions.ConditionCompleted).WithReason("Still there"),
+		Condition().WithType(konditions.ConditionType("DNS")).WithStatus(konditions.ConditionCreated),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patchType != types.ApplyPatchType || owner != "bucket-controller" {
+		t.Errorf("Expected the status to be applied by the field manager, got %s by %q", patchType, owner)
+	}
+
+	if applied["kind"] != "Sample" || applied["apiVersion"] != envtest.GroupVersion.String() {
+		t.Error("Expected the patch to identify the resource, got: ", applied)
+	}
+
+	if bucket := sample.Conditions().FindType(konditions.ConditionType("Bucket")); bucket == nil || !bucket.LastTransitionTime.Equal(&transitioned) {
+		t.Error("Expected the LastTransitionTime to be kept when the status didn't change, got: ", bucket)
+	}
+
+	if dns := sample.Conditions().FindType(konditions.ConditionType("DNS")); dns == nil || dns.LastTransitionTime.IsZero() {
+		t.Error("Expected the LastTransitionTime to be set on new conditions, got: ", dns)
+	}
+
+	if err := ApplyStatus(ctx, c, sample, "bucket-controller", Conditions(Condition().WithReason("No type"))); !errors.Is(err, MissingTypeErr) {
+		t.Error("Expected a condition without a type to be rejected, got: ", err)
+	}
+}
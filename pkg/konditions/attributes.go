@@ -0,0 +1,148 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// MaxAttributes is the maximum number of attributes a condition can hold.
+	MaxAttributes = 16
+
+	// MaxAttributeValueLength is the maximum length, in bytes, of the value of an attribute.
+	MaxAttributeValueLength = 256
+)
+
+var AttributeNotFoundErr = errors.New("Attribute not found")
+var InvalidAttributeErr = errors.New("Invalid attribute")
+
+// Returns the value of the attribute with the given key. The boolean is false if the condition
+// doesn't have the attribute.
+func (c Condition) GetAttr(key string) (string, bool) {
+	value, ok := c.Attributes[key]
+	return value, ok
+}
+
+// Returns the value of the attribute with the given key as an integer. AttributeNotFoundErr is
+// returned when the condition doesn't have the attribute.
+//
+//	attempts, err := condition.GetInt("attempts")
+//	if err != nil && !errors.Is(err, konditions.AttributeNotFoundErr) {
+//		return condition, err
+//	}
+//	condition.SetInt("attempts", attempts+1)
+func (c Condition) GetInt(key string) (int64, error) {
+	value, ok := c.Attributes[key]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", AttributeNotFoundErr, key)
+	}
+
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s is not an integer: %w", InvalidAttributeErr, key, err)
+	}
+
+	return i, nil
+}
+
+// Returns the value of the attribute with the given key as a time. The value needs to be stored
+// in RFC3339 format, which is what SetTime does. AttributeNotFoundErr is returned when the condition
+// doesn't have the attribute.
+func (c Condition) GetTime(key string) (time.Time, error) {
+	value, ok := c.Attributes[key]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", AttributeNotFoundErr, key)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s is not a time: %w", InvalidAttributeErr, key, err)
+	}
+
+	return t, nil
+}
+
+// Sets the attribute with the given key on the condition. Attributes let stateful steps checkpoint
+// small bits of data with the condition they belong to, so the data follows the lifecycle of the
+// condition: it is stored with the condition and gone once the condition is reset.
+//
+//	condition.SetAttr("etag", bucket.ETag)
+//	res.Status.Conditions.SetCondition(condition)
+//
+// Keys follow the same rules as the keys of Kubernetes labels, values can't be longer than
+// MaxAttributeValueLength and a condition can't hold more than MaxAttributes attributes. An error
+// wrapping InvalidAttributeErr is returned, and the condition is left untouched, otherwise.
+func (c *Condition) SetAttr(key, value string) error {
+	if err := validateAttribute(key, value); err != nil {
+		return err
+	}
+
+	if _, ok := c.Attributes[key]; !ok && len(c.Attributes) >= MaxAttributes {
+		return fmt.Errorf("%w: a condition can't have more than %d attributes", InvalidAttributeErr, MaxAttributes)
+	}
+
+	if c.Attributes == nil {
+		c.Attributes = map[string]string{}
+	}
+
+	c.Attributes[key] = value
+	return nil
+}
+
+// Sets the attribute with the given key to the integer value. See SetAttr.
+func (c *Condition) SetInt(key string, value int64) error {
+	return c.SetAttr(key, strconv.FormatInt(value, 10))
+}
+
+// Sets the attribute with the given key to the time value, formatted as RFC3339. See SetAttr.
+func (c *Condition) SetTime(key string, value time.Time) error {
+	return c.SetAttr(key, value.UTC().Format(time.RFC3339Nano))
+}
+
+// Removes the attribute with the given key. The return value indicates whether the attribute
+// existed or not.
+func (c *Condition) DeleteAttr(key string) bool {
+	if _, ok := c.Attributes[key]; !ok {
+		return false
+	}
+
+	delete(c.Attributes, key)
+	if len(c.Attributes) == 0 {
+		c.Attributes = nil
+	}
+
+	return true
+}
+
+// Returns an error wrapping InvalidAttributeErr if any of the attributes of the condition
+// is invalid. SetCondition validates the attributes of a condition before storing it.
+func (c Condition) ValidateAttributes() error {
+	if len(c.Attributes) > MaxAttributes {
+		return fmt.Errorf("%w: a condition can't have more than %d attributes", InvalidAttributeErr, MaxAttributes)
+	}
+
+	for key, value := range c.Attributes {
+		if err := validateAttribute(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateAttribute(key, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+		return fmt.Errorf("%w: key %q: %s", InvalidAttributeErr, key, strings.Join(errs, ", "))
+	}
+
+	if len(value) > MaxAttributeValueLength {
+		return fmt.Errorf("%w: value of %q is longer than %d bytes", InvalidAttributeErr, key, MaxAttributeValueLength)
+	}
+
+	return nil
+}
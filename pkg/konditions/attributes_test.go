@@ -0,0 +1,89 @@
+package konditions
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionAttributes(t *testing.T) {
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionCreated}
+
+	if err := condition.SetAttr("etag", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := condition.GetAttr("etag"); !ok || value != "abc" {
+		t.Error("Unexpected etag: ", value)
+	}
+
+	if err := condition.SetInt("attempts", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts, err := condition.GetInt("attempts"); err != nil || attempts != 3 {
+		t.Error("Unexpected attempts: ", attempts, err)
+	}
+
+	now := time.Now()
+	if err := condition.SetTime("started", now); err != nil {
+		t.Fatal(err)
+	}
+
+	if started, err := condition.GetTime("started"); err != nil || !started.Equal(now) {
+		t.Error("Unexpected time: ", started, err)
+	}
+
+	if _, err := condition.GetInt("etag"); !errors.Is(err, InvalidAttributeErr) {
+		t.Error("Expected an invalid attribute error, got: ", err)
+	}
+
+	if _, err := condition.GetInt("missing"); !errors.Is(err, AttributeNotFoundErr) {
+		t.Error("Expected an attribute not found error, got: ", err)
+	}
+
+	if !condition.DeleteAttr("etag") || condition.DeleteAttr("etag") {
+		t.Error("Expected the attribute to be deleted once")
+	}
+}
+
+func TestConditionAttributesValidation(t *testing.T) {
+	condition := Condition{Type: ConditionType("Bucket")}
+
+	if err := condition.SetAttr("not a key", "value"); !errors.Is(err, InvalidAttributeErr) {
+		t.Error("Expected the key to be rejected, got: ", err)
+	}
+
+	if err := condition.SetAttr("token", strings.Repeat("a", MaxAttributeValueLength+1)); !errors.Is(err, InvalidAttributeErr) {
+		t.Error("Expected the value to be rejected, got: ", err)
+	}
+
+	for i := 0; i < MaxAttributes; i++ {
+		if err := condition.SetInt(string(rune('a'+i)), int64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := condition.SetAttr("overflow", "value"); !errors.Is(err, InvalidAttributeErr) {
+		t.Error("Expected the attribute to be rejected, got: ", err)
+	}
+
+	conditions := Conditions{}
+	invalid := Condition{Type: ConditionType("Bucket"), Attributes: map[string]string{"not a key": "value"}}
+	if err := conditions.SetCondition(invalid); !errors.Is(err, InvalidAttributeErr) {
+		t.Error("Expected SetCondition to validate attributes, got: ", err)
+	}
+}
+
+func TestConditionAttributesDeepCopy(t *testing.T) {
+	condition := Condition{Type: ConditionType("Bucket")}
+	condition.SetAttr("etag", "abc")
+
+	copy := condition.DeepCopy()
+	copy.SetAttr("etag", "def")
+
+	if value, _ := condition.GetAttr("etag"); value != "abc" {
+		t.Error("Expected the copy to not share attributes with the original, got: ", value)
+	}
+}
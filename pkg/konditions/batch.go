@@ -0,0 +1,136 @@
+package konditions
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusBatch stages the changes made to the conditions of a resource and writes them all at once, when the
+// batch is committed. Reconciles that work on several conditions make a single write instead of one per condition:
+//
+//	batch := konditions.NewStatusBatch(reconciler.Client, &res)
+//	batch.SetCondition(konditions.Condition{Type: ConditionType("Bucket"), Status: konditions.ConditionCompleted})
+//	batch.SetCondition(konditions.Condition{Type: ConditionType("DNS"), Status: konditions.ConditionCreated})
+//	batch.RemoveConditionWith(ConditionType("Legacy"))
+//
+//	if err := batch.Commit(ctx); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+// The changes are made to the conditions of the resource as they're staged, the resource can be read in between. If
+// the write conflicts, the resource is fetched again, the staged changes are made again on the fresh copy and the
+// write is retried. A change that can't be made anymore, a condition that became terminal for instance, stops the
+// commit with its error.
+//
+// Since the resource is fetched again on conflicts, the changes made to it outside of the batch are lost when that
+// happens. Resources that encode their conditions, like AnnotationConditions, need to be decoded again once fetched
+// and can't be used with a batch.
+type StatusBatch struct {
+	// Persister writes the resource, StatusPersister by default.
+	Persister Persister
+
+	client client.Client
+	obj    ConditionalResource
+	clean  Conditions
+	staged []func(*Conditions) error
+}
+
+// NewStatusBatch returns an empty batch for the resource. The client fetches the resource on conflicts, and writes
+// it through its status subresource unless the Persister of the batch is changed.
+func NewStatusBatch(c client.Client, obj ConditionalResource) *StatusBatch {
+	return &StatusBatch{
+		Persister: StatusPersister{Client: c},
+		client:    c,
+		obj:       obj,
+		clean:     obj.Conditions().DeepCopy(),
+	}
+}
+
+// See Conditions.SetCondition
+func (b *StatusBatch) SetCondition(condition Condition) error {
+	return b.stage(func(c *Conditions) error {
+		return c.SetCondition(condition)
+	})
+}
+
+// See Conditions.SetConditionForce
+func (b *StatusBatch) SetConditionForce(condition Condition) error {
+	return b.stage(func(c *Conditions) error {
+		return c.SetConditionForce(condition)
+	})
+}
+
+// See Conditions.Reset
+func (b *StatusBatch) Reset(ct ConditionType, reason string) error {
+	return b.stage(func(c *Conditions) error {
+		return c.Reset(ct, reason)
+	})
+}
+
+// See Conditions.RemoveConditionWith
+func (b *StatusBatch) RemoveConditionWith(ct ConditionType) (removed bool) {
+	b.stage(func(c *Conditions) error {
+		removed = c.RemoveConditionWith(ct)
+		return nil
+	})
+
+	return removed
+}
+
+// Staged returns the number of changes staged since the batch was last committed.
+func (b *StatusBatch) Staged() int {
+	return len(b.staged)
+}
+
+// Commit writes the resource with the staged changes, retrying on conflicts, see StatusBatch. The resource isn't
+// written if the changes didn't change its conditions. The batch is empty once the changes are written and can be
+// used again.
+func (b *StatusBatch) Commit(ctx context.Context) error {
+	if len(b.staged) == 0 {
+		return nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if b.obj.Conditions().Equal(b.clean) {
+			return nil
+		}
+
+		err := b.Persister.Persist(ctx, b.obj)
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+
+		if getErr := b.client.Get(ctx, client.ObjectKeyFromObject(b.obj), b.obj); getErr != nil {
+			return getErr
+		}
+
+		b.clean = b.obj.Conditions().DeepCopy()
+		for _, change := range b.staged {
+			if changeErr := change(b.obj.Conditions()); changeErr != nil {
+				return changeErr
+			}
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	b.staged = nil
+	b.clean = b.obj.Conditions().DeepCopy()
+	return nil
+}
+
+func (b *StatusBatch) stage(change func(*Conditions) error) error {
+	if err := change(b.obj.Conditions()); err != nil {
+		return err
+	}
+
+	b.staged = append(b.staged, change)
+	return nil
+}
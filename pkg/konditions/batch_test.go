@@ -0,0 +1,119 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newBatchTestClient(res *testResource, writes *int) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(res).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				*writes++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+}
+
+func TestStatusBatch(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("batch")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Legacy"), Status: ConditionCompleted})
+
+	var writes int
+	c := newBatchTestClient(res, &writes)
+
+	batch := NewStatusBatch(c, res)
+	batch.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	batch.SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCreated})
+	batch.RemoveConditionWith(ConditionType("Legacy"))
+
+	if !res.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCreated) || batch.Staged() != 3 {
+		t.Error("Expected the changes to be made in memory as they're staged, got: ", res.Conditions())
+	}
+
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if writes != 1 || batch.Staged() != 0 {
+		t.Error("Expected a single write, got: ", writes)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*stored.Conditions()) != 2 || !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Unexpected conditions: ", stored.Conditions())
+	}
+
+	batch.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	if err := batch.Commit(ctx); err != nil || writes != 1 {
+		t.Error("Expected changes that don't change the conditions to not be written, got: ", writes, err)
+	}
+}
+
+func TestStatusBatchConflict(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("batch")
+
+	var writes int
+	c := newBatchTestClient(res, &writes)
+
+	stale := res.DeepCopyObject().(*testResource)
+
+	// Someone else writes the resource in the meantime.
+	res.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted})
+	if err := c.Status().Update(ctx, res); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := NewStatusBatch(c, stale)
+	batch.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+
+	if err := batch.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCompleted) || !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the changes to be made on the fresh resource, got: ", stored.Conditions())
+	}
+}
+
+func TestStatusBatchConflictTerminal(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("batch")
+
+	var writes int
+	c := newBatchTestClient(res, &writes)
+
+	stale := res.DeepCopyObject().(*testResource)
+
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError})
+	if err := c.Status().Update(ctx, res); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := NewStatusBatch(c, stale)
+	batch.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+
+	if err := batch.Commit(ctx); !errors.Is(err, TerminalConditionErr) {
+		t.Error("Expected TerminalConditionErr, got: ", err)
+	}
+}
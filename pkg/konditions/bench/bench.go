@@ -0,0 +1,149 @@
+// Package bench exposes the benchmarks of Konditionner so they can be run against the condition counts of
+// your own resources, and performance budgets to make a test fail when an operation becomes too slow.
+//
+//	func BenchmarkConditions(b *testing.B) {
+//		b.Run("SetCondition", bench.SetCondition(40))
+//		b.Run("FindType", bench.FindType(40))
+//	}
+//
+//	func TestConditionsBudget(t *testing.T) {
+//		bench.Enforce(t,
+//			bench.Budget{Name: "SetCondition", Benchmark: bench.SetCondition(40), MaxNsPerOp: 2000},
+//			bench.Budget{Name: "LockExecute", Benchmark: bench.LockExecute(40), MaxAllocsPerOp: 2000},
+//		)
+//	}
+package bench
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Conditions returns a conditions set with n conditions, of types Type0 to Type{n-1}.
+func Conditions(n int) konditions.Conditions {
+	conditions := make(konditions.Conditions, 0, n)
+	for i := 0; i < n; i++ {
+		conditions = append(conditions, konditions.Condition{
+			Type:   konditions.ConditionType(fmt.Sprintf("Type%d", i)),
+			Status: konditions.ConditionCreated,
+		})
+	}
+
+	return conditions
+}
+
+// SetCondition benchmarks updating the last condition of a set of n conditions.
+func SetCondition(n int) func(*testing.B) {
+	return func(b *testing.B) {
+		conditions := Conditions(n)
+		condition := konditions.Condition{Type: konditions.ConditionType(fmt.Sprintf("Type%d", n-1)), Status: konditions.ConditionCompleted}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			conditions.SetCondition(condition)
+		}
+	}
+}
+
+// FindType benchmarks finding the last condition of a set of n conditions.
+func FindType(n int) func(*testing.B) {
+	return func(b *testing.B) {
+		conditions := Conditions(n)
+		ct := konditions.ConditionType(fmt.Sprintf("Type%d", n-1))
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			conditions.FindType(ct)
+		}
+	}
+}
+
+// LockExecute benchmarks a Lock, backed by a fake client, on a resource that already has n conditions. It measures
+// the overhead of the lock and the serialization of the conditions, not the latency of an API server.
+func LockExecute(n int) func(*testing.B) {
+	return func(b *testing.B) {
+		ctx := context.Background()
+		res := &resource{ObjectMeta: meta.ObjectMeta{Name: "benchmark", Namespace: "default"}}
+
+		scheme := runtime.NewScheme()
+		gv := schema.GroupVersion{Group: "bench.konditionner.io", Version: "v1"}
+		scheme.AddKnownTypeWithName(gv.WithKind("Resource"), &resource{})
+		meta.AddToGroupVersion(scheme, gv)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(res).WithObjects(res).Build()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.Status.Conditions = Conditions(n)
+			err := konditions.NewLock(res, c, konditions.ConditionType("Bench")).Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+				condition.Status = konditions.ConditionCompleted
+				return condition, nil
+			})
+
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Budget is the performance budget of a benchmark. A limit of 0 isn't enforced.
+type Budget struct {
+	Name           string
+	Benchmark      func(*testing.B)
+	MaxNsPerOp     int64
+	MaxAllocsPerOp int64
+}
+
+// Enforce runs the benchmarks of the budgets and fails the test for every budget exceeded. Benchmarks take
+// time to run, Enforce skips the test when it runs with -short.
+func Enforce(t *testing.T, budgets ...Budget) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("Performance budgets are not enforced with -short")
+	}
+
+	for _, budget := range budgets {
+		result := testing.Benchmark(budget.Benchmark)
+
+		if budget.MaxNsPerOp > 0 && result.NsPerOp() > budget.MaxNsPerOp {
+			t.Errorf("%s: %d ns/op exceeds the budget of %d ns/op", budget.Name, result.NsPerOp(), budget.MaxNsPerOp)
+		}
+
+		if budget.MaxAllocsPerOp > 0 && result.AllocsPerOp() > budget.MaxAllocsPerOp {
+			t.Errorf("%s: %d allocs/op exceeds the budget of %d allocs/op", budget.Name, result.AllocsPerOp(), budget.MaxAllocsPerOp)
+		}
+	}
+}
+
+type resource struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Status struct {
+		Conditions konditions.Conditions `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+func (r *resource) Conditions() *konditions.Conditions {
+	return &r.Status.Conditions
+}
+
+func (r *resource) DeepCopyObject() runtime.Object {
+	out := &resource{}
+	out.TypeMeta = r.TypeMeta
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status.Conditions = r.Status.Conditions.DeepCopy()
+	return out
+}
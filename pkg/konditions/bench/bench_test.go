@@ -0,0 +1,22 @@
+package bench
+
+import (
+	"testing"
+)
+
+func BenchmarkConditions(b *testing.B) {
+	b.Run("SetCondition", SetCondition(40))
+	b.Run("FindType", FindType(40))
+	b.Run("LockExecute", LockExecute(40))
+}
+
+func TestEnforce(t *testing.T) {
+	Enforce(t, Budget{Name: "FindType", Benchmark: FindType(10), MaxNsPerOp: int64(1e9)})
+}
+
+func TestConditions(t *testing.T) {
+	conditions := Conditions(3)
+	if len(conditions) != 3 || conditions.FindType("Type2") == nil {
+		t.Error("Unexpected conditions: ", conditions)
+	}
+}
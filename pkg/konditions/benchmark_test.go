@@ -0,0 +1,68 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+var benchmarkSizes = []int{1, 10, 100}
+
+func benchmarkConditions(n int) Conditions {
+	conditions := make(Conditions, 0, n)
+	for i := 0; i < n; i++ {
+		conditions = append(conditions, Condition{Type: ConditionType(fmt.Sprintf("Type%d", i)), Status: ConditionCreated})
+	}
+
+	return conditions
+}
+
+func BenchmarkSetCondition(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			conditions := benchmarkConditions(n)
+			condition := Condition{Type: ConditionType(fmt.Sprintf("Type%d", n-1)), Status: ConditionCompleted}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				conditions.SetCondition(condition)
+			}
+		})
+	}
+}
+
+func BenchmarkFindType(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			conditions := benchmarkConditions(n)
+			ct := ConditionType(fmt.Sprintf("Type%d", n-1))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				conditions.FindType(ct)
+			}
+		})
+	}
+}
+
+func BenchmarkLockExecute(b *testing.B) {
+	ctx := context.Background()
+	res := newTestResource("benchmark")
+	c := newTestClient(res)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res.Status.Conditions = Conditions{}
+		err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, func(condition Condition) (Condition, error) {
+			condition.Status = ConditionCompleted
+			return condition, nil
+		})
+
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultBulkConcurrency is the number of resources updated concurrently by BulkSetCondition.
+const DefaultBulkConcurrency = 10
+
+var BulkPartialFailureErr = errors.New("Condition could not be set on every resource")
+
+type bulkOptions struct {
+	concurrency int
+	listOptions []client.ListOption
+}
+
+// BulkOption configures BulkSetCondition.
+type BulkOption func(*bulkOptions)
+
+// WithConcurrency configures how many resources are updated at the same time, DefaultBulkConcurrency otherwise.
+func WithConcurrency(n int) BulkOption {
+	return func(o *bulkOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithListOptions configures how the resources are listed: the namespace, the label selector, etc.
+func WithListOptions(opts ...client.ListOption) BulkOption {
+	return func(o *bulkOptions) {
+		o.listOptions = append(o.listOptions, opts...)
+	}
+}
+
+// BulkResult reports what happened to each resource matched by BulkSetCondition.
+type BulkResult struct {
+	// Resources that were updated.
+	Updated []types.NamespacedName
+
+	// Resources that already had the condition with the same status and reason.
+	Unchanged []types.NamespacedName
+
+	// Resources that couldn't be updated, with the reason why.
+	Failed map[types.NamespacedName]error
+}
+
+// BulkSetCondition lists the resources matching the options and sets the condition on each of them, through
+// their status subresource. Platform teams need this for fleet-wide operations, marking every tenant as
+// being in maintenance, for instance:
+//
+//	result, err := konditions.BulkSetCondition(ctx, c, &TenantList{}, konditions.Condition{
+//		Type:   ConditionType("Maintenance"),
+//		Status: konditions.ConditionCreated,
+//		Reason: "Database upgrade in progress",
+//	}, konditions.WithListOptions(client.MatchingLabels{"tier": "free"}))
+//
+// The items of the list need to be ConditionalResources. Updates are made concurrently, see WithConcurrency,
+// and are retried on conflicts. A failure on a resource doesn't stop the others: the result reports the
+// failures and the error returned wraps BulkPartialFailureErr along with the error of every failure. The same
+// rules as SetCondition apply, a condition in a terminal status can't transition to another status.
+//
+// No update is made if one of the items isn't a ConditionalResource. When the context is done, the resources that
+// weren't updated yet are left alone: they aren't part of the result and the error of the context is returned.
+func BulkSetCondition(ctx context.Context, c client.Client, list client.ObjectList, condition Condition, opts ...BulkOption) (BulkResult, error) {
+	options := bulkOptions{concurrency: DefaultBulkConcurrency}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	result := BulkResult{Failed: map[types.NamespacedName]error{}}
+
+	if err := c.List(ctx, list, options.listOptions...); err != nil {
+		return result, err
+	}
+
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return result, err
+	}
+
+	// Every item is checked before the first update, the resources are either all updated or none of them is.
+	resources := make([]ConditionalResource, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(ConditionalResource)
+		if !ok {
+			return result, fmt.Errorf("%w: %T is not a ConditionalResource", UnsupportedResourceErr, item)
+		}
+		resources = append(resources, obj)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(options.concurrency, 1))
+
+dispatch:
+	for _, obj := range resources {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case sem <- struct{}{}:
+		}
+
+		// The context and the semaphore can both be ready, the context wins.
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(obj ConditionalResource) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			updated, err := setConditionOn(ctx, c, obj, condition)
+			key := client.ObjectKeyFromObject(obj)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err != nil:
+				result.Failed[key] = err
+			case updated:
+				result.Updated = append(result.Updated, key)
+			default:
+				result.Unchanged = append(result.Unchanged, key)
+			}
+		}(obj)
+	}
+
+	wg.Wait()
+
+	sortKeys(result.Updated)
+	sortKeys(result.Unchanged)
+
+	if err := ctx.Err(); err != nil {
+		return result, errors.Join(err, result.Err())
+	}
+
+	return result, result.Err()
+}
+
+// Err returns an error wrapping BulkPartialFailureErr and the error of every failure, or nil if no resource failed.
+func (r BulkResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	keys := make([]types.NamespacedName, 0, len(r.Failed))
+	for key := range r.Failed {
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+
+	errs := []error{fmt.Errorf("%w: %d failed", BulkPartialFailureErr, len(r.Failed))}
+	for _, key := range keys {
+		errs = append(errs, fmt.Errorf("%s: %w", key, r.Failed[key]))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sets the condition on the resource and persists it, fetching the resource again on conflicts.
+func setConditionOn(ctx context.Context, c client.Client, obj ConditionalResource, condition Condition) (updated bool, err error) {
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		existing := obj.Conditions().FindType(condition.Type)
+		if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason {
+			updated = false
+			return nil
+		}
+
+		if err := obj.Conditions().SetCondition(condition); err != nil {
+			return err
+		}
+
+		err := c.Status().Update(ctx, obj)
+		if apierrors.IsConflict(err) {
+			if getErr := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+
+		updated = err == nil
+		return err
+	})
+
+	return updated, err
+}
+
+func sortKeys(keys []types.NamespacedName) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+}
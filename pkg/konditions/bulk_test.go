@@ -0,0 +1,96 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestBulkSetCondition(t *testing.T) {
+	ctx := context.Background()
+
+	tenants := []*testResource{newTestResource("a"), newTestResource("b"), newTestResource("c"), newTestResource("d")}
+	tenants[0].SetLabels(map[string]string{"tier": "free"})
+	tenants[1].SetLabels(map[string]string{"tier": "free"})
+	tenants[1].Status.Conditions = Conditions{{Type: ConditionType("Maintenance"), Status: ConditionCreated, Reason: "Database upgrade"}}
+	tenants[2].SetLabels(map[string]string{"tier": "free"})
+	tenants[2].Status.Conditions = Conditions{{Type: ConditionType("Maintenance"), Status: ConditionError}}
+
+	c := newTestClient(tenants[0], tenants[1], tenants[2], tenants[3])
+
+	result, err := BulkSetCondition(ctx, c, &testResourceList{}, Condition{
+		Type:   ConditionType("Maintenance"),
+		Status: ConditionCreated,
+		Reason: "Database upgrade",
+	}, WithListOptions(client.MatchingLabels{"tier": "free"}), WithConcurrency(2))
+
+	if !errors.Is(err, BulkPartialFailureErr) || !errors.Is(err, TerminalConditionErr) {
+		t.Error("Expected a partial failure, got: ", err)
+	}
+
+	if len(result.Updated) != 1 || result.Updated[0].Name != "a" {
+		t.Error("Unexpected updated resources: ", result.Updated)
+	}
+
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Name != "b" {
+		t.Error("Unexpected unchanged resources: ", result.Unchanged)
+	}
+
+	if len(result.Failed) != 1 {
+		t.Error("Unexpected failures: ", result.Failed)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(tenants[0]), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Maintenance"), ConditionCreated) {
+		t.Error("Expected the condition to be stored, got: ", stored.Status.Conditions)
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(tenants[3]), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored.Status.Conditions) != 0 {
+		t.Error("Expected the resource not matching the selector to be left untouched")
+	}
+}
+
+func TestBulkSetConditionCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var writes int
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(newTestResource("a"), newTestResource("b"), newTestResource("c")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				writes++
+				// The context is cancelled while the first resource is updated.
+				cancel()
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	result, err := BulkSetCondition(ctx, c, &testResourceList{}, Condition{
+		Type:   ConditionType("Maintenance"),
+		Status: ConditionCreated,
+	}, WithConcurrency(1))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected the error of the context, got: ", err)
+	}
+
+	if writes != 1 || len(result.Updated)+len(result.Unchanged)+len(result.Failed) != 1 {
+		t.Error("Expected the resources left to be skipped once the context is done, got: ", writes, result)
+	}
+}
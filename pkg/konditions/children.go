@@ -0,0 +1,136 @@
+package konditions
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxListedChildren is the number of children listed in the reason of the condition returned by SummarizeChildren,
+// the others are counted.
+var MaxListedChildren = 5
+
+// RollupStrategy decides the status of the condition that summarizes the conditions of children, see SummarizeChildren.
+type RollupStrategy int
+
+const (
+	// WorstOf gives the summary the status of the child that is the furthest from being completed: errored
+	// children first, then the ones in progress, suspended and terminated. The summary is completed when every child is.
+	WorstOf RollupStrategy = iota
+
+	// AllCompleted makes the summary ConditionCompleted when every child is, ConditionInitialized otherwise: the
+	// children that failed are listed in the reason, but don't fail the summary.
+	AllCompleted
+
+	// Percentage makes the summary ConditionCompleted once the Threshold of the children is completed, and
+	// ConditionError once too many children failed for the threshold to be reached. It's ConditionInitialized otherwise.
+	Percentage
+)
+
+// RollupRule configures how SummarizeChildren summarizes the conditions of the children.
+type RollupRule struct {
+	// Type is the type of the condition of the children. A child without the condition counts as ConditionInitialized.
+	Type ConditionType
+
+	// Target is the type of the summary, Type if it's empty.
+	Target ConditionType
+
+	Strategy RollupStrategy
+
+	// Threshold is the share of the children, between 0 and 1, that need to be completed for the summary to be
+	// completed with the Percentage strategy.
+	Threshold float64
+}
+
+// SummarizeChildren returns a single condition that summarizes the condition of the type of the rule across the children,
+// for a parent that tracks many children: a Cluster that summarizes its Nodes, for instance. The reason of the summary
+// counts the children that are completed and lists the ones that aren't, see MaxListedChildren:
+//
+//	summary := konditions.SummarizeChildren(nodes, konditions.RollupRule{
+//		Type:   ConditionType("Ready"),
+//		Target: ConditionType("NodesReady"),
+//	})
+//	// NodesReady: Error, "48/50 completed: node-a is Error (Disk full), node-b is Locked"
+//
+//	cluster.Conditions().SetCondition(summary)
+//
+// The classes of the statuses decide which children are completed, failed or in progress, see StatusClass. The summary
+// doesn't have a LastTransitionTime, SetCondition sets it when it changes.
+func SummarizeChildren(children []ConditionalResource, rule RollupRule) Condition {
+	summary := Condition{Type: rule.Target}
+	if summary.Type == "" {
+		summary.Type = rule.Type
+	}
+
+	var completed, failed int
+	var offending []string
+	worst := Condition{Status: ConditionCompleted}
+
+	for _, child := range children {
+		condition := child.Conditions().FindOrInitializeFor(rule.Type)
+		switch condition.Class() {
+		case ClassCompleted:
+			completed++
+			continue
+		case ClassError:
+			failed++
+		}
+
+		if severity(condition) > severity(worst) {
+			worst = condition
+		}
+
+		description := fmt.Sprintf("%s is %s", child.GetName(), condition.Status)
+		if condition.Reason != "" && condition.Class() == ClassError {
+			description += fmt.Sprintf(" (%s)", condition.Reason)
+		}
+		offending = append(offending, description)
+	}
+
+	switch rule.Strategy {
+	case AllCompleted:
+		summary.Status = ConditionInitialized
+		if completed == len(children) {
+			summary.Status = ConditionCompleted
+		}
+	case Percentage:
+		needed := int(math.Ceil(rule.Threshold * float64(len(children))))
+		switch {
+		case completed >= needed:
+			summary.Status = ConditionCompleted
+		case len(children)-failed < needed:
+			summary.Status = ConditionError
+		default:
+			summary.Status = ConditionInitialized
+		}
+	default:
+		summary.Status = worst.Status
+	}
+
+	summary.Reason = fmt.Sprintf("%d/%d completed", completed, len(children))
+	if len(offending) > MaxListedChildren {
+		offending = append(offending[:MaxListedChildren], fmt.Sprintf("and %d more", len(offending)-MaxListedChildren))
+	}
+
+	if len(offending) > 0 {
+		summary.Reason += ": " + strings.Join(offending, ", ")
+	}
+
+	return summary
+}
+
+// Returns how far the condition is from being completed, for WorstOf.
+func severity(condition Condition) int {
+	switch condition.Class() {
+	case ClassError:
+		return 4
+	case ClassInProgress:
+		return 3
+	case ClassSuspended:
+		return 2
+	case ClassTerminal:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,79 @@
+package konditions
+
+import (
+	"strings"
+	"testing"
+)
+
+func newChildren(statuses ...ConditionStatus) []ConditionalResource {
+	var children []ConditionalResource
+	for i, status := range statuses {
+		child := newTestResource(string(rune('a'+i)) + "-node")
+		if status != "" {
+			child.Conditions().SetCondition(Condition{Type: ConditionType("Ready"), Status: status, Reason: "Disk full"})
+		}
+		children = append(children, child)
+	}
+
+	return children
+}
+
+func TestSummarizeChildrenWorstOf(t *testing.T) {
+	children := newChildren(ConditionCompleted, ConditionLocked, ConditionError, ConditionCompleted)
+	summary := SummarizeChildren(children, RollupRule{Type: ConditionType("Ready"), Target: ConditionType("NodesReady")})
+
+	if summary.Type != ConditionType("NodesReady") || summary.Status != ConditionError {
+		t.Error("Expected the summary to have the worst status, got: ", summary)
+	}
+
+	if summary.Reason != "2/4 completed: b-node is Locked, c-node is Error (Disk full)" {
+		t.Error("Unexpected reason: ", summary.Reason)
+	}
+
+	summary = SummarizeChildren(newChildren(ConditionCompleted, ConditionCompleted), RollupRule{Type: ConditionType("Ready")})
+	if summary.Type != ConditionType("Ready") || summary.Status != ConditionCompleted || summary.Reason != "2/2 completed" {
+		t.Error("Expected the summary to be completed, got: ", summary)
+	}
+
+	summary = SummarizeChildren(newChildren(ConditionCompleted, ""), RollupRule{Type: ConditionType("Ready")})
+	if summary.Status != ConditionInitialized {
+		t.Error("Expected a child without the condition to be initialized, got: ", summary)
+	}
+}
+
+func TestSummarizeChildrenAllCompleted(t *testing.T) {
+	rule := RollupRule{Type: ConditionType("Ready"), Strategy: AllCompleted}
+
+	if summary := SummarizeChildren(newChildren(ConditionCompleted, ConditionError), rule); summary.Status != ConditionInitialized {
+		t.Error("Expected the summary to wait on every child, got: ", summary)
+	}
+
+	if summary := SummarizeChildren(newChildren(ConditionCompleted, ConditionCompleted), rule); summary.Status != ConditionCompleted {
+		t.Error("Expected the summary to be completed, got: ", summary)
+	}
+}
+
+func TestSummarizeChildrenPercentage(t *testing.T) {
+	rule := RollupRule{Type: ConditionType("Ready"), Strategy: Percentage, Threshold: 0.75}
+
+	cases := map[ConditionStatus][]ConditionStatus{
+		ConditionCompleted:   {ConditionCompleted, ConditionCompleted, ConditionCompleted, ConditionLocked},
+		ConditionInitialized: {ConditionCompleted, ConditionCompleted, ConditionError, ConditionLocked},
+		ConditionError:       {ConditionCompleted, ConditionError, ConditionError, ConditionLocked},
+	}
+
+	for expected, statuses := range cases {
+		if summary := SummarizeChildren(newChildren(statuses...), rule); summary.Status != expected {
+			t.Errorf("Expected %s for %v, got: %s", expected, statuses, summary.Status)
+		}
+	}
+}
+
+func TestSummarizeChildrenListedChildren(t *testing.T) {
+	children := newChildren(ConditionLocked, ConditionLocked, ConditionLocked, ConditionLocked, ConditionLocked, ConditionLocked, ConditionLocked)
+	summary := SummarizeChildren(children, RollupRule{Type: ConditionType("Ready")})
+
+	if !strings.HasSuffix(summary.Reason, "e-node is Locked, and 2 more") {
+		t.Error("Expected the children to be listed up to MaxListedChildren, got: ", summary.Reason)
+	}
+}
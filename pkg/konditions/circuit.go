@@ -0,0 +1,221 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ConditionDependencyUnavailable means the condition wasn't attempted because the external dependency it
+// relies on is failing, see CircuitBreaker. The condition is attempted again once the circuit lets it through.
+const ConditionDependencyUnavailable ConditionStatus = "DependencyUnavailable"
+
+var DependencyUnavailableErr = errors.New("Dependency is unavailable")
+
+// DependencyUnavailableError is returned by Execute when the circuit of the dependency of the condition is open.
+// It wraps DependencyUnavailableErr and tells when the dependency will be attempted again.
+type DependencyUnavailableError struct {
+	Dependency string
+	RetryAfter time.Duration
+}
+
+func (e *DependencyUnavailableError) Error() string {
+	return fmt.Sprintf("%s: %s, retrying in %s", DependencyUnavailableErr, e.Dependency, e.RetryAfter)
+}
+
+func (e *DependencyUnavailableError) Unwrap() error {
+	return DependencyUnavailableErr
+}
+
+// CircuitState is the state of the circuit of a dependency.
+type CircuitState string
+
+const (
+	// CircuitClosed lets every attempt through.
+	CircuitClosed CircuitState = "Closed"
+
+	// CircuitOpen rejects every attempt until the cool down elapsed.
+	CircuitOpen CircuitState = "Open"
+
+	// CircuitHalfOpen lets a single attempt through, to find out whether the dependency recovered.
+	CircuitHalfOpen CircuitState = "HalfOpen"
+)
+
+type circuit struct {
+	state     CircuitState
+	failures  []time.Time
+	openUntil time.Time
+
+	// probe is when the attempt that probes a half-open circuit started. A probe that never reports
+	// back, because its lock failed to be acquired for instance, is replaced after the cool down.
+	probe time.Time
+}
+
+// CircuitBreaker protects the external dependencies of condition types, a cloud provider for instance, during
+// outages. Condition types are grouped by dependency, and the failures of every condition of a dependency, across
+// all resources, are counted together. Once they reach the threshold within the window, the circuit of the
+// dependency opens: locks for the types of the dependency aren't acquired anymore, the conditions are set to
+// ConditionDependencyUnavailable instead, until the cool down elapsed.
+//
+//	breaker := konditions.NewCircuitBreaker(10, time.Minute, 30*time.Second).
+//		Dependency("aws", ConditionType("Bucket"), ConditionType("Queue"))
+//
+//	reconciler := konditions.NewReconciler[MyCRD](mgr.GetClient()).
+//		On(ConditionType("Bucket"), bucketHandler).
+//		WithLockOptions(konditions.WithCircuitBreaker(breaker)).
+//		Build()
+//
+// Once the cool down elapsed, the circuit is half-open and lets a single attempt through. The circuit closes if it
+// succeeds, and opens again otherwise. The ConditionReconciler requeues the resources rejected by the circuit for
+// when the cool down elapses.
+//
+// The state of the circuits lives in memory, it is shared by the locks of the process that use the same breaker.
+type CircuitBreaker struct {
+	Threshold int
+	Window    time.Duration
+	CoolDown  time.Duration
+
+	mu           sync.Mutex
+	dependencies map[ConditionType]string
+	circuits     map[string]*circuit
+
+	// now returns the current time. It defaults to time.Now and exists for tests.
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a breaker that opens a circuit after `threshold` failures within the window, and
+// keeps it open for the cool down.
+func NewCircuitBreaker(threshold int, window, coolDown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold:    threshold,
+		Window:       window,
+		CoolDown:     coolDown,
+		dependencies: map[ConditionType]string{},
+		circuits:     map[string]*circuit{},
+		now:          time.Now,
+	}
+}
+
+// Dependency groups the condition types under the dependency given. A type belongs to a single dependency,
+// registering it again moves it to the new dependency.
+func (b *CircuitBreaker) Dependency(name string, types ...ConditionType) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ct := range types {
+		b.dependencies[ct] = name
+	}
+
+	if _, ok := b.circuits[name]; !ok {
+		b.circuits[name] = &circuit{state: CircuitClosed}
+	}
+
+	return b
+}
+
+// State returns the state of the circuit of the dependency.
+func (b *CircuitBreaker) State(dependency string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[dependency]
+	if !ok {
+		return CircuitClosed
+	}
+
+	if c.state == CircuitOpen && !b.now().Before(c.openUntil) {
+		return CircuitHalfOpen
+	}
+
+	return c.state
+}
+
+// Allow returns nil if the condition type can be attempted, a *DependencyUnavailableError otherwise. Types
+// that don't belong to any dependency are always allowed.
+func (b *CircuitBreaker) Allow(ct ConditionType) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dependency, ok := b.dependencies[ct]
+	if !ok {
+		return nil
+	}
+
+	c := b.circuits[dependency]
+	now := b.now()
+
+	switch c.state {
+	case CircuitClosed:
+		return nil
+	case CircuitOpen:
+		if now.Before(c.openUntil) {
+			return &DependencyUnavailableError{Dependency: dependency, RetryAfter: c.openUntil.Sub(now)}
+		}
+		c.state = CircuitHalfOpen
+		c.probe = time.Time{}
+	}
+
+	// Half-open, a single attempt probes the dependency.
+	if !c.probe.IsZero() && now.Sub(c.probe) < b.CoolDown {
+		return &DependencyUnavailableError{Dependency: dependency, RetryAfter: b.CoolDown - now.Sub(c.probe)}
+	}
+
+	c.probe = now
+	return nil
+}
+
+// Record the outcome of an attempt of the condition type.
+func (b *CircuitBreaker) Record(ct ConditionType, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dependency, ok := b.dependencies[ct]
+	if !ok {
+		return
+	}
+
+	c := b.circuits[dependency]
+	now := b.now()
+
+	if !failed {
+		if c.state == CircuitHalfOpen {
+			c.state = CircuitClosed
+			c.failures = nil
+			c.probe = time.Time{}
+		}
+		return
+	}
+
+	if c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+		c.openUntil = now.Add(b.CoolDown)
+		c.probe = time.Time{}
+		return
+	}
+
+	// Only the failures within the window count.
+	failures := c.failures[:0]
+	for _, t := range c.failures {
+		if now.Sub(t) < b.Window {
+			failures = append(failures, t)
+		}
+	}
+	c.failures = append(failures, now)
+
+	if len(c.failures) >= b.Threshold {
+		c.state = CircuitOpen
+		c.openUntil = now.Add(b.CoolDown)
+		c.failures = nil
+	}
+}
+
+// WithCircuitBreaker configures the lock to check the circuit of the dependency of the condition before
+// acquiring it, and to record the outcome of the task. See CircuitBreaker.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithCircuitBreaker(breaker))
+func WithCircuitBreaker(breaker *CircuitBreaker) LockOption {
+	return func(l *Lock) {
+		l.breaker = breaker
+	}
+}
@@ -0,0 +1,138 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute, 30*time.Second).Dependency("aws", ConditionType("Bucket"))
+	breaker.now = func() time.Time { return now }
+
+	breaker.Record(ConditionType("Bucket"), true)
+	now = now.Add(2 * time.Minute)
+	breaker.Record(ConditionType("Bucket"), true)
+
+	if state := breaker.State("aws"); state != CircuitClosed {
+		t.Fatal("Expected failures outside of the window not to count, got: ", state)
+	}
+
+	breaker.Record(ConditionType("Bucket"), true)
+	if state := breaker.State("aws"); state != CircuitOpen {
+		t.Fatal("Expected the circuit to open, got: ", state)
+	}
+
+	var unavailable *DependencyUnavailableError
+	if err := breaker.Allow(ConditionType("Bucket")); !errors.As(err, &unavailable) || unavailable.RetryAfter != 30*time.Second || !errors.Is(err, DependencyUnavailableErr) {
+		t.Fatal("Expected the attempt to be rejected, got: ", err)
+	}
+
+	if err := breaker.Allow(ConditionType("DNS")); err != nil {
+		t.Error("Expected types without a dependency to be allowed, got: ", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := breaker.Allow(ConditionType("Bucket")); err != nil {
+		t.Fatal("Expected a probe to be allowed once the cool down elapsed, got: ", err)
+	}
+
+	if err := breaker.Allow(ConditionType("Bucket")); err == nil {
+		t.Fatal("Expected a single probe to be allowed")
+	}
+
+	breaker.Record(ConditionType("Bucket"), true)
+	if state := breaker.State("aws"); state != CircuitOpen {
+		t.Fatal("Expected a failed probe to open the circuit again, got: ", state)
+	}
+
+	now = now.Add(30 * time.Second)
+	if err := breaker.Allow(ConditionType("Bucket")); err != nil {
+		t.Fatal(err)
+	}
+
+	breaker.Record(ConditionType("Bucket"), false)
+	if state := breaker.State("aws"); state != CircuitClosed {
+		t.Error("Expected a successful probe to close the circuit, got: ", state)
+	}
+}
+
+func TestReconcilerWithCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute, 30*time.Second).Dependency("aws", ConditionType("Bucket"))
+	breaker.now = func() time.Time { return now }
+
+	failing := newTestResource("failing")
+	other := newTestResource("other")
+	protected := newTestResource("protected")
+	c := newTestClient(failing, other, protected)
+
+	available := false
+	var statuses []ConditionStatus
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			statuses = append(statuses, condition.Status)
+			if !available {
+				return condition, errors.New("Service unavailable")
+			}
+
+			condition.Status = ConditionCompleted
+			return condition, nil
+		}).
+		WithLockOptions(WithCircuitBreaker(breaker)).
+		Build()
+
+	for _, res := range []*testResource{failing, other} {
+		if _, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}); err == nil {
+			t.Fatal("Expected the handler to fail")
+		}
+	}
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(protected)}
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != 30*time.Second {
+		t.Error("Expected the resource to be requeued once the cool down elapses, got: ", result)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionDependencyUnavailable) {
+		t.Fatal("Expected the condition to be marked unavailable, got: ", stored.Conditions())
+	}
+
+	now = now.Add(30 * time.Second)
+	available = true
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 3 || statuses[2] != ConditionInitialized {
+		t.Error("Expected the handler to see the status the condition had before being short-circuited, got: ", statuses)
+	}
+
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to complete once the dependency recovered, got: ", stored.Conditions())
+	}
+
+	if state := breaker.State("aws"); state != CircuitClosed {
+		t.Error("Expected the circuit to close, got: ", state)
+	}
+}
@@ -0,0 +1,90 @@
+package konditions
+
+import "sync"
+
+// StatusClass classifies a status by what it means for the work on a condition. The helpers of Konditionner don't
+// look at the statuses directly but at their class: summaries, requeues, priorities, stuck detection, metrics, etc.
+// A custom status registered with a class is handled like the statuses of Konditionner of the same class.
+type StatusClass int
+
+const (
+	// ClassInProgress is for the statuses of a condition that is still being worked on: ConditionInitialized,
+	// ConditionCreated, ConditionLocked, ConditionTerminating and ConditionDependencyUnavailable. It's also the
+	// class of the statuses that aren't registered.
+	ClassInProgress StatusClass = iota
+
+	// ClassCompleted is for the statuses of a condition whose work is done: ConditionCompleted. A completed
+	// condition can be worked on again, when its inputs change for instance.
+	ClassCompleted
+
+	// ClassSuspended is for the statuses of a condition whose work is paused: ConditionSuspended.
+	ClassSuspended
+
+	// ClassTerminal is for the statuses of a condition that shouldn't be worked on anymore, without having
+	// failed: ConditionTerminated.
+	ClassTerminal
+
+	// ClassError is for the statuses of a condition that failed and shouldn't be worked on anymore:
+	// ConditionError and ConditionExhausted.
+	ClassError
+)
+
+func (c StatusClass) String() string {
+	switch c {
+	case ClassCompleted:
+		return "Completed"
+	case ClassSuspended:
+		return "Suspended"
+	case ClassTerminal:
+		return "Terminal"
+	case ClassError:
+		return "Error"
+	default:
+		return "InProgress"
+	}
+}
+
+var statusClasses = struct {
+	sync.RWMutex
+	statuses map[ConditionStatus]StatusClass
+}{statuses: map[ConditionStatus]StatusClass{
+	ConditionCompleted:  ClassCompleted,
+	ConditionSuspended:  ClassSuspended,
+	ConditionTerminated: ClassTerminal,
+	ConditionError:      ClassError,
+	ConditionExhausted:  ClassError,
+}}
+
+// RegisterStatusClass declares the class of a status, replacing the class registered before, if any. The statuses
+// that aren't registered are ClassInProgress. It should be called before conditions with the status are set for
+// the first time, usually from an init function:
+//
+//	const ConditionIssued konditions.ConditionStatus = "Issued"
+//	const ConditionRevoked konditions.ConditionStatus = "Revoked"
+//
+//	func init() {
+//		konditions.RegisterStatusClass(ConditionIssued, konditions.ClassCompleted)
+//		konditions.RegisterStatusClass(ConditionRevoked, konditions.ClassError)
+//	}
+//
+// The class of a status changes how SetCondition treats it: the statuses of ClassTerminal and ClassError are
+// terminal, see IsTerminal.
+func RegisterStatusClass(status ConditionStatus, class StatusClass) {
+	statusClasses.Lock()
+	defer statusClasses.Unlock()
+
+	statusClasses.statuses[status] = class
+}
+
+// StatusClassOf returns the class registered for the status, ClassInProgress if none is.
+func StatusClassOf(status ConditionStatus) StatusClass {
+	statusClasses.RLock()
+	defer statusClasses.RUnlock()
+
+	return statusClasses.statuses[status]
+}
+
+// Class returns the class of the status of the condition, see StatusClassOf.
+func (c Condition) Class() StatusClass {
+	return StatusClassOf(c.Status)
+}
@@ -0,0 +1,86 @@
+package konditions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func registerStatusClass(t *testing.T, status ConditionStatus, class StatusClass) {
+	t.Helper()
+
+	RegisterStatusClass(status, class)
+	t.Cleanup(func() {
+		statusClasses.Lock()
+		defer statusClasses.Unlock()
+		delete(statusClasses.statuses, status)
+	})
+}
+
+func TestStatusClassOf(t *testing.T) {
+	cases := map[ConditionStatus]StatusClass{
+		ConditionInitialized:           ClassInProgress,
+		ConditionLocked:                ClassInProgress,
+		ConditionDependencyUnavailable: ClassInProgress,
+		ConditionCompleted:             ClassCompleted,
+		ConditionSuspended:             ClassSuspended,
+		ConditionTerminated:            ClassTerminal,
+		ConditionError:                 ClassError,
+		ConditionExhausted:             ClassError,
+		ConditionStatus("Unknown"):     ClassInProgress,
+	}
+
+	for status, expected := range cases {
+		if class := StatusClassOf(status); class != expected {
+			t.Errorf("Expected %s to be %s, got: %s", status, expected, class)
+		}
+	}
+}
+
+func TestRegisterStatusClass(t *testing.T) {
+	issued := ConditionStatus("Issued")
+	revoked := ConditionStatus("Revoked")
+	registerStatusClass(t, issued, ClassCompleted)
+	registerStatusClass(t, revoked, ClassError)
+
+	if class := (Condition{Status: issued}).Class(); class != ClassCompleted {
+		t.Error("Expected the custom status to be completed, got: ", class)
+	}
+
+	conditions := Conditions{}
+	_ = conditions.SetCondition(Condition{Type: ConditionType("Certificate"), Status: revoked, Reason: "Key compromised"})
+
+	if !conditions.MustType(ConditionType("Certificate")).IsTerminal() {
+		t.Error("Expected a status of ClassError to be terminal")
+	}
+
+	err := conditions.SetCondition(Condition{Type: ConditionType("Certificate"), Status: ConditionInitialized})
+	if !errors.Is(err, TerminalConditionErr) {
+		t.Error("Expected the transition out of the custom status to be refused, got: ", err)
+	}
+}
+
+func TestStatusClassUsedByHelpers(t *testing.T) {
+	issued := ConditionStatus("Issued")
+	registerStatusClass(t, issued, ClassCompleted)
+
+	transition := meta.NewTime(time.Now().Add(-time.Hour))
+	conditions := Conditions{
+		{Type: ConditionType("Certificate"), Status: issued, LastTransitionTime: transition},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted, LastTransitionTime: transition},
+	}
+
+	metaConditions := []meta.Condition{}
+	conditions.SummarizeInto(&metaConditions, 1)
+
+	if !apimeta.IsStatusConditionFalse(metaConditions, ReconcilingCondition) {
+		t.Error("Expected the custom status to not be reconciling")
+	}
+
+	if conditions[0].IsStuck(time.Now(), time.Minute) {
+		t.Error("Expected a completed custom status to not be stuck")
+	}
+}
@@ -0,0 +1,128 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var UnsupportedResourceErr = errors.New("Resource is not supported by the persister")
+var ClientRequiredErr = errors.New("Lock requires a controller-runtime client")
+
+// DynamicStatusPersister writes the resource through the dynamic client of client-go. It lets
+// controllers that aren't built with controller-runtime use a Lock: the lock writes through its Persister,
+// the client passed to NewLock can be nil, see NewLock for the options that still need one.
+//
+//	persister := konditions.DynamicStatusPersister{
+//		Client:   dynamicClient,
+//		Resource: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "buckets"},
+//	}
+//	lock := konditions.NewLock(res, nil, ConditionType("Bucket"), konditions.WithPersister(persister))
+//
+// The resource is converted to an unstructured object before being sent, and the resourceVersion the
+// API server returns is set back on the resource so the next write doesn't conflict.
+type DynamicStatusPersister struct {
+	Client   dynamic.Interface
+	Resource schema.GroupVersionResource
+
+	// When true, the whole object is updated instead of its status subresource. See ObjectPersister.
+	WithoutStatusSubresource bool
+}
+
+func (p DynamicStatusPersister) Persist(ctx context.Context, obj ConditionalResource) error {
+	o, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+
+	u, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return err
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+
+	resource := p.Client.Resource(p.Resource).Namespace(o.GetNamespace())
+
+	var updated *unstructured.Unstructured
+	if p.WithoutStatusSubresource {
+		updated, err = resource.Update(ctx, u, meta.UpdateOptions{})
+	} else {
+		updated, err = resource.UpdateStatus(ctx, u, meta.UpdateOptions{})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	o.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// StatusUpdater is implemented by the typed clients generated by client-go, `clientset.AppsV1().Deployments(namespace)`
+// for instance, as well as the clients generated for custom resources with client-gen.
+type StatusUpdater[T ConditionalResource] interface {
+	UpdateStatus(ctx context.Context, obj T, opts meta.UpdateOptions) (T, error)
+}
+
+// TypedStatusPersister returns a Persister that writes the resource through the status subresource of a typed
+// client-go client. Like DynamicStatusPersister, the client passed to NewLock can be nil.
+//
+//	persister := konditions.TypedStatusPersister[*v1.Bucket](clientset.ExampleV1().Buckets(namespace))
+//	lock := konditions.NewLock(res, nil, ConditionType("Bucket"), konditions.WithPersister(persister))
+//
+// The resourceVersion returned by the API server is set back on the resource.
+func TypedStatusPersister[T ConditionalResource](updater StatusUpdater[T]) Persister {
+	return PersisterFunc(func(ctx context.Context, obj ConditionalResource) error {
+		typed, ok := obj.(T)
+		if !ok {
+			return fmt.Errorf("%w: expected %T, got %T", UnsupportedResourceErr, *new(T), obj)
+		}
+
+		updated, err := updater.UpdateStatus(ctx, typed, meta.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+
+		typed.SetResourceVersion(updated.GetResourceVersion())
+		return nil
+	})
+}
+
+// Returns an error wrapping ClientRequiredErr when the lock doesn't have a client but writes with a persister of
+// controller-runtime, or is configured with an option that reads or patches the resource through the client.
+func (l *Lock) checkClient() error {
+	if l.client != nil {
+		return nil
+	}
+
+	switch p := l.persister.(type) {
+	case StatusPersister:
+		if p.Client == nil {
+			return fmt.Errorf("%w: StatusPersister writes through it", ClientRequiredErr)
+		}
+	case ObjectPersister:
+		if p.Client == nil {
+			return fmt.Errorf("%w: ObjectPersister writes through it", ClientRequiredErr)
+		}
+	}
+
+	switch {
+	case l.fencing:
+		return fmt.Errorf("%w: WithFencing patches the resource", ClientRequiredErr)
+	case l.watchdog != nil && l.watchdog.Threshold > 0:
+		return fmt.Errorf("%w: WithWatchdog patches the resource", ClientRequiredErr)
+	case l.unlockTimeout > 0 && l.reader == nil:
+		return fmt.Errorf("%w: WaitForUnlock reads the resource, unless the lock has an API reader", ClientRequiredErr)
+	}
+
+	return nil
+}
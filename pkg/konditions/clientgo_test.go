@@ -0,0 +1,120 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestDynamicStatusPersister(t *testing.T) {
+	ctx := context.Background()
+	gvr := testGroupVersion.WithResource("testresources")
+
+	res := newTestResource("dynamic")
+	res.SetGroupVersionKind(testGroupVersion.WithKind("TestResource"))
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gvr: "TestResourceList",
+	}, &unstructured.Unstructured{Object: content})
+
+	persister := DynamicStatusPersister{Client: c, Resource: gvr}
+	err = NewLock(res, nil, ConditionType("Bucket"), WithPersister(persister)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := c.Resource(gvr).Namespace(res.Namespace).Get(ctx, res.Name, meta.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(stored.Object, "status", "conditions")
+	if len(conditions) != 1 || conditions[0].(map[string]interface{})["status"] != string(ConditionCompleted) {
+		t.Error("Unexpected conditions stored: ", conditions)
+	}
+}
+
+type testStatusUpdater struct {
+	updates []ConditionStatus
+}
+
+func (u *testStatusUpdater) UpdateStatus(ctx context.Context, obj *testResource, opts meta.UpdateOptions) (*testResource, error) {
+	u.updates = append(u.updates, obj.Conditions().FindOrInitializeFor(ConditionType("Bucket")).Status)
+
+	updated := obj.DeepCopyObject().(*testResource)
+	updated.SetResourceVersion("42")
+	return updated, nil
+}
+
+func TestTypedStatusPersister(t *testing.T) {
+	res := newTestResource("typed")
+	updater := &testStatusUpdater{}
+
+	err := NewLock(res, nil, ConditionType("Bucket"), WithPersister(TypedStatusPersister[*testResource](updater))).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updater.updates) != 2 || updater.updates[0] != ConditionLocked || updater.updates[1] != ConditionCompleted {
+		t.Error("Unexpected updates: ", updater.updates)
+	}
+
+	if res.GetResourceVersion() != "42" {
+		t.Error("Expected the resource version to be updated, got: ", res.GetResourceVersion())
+	}
+
+	annotated, err := NewAnnotationConditions(res, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := TypedStatusPersister[*testResource](updater).Persist(context.Background(), annotated); !errors.Is(err, UnsupportedResourceErr) {
+		t.Error("Expected UnsupportedResourceErr, got: ", err)
+	}
+}
+
+func TestLockWithoutClient(t *testing.T) {
+	cases := map[string][]LockOption{
+		"default persister": nil,
+		"fencing":           {WithPersister(TypedStatusPersister[*testResource](&testStatusUpdater{})), WithFencing()},
+		"watchdog":          {WithPersister(TypedStatusPersister[*testResource](&testStatusUpdater{})), WithWatchdog(&Watchdog{Threshold: time.Second})},
+		"wait for unlock":   {WithPersister(TypedStatusPersister[*testResource](&testStatusUpdater{})), WaitForUnlock(time.Millisecond, time.Second)},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := newTestResource("client")
+			err := NewLock(res, nil, ConditionType("Bucket"), opts...).Execute(context.Background(), func(condition Condition) (Condition, error) {
+				t.Error("Expected the task not to run")
+				return condition, nil
+			})
+
+			if !errors.Is(err, ClientRequiredErr) {
+				t.Error("Expected ClientRequiredErr, got: ", err)
+			}
+
+			if res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionLocked) {
+				t.Error("Expected the condition not to be locked, got: ", res.Conditions())
+			}
+		})
+	}
+}
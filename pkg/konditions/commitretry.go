@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CommitRetry retries the write that releases the lock when it fails transiently. Once the task ran, its work
+// is only reflected on the resource by that write. Returning the error requeues the resource and runs the task
+// again, which may duplicate the side effects the task had on external systems.
+//
+//	retry := konditions.CommitRetry{Retries: 3, Backoff: konditions.Backoff{Base: 100 * time.Millisecond, Max: 2 * time.Second}}
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithCommitRetry(retry))
+//
+// Only the commit is retried, acquiring the lock isn't. The delay between attempts is computed by the Backoff,
+// unless the Kubernetes API asks for a longer one, like it does when it throttles the client.
+//
+// Retriable decides which errors are retried, IsTransientError is used when it isn't set. A write that timed out
+// may still have been applied by the Kubernetes API, in which case the next attempt conflicts with it. Conflicts
+// aren't transient: the error is returned so the resource can be read again.
+type CommitRetry struct {
+	Retries   int
+	Backoff   Backoff
+	Retriable func(error) bool
+}
+
+// WithCommitRetry configures the lock to retry the write that releases the lock, see CommitRetry.
+func WithCommitRetry(retry CommitRetry) LockOption {
+	return func(l *Lock) {
+		l.commitRetry = &retry
+	}
+}
+
+// IsTransientError returns true if the error is one the Kubernetes API is expected to recover from on its own:
+// timeouts, throttling and unavailability.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (r CommitRetry) retriable(err error) bool {
+	if r.Retriable != nil {
+		return r.Retriable(err)
+	}
+
+	return IsTransientError(err)
+}
+
+// Returns the delay to wait before the attempt, the one asked by the Kubernetes API if it's longer than the backoff.
+func (r CommitRetry) delay(attempt int, err error) time.Duration {
+	d := r.Backoff.Duration(attempt)
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		if suggested := time.Duration(seconds) * time.Second; suggested > d {
+			d = suggested
+		}
+	}
+
+	return d
+}
+
+// Persists the release of the lock, retrying it as configured by WithCommitRetry. The error of the last attempt
+// is returned, even if the context is done before the retries are spent. The release isn't persisted when it
+// doesn't change the conditions, see Tracker.
+func (l *Lock) persistRelease(ctx context.Context, persister Persister) error {
+	// The conditions are the ones the lock last read or wrote, a condition committed twice for instance, the release
+	// doesn't need to be written.
+	if !l.tracker.IsDirty() {
+		return nil
+	}
+
+	err := l.persistWith(ctx, persister)
+	if l.commitRetry == nil {
+		return err
+	}
+
+	for attempt := 1; err != nil && attempt <= l.commitRetry.Retries && l.commitRetry.retriable(err); attempt++ {
+		timer := time.NewTimer(l.commitRetry.delay(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		err = l.persistWith(ctx, persister)
+	}
+
+	if err == nil {
+		l.persistFailed = false
+	}
+
+	return err
+}
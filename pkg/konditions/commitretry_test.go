@@ -0,0 +1,163 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// Returns a client that fails the writes made after the lock is acquired with the error, `failures` times.
+func newCommitRetryTestClient(res *testResource, failures int, failure error, writes *int) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(res).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				*writes++
+				if *writes > 1 && *writes <= failures+1 {
+					return failure
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+}
+
+func completeTask(condition Condition) (Condition, error) {
+	condition.Status = ConditionCompleted
+	return condition, nil
+}
+
+func TestLockWithCommitRetry(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("retry")
+
+	var writes int
+	c := newCommitRetryTestClient(res, 2, apierrors.NewTooManyRequests("throttled", 0), &writes)
+
+	var runs int
+	lock := NewLock(res, c, ConditionType("Bucket"), RestoreOnFailure(), WithCommitRetry(CommitRetry{Retries: 3, Backoff: Backoff{Base: time.Millisecond}}))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		runs++
+		return completeTask(condition)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runs != 1 || writes != 4 {
+		t.Errorf("Expected the task to run once and the commit to be retried twice, got %d runs and %d writes", runs, writes)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) || !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be committed, got: ", stored.Conditions())
+	}
+}
+
+func TestLockWithCommitRetryExhausted(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("exhausted")
+	timeout := apierrors.NewServerTimeout(schema.GroupResource{Resource: "testresources"}, "update", 0)
+
+	var writes int
+	c := newCommitRetryTestClient(res, 10, timeout, &writes)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithCommitRetry(CommitRetry{Retries: 2, Backoff: Backoff{Base: time.Millisecond}}))
+	if err := lock.Execute(ctx, completeTask); !apierrors.IsServerTimeout(err) {
+		t.Error("Expected the error of the last attempt, got: ", err)
+	}
+
+	if writes != 4 {
+		t.Error("Expected the commit to be attempted 3 times, got: ", writes-1)
+	}
+}
+
+func TestLockWithCommitRetryNotRetriable(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("invalid")
+	invalid := apierrors.NewBadRequest("invalid")
+
+	var writes int
+	c := newCommitRetryTestClient(res, 1, invalid, &writes)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithCommitRetry(CommitRetry{Retries: 3, Backoff: Backoff{Base: time.Millisecond}}))
+	if err := lock.Execute(ctx, completeTask); !apierrors.IsBadRequest(err) || writes != 2 {
+		t.Errorf("Expected the error to be returned without retrying, got %v after %d writes", err, writes)
+	}
+
+	res = newTestResource("custom")
+	writes = 0
+	c = newCommitRetryTestClient(res, 1, invalid, &writes)
+
+	retry := CommitRetry{Retries: 3, Backoff: Backoff{Base: time.Millisecond}, Retriable: apierrors.IsBadRequest}
+	lock = NewLock(res, c, ConditionType("Bucket"), WithCommitRetry(retry))
+	if err := lock.Execute(ctx, completeTask); err != nil || writes != 3 {
+		t.Errorf("Expected Retriable to decide what is retried, got %v after %d writes", err, writes)
+	}
+}
+
+func TestLockWithCommitRetryContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	res := newTestResource("cancelled")
+	failure := apierrors.NewServiceUnavailable("unavailable")
+
+	var writes int
+	c := newCommitRetryTestClient(res, 10, failure, &writes)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithCommitRetry(CommitRetry{Retries: 3, Backoff: Backoff{Base: time.Hour}}))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		cancel()
+		return completeTask(condition)
+	})
+
+	if !errors.Is(err, failure) || writes != 2 {
+		t.Errorf("Expected the retries to stop with the context, got %v after %d writes", err, writes)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	transient := []error{
+		apierrors.NewTooManyRequests("throttled", 1),
+		apierrors.NewServerTimeout(schema.GroupResource{Resource: "testresources"}, "update", 1),
+		apierrors.NewTimeoutError("timeout", 1),
+		apierrors.NewServiceUnavailable("unavailable"),
+		context.DeadlineExceeded,
+	}
+
+	for _, err := range transient {
+		if !IsTransientError(err) {
+			t.Error("Expected the error to be transient: ", err)
+		}
+	}
+
+	for _, err := range []error{nil, apierrors.NewBadRequest("invalid"), apierrors.NewConflict(schema.GroupResource{}, "retry", errors.New("modified"))} {
+		if IsTransientError(err) {
+			t.Error("Expected the error not to be transient: ", err)
+		}
+	}
+}
+
+func TestCommitRetryDelay(t *testing.T) {
+	retry := CommitRetry{Backoff: Backoff{Base: time.Millisecond}}
+
+	if d := retry.delay(2, errors.New("failed")); d != 2*time.Millisecond {
+		t.Error("Expected the delay of the backoff, got: ", d)
+	}
+
+	if d := retry.delay(1, apierrors.NewTooManyRequests("throttled", 2)); d != 2*time.Second {
+		t.Error("Expected the delay asked by the Kubernetes API, got: ", d)
+	}
+}
@@ -31,7 +31,12 @@ import (
 //		myResource.Status.Conditions.SetCondition(condition)
 //
 //	}
-
+//
+// The markers below make controller-gen declare the conditions as a list map keyed by type, so server-side apply
+// merges them entry by entry. CRDs that aren't generated by controller-gen can use the openapi package instead.
+//
+// +listType=map
+// +listMapKey=type
 type Conditions []Condition
 
 // ConditionType is a user-extendable type that is stored in each Condition
@@ -88,6 +93,16 @@ const (
 	// as acquired a lock on this condition. It is important to note, however, that it's not a "real" lock. We're in a distributed system and
 	// the etcd/kubernetes client interaction include layers of caching and logic.
 	ConditionLocked ConditionStatus = "Locked"
+
+	// ConditionSuspended means the work on the condition is paused, usually by a human through the
+	// PausedAnnotation. A suspended condition remembers the status it had before being suspended, in
+	// ResumeStatus, so it can resume where it left off once the pause is lifted.
+	ConditionSuspended ConditionStatus = "Suspended"
+
+	// ConditionExhausted means the condition failed more often than its RetryBudget allows. Like ConditionError,
+	// it is terminal, but it is only meant to be reset by a human: automated resets (remediation, MarkStale, etc.)
+	// leave exhausted conditions alone.
+	ConditionExhausted ConditionStatus = "Exhausted"
 )
 
 // Condition is an individual condition that makes the Conditions type. Each of those conditions are created
@@ -131,6 +146,61 @@ type Condition struct {
 	// +kubebuilder:validation:MaxLength=1024
 	// +kubebuilder:validation:MinLength=1
 	Reason string `json:"reason,omitempty" protobuf:"bytes,5,opt,name=reason"`
+
+	// InputHash is a hash of the inputs the condition was reconciled with. It is set by an Invalidator
+	// and used to detect when the inputs of a condition changed, in which case the condition needs
+	// to be reconciled again.
+	// ---
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	InputHash string `json:"inputHash,omitempty" protobuf:"bytes,6,opt,name=inputHash"`
+
+	// ResumeStatus is the status the condition had before it was suspended. It is only set
+	// when the condition is ConditionSuspended.
+	// ---
+	// +optional
+	// +kubebuilder:validation:MaxLength=128
+	ResumeStatus ConditionStatus `json:"resumeStatus,omitempty" protobuf:"bytes,7,opt,name=resumeStatus"`
+
+	// Ref identifies the external resource the condition is about, a bucket name or an ARN for
+	// instance. It is set by Lock.ExecuteWithIntent before the external resource is created so it can
+	// be found even if the operator crashes before the condition is released.
+	// ---
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Ref string `json:"ref,omitempty" protobuf:"bytes,8,opt,name=ref"`
+
+	// Attributes are small bits of data checkpointed alongside the condition: a retry count, the
+	// etag of an external resource, a resume token, etc. The attributes should be set through
+	// SetAttr, SetInt and SetTime, which validate the keys and values.
+	// ---
+	// +optional
+	// +kubebuilder:validation:MaxProperties=16
+	Attributes map[string]string `json:"attributes,omitempty" protobuf:"bytes,9,rep,name=attributes"`
+
+	// ObservedGeneration is the generation of the resource the condition was last reconciled
+	// against. It is set by the Lock when the task returns and compared to the generation of the
+	// resource by StaleFor to find the conditions that need to be reconciled again.
+	// ---
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,10,opt,name=observedGeneration"`
+
+	// Manager is the name of the controller, or person, that last wrote the condition. It is set by
+	// the Lock when configured with WithManager, and by ApplyOverrides with the author of the override.
+	// ---
+	// +optional
+	// +kubebuilder:validation:MaxLength=128
+	Manager string `json:"manager,omitempty" protobuf:"bytes,11,opt,name=manager"`
+
+	// LastHeartbeatTime is the last time the task working on the condition reported that it was alive, with
+	// Lock.Progress or through the heartbeats of a Watchdog. Unlike LastTransitionTime, it changes while the
+	// status stays the same: it tells a condition that is in progress apart from a condition that is stuck.
+	// ---
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastHeartbeatTime *meta.Time `json:"lastHeartbeatTime,omitempty" protobuf:"bytes,12,opt,name=lastHeartbeatTime"`
 }
 
 // Helper function that returns true if the Status of the condition is equal
@@ -145,6 +215,15 @@ func (c Condition) StatusIsOneOf(statuses ...ConditionStatus) bool {
 	return false
 }
 
+// Returns true if the condition is in a terminal status: ConditionError, ConditionExhausted,
+// ConditionTerminated, or any status registered as ClassTerminal or ClassError, see RegisterStatusClass.
+// A condition in a terminal status shouldn't be worked on anymore and SetCondition won't let it
+// transition to another status.
+func (c Condition) IsTerminal() bool {
+	class := c.Class()
+	return class == ClassTerminal || class == ClassError
+}
+
 // Kubernetes requires any struct that can be stored in a Custom Resource Definition(CRD) to
 // implement these DeepCopy functions. They aren't interfaces as the arguments and return values
 // are explicitly typed. Usually, when using tools like kube-builder/controller-runtime, those functions
@@ -170,6 +249,15 @@ func (in *Conditions) DeepCopy() Conditions {
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LastHeartbeatTime != nil {
+		out.LastHeartbeatTime = in.LastHeartbeatTime.DeepCopy()
+	}
+	if in.Attributes != nil {
+		out.Attributes = make(map[string]string, len(in.Attributes))
+		for key, value := range in.Attributes {
+			out.Attributes[key] = value
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
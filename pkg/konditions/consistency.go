@@ -0,0 +1,49 @@
+package konditions
+
+import "sigs.k8s.io/controller-runtime/pkg/client"
+
+// Consistency is how much a Lock relies on its API reader, from none at all to checking every step, see WithAPIReader.
+type Consistency int
+
+const (
+	// ConsistencyCached only uses the API reader to fetch the resource while waiting for a locked condition to be
+	// released, see WaitForUnlock. The lock trusts the resource it was given, usually read from the cache of the manager.
+	ConsistencyCached Consistency = iota
+
+	// ConsistencyFresh also reads the resource from the API reader before locking the condition, and refreshes
+	// it if it is stale. It's the same as WithFreshnessCheck(reader, RefreshStale).
+	ConsistencyFresh
+
+	// ConsistencyStrict also reads the resource from the API reader once the condition is released to verify it
+	// was stored as it was written, see WithVerify.
+	ConsistencyStrict
+)
+
+// WithAPIReader configures the lock to read the resource with the reader given instead of its client. The reader is
+// meant to bypass the cache of the manager, mgr.GetAPIReader() for instance, and the consistency decides when the lock
+// reads the resource: each level is a read to the Kubernetes API more than the one before, on every execution.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithAPIReader(mgr.GetAPIReader(), konditions.ConsistencyStrict))
+//
+// Locks that create costly, or unique, external resources can be strict, while the others keep reading from the cache.
+func WithAPIReader(reader client.Reader, consistency Consistency) LockOption {
+	return func(l *Lock) {
+		l.reader = reader
+		if consistency >= ConsistencyStrict {
+			l.verifier = reader
+		}
+
+		if consistency >= ConsistencyFresh {
+			l.freshness = &freshnessCheck{reader: reader, policy: RefreshStale}
+		}
+	}
+}
+
+// Returns the reader the lock fetches the resource with: its API reader if it has one, its client otherwise.
+func (l *Lock) apiReader() client.Reader {
+	if l.reader != nil {
+		return l.reader
+	}
+
+	return l.client
+}
@@ -0,0 +1,81 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingReader counts the reads made through it and lets the tests change the resources read.
+type countingReader struct {
+	client.Reader
+
+	reads  int
+	mutate func(obj client.Object)
+}
+
+func (r *countingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.reads++
+	if err := r.Reader.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+
+	if r.mutate != nil {
+		r.mutate(obj)
+	}
+
+	return nil
+}
+
+func TestLockWithAPIReader(t *testing.T) {
+	ctx := context.Background()
+	cases := map[Consistency]int{
+		ConsistencyCached: 0,
+		ConsistencyFresh:  1,
+		ConsistencyStrict: 2,
+	}
+
+	for consistency, reads := range cases {
+		res := newTestResource("consistency")
+		c := newTestClient(res)
+		reader := &countingReader{Reader: c}
+
+		err := NewLock(res, c, ConditionType("Bucket"), WithAPIReader(reader, consistency)).Execute(ctx, func(condition Condition) (Condition, error) {
+			condition.Status = ConditionCompleted
+			return condition, nil
+		})
+
+		if err != nil {
+			t.Error("Unexpected error: ", err)
+		}
+
+		if reader.reads != reads {
+			t.Errorf("Expected %d reads for consistency %d, got: %d", reads, consistency, reader.reads)
+		}
+	}
+}
+
+func TestLockWithAPIReaderStrictMismatch(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("consistency")
+	c := newTestClient(res)
+
+	// A mutating webhook, for instance, changed the condition once it was released.
+	reader := &countingReader{Reader: c, mutate: func(obj client.Object) {
+		conditions := obj.(*testResource).Conditions()
+		if conditions.TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+			conditions.SetConditionForce(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+		}
+	}}
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithAPIReader(reader, ConsistencyStrict)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if !errors.Is(err, CommitNotVerifiedErr) {
+		t.Error("Expected CommitNotVerifiedErr, got: ", err)
+	}
+}
@@ -0,0 +1,76 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var NoResourceInContextErr = errors.New("Context does not carry a resource, see konditions.Into")
+
+type batchContextKey struct{}
+
+// Into returns a copy of the context that carries the resource, with the client to write it. The helpers deep in
+// a reconciliation, or a Task, can then record conditions with the context alone, see SetConditionCtx:
+//
+//	ctx = konditions.Into(ctx, reconciler.Client, &res)
+//	if err := createBucket(ctx); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	if err := konditions.CommitCtx(ctx); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+// The changes are staged in a StatusBatch and written together by CommitCtx.
+func Into(ctx context.Context, c client.Client, obj ConditionalResource) context.Context {
+	return context.WithValue(ctx, batchContextKey{}, NewStatusBatch(c, obj))
+}
+
+// BatchFrom returns the StatusBatch of the resource carried by the context, see Into.
+func BatchFrom(ctx context.Context) (*StatusBatch, bool) {
+	batch, ok := ctx.Value(batchContextKey{}).(*StatusBatch)
+	return batch, ok
+}
+
+// SetConditionCtx stages the condition onto the resource carried by the context, see Into and
+// StatusBatch.SetCondition. NoResourceInContextErr is returned if the context doesn't carry a resource.
+//
+//	func createBucket(ctx context.Context) error {
+//		// ...
+//		return konditions.SetConditionCtx(ctx, konditions.Condition{
+//			Type:   ConditionType("Bucket"),
+//			Status: konditions.ConditionCreated,
+//			Reason: "Bucket created",
+//		})
+//	}
+func SetConditionCtx(ctx context.Context, condition Condition) error {
+	batch, ok := BatchFrom(ctx)
+	if !ok {
+		return NoResourceInContextErr
+	}
+
+	return batch.SetCondition(condition)
+}
+
+// RemoveConditionCtx stages the removal of the condition from the resource carried by the context, see Into and
+// StatusBatch.RemoveConditionWith.
+func RemoveConditionCtx(ctx context.Context, ct ConditionType) (bool, error) {
+	batch, ok := BatchFrom(ctx)
+	if !ok {
+		return false, NoResourceInContextErr
+	}
+
+	return batch.RemoveConditionWith(ct), nil
+}
+
+// CommitCtx writes the changes staged onto the resource carried by the context, see StatusBatch.Commit.
+func CommitCtx(ctx context.Context) error {
+	batch, ok := BatchFrom(ctx)
+	if !ok {
+		return NoResourceInContextErr
+	}
+
+	return batch.Commit(ctx)
+}
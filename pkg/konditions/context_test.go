@@ -0,0 +1,65 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestContextConditions(t *testing.T) {
+	res := newTestResource("context")
+	c := newTestClient(res)
+
+	ctx := Into(context.Background(), c, res)
+
+	createBucket := func(ctx context.Context) error {
+		return SetConditionCtx(ctx, Condition{Type: ConditionType("Bucket"), Status: ConditionCreated, Reason: "Bucket created"})
+	}
+
+	if err := createBucket(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetConditionCtx(ctx, Condition{Type: ConditionType("DNS"), Status: ConditionCompleted}); err != nil {
+		t.Fatal(err)
+	}
+
+	if removed, err := RemoveConditionCtx(ctx, ConditionType("DNS")); err != nil || !removed {
+		t.Error("Expected the condition to be removed, got: ", removed, err)
+	}
+
+	if batch, ok := BatchFrom(ctx); !ok || batch.Staged() != 3 {
+		t.Error("Expected the changes to be staged, got: ", batch)
+	}
+
+	if err := CommitCtx(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*stored.Conditions()) != 1 || !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCreated) {
+		t.Error("Unexpected conditions: ", stored.Conditions())
+	}
+}
+
+func TestContextConditionsWithoutResource(t *testing.T) {
+	ctx := context.Background()
+
+	if err := SetConditionCtx(ctx, Condition{Type: ConditionType("Bucket"), Status: ConditionCreated}); !errors.Is(err, NoResourceInContextErr) {
+		t.Error("Expected NoResourceInContextErr, got: ", err)
+	}
+
+	if _, err := RemoveConditionCtx(ctx, ConditionType("Bucket")); !errors.Is(err, NoResourceInContextErr) {
+		t.Error("Expected NoResourceInContextErr, got: ", err)
+	}
+
+	if err := CommitCtx(ctx); !errors.Is(err, NoResourceInContextErr) {
+		t.Error("Expected NoResourceInContextErr, got: ", err)
+	}
+}
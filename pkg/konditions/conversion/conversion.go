@@ -0,0 +1,202 @@
+// Package conversion maps the conditions of a resource between the versions of its CRD. Condition types evolve with
+// the API of an operator: they're renamed, split in finer grained conditions, merged, or dropped. A Mapping declares
+// those changes as a table, and converts the conditions inside the conversion functions of a webhook:
+//
+//	var v1alpha1ToV1 = conversion.Mapping{
+//		{From: []konditions.ConditionType{"Provisioned"}, To: []konditions.ConditionType{"Bucket"}},
+//		{From: []konditions.ConditionType{"Network"}, To: []konditions.ConditionType{"DNS", "Certificate"}},
+//		{From: []konditions.ConditionType{"Quota", "Limits"}, To: []konditions.ConditionType{"Capacity"}},
+//		{From: []konditions.ConditionType{"Legacy"}},
+//	}
+//
+//	func (src *Bucket) ConvertTo(dstRaw ctrlconversion.Hub) error {
+//		dst := dstRaw.(*v1.Bucket)
+//		conditions, err := v1alpha1ToV1.Convert(src.Status.Conditions)
+//		if err != nil {
+//			return err
+//		}
+//		dst.Status.Conditions = conditions
+//		// ...
+//	}
+//
+//	func (dst *Bucket) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+//		src := srcRaw.(*v1.Bucket)
+//		conditions, err := v1alpha1ToV1.Reverse().Convert(src.Status.Conditions)
+//		// ...
+//	}
+package conversion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+var InvalidRuleErr = errors.New("Invalid conversion rule")
+
+// Rule converts the conditions of the From types into conditions of the To types. The kind of conversion depends on
+// the number of types on each side:
+//
+//	From  To
+//	1     1   Rename: the condition keeps everything but its type.
+//	1     n   Split: each of the To types gets a copy of the condition.
+//	n     1   Merge: the To type gets the least advanced of the conditions, see Merge.
+//	n     0   Drop: the conditions are removed.
+//
+// Converting n types into m types isn't supported, it can be expressed with a merge followed by a split.
+type Rule struct {
+	From []konditions.ConditionType
+	To   []konditions.ConditionType
+
+	// Merge returns the condition of a merge from the conditions of the From types that exist, it's only used when
+	// the rule merges conditions. The default, DefaultMerge, is used if it's nil.
+	Merge func(conditions konditions.Conditions) konditions.Condition
+}
+
+// Mapping is a table of rules. The conditions that aren't converted by any of the rules are kept as they are.
+type Mapping []Rule
+
+// Validate returns an error wrapping InvalidRuleErr if a rule doesn't convert any type, converts n types into m
+// types, or if a type is converted by more than one rule, or produced by more than one rule.
+func (m Mapping) Validate() error {
+	from := map[konditions.ConditionType]int{}
+	to := map[konditions.ConditionType]int{}
+
+	for i, rule := range m {
+		if len(rule.From) == 0 {
+			return fmt.Errorf("%w: rule %d doesn't convert any type", InvalidRuleErr, i)
+		}
+
+		if len(rule.From) > 1 && len(rule.To) > 1 {
+			return fmt.Errorf("%w: rule %d converts %d types into %d types", InvalidRuleErr, i, len(rule.From), len(rule.To))
+		}
+
+		for _, ct := range rule.From {
+			if previous, ok := from[ct]; ok {
+				return fmt.Errorf("%w: %s is converted by rules %d and %d", InvalidRuleErr, ct, previous, i)
+			}
+			from[ct] = i
+		}
+
+		for _, ct := range rule.To {
+			if previous, ok := to[ct]; ok {
+				return fmt.Errorf("%w: %s is produced by rules %d and %d", InvalidRuleErr, ct, previous, i)
+			}
+			to[ct] = i
+		}
+	}
+
+	return nil
+}
+
+// Convert returns the conditions converted by the rules of the mapping. The conditions given aren't modified.
+//
+// A condition produced by a rule replaces the condition of the same type that isn't converted by any rule, if any.
+// The conditions returned are normalized, see Conditions.Normalize. When anomalies were fixed, the conditions are
+// returned along with a *konditions.NormalizedError.
+func (m Mapping) Convert(conditions konditions.Conditions) (konditions.Conditions, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	converted := map[konditions.ConditionType]bool{}
+	produced := konditions.Conditions{}
+
+	for _, rule := range m {
+		sources := konditions.Conditions{}
+		for _, ct := range rule.From {
+			converted[ct] = true
+			if condition, ok := conditions.GetType(ct); ok {
+				sources = append(sources, condition)
+			}
+		}
+
+		if len(sources) == 0 || len(rule.To) == 0 {
+			continue
+		}
+
+		source := sources[0]
+		if len(rule.From) > 1 {
+			merge := rule.Merge
+			if merge == nil {
+				merge = DefaultMerge
+			}
+			source = merge(sources)
+		}
+
+		for _, ct := range rule.To {
+			condition := *source.DeepCopy()
+			condition.Type = ct
+			produced = append(produced, condition)
+		}
+	}
+
+	result := konditions.Conditions{}
+	for _, condition := range conditions {
+		if converted[condition.Type] {
+			continue
+		}
+
+		if _, ok := produced.GetType(condition.Type); ok {
+			continue
+		}
+
+		result = append(result, *condition.DeepCopy())
+	}
+	result = append(result, produced...)
+
+	if err := result.Normalize(); err != nil {
+		return result, &konditions.NormalizedError{Anomalies: err}
+	}
+
+	return result, nil
+}
+
+// Reverse returns the mapping that converts the conditions back: renames are reversed, splits become merges and
+// merges become splits. Dropped conditions can't be restored, the rules that drop conditions are left out.
+//
+// Converting back and forth isn't lossless for splits and merges: a condition merged from two conditions is split
+// back into two copies of the merged condition. Versions that need to round-trip the conditions exactly should keep
+// the same types.
+func (m Mapping) Reverse() Mapping {
+	reversed := make(Mapping, 0, len(m))
+	for _, rule := range m {
+		if len(rule.To) == 0 {
+			continue
+		}
+
+		reversed = append(reversed, Rule{From: rule.To, To: rule.From})
+	}
+
+	return reversed
+}
+
+// DefaultMerge returns the least advanced of the conditions: a merged condition is only completed when all of its
+// sources are. The conditions in a terminal status come first, then the conditions in progress, and the completed
+// conditions last. Conditions in the same group are ordered by their LastTransitionTime, the latest first.
+func DefaultMerge(conditions konditions.Conditions) konditions.Condition {
+	sorted := conditions.DeepCopy()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if a, b := progress(sorted[i]), progress(sorted[j]); a != b {
+			return a < b
+		}
+
+		return sorted[j].LastTransitionTime.Before(&sorted[i].LastTransitionTime)
+	})
+
+	return sorted[0]
+}
+
+// Returns how far along the condition is: 0 when it's terminal, 1 when it's in progress, 2 when it's completed.
+func progress(condition konditions.Condition) int {
+	switch {
+	case condition.IsTerminal():
+		return 0
+	case condition.Class() == konditions.ClassCompleted:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,192 @@
+package conversion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func types(values ...string) []konditions.ConditionType {
+	ct := make([]konditions.ConditionType, len(values))
+	for i, value := range values {
+		ct[i] = konditions.ConditionType(value)
+	}
+	return ct
+}
+
+func condition(ct string, status konditions.ConditionStatus, reason string, ago time.Duration) konditions.Condition {
+	return konditions.Condition{
+		Type:               konditions.ConditionType(ct),
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: meta.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Add(-ago)),
+	}
+}
+
+var mapping = Mapping{
+	{From: types("Provisioned"), To: types("Bucket")},
+	{From: types("Network"), To: types("DNS", "Certificate")},
+	{From: types("Quota", "Limits"), To: types("Capacity")},
+	{From: types("Legacy")},
+}
+
+func TestMappingConvert(t *testing.T) {
+	conditions := konditions.Conditions{
+		condition("Provisioned", konditions.ConditionCompleted, "Bucket created", time.Hour),
+		condition("Network", konditions.ConditionLocked, "Resource locked", time.Minute),
+		condition("Quota", konditions.ConditionCompleted, "Quota set", time.Hour),
+		condition("Limits", konditions.ConditionError, "Limit exceeded", 2*time.Hour),
+		condition("Legacy", konditions.ConditionCompleted, "Done", time.Hour),
+		condition("Versioning", konditions.ConditionInitialized, "", time.Hour),
+	}
+
+	converted, err := mapping.Convert(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(converted) != 5 {
+		t.Fatal("Unexpected conditions: ", converted)
+	}
+
+	for _, ct := range types("Provisioned", "Network", "Quota", "Limits", "Legacy") {
+		if _, ok := converted.GetType(ct); ok {
+			t.Error("Expected the condition to be converted, got: ", ct)
+		}
+	}
+
+	bucket := converted.MustType("Bucket")
+	if bucket.Status != konditions.ConditionCompleted || bucket.Reason != "Bucket created" || !bucket.LastTransitionTime.Equal(&conditions[0].LastTransitionTime) {
+		t.Error("Expected the renamed condition to be kept as it was, got: ", bucket)
+	}
+
+	for _, ct := range types("DNS", "Certificate") {
+		if split := converted.MustType(ct); split.Status != konditions.ConditionLocked {
+			t.Errorf("Expected %s to be a copy of the split condition, got: %s", ct, split.Status)
+		}
+	}
+
+	if capacity := converted.MustType("Capacity"); capacity.Status != konditions.ConditionError || capacity.Reason != "Limit exceeded" {
+		t.Error("Expected the merged condition to be the one in error, got: ", capacity)
+	}
+
+	if _, ok := converted.GetType("Versioning"); !ok {
+		t.Error("Expected the conditions that aren't converted to be kept")
+	}
+
+	if conditions[0].Type != "Provisioned" {
+		t.Error("Expected the conditions given not to be modified")
+	}
+}
+
+func TestMappingConvertReplacesExisting(t *testing.T) {
+	converted, err := mapping.Convert(konditions.Conditions{
+		condition("Provisioned", konditions.ConditionCompleted, "Bucket created", time.Hour),
+		condition("Bucket", konditions.ConditionInitialized, "Stale", time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(converted) != 1 || converted[0].Reason != "Bucket created" {
+		t.Error("Expected the converted condition to replace the existing one, got: ", converted)
+	}
+}
+
+func TestMappingReverse(t *testing.T) {
+	reversed := mapping.Reverse()
+	if len(reversed) != 3 {
+		t.Fatal("Expected the drop rule to be left out, got: ", reversed)
+	}
+
+	converted, err := reversed.Convert(konditions.Conditions{
+		condition("Bucket", konditions.ConditionCompleted, "Bucket created", time.Hour),
+		condition("DNS", konditions.ConditionCompleted, "Record created", time.Hour),
+		condition("Certificate", konditions.ConditionLocked, "Resource locked", time.Minute),
+		condition("Capacity", konditions.ConditionCompleted, "Capacity set", time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if network := converted.MustType("Network"); network.Status != konditions.ConditionLocked {
+		t.Error("Expected the split to be merged back into the least advanced condition, got: ", network.Status)
+	}
+
+	for _, ct := range types("Provisioned", "Quota", "Limits") {
+		if _, ok := converted.GetType(ct); !ok {
+			t.Error("Expected the condition to be converted back: ", ct)
+		}
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	cases := map[string]Mapping{
+		"no from":         {{To: types("Bucket")}},
+		"n to m":          {{From: types("A", "B"), To: types("C", "D")}},
+		"converted twice": {{From: types("A"), To: types("B")}, {From: types("A"), To: types("C")}},
+		"produced twice":  {{From: types("A"), To: types("C")}, {From: types("B"), To: types("C")}},
+	}
+
+	for name, m := range cases {
+		if _, err := m.Convert(nil); !errors.Is(err, InvalidRuleErr) {
+			t.Errorf("%s: expected the mapping to be invalid, got: %v", name, err)
+		}
+	}
+
+	if err := mapping.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMergeFunc(t *testing.T) {
+	m := Mapping{{
+		From: types("Quota", "Limits"),
+		To:   types("Capacity"),
+		Merge: func(conditions konditions.Conditions) konditions.Condition {
+			return konditions.Condition{Status: konditions.ConditionCompleted, Reason: "Custom"}
+		},
+	}}
+
+	converted, err := m.Convert(konditions.Conditions{condition("Quota", konditions.ConditionError, "", time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if capacity := converted.MustType("Capacity"); capacity.Reason != "Custom" {
+		t.Error("Expected the merge function of the rule to be used, got: ", capacity)
+	}
+}
+
+func TestConvertNormalizes(t *testing.T) {
+	m := Mapping{{From: types("Provisioned"), To: types("Bucket")}}
+
+	converted, err := m.Convert(konditions.Conditions{
+		condition("DNS", konditions.ConditionCompleted, "", time.Hour),
+		condition("DNS", konditions.ConditionError, "latest", time.Minute),
+	})
+
+	var normalized *konditions.NormalizedError
+	if !errors.As(err, &normalized) || !errors.Is(err, konditions.DuplicateConditionTypeErr) {
+		t.Fatal("Expected the duplicates to be reported, got: ", err)
+	}
+
+	if len(converted) != 1 || converted.MustType("DNS").Reason != "latest" {
+		t.Error("Expected the normalized conditions along with the anomalies, got: ", converted)
+	}
+}
+
+func TestDefaultMerge(t *testing.T) {
+	merged := DefaultMerge(konditions.Conditions{
+		condition("A", konditions.ConditionCompleted, "", 0),
+		condition("B", konditions.ConditionInitialized, "older", time.Hour),
+		condition("C", konditions.ConditionLocked, "latest", time.Minute),
+	})
+
+	if merged.Reason != "latest" {
+		t.Error("Expected the latest condition in progress, got: ", merged)
+	}
+}
@@ -0,0 +1,122 @@
+package konditions
+
+import (
+	"fmt"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DeadlineStartAttribute is the time the deadline of a condition started, see Deadline.
+	DeadlineStartAttribute = "konditionner.io/deadline-start"
+
+	// DeadlineExceededReason prefixes the reason of the conditions that missed their deadline.
+	DeadlineExceededReason = "DeadlineExceeded"
+)
+
+// Deadline declares how long a condition type has to reach one of the Target statuses, ConditionCompleted by
+// default, once it entered the From status, ConditionInitialized by default.
+//
+//	// Certificate must reach Completed within 10m of Initialized.
+//	deadline := konditions.Deadline{Type: ConditionType("Certificate"), Within: 10 * time.Minute}
+//
+// Deadlines are enforced by EnforceDeadlines, or by the remediation controller. The start of the deadline is
+// recorded in the DeadlineStartAttribute of the condition the first time it's seen in the From status, so the
+// deadline holds while the condition goes through other statuses. Reset starts a new deadline.
+type Deadline struct {
+	Type   ConditionType
+	From   ConditionStatus
+	Target []ConditionStatus
+	Within time.Duration
+}
+
+func (d Deadline) from() ConditionStatus {
+	if d.From == "" {
+		return ConditionInitialized
+	}
+
+	return d.From
+}
+
+func (d Deadline) target() []ConditionStatus {
+	if len(d.Target) == 0 {
+		return []ConditionStatus{ConditionCompleted}
+	}
+
+	return d.Target
+}
+
+// DeadlineResult is returned by EnforceDeadlines.
+type DeadlineResult struct {
+	// Exceeded holds the types of the conditions that were set to ConditionError because they missed their deadline.
+	Exceeded []ConditionType
+
+	// Changed is true if any condition was modified, the conditions need to be persisted.
+	Changed bool
+
+	// RequeueAfter is how long until the next deadline, 0 when no deadline is pending.
+	RequeueAfter time.Duration
+}
+
+// EnforceDeadlines sets the conditions that missed their deadline to ConditionError, with a reason starting with
+// DeadlineExceededReason, and computes how long until the next deadline needs to be checked.
+//
+//	result := res.Status.Conditions.EnforceDeadlines(time.Now(), deadlines...)
+//	if result.Changed {
+//		if err := reconciler.Status().Update(ctx, &res); err != nil {
+//			return ctrl.Result{}, err
+//		}
+//	}
+//
+//	return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
+//
+// Conditions that reached a target, or a terminal status, aren't subject to their deadline anymore. Locked
+// conditions are left alone, even past their deadline, since a task is working on them: the deadline is enforced
+// once the lock is released.
+func (c *Conditions) EnforceDeadlines(now time.Time, deadlines ...Deadline) DeadlineResult {
+	var result DeadlineResult
+	if c == nil {
+		return result
+	}
+
+	for _, deadline := range deadlines {
+		condition, ok := c.GetType(deadline.Type)
+		if !ok || condition.IsTerminal() || condition.StatusIsOneOf(deadline.target()...) {
+			continue
+		}
+
+		start, err := condition.GetTime(DeadlineStartAttribute)
+		if condition.Status == deadline.from() && (err != nil || start.Before(condition.LastTransitionTime.Time)) {
+			start = condition.LastTransitionTime.Time
+			if condition.SetTime(DeadlineStartAttribute, start) == nil && c.SetCondition(condition) == nil {
+				result.Changed = true
+			}
+		} else if err != nil {
+			// The condition was never seen in the From status, it doesn't have a deadline.
+			continue
+		}
+
+		remaining := start.Add(deadline.Within).Sub(now)
+		if remaining > 0 {
+			if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+				result.RequeueAfter = remaining
+			}
+			continue
+		}
+
+		if condition.Status == ConditionLocked {
+			continue
+		}
+
+		condition.Status = ConditionError
+		condition.Reason = fmt.Sprintf("%s: %s didn't reach %s within %s of %s", DeadlineExceededReason, condition.Type, deadline.target()[0], deadline.Within, deadline.from())
+		condition.LastTransitionTime = meta.Time{}
+		if c.SetCondition(condition) == nil {
+			result.Exceeded = append(result.Exceeded, condition.Type)
+			result.Changed = true
+		}
+	}
+
+	return result
+}
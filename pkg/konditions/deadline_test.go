@@ -0,0 +1,72 @@
+package konditions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnforceDeadlines(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadline := Deadline{Type: ConditionType("Certificate"), Within: 10 * time.Minute}
+
+	conditions := Conditions{
+		{Type: ConditionType("Certificate"), Status: ConditionInitialized, LastTransitionTime: meta.NewTime(start)},
+	}
+
+	result := conditions.EnforceDeadlines(start.Add(time.Minute), deadline)
+	if !result.Changed || len(result.Exceeded) != 0 || result.RequeueAfter != 9*time.Minute {
+		t.Fatalf("Expected the start of the deadline to be recorded, got: %+v", result)
+	}
+
+	// The condition moves on, the deadline holds.
+	condition := conditions.FindOrInitializeFor(ConditionType("Certificate"))
+	condition.Status = ConditionCreated
+	condition.LastTransitionTime = meta.NewTime(start.Add(2 * time.Minute))
+	conditions.SetCondition(condition)
+
+	result = conditions.EnforceDeadlines(start.Add(5*time.Minute), deadline)
+	if result.Changed || result.RequeueAfter != 5*time.Minute {
+		t.Fatalf("Expected the deadline to be pending, got: %+v", result)
+	}
+
+	result = conditions.EnforceDeadlines(start.Add(11*time.Minute), deadline)
+	if !result.Changed || len(result.Exceeded) != 1 || result.RequeueAfter != 0 {
+		t.Fatalf("Expected the deadline to be exceeded, got: %+v", result)
+	}
+
+	condition = conditions.FindOrInitializeFor(ConditionType("Certificate"))
+	if condition.Status != ConditionError || !strings.HasPrefix(condition.Reason, DeadlineExceededReason) {
+		t.Error("Expected the condition to be errored, got: ", condition)
+	}
+}
+
+func TestEnforceDeadlinesSkipped(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadlines := []Deadline{
+		{Type: ConditionType("Completed"), Within: time.Minute},
+		{Type: ConditionType("Locked"), Within: time.Minute},
+		{Type: ConditionType("Unseen"), Within: time.Minute},
+		{Type: ConditionType("Missing"), Within: time.Minute},
+	}
+
+	locked := Condition{Type: ConditionType("Locked"), Status: ConditionLocked, LastTransitionTime: meta.NewTime(start)}
+	locked.SetTime(DeadlineStartAttribute, start)
+
+	conditions := Conditions{
+		{Type: ConditionType("Completed"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(start)},
+		locked,
+		{Type: ConditionType("Unseen"), Status: ConditionCreated, LastTransitionTime: meta.NewTime(start)},
+	}
+
+	result := conditions.EnforceDeadlines(start.Add(time.Hour), deadlines...)
+	if result.Changed || len(result.Exceeded) != 0 {
+		t.Errorf("Expected no condition to be affected, got: %+v", result)
+	}
+
+	if !conditions.TypeHasStatus(ConditionType("Locked"), ConditionLocked) {
+		t.Error("Expected the locked condition to be left alone until released")
+	}
+}
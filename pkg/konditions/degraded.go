@@ -0,0 +1,150 @@
+package konditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// ConsecutiveErrorsAttribute is the attribute a DegradedDetector uses to count the consecutive
+	// errors of a condition.
+	ConsecutiveErrorsAttribute = "konditionner.io/consecutive-errors"
+
+	// DegradedCondition is the type of the companion condition set by a DegradedDetector, unless
+	// it is configured with another one.
+	DegradedCondition ConditionType = "Degraded"
+
+	// DegradedEventReason is the reason of the Warning event emitted when a condition becomes degraded.
+	DegradedEventReason = "ConditionDegraded"
+)
+
+// DegradedDetector distinguishes one-off failures from persistent breakage. It counts the consecutive
+// errors of every condition, in the ConsecutiveErrorsAttribute of the condition, and once a condition
+// reaches the threshold, sets a companion condition, DegradedCondition, to ConditionError and emits a
+// Warning event on the resource.
+//
+// Since ConditionError is terminal, consecutive errors are errors separated by resets: by the user, the
+// remediation controller, etc. The counter survives Reset and is only cleared once the condition is
+// released with a status other than ConditionInitialized, ConditionLocked, ConditionSuspended or ConditionExhausted. The
+// companion condition is removed once no condition is past the threshold anymore.
+//
+//	detector := konditions.NewDegradedDetector(3, mgr.GetEventRecorderFor("my-operator"))
+//	lock := konditions.NewLock(&res, c, ConditionType("Bucket"), konditions.WithDegradedDetection(detector))
+//
+// The detector can also be used without a lock, before persisting the resource:
+//
+//	condition = detector.Observe(&res, condition)
+//	res.Status.Conditions.SetCondition(condition)
+//	detector.Update(&res)
+type DegradedDetector struct {
+	// Threshold is the number of consecutive errors after which a condition is degraded.
+	Threshold int64
+
+	// Type is the type of the companion condition, DegradedCondition if empty.
+	Type ConditionType
+
+	// Recorder receives the Warning events, events aren't emitted if it is nil.
+	Recorder record.EventRecorder
+}
+
+// NewDegradedDetector returns a detector with the threshold given, which emits its events to the
+// recorder. The recorder can be nil.
+func NewDegradedDetector(threshold int64, recorder record.EventRecorder) *DegradedDetector {
+	return &DegradedDetector{
+		Threshold: threshold,
+		Type:      DegradedCondition,
+		Recorder:  recorder,
+	}
+}
+
+// Observe returns the condition with its counter of consecutive errors updated. The condition is
+// expected to be the one that is about to be stored on the resource, with the attributes of the condition
+// it replaces. A Warning event is emitted when the condition reaches the threshold.
+func (d *DegradedDetector) Observe(obj ConditionalResource, condition Condition) Condition {
+	if condition.Type == d.companion() {
+		return condition
+	}
+
+	condition = *condition.DeepCopy()
+	switch condition.Status {
+	case ConditionError:
+	case ConditionInitialized, ConditionLocked, ConditionSuspended, ConditionExhausted:
+		return condition
+	default:
+		condition.DeleteAttr(ConsecutiveErrorsAttribute)
+		return condition
+	}
+
+	count, _ := condition.GetInt(ConsecutiveErrorsAttribute)
+	count++
+
+	// A condition that can't hold any more attributes isn't tracked.
+	if err := condition.SetInt(ConsecutiveErrorsAttribute, count); err != nil {
+		return condition
+	}
+
+	if count == d.Threshold && d.Recorder != nil {
+		d.Recorder.Event(obj, corev1.EventTypeWarning, DegradedEventReason, sanitizeMessage(fmt.Sprintf("%s failed %d times in a row: %s", condition.Type, count, condition.Reason)))
+	}
+
+	return condition
+}
+
+// Update sets, or removes, the companion condition of the resource depending on whether any of its
+// conditions reached the threshold. It returns the types of the degraded conditions.
+//
+// The changes are only made in memory, it is up to the caller to persist the resource.
+func (d *DegradedDetector) Update(obj ConditionalResource) []ConditionType {
+	var degraded []ConditionType
+	for _, condition := range *obj.Conditions() {
+		if condition.Type == d.companion() || condition.Class() != ClassError {
+			continue
+		}
+
+		if count, err := condition.GetInt(ConsecutiveErrorsAttribute); err == nil && count >= d.Threshold {
+			degraded = append(degraded, condition.Type)
+		}
+	}
+
+	if len(degraded) == 0 {
+		obj.Conditions().RemoveConditionWith(d.companion())
+		return nil
+	}
+
+	sort.Slice(degraded, func(i, j int) bool { return degraded[i] < degraded[j] })
+
+	types := make([]string, len(degraded))
+	for i, ct := range degraded {
+		types[i] = string(ct)
+	}
+
+	obj.Conditions().SetCondition(Condition{
+		Type:   d.companion(),
+		Status: ConditionError,
+		Reason: fmt.Sprintf("Failed at least %d times in a row: %s", d.Threshold, strings.Join(types, ", ")),
+	})
+
+	return degraded
+}
+
+func (d *DegradedDetector) companion() ConditionType {
+	if d.Type == "" {
+		return DegradedCondition
+	}
+
+	return d.Type
+}
+
+// WithDegradedDetection configures the lock to run the detector every time it releases the condition,
+// see DegradedDetector.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithDegradedDetection(detector))
+func WithDegradedDetection(detector *DegradedDetector) LockOption {
+	return func(l *Lock) {
+		l.degraded = detector
+	}
+}
@@ -0,0 +1,81 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+)
+
+func TestDegradedDetector(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("degraded")
+	c := newTestClient(res)
+	recorder := record.NewFakeRecorder(10)
+	detector := NewDegradedDetector(2, recorder)
+
+	run := func(err error) {
+		res.Conditions().Reset(ConditionType("Bucket"), "Retry")
+		lockErr := NewLock(res, c, ConditionType("Bucket"), WithDegradedDetection(detector)).Execute(ctx, func(condition Condition) (Condition, error) {
+			condition.Status = ConditionCreated
+			return condition, err
+		})
+		if !errors.Is(lockErr, err) {
+			t.Fatal("Unexpected error: ", lockErr)
+		}
+	}
+
+	run(errors.New("Bucket quota exceeded"))
+	if res.Conditions().FindType(DegradedCondition) != nil {
+		t.Fatal("Expected a single error not to degrade the resource")
+	}
+
+	run(errors.New("Bucket quota exceeded"))
+	degraded := res.Conditions().FindType(DegradedCondition)
+	if degraded == nil || degraded.Status != ConditionError {
+		t.Fatal("Expected the resource to be degraded, got: ", res.Status.Conditions)
+	}
+
+	if count, _ := res.Conditions().FindType(ConditionType("Bucket")).GetInt(ConsecutiveErrorsAttribute); count != 2 {
+		t.Error("Expected 2 consecutive errors, got: ", count)
+	}
+
+	if len(recorder.Events) != 1 {
+		t.Fatal("Expected a warning event once the threshold is reached")
+	}
+
+	if event := <-recorder.Events; event != "Warning ConditionDegraded Bucket failed 2 times in a row: Bucket quota exceeded" {
+		t.Error("Unexpected event: ", event)
+	}
+
+	run(errors.New("Bucket quota exceeded"))
+	if len(recorder.Events) != 0 {
+		t.Error("Expected the event to be emitted only once")
+	}
+
+	run(nil)
+	if res.Conditions().FindType(DegradedCondition) != nil {
+		t.Error("Expected the companion condition to be removed once the condition recovered")
+	}
+
+	if _, ok := res.Conditions().FindType(ConditionType("Bucket")).GetAttr(ConsecutiveErrorsAttribute); ok {
+		t.Error("Expected the counter to be cleared once the condition recovered")
+	}
+}
+
+func TestDegradedDetectorCustomType(t *testing.T) {
+	res := newTestResource("degraded")
+	detector := &DegradedDetector{Threshold: 1, Type: ConditionType("Broken")}
+
+	condition := detector.Observe(res, Condition{Type: ConditionType("DNS"), Status: ConditionError})
+	res.Conditions().SetCondition(condition)
+
+	if degraded := detector.Update(res); len(degraded) != 1 || degraded[0] != ConditionType("DNS") {
+		t.Error("Expected DNS to be degraded, got: ", degraded)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Broken"), ConditionError) {
+		t.Error("Expected the custom companion condition to be set, got: ", res.Status.Conditions)
+	}
+}
@@ -0,0 +1,55 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var ObjectDeletedErr = errors.New("Resource was deleted while its condition was locked")
+
+// DeletedBehavior decides what Execute does when the resource is deleted while the Task runs: the condition can't be
+// released, the Kubernetes API returns NotFound, or Gone, see WithDeletedBehavior.
+type DeletedBehavior int
+
+const (
+	// IgnoreDeleted makes Execute return the error of the Task, nil if it succeeded: there's no condition left to
+	// release. ExecuteResult.Deleted tells the reconciler the resource is gone. This is the default.
+	IgnoreDeleted DeletedBehavior = iota
+
+	// ReportDeleted makes Execute return an error wrapping ObjectDeletedErr and the error of the Kubernetes API.
+	ReportDeleted
+)
+
+// WithDeletedBehavior configures what Execute does when the resource is deleted while the Task runs. Without this
+// option, the deletion is ignored.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithDeletedBehavior(konditions.ReportDeleted))
+//	err := lock.Execute(ctx, createBucket)
+//	if errors.Is(err, konditions.ObjectDeletedErr) {
+//		// ... The bucket may have been created for a resource that doesn't exist anymore ...
+//	}
+func WithDeletedBehavior(behavior DeletedBehavior) LockOption {
+	return func(l *Lock) {
+		l.deletedBehavior = behavior
+	}
+}
+
+// Returns true if the error returned by the Kubernetes API means the resource doesn't exist anymore.
+func isDeleted(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsGone(err)
+}
+
+// Returns the error Execute returns when the condition couldn't be released because the resource was deleted while
+// the task ran. The error of the task, taskErr, isn't masked by the error of the release unless the deletion is
+// reported.
+func (l *Lock) releaseDeleted(releaseErr, taskErr error) error {
+	l.result.Deleted = true
+
+	if l.deletedBehavior == ReportDeleted {
+		return fmt.Errorf("%w: %w", ObjectDeletedErr, releaseErr)
+	}
+
+	return taskErr
+}
@@ -0,0 +1,104 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestLockResourceDeletedDuringTask(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("deleted")
+	c := newTestClient(res)
+
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(ctx, func(condition Condition) (Condition, error) {
+		if err := c.Delete(ctx, res.DeepCopyObject().(client.Object)); err != nil {
+			t.Fatal(err)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Error("Expected the deletion to be ignored, got: ", err)
+	}
+
+	if !result.Deleted || !result.TaskRan {
+		t.Error("Unexpected result: ", result)
+	}
+}
+
+func TestLockResourceDeletedDuringFailedTask(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("deleted")
+	c := newTestClient(res)
+
+	taskErr := errors.New("Bucket already exists")
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := c.Delete(ctx, res.DeepCopyObject().(client.Object)); err != nil {
+			t.Fatal(err)
+		}
+
+		return condition, taskErr
+	})
+
+	if !errors.Is(err, taskErr) {
+		t.Error("Expected the error of the task, got: ", err)
+	}
+}
+
+func TestLockWithDeletedBehaviorReport(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("deleted")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithDeletedBehavior(ReportDeleted))
+	lock.RemoveOnRelease()
+
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := c.Delete(ctx, res.DeepCopyObject().(client.Object)); err != nil {
+			t.Fatal(err)
+		}
+
+		return condition, nil
+	})
+
+	if !errors.Is(err, ObjectDeletedErr) || !apierrors.IsNotFound(err) {
+		t.Error("Expected ObjectDeletedErr wrapping NotFound, got: ", err)
+	}
+}
+
+func TestConditionReconcilerResourceDeleted(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("deleted")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var calls []ConditionType
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			calls = append(calls, condition.Type)
+			condition.Status = ConditionCompleted
+			return condition, c.Delete(ctx, obj.DeepCopyObject().(client.Object))
+		}).
+		On(ConditionType("DNS"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			calls = append(calls, condition.Type)
+			condition.Status = ConditionCompleted
+			return condition, nil
+		}).
+		Build()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil || !result.IsZero() {
+		t.Error("Expected the reconciliation to stop, got: ", result, err)
+	}
+
+	if len(calls) != 1 {
+		t.Error("Expected the handlers to stop once the resource is deleted, got: ", calls)
+	}
+}
@@ -0,0 +1,70 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ResourceDeletingErr = errors.New("Resource is being deleted")
+
+// DeletionPolicy decides what a Lock does when Execute is called on a resource that is being deleted, that
+// is, a resource with a DeletionTimestamp. See WithDeletionPolicy.
+//
+// By default, tasks are refused: Execute returns ResourceDeletingErr and the condition is left untouched. A
+// termination task can be registered for a condition type, in which case Execute runs the termination task
+// instead of the task it was given.
+type DeletionPolicy struct {
+	terminations map[ConditionType]Task
+}
+
+// NewDeletionPolicy returns a policy that refuses to run tasks on resources that are being deleted.
+func NewDeletionPolicy() *DeletionPolicy {
+	return &DeletionPolicy{
+		terminations: map[ConditionType]Task{},
+	}
+}
+
+// TerminateWith registers the task that runs, instead of the task given to Execute, when the condition type
+// is locked on a resource that is being deleted.
+//
+//	policy := konditions.NewDeletionPolicy().
+//		TerminateWith(ConditionType("Bucket"), func(condition konditions.Condition) (konditions.Condition, error) {
+//			if err := deleteBucketForResource(ctx, &res); err != nil {
+//				return condition, err
+//			}
+//
+//			condition.Status = konditions.ConditionTerminated
+//			condition.Reason = "Bucket deleted"
+//			return condition, nil
+//		})
+func (p *DeletionPolicy) TerminateWith(ct ConditionType, task Task) *DeletionPolicy {
+	p.terminations[ct] = task
+	return p
+}
+
+// Returns the task to run for the condition type when the resource is being deleted.
+func (p *DeletionPolicy) taskFor(ct ConditionType) (Task, error) {
+	task, ok := p.terminations[ct]
+	if !ok {
+		return nil, fmt.Errorf("%w: refusing to run the task for %s", ResourceDeletingErr, ct)
+	}
+
+	return task, nil
+}
+
+// WithDeletionPolicy configures the lock to check the DeletionTimestamp of the resource before running a
+// task. This prevents the classic bug of provisioning external resources for an object that is being deleted,
+// because a reconciliation started before the deletion, or because the task doesn't check for it.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithDeletionPolicy(policy))
+//	err := lock.Execute(ctx, createBucket)
+//	if errors.Is(err, konditions.ResourceDeletingErr) {
+//		// The resource is being deleted and there's no termination task for Bucket.
+//	}
+//
+// The check happens before the lock is acquired, a refused task doesn't modify the condition.
+func WithDeletionPolicy(policy *DeletionPolicy) LockOption {
+	return func(l *Lock) {
+		l.deletionPolicy = policy
+	}
+}
@@ -0,0 +1,92 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newDeletingTestResource(name string) *testResource {
+	res := newTestResource(name)
+	res.Finalizers = []string{DefaultFinalizer}
+	res.DeletionTimestamp = &meta.Time{Time: now().Time}
+	return res
+}
+
+func TestLockDeletionPolicyRefuses(t *testing.T) {
+	ctx := context.Background()
+	res := newDeletingTestResource("refused")
+	c := newTestClient(res)
+
+	policy := NewDeletionPolicy()
+	lock := NewLock(res, c, ConditionType("Bucket"), WithDeletionPolicy(policy))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		t.Error("Expected the task not to run on a resource being deleted")
+		return condition, nil
+	})
+
+	if !errors.Is(err, ResourceDeletingErr) {
+		t.Fatal("Expected the task to be refused, got: ", err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Conditions().FindType(ConditionType("Bucket")) != nil {
+		t.Error("Expected the condition to be left untouched, got: ", stored.Conditions())
+	}
+}
+
+func TestLockDeletionPolicyTerminates(t *testing.T) {
+	ctx := context.Background()
+	res := newDeletingTestResource("terminated")
+	c := newTestClient(res)
+
+	policy := NewDeletionPolicy().TerminateWith(ConditionType("Bucket"), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionTerminated
+		condition.Reason = "Bucket deleted"
+		return condition, nil
+	})
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithDeletionPolicy(policy))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		t.Error("Expected the termination task to run instead")
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionTerminated) {
+		t.Error("Expected the termination task to run, got: ", stored.Conditions())
+	}
+}
+
+func TestLockDeletionPolicyNotDeleting(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("alive")
+	c := newTestClient(res)
+
+	ran := false
+	lock := NewLock(res, c, ConditionType("Bucket"), WithDeletionPolicy(NewDeletionPolicy()))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		ran = true
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil || !ran {
+		t.Error("Expected the task to run on a resource that isn't being deleted, got: ", err)
+	}
+}
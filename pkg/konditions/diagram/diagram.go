@@ -0,0 +1,204 @@
+// Package diagram renders the state machines declared for conditions as Mermaid or Graphviz (DOT) diagrams, so the
+// lifecycle of the conditions of an operator can be reviewed, and documented, from what the code declares instead of
+// being drawn by hand.
+//
+// The state machines are declared by the transitions of a KonditionPolicy:
+//
+//	machine := diagram.FromPolicy(&bucketPolicy)
+//	out, err := machine.Export(diagram.Mermaid)
+//
+// The kondition command renders the policies stored in YAML files, see cmd/kondition.
+package diagram
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/policy"
+)
+
+var UnsupportedFormatErr = errors.New("Unsupported diagram format")
+
+// Format of a diagram.
+type Format string
+
+const (
+	// Mermaid renders a stateDiagram-v2, which GitHub and most documentation tools render natively.
+	Mermaid Format = "mermaid"
+
+	// DOT renders a Graphviz digraph.
+	DOT Format = "dot"
+)
+
+// StateMachine is the set of transitions allowed between the statuses of conditions.
+type StateMachine struct {
+	// Name is the title of the diagram.
+	Name string
+
+	// Types are the condition types the state machine applies to, every type if empty.
+	Types []konditions.ConditionType
+
+	// Initial is the status conditions start in, ConditionInitialized if empty.
+	Initial konditions.ConditionStatus
+
+	// Transitions maps a status to the statuses a condition can transition to from it.
+	Transitions map[konditions.ConditionStatus][]konditions.ConditionStatus
+}
+
+// FromPolicy returns the state machine declared by the transitions of the policy. The policy applies to the
+// RequiredTypes of the policy, or to every type when the policy doesn't require any.
+func FromPolicy(p *policy.KonditionPolicy) StateMachine {
+	machine := StateMachine{
+		Name:        p.Name,
+		Types:       p.Spec.RequiredTypes,
+		Transitions: map[konditions.ConditionStatus][]konditions.ConditionStatus{},
+	}
+
+	for _, transition := range p.Spec.Transitions {
+		machine.Transitions[transition.From] = append(machine.Transitions[transition.From], transition.To...)
+	}
+
+	return machine
+}
+
+// Export renders the state machine in the format given. The output is deterministic: statuses are sorted, and each
+// transition is rendered once.
+func (m StateMachine) Export(format Format) ([]byte, error) {
+	switch format {
+	case Mermaid:
+		return m.mermaid(), nil
+	case DOT:
+		return m.dot(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q, expected %s or %s", UnsupportedFormatErr, format, Mermaid, DOT)
+	}
+}
+
+type edge struct {
+	from, to konditions.ConditionStatus
+}
+
+func (m StateMachine) initial() konditions.ConditionStatus {
+	if m.Initial == "" {
+		return konditions.ConditionInitialized
+	}
+
+	return m.Initial
+}
+
+// Returns the transitions, sorted and without duplicates.
+func (m StateMachine) edges() []edge {
+	seen := map[edge]bool{}
+	var edges []edge
+
+	for from, targets := range m.Transitions {
+		for _, to := range targets {
+			e := edge{from: from, to: to}
+			if !seen[e] {
+				seen[e] = true
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+
+	return edges
+}
+
+// Returns every status of the state machine, sorted.
+func (m StateMachine) statuses() []konditions.ConditionStatus {
+	seen := map[konditions.ConditionStatus]bool{m.initial(): true}
+	for _, e := range m.edges() {
+		seen[e.from] = true
+		seen[e.to] = true
+	}
+
+	statuses := make([]konditions.ConditionStatus, 0, len(seen))
+	for status := range seen {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+
+	return statuses
+}
+
+func (m StateMachine) title() string {
+	if len(m.Types) == 0 {
+		return m.Name
+	}
+
+	types := make([]string, len(m.Types))
+	for i, ct := range m.Types {
+		types[i] = string(ct)
+	}
+
+	return fmt.Sprintf("%s (%s)", m.Name, strings.Join(types, ", "))
+}
+
+func (m StateMachine) mermaid() []byte {
+	var out strings.Builder
+
+	if title := m.title(); title != "" {
+		fmt.Fprintf(&out, "---\ntitle: %s\n---\n", title)
+	}
+	out.WriteString("stateDiagram-v2\n")
+
+	// Statuses are user defined, they can hold characters Mermaid doesn't accept in an identifier.
+	for _, status := range m.statuses() {
+		if id := mermaidID(status); id != string(status) {
+			fmt.Fprintf(&out, "    state %q as %s\n", string(status), id)
+		}
+	}
+
+	fmt.Fprintf(&out, "    [*] --> %s\n", mermaidID(m.initial()))
+	for _, e := range m.edges() {
+		fmt.Fprintf(&out, "    %s --> %s\n", mermaidID(e.from), mermaidID(e.to))
+	}
+
+	return []byte(out.String())
+}
+
+func mermaidID(status konditions.ConditionStatus) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, string(status))
+}
+
+func (m StateMachine) dot() []byte {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "digraph %s {\n", dotQuote(m.Name))
+	out.WriteString("    rankdir=LR;\n")
+	if title := m.title(); title != "" {
+		fmt.Fprintf(&out, "    label=%s;\n", dotQuote(title))
+	}
+
+	out.WriteString("    \"[*]\" [shape=point];\n")
+	for _, status := range m.statuses() {
+		fmt.Fprintf(&out, "    %s [shape=box, style=rounded];\n", dotQuote(string(status)))
+	}
+
+	fmt.Fprintf(&out, "    \"[*]\" -> %s;\n", dotQuote(string(m.initial())))
+	for _, e := range m.edges() {
+		fmt.Fprintf(&out, "    %s -> %s;\n", dotQuote(string(e.from)), dotQuote(string(e.to)))
+	}
+	out.WriteString("}\n")
+
+	return []byte(out.String())
+}
+
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,81 @@
+package diagram
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/policy"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPolicy() *policy.KonditionPolicy {
+	return &policy.KonditionPolicy{
+		ObjectMeta: meta.ObjectMeta{Name: "buckets"},
+		Spec: policy.KonditionPolicySpec{
+			RequiredTypes: []konditions.ConditionType{"Bucket"},
+			Transitions: []policy.AllowedTransition{
+				{From: konditions.ConditionLocked, To: []konditions.ConditionStatus{konditions.ConditionError, konditions.ConditionCompleted}},
+				{From: konditions.ConditionInitialized, To: []konditions.ConditionStatus{konditions.ConditionLocked}},
+				{From: konditions.ConditionLocked, To: []konditions.ConditionStatus{"Waiting for DNS", konditions.ConditionCompleted}},
+			},
+		},
+	}
+}
+
+func TestExportMermaid(t *testing.T) {
+	out, err := FromPolicy(newTestPolicy()).Export(Mermaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `---
+title: buckets (Bucket)
+---
+stateDiagram-v2
+    state "Waiting for DNS" as Waiting_for_DNS
+    [*] --> Initialized
+    Initialized --> Locked
+    Locked --> Completed
+    Locked --> Error
+    Locked --> Waiting_for_DNS
+`
+
+	if string(out) != expected {
+		t.Errorf("Unexpected diagram:\n%s\nexpected:\n%s", out, expected)
+	}
+}
+
+func TestExportDOT(t *testing.T) {
+	out, err := FromPolicy(newTestPolicy()).Export(DOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `digraph "buckets" {
+    rankdir=LR;
+    label="buckets (Bucket)";
+    "[*]" [shape=point];
+    "Completed" [shape=box, style=rounded];
+    "Error" [shape=box, style=rounded];
+    "Initialized" [shape=box, style=rounded];
+    "Locked" [shape=box, style=rounded];
+    "Waiting for DNS" [shape=box, style=rounded];
+    "[*]" -> "Initialized";
+    "Initialized" -> "Locked";
+    "Locked" -> "Completed";
+    "Locked" -> "Error";
+    "Locked" -> "Waiting for DNS";
+}
+`
+
+	if string(out) != expected {
+		t.Errorf("Unexpected diagram:\n%s\nexpected:\n%s", out, expected)
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	if _, err := FromPolicy(newTestPolicy()).Export(Format("svg")); !errors.Is(err, UnsupportedFormatErr) {
+		t.Error("Expected the format to be unsupported, got: ", err)
+	}
+}
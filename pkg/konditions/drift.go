@@ -0,0 +1,157 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultDriftInterval is how often a DriftReconciler checks the conditions of a resource, unless configured otherwise.
+const DefaultDriftInterval = 10 * time.Minute
+
+// DriftStatus is the result of a CheckFunc.
+type DriftStatus string
+
+const (
+	// InSync means the external state matches what the condition completed.
+	InSync DriftStatus = "InSync"
+
+	// Drifted means the external state doesn't match what the condition completed anymore, the work needs to be done again.
+	Drifted DriftStatus = "Drifted"
+
+	// DriftUnknown means the check couldn't tell, the condition is left untouched.
+	DriftUnknown DriftStatus = "Unknown"
+)
+
+// CheckFunc checks whether the work a completed condition did still holds: the DNS record still exists and points to
+// the right address, the bucket still has the right policy, etc. The message explains the drift, it becomes part of
+// the reason of the condition when it is Drifted.
+//
+//	func checkRecord(ctx context.Context, res *MyCRD, condition konditions.Condition) (konditions.DriftStatus, string) {
+//		record, err := dns.Lookup(ctx, res.Spec.Hostname)
+//		if err != nil {
+//			return konditions.DriftUnknown, err.Error()
+//		}
+//
+//		if record.Address != res.Status.Address {
+//			return konditions.Drifted, fmt.Sprintf("record points to %s", record.Address)
+//		}
+//
+//		return konditions.InSync, ""
+//	}
+type CheckFunc[PT ConditionalResource] func(ctx context.Context, obj PT, condition Condition) (DriftStatus, string)
+
+// DriftReconciler runs the checkers registered for the completed conditions of a resource, periodically, and
+// downgrades the conditions that Drifted to ConditionInitialized, with a reason explaining the drift. The handler
+// of the condition, a ConditionReconciler or any workflow picking the next actionable condition, then redoes the work.
+//
+//	drift := konditions.NewDriftReconciler[MyCRD](mgr.GetClient()).
+//		Check(ConditionType("DNSRecord"), checkRecord).
+//		Every(30 * time.Minute)
+//
+//	err := ctrl.NewControllerManagedBy(mgr).Named("mycrd-drift").For(&MyCRD{}).Complete(drift)
+type DriftReconciler[T any, PT interface {
+	*T
+	ConditionalResource
+}] struct {
+	client      client.Client
+	checkers    map[ConditionType]CheckFunc[PT]
+	interval    time.Duration
+	lockOptions []LockOption
+}
+
+// NewDriftReconciler returns a drift reconciler for the custom resource T, without checkers.
+func NewDriftReconciler[T any, PT interface {
+	*T
+	ConditionalResource
+}](c client.Client) *DriftReconciler[T, PT] {
+	return &DriftReconciler[T, PT]{
+		client:   c,
+		checkers: map[ConditionType]CheckFunc[PT]{},
+		interval: DefaultDriftInterval,
+	}
+}
+
+// Check registers the checker of the condition type, replacing the checker registered before, if any.
+func (r *DriftReconciler[T, PT]) Check(ct ConditionType, check CheckFunc[PT]) *DriftReconciler[T, PT] {
+	r.checkers[ct] = check
+	return r
+}
+
+// Every configures how often the conditions of a resource are checked, DefaultDriftInterval otherwise.
+func (r *DriftReconciler[T, PT]) Every(interval time.Duration) *DriftReconciler[T, PT] {
+	r.interval = interval
+	return r
+}
+
+// WithLockOptions configures the options passed to the locks the drifted conditions are downgraded with.
+func (r *DriftReconciler[T, PT]) WithLockOptions(opts ...LockOption) *DriftReconciler[T, PT] {
+	r.lockOptions = append(r.lockOptions, opts...)
+	return r
+}
+
+// Reconcile fetches the resource, runs a Pass and requeues the resource for the next check.
+func (r *DriftReconciler[T, PT]) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := PT(new(T))
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	if _, err := r.Pass(ctx, obj); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: r.interval}, nil
+}
+
+// Pass runs the checkers of the completed conditions of the resource once, and downgrades the conditions that
+// drifted. The types of the conditions downgraded are returned, sorted. It can be called from any reconciler,
+// before the conditions are worked on.
+func (r *DriftReconciler[T, PT]) Pass(ctx context.Context, obj PT) ([]ConditionType, error) {
+	types := make([]ConditionType, 0, len(r.checkers))
+	for ct := range r.checkers {
+		types = append(types, ct)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var drifted []ConditionType
+	var errs []error
+	for _, ct := range types {
+		condition, ok := obj.Conditions().GetType(ct)
+		if !ok || condition.Status != ConditionCompleted {
+			continue
+		}
+
+		status, message := r.checkers[ct](ctx, obj, condition)
+		if status != Drifted {
+			continue
+		}
+
+		lock := NewLock(obj, r.client, ct, r.lockOptions...)
+		err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+			condition.Status = ConditionInitialized
+			condition.Reason = "Drifted"
+			if message != "" {
+				condition.Reason += ": " + message
+			}
+			return condition, nil
+		})
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		drifted = append(drifted, ct)
+	}
+
+	return drifted, errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDriftReconciler(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("drift")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("DNSRecord"), Status: ConditionCompleted})
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Certificate"), Status: ConditionCompleted})
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Volume"), Status: ConditionCreated})
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var checked []ConditionType
+	check := func(status DriftStatus, message string) CheckFunc[*testResource] {
+		return func(ctx context.Context, obj *testResource, condition Condition) (DriftStatus, string) {
+			checked = append(checked, condition.Type)
+			return status, message
+		}
+	}
+
+	drift := NewDriftReconciler[testResource](c).
+		Check(ConditionType("DNSRecord"), check(Drifted, "record points to 10.0.0.2")).
+		Check(ConditionType("Bucket"), check(InSync, "")).
+		Check(ConditionType("Certificate"), check(DriftUnknown, "lookup failed")).
+		Check(ConditionType("Volume"), check(Drifted, "")).
+		Every(time.Hour)
+
+	result, err := drift.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != time.Hour {
+		t.Error("Expected the resource to be checked again later, got: ", result)
+	}
+
+	if len(checked) != 3 {
+		t.Error("Expected only the completed conditions to be checked, got: ", checked)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	dns := stored.Conditions().FindOrInitializeFor(ConditionType("DNSRecord"))
+	if dns.Status != ConditionInitialized || dns.Reason != "Drifted: record points to 10.0.0.2" {
+		t.Error("Expected the drifted condition to be downgraded, got: ", dns)
+	}
+
+	for _, ct := range []ConditionType{ConditionType("Bucket"), ConditionType("Certificate")} {
+		if !stored.Conditions().TypeHasStatus(ct, ConditionCompleted) {
+			t.Errorf("Expected %s to be left untouched, got: %v", ct, stored.Conditions())
+		}
+	}
+
+	priorities := Priorities{ConditionType("DNSRecord"): 1}
+	if next, ok := priorities.Next(*stored.Conditions()); !ok || next.Type != ConditionType("DNSRecord") {
+		t.Error("Expected the drifted condition to be actionable, got: ", next)
+	}
+}
@@ -0,0 +1,172 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// DefaultAggregationWindow is the window used by an EventAggregator when none is given.
+const DefaultAggregationWindow = 5 * time.Second
+
+// TransitionsEventReason is the reason of the events emitted by an EventAggregator.
+const TransitionsEventReason = "ConditionTransitions"
+
+// aggregateKey is the deduplication key of an aggregate: every transition of the same condition
+// type, on the same resource, within a window is coalesced into a single event.
+type aggregateKey struct {
+	uid           types.UID
+	key           types.NamespacedName
+	conditionType ConditionType
+}
+
+type aggregate struct {
+	obj      ConditionalResource
+	statuses []ConditionStatus
+	first    time.Time
+	last     time.Time
+}
+
+// EventAggregator coalesces the transitions of conditions into periodic, summarized events. Emitting an event
+// for every transition makes `kubectl describe` unreadable on chatty resources, the aggregator emits
+// a single event per condition type and window instead:
+//
+//	Normal  ConditionTransitions  Bucket: Initialized→Locked→Created over 3s
+//
+// The aggregator is a Listener, and a Runnable that flushes the events at the end of every window:
+//
+//	aggregator := konditions.NewEventAggregator(mgr.GetEventRecorderFor("my-operator"), 5*time.Second)
+//	if err := mgr.Add(aggregator); err != nil {
+//		return err
+//	}
+//
+//	lock := konditions.NewLock(&res, c, ConditionType("Bucket"), konditions.WithListener(aggregator.Listener()))
+//
+// The event is a Warning if the condition ended the window in ConditionError, Normal otherwise.
+type EventAggregator struct {
+	recorder record.EventRecorder
+	window   time.Duration
+
+	mu      sync.Mutex
+	pending map[aggregateKey]*aggregate
+
+	// now returns the current time. It defaults to time.Now and exists for tests.
+	now func() time.Time
+}
+
+// NewEventAggregator returns an aggregator that emits its events to the recorder given, every window. The
+// DefaultAggregationWindow is used if the window is 0.
+func NewEventAggregator(recorder record.EventRecorder, window time.Duration) *EventAggregator {
+	if window <= 0 {
+		window = DefaultAggregationWindow
+	}
+
+	return &EventAggregator{
+		recorder: recorder,
+		window:   window,
+		pending:  map[aggregateKey]*aggregate{},
+		now:      time.Now,
+	}
+}
+
+// Listener returns the Listener that records the transitions into the aggregator.
+func (a *EventAggregator) Listener() Listener {
+	return a.Record
+}
+
+// Record adds the transition to the aggregate of its resource and condition type. Consecutive
+// transitions to the same status are only recorded once.
+func (a *EventAggregator) Record(obj ConditionalResource, transition Transition) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	key := aggregateKey{
+		uid:           obj.GetUID(),
+		key:           types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		conditionType: transition.Type,
+	}
+
+	agg, ok := a.pending[key]
+	if !ok {
+		agg = &aggregate{first: now}
+		if transition.Old != nil {
+			agg.statuses = append(agg.statuses, transition.Old.Status)
+		}
+		a.pending[key] = agg
+	}
+
+	status := ConditionStatus("Removed")
+	if transition.New != nil {
+		status = transition.New.Status
+	}
+
+	if len(agg.statuses) == 0 || agg.statuses[len(agg.statuses)-1] != status {
+		agg.statuses = append(agg.statuses, status)
+	}
+
+	agg.obj = obj
+	agg.last = now
+}
+
+// Flush emits an event for every aggregate recorded since the last flush, and clears them.
+func (a *EventAggregator) Flush() {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = map[aggregateKey]*aggregate{}
+	a.mu.Unlock()
+
+	keys := make([]aggregateKey, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+
+	// Events are emitted in a stable order, which keeps the output of the recorder predictable.
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].key != keys[j].key {
+			return keys[i].key.String() < keys[j].key.String()
+		}
+		return keys[i].conditionType < keys[j].conditionType
+	})
+
+	for _, key := range keys {
+		agg := pending[key]
+
+		statuses := make([]string, len(agg.statuses))
+		for i, status := range agg.statuses {
+			statuses[i] = string(status)
+		}
+
+		eventType := corev1.EventTypeNormal
+		if agg.statuses[len(agg.statuses)-1] == ConditionError {
+			eventType = corev1.EventTypeWarning
+		}
+
+		message := fmt.Sprintf("%s: %s over %s", key.conditionType, strings.Join(statuses, "→"), agg.last.Sub(agg.first).Round(time.Second))
+		a.recorder.Event(agg.obj, eventType, TransitionsEventReason, sanitizeMessage(message))
+	}
+}
+
+// Start flushes the aggregator at the end of every window until the context is done, at which point the
+// aggregator is flushed one last time. It implements manager.Runnable.
+func (a *EventAggregator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(a.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.Flush()
+			return nil
+		case <-ticker.C:
+			a.Flush()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventAggregator(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	aggregator := NewEventAggregator(recorder, time.Second)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	aggregator.now = func() time.Time { return now }
+
+	res := newTestResource("aggregator")
+	tracker := NewTracker(res, aggregator.Listener())
+
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionInitialized})
+	now = now.Add(time.Second)
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionLocked})
+	now = now.Add(2 * time.Second)
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+	tracker.SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionError})
+
+	if len(recorder.Events) != 0 {
+		t.Fatal("Expected no event before the aggregator is flushed")
+	}
+
+	aggregator.Flush()
+
+	expected := []string{
+		"Normal ConditionTransitions Bucket: Initialized→Locked→Created over 3s",
+		"Warning ConditionTransitions DNS: Error over 0s",
+	}
+
+	for _, message := range expected {
+		select {
+		case event := <-recorder.Events:
+			if event != message {
+				t.Errorf("Expected %q, got %q", message, event)
+			}
+		default:
+			t.Fatal("Expected event: ", message)
+		}
+	}
+
+	aggregator.Flush()
+	if len(recorder.Events) != 0 {
+		t.Error("Expected the aggregates to be cleared after a flush")
+	}
+
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	aggregator.Flush()
+
+	if event := <-recorder.Events; event != "Normal ConditionTransitions Bucket: Created→Completed over 0s" {
+		t.Error("Expected the next window to start from the previous status, got: ", event)
+	}
+}
+
+func TestEventAggregatorFlushesOnStop(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	aggregator := NewEventAggregator(recorder, time.Hour)
+
+	res := newTestResource("aggregator")
+	NewTracker(res, aggregator.Listener()).SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := aggregator.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.Events) != 1 {
+		t.Error("Expected the pending aggregates to be flushed when the aggregator stops")
+	}
+}
@@ -0,0 +1,239 @@
+// Package export dumps the conditions of every instance of a kind as flat records, one record per condition, for
+// offline analysis: capacity planning, post-mortems, or loading into a spreadsheet or a data warehouse.
+//
+//	records, err := export.List(ctx, c, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"})
+//	if err != nil {
+//		return err
+//	}
+//
+//	err = export.Write(os.Stdout, export.CSV, records)
+//
+// The kondition command exports the conditions of the kinds given, see cmd/kondition.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var UnsupportedFormatErr = errors.New("Unsupported export format")
+
+// Format of an export.
+type Format string
+
+const (
+	// JSON writes the records as a JSON array.
+	JSON Format = "json"
+
+	// JSONLines writes a JSON object per line, which most data tools load without parsing the whole file.
+	JSONLines Format = "jsonl"
+
+	// CSV writes the records with a header line. The attributes are written as a JSON object.
+	CSV Format = "csv"
+)
+
+// DefaultPageSize is the number of resources List fetches per call to the API.
+const DefaultPageSize = 500
+
+// Record is a condition of a resource, flattened with the identity of the resource.
+type Record struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	Generation int64  `json:"generation"`
+
+	Type               konditions.ConditionType   `json:"type"`
+	Status             konditions.ConditionStatus `json:"status"`
+	Reason             string                     `json:"reason,omitempty"`
+	Manager            string                     `json:"manager,omitempty"`
+	Ref                string                     `json:"ref,omitempty"`
+	ResumeStatus       konditions.ConditionStatus `json:"resumeStatus,omitempty"`
+	ObservedGeneration int64                      `json:"observedGeneration,omitempty"`
+	LastTransitionTime time.Time                  `json:"lastTransitionTime"`
+	LastHeartbeatTime  *time.Time                 `json:"lastHeartbeatTime,omitempty"`
+	Attributes         map[string]string          `json:"attributes,omitempty"`
+}
+
+// Records returns a record for each condition of the object, stored at the path given by fields, or at
+// konditions.DefaultConditionsFields if no fields are given. An object without conditions has no records.
+//
+// The records are those of the normalized conditions. When anomalies were fixed, the records are returned along with
+// an error wrapping the *konditions.NormalizedError.
+func Records(obj *unstructured.Unstructured, fields ...string) ([]Record, error) {
+	conditions, _, err := konditions.NestedConditions(obj, fieldsOrDefault(fields)...)
+	if err != nil {
+		err = fmt.Errorf("%s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+
+		var normalized *konditions.NormalizedError
+		if !errors.As(err, &normalized) {
+			return nil, err
+		}
+	}
+
+	records := make([]Record, 0, len(conditions))
+	for _, condition := range conditions {
+		record := Record{
+			APIVersion:         obj.GetAPIVersion(),
+			Kind:               obj.GetKind(),
+			Namespace:          obj.GetNamespace(),
+			Name:               obj.GetName(),
+			UID:                string(obj.GetUID()),
+			Generation:         obj.GetGeneration(),
+			Type:               condition.Type,
+			Status:             condition.Status,
+			Reason:             condition.Reason,
+			Manager:            condition.Manager,
+			Ref:                condition.Ref,
+			ResumeStatus:       condition.ResumeStatus,
+			ObservedGeneration: condition.ObservedGeneration,
+			LastTransitionTime: condition.LastTransitionTime.UTC(),
+			Attributes:         condition.Attributes,
+		}
+
+		if condition.LastHeartbeatTime != nil {
+			heartbeat := condition.LastHeartbeatTime.UTC()
+			record.LastHeartbeatTime = &heartbeat
+		}
+
+		records = append(records, record)
+	}
+
+	return records, err
+}
+
+func fieldsOrDefault(fields []string) []string {
+	if len(fields) == 0 {
+		return konditions.DefaultConditionsFields
+	}
+
+	return fields
+}
+
+// List lists every resource of the kind, a page at a time, and returns the records of their conditions. The
+// options narrow down the resources listed, client.InNamespace for instance. The conditions are read at the path
+// given by fields, see Records.
+//
+// The anomalies fixed in the conditions of the resources are joined in the error returned along with the records,
+// every resource is exported all the same. Any other error stops the listing.
+func List(ctx context.Context, c client.Reader, gvk schema.GroupVersionKind, fields []string, opts ...client.ListOption) ([]Record, error) {
+	records := []Record{}
+	var anomalies []error
+
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	if listOptions.Limit == 0 {
+		listOptions.Limit = DefaultPageSize
+	}
+
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, listOptions); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetKind() == "" {
+				obj.SetGroupVersionKind(gvk)
+			}
+
+			r, err := Records(obj, fields...)
+			var normalized *konditions.NormalizedError
+			if errors.As(err, &normalized) {
+				anomalies = append(anomalies, err)
+			} else if err != nil {
+				return nil, err
+			}
+			records = append(records, r...)
+		}
+
+		if list.GetContinue() == "" {
+			return records, errors.Join(anomalies...)
+		}
+		listOptions.Continue = list.GetContinue()
+	}
+}
+
+// Header is the header line of the CSV format, the columns are in the order of the fields of Record.
+var Header = []string{
+	"apiVersion", "kind", "namespace", "name", "uid", "generation",
+	"type", "status", "reason", "manager", "ref", "resumeStatus", "observedGeneration",
+	"lastTransitionTime", "lastHeartbeatTime", "attributes",
+}
+
+// Write writes the records in the format given.
+func Write(w io.Writer, format Format, records []Record) error {
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case JSONLines:
+		encoder := json.NewEncoder(w)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		return writeCSV(w, records)
+	default:
+		return fmt.Errorf("%w: %q", UnsupportedFormatErr, format)
+	}
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Header); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		heartbeat := ""
+		if record.LastHeartbeatTime != nil {
+			heartbeat = record.LastHeartbeatTime.Format(time.RFC3339)
+		}
+
+		attributes := ""
+		if len(record.Attributes) > 0 {
+			data, err := json.Marshal(record.Attributes)
+			if err != nil {
+				return err
+			}
+			attributes = string(data)
+		}
+
+		transition := ""
+		if !record.LastTransitionTime.IsZero() {
+			transition = record.LastTransitionTime.Format(time.RFC3339)
+		}
+
+		row := []string{
+			record.APIVersion, record.Kind, record.Namespace, record.Name, record.UID, strconv.FormatInt(record.Generation, 10),
+			string(record.Type), string(record.Status), record.Reason, record.Manager, record.Ref, string(record.ResumeStatus), strconv.FormatInt(record.ObservedGeneration, 10),
+			transition, heartbeat, attributes,
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
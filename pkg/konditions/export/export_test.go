@@ -0,0 +1,148 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var bucketGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"}
+
+func newBucket(namespace, name string, conditions ...interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": namespace, "uid": name + "-uid", "generation": int64(2)},
+		"status":   map[string]interface{}{"conditions": conditions},
+	}}
+	obj.SetGroupVersionKind(bucketGVK)
+
+	return obj
+}
+
+func TestRecords(t *testing.T) {
+	obj := newBucket("default", "logs",
+		map[string]interface{}{"type": "Bucket", "status": "Locked", "reason": "Resource locked", "manager": "bucket-controller", "lastTransitionTime": "2024-01-01T09:00:00Z", "lastHeartbeatTime": "2024-01-01T09:05:00Z", "attributes": map[string]interface{}{"etag": "abc"}},
+		map[string]interface{}{"type": "DNS", "status": "Completed", "lastTransitionTime": "2024-01-01T08:00:00Z", "observedGeneration": int64(1)},
+	)
+
+	records, err := Records(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 {
+		t.Fatal("Expected a record per condition, got: ", records)
+	}
+
+	bucket := records[0]
+	if bucket.Kind != "Bucket" || bucket.APIVersion != "example.com/v1" || bucket.Namespace != "default" || bucket.Name != "logs" || bucket.UID != "logs-uid" || bucket.Generation != 2 {
+		t.Error("Expected the record to identify the resource, got: ", bucket)
+	}
+
+	if bucket.Status != "Locked" || bucket.Manager != "bucket-controller" || bucket.LastHeartbeatTime == nil || bucket.Attributes["etag"] != "abc" {
+		t.Error("Expected the record to hold the condition, got: ", bucket)
+	}
+
+	if records[1].ObservedGeneration != 1 || records[1].LastHeartbeatTime != nil {
+		t.Error("Unexpected record: ", records[1])
+	}
+
+	empty := newBucket("default", "empty")
+	if records, err := Records(empty); err != nil || len(records) != 0 {
+		t.Error("Expected no records for a resource without conditions, got: ", records, err)
+	}
+
+	duplicated := newBucket("default", "duplicated",
+		map[string]interface{}{"type": "Bucket", "status": "Locked", "lastTransitionTime": "2024-01-01T09:00:00Z"},
+		map[string]interface{}{"type": "Bucket", "status": "Completed", "lastTransitionTime": "2024-01-01T10:00:00Z"},
+	)
+
+	var normalized *konditions.NormalizedError
+	if records, err := Records(duplicated); !errors.As(err, &normalized) || len(records) != 1 || records[0].Status != "Completed" {
+		t.Error("Expected the records of the normalized conditions along with the anomalies, got: ", records, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		newBucket("default", "logs", map[string]interface{}{"type": "Bucket", "status": "Completed", "lastTransitionTime": "2024-01-01T09:00:00Z"}),
+		newBucket("default", "images", map[string]interface{}{"type": "Bucket", "status": "Error", "lastTransitionTime": "2024-01-01T09:00:00Z"}),
+		newBucket("staging", "logs", map[string]interface{}{"type": "Bucket", "status": "Locked", "lastTransitionTime": "2024-01-01T09:00:00Z"}),
+	).Build()
+
+	records, err := List(context.Background(), c, bucketGVK, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Error("Expected the records of every namespace, got: ", records)
+	}
+
+	records, err = List(context.Background(), c, bucketGVK, nil, client.InNamespace("staging"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || records[0].Namespace != "staging" {
+		t.Error("Expected the records of the namespace, got: ", records)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	records, err := Records(newBucket("default", "logs",
+		map[string]interface{}{"type": "Bucket", "status": "Locked", "reason": "Locked, for now", "lastTransitionTime": "2024-01-01T09:00:00Z", "attributes": map[string]interface{}{"b": "2", "a": "1"}},
+		map[string]interface{}{"type": "DNS", "status": "Completed", "lastTransitionTime": "2024-01-01T08:00:00Z"},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, CSV, records); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(Header, ",") {
+		t.Fatal("Expected a header and a row per record, got: ", rows)
+	}
+
+	if rows[1][8] != "Locked, for now" || rows[1][13] != "2024-01-01T09:00:00Z" || rows[1][15] != `{"a":"1","b":"2"}` {
+		t.Error("Unexpected row: ", rows[1])
+	}
+
+	out.Reset()
+	if err := Write(&out, JSONLines, records); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var record Record
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &record) != nil || record.Type != "DNS" {
+		t.Error("Expected a JSON object per line, got: ", out.String())
+	}
+
+	out.Reset()
+	var decoded []Record
+	if err := Write(&out, JSON, records); err != nil || json.Unmarshal(out.Bytes(), &decoded) != nil || len(decoded) != 2 {
+		t.Error("Expected a JSON array, got: ", out.String())
+	}
+
+	if err := Write(&out, Format("parquet"), records); !errors.Is(err, UnsupportedFormatErr) {
+		t.Error("Expected the format to be unsupported, got: ", err)
+	}
+}
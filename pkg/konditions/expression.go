@@ -0,0 +1,464 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var InvalidExpressionErr = errors.New("Invalid expression")
+
+// Expression is a compiled rule about a set of conditions, declared as a string. Expressions let
+// rules be configured, through a CRD or a flag, instead of being written in Go:
+//
+//	all(type in ["Bucket", "DNS"], status == "Completed") && none(status == "Error")
+//
+// An expression is made of quantifiers, combined with &&, || and !, and grouped with parentheses:
+//   - all(filter, predicate) is true if every condition matching the filter matches the predicate.
+//   - any(filter, predicate) is true if at least one condition matching the filter matches the predicate.
+//   - none(filter, predicate) is true if no condition matching the filter matches the predicate.
+//
+// The filter can be omitted, in which case the predicate is applied to every condition: `none(status == "Error")`.
+// Like in most languages, all() is true when no condition matches the filter. Use any() to require a
+// condition to exist: `any(type == "DNS")`.
+//
+// Filters and predicates compare the fields of a condition, `type`, `status`, `reason` and `ref`, with
+// strings, using ==, != and in. They can be combined with &&, || and ! as well:
+//
+//	any(type == "Bucket", status in ["Created", "Completed"] && reason != "")
+//
+// Expressions are compiled once and can be evaluated concurrently.
+type Expression struct {
+	source string
+	root   setNode
+}
+
+// CompileExpression parses the source given into an Expression. An error wrapping InvalidExpressionErr is
+// returned if the source isn't a valid expression.
+func CompileExpression(source string) (*Expression, error) {
+	p := &parser{source: source}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseSet()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.value)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// MustCompileExpression is like CompileExpression but panics if the source isn't a valid expression. It is meant
+// for expressions declared as package variables.
+func MustCompileExpression(source string) *Expression {
+	expr, err := CompileExpression(source)
+	if err != nil {
+		panic(err)
+	}
+
+	return expr
+}
+
+// Evaluate returns whether the conditions satisfy the expression.
+func (e *Expression) Evaluate(conditions Conditions) bool {
+	return e.root.eval(conditions)
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// MarshalText implements encoding.TextMarshaler, expressions are stored as their source.
+func (e Expression) MarshalText() ([]byte, error) {
+	return []byte(e.source), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler which lets expressions be used as fields of
+// a custom resource, or of a configuration file. The source is compiled when it is unmarshaled.
+func (e *Expression) UnmarshalText(text []byte) error {
+	expr, err := CompileExpression(string(text))
+	if err != nil {
+		return err
+	}
+
+	*e = *expr
+	return nil
+}
+
+// setNode evaluates a set of conditions, conditionNode a single condition.
+type setNode interface {
+	eval(conditions Conditions) bool
+}
+
+type conditionNode interface {
+	match(condition Condition) bool
+}
+
+type quantifier struct {
+	name      string
+	filter    conditionNode
+	predicate conditionNode
+}
+
+func (q quantifier) eval(conditions Conditions) bool {
+	for _, condition := range conditions {
+		if q.filter != nil && !q.filter.match(condition) {
+			continue
+		}
+
+		matched := q.predicate.match(condition)
+		switch {
+		case q.name == "all" && !matched:
+			return false
+		case q.name == "any" && matched:
+			return true
+		case q.name == "none" && matched:
+			return false
+		}
+	}
+
+	return q.name != "any"
+}
+
+type setAnd struct{ left, right setNode }
+type setOr struct{ left, right setNode }
+type setNot struct{ node setNode }
+
+func (n setAnd) eval(c Conditions) bool { return n.left.eval(c) && n.right.eval(c) }
+func (n setOr) eval(c Conditions) bool  { return n.left.eval(c) || n.right.eval(c) }
+func (n setNot) eval(c Conditions) bool { return !n.node.eval(c) }
+
+type conditionAnd struct{ left, right conditionNode }
+type conditionOr struct{ left, right conditionNode }
+type conditionNot struct{ node conditionNode }
+
+func (n conditionAnd) match(c Condition) bool { return n.left.match(c) && n.right.match(c) }
+func (n conditionOr) match(c Condition) bool  { return n.left.match(c) || n.right.match(c) }
+func (n conditionNot) match(c Condition) bool { return !n.node.match(c) }
+
+type comparison struct {
+	field  string
+	negate bool
+	values []string
+}
+
+func (n comparison) match(c Condition) bool {
+	var value string
+	switch n.field {
+	case "type":
+		value = string(c.Type)
+	case "status":
+		value = string(c.Status)
+	case "reason":
+		value = c.Reason
+	case "ref":
+		value = c.Ref
+	}
+
+	for _, v := range n.values {
+		if v == value {
+			return !n.negate
+		}
+	}
+
+	return n.negate
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+var operators = []string{"==", "!=", "&&", "||", "!", "(", ")", "[", "]", ","}
+
+func (p *parser) tokenize() error {
+	src := p.source
+	for i := 0; i < len(src); {
+		ch := src[i]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+			continue
+		case ch == '"':
+			end := i + 1
+			for end < len(src) && src[end] != '"' {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			if end >= len(src) {
+				return fmt.Errorf("%w: unterminated string at %d", InvalidExpressionErr, i)
+			}
+
+			value, err := strconv.Unquote(src[i : end+1])
+			if err != nil {
+				return fmt.Errorf("%w: invalid string at %d: %w", InvalidExpressionErr, i, err)
+			}
+
+			p.tokens = append(p.tokens, token{kind: tokenString, value: value, pos: i})
+			i = end + 1
+			continue
+		case ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z'):
+			end := i
+			for end < len(src) && (src[end] == '_' || (src[end] >= 'a' && src[end] <= 'z') || (src[end] >= 'A' && src[end] <= 'Z') || (src[end] >= '0' && src[end] <= '9')) {
+				end++
+			}
+
+			p.tokens = append(p.tokens, token{kind: tokenIdent, value: src[i:end], pos: i})
+			i = end
+			continue
+		}
+
+		matched := false
+		for _, op := range operators {
+			if strings.HasPrefix(src[i:], op) {
+				p.tokens = append(p.tokens, token{kind: tokenOperator, value: op, pos: i})
+				i += len(op)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("%w: unexpected character %q at %d", InvalidExpressionErr, ch, i)
+		}
+	}
+
+	p.tokens = append(p.tokens, token{kind: tokenEOF, pos: len(src)})
+	return nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.value == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if tok := p.next(); tok.kind != tokenOperator || tok.value != op {
+		return p.errorf(tok, "expected %q", op)
+	}
+
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	if tok.kind == tokenEOF {
+		return fmt.Errorf("%w: %s at the end of the expression", InvalidExpressionErr, fmt.Sprintf(format, args...))
+	}
+
+	return fmt.Errorf("%w: %s at %d", InvalidExpressionErr, fmt.Sprintf(format, args...), tok.pos)
+}
+
+func (p *parser) parseSet() (setNode, error) {
+	left, err := p.parseSetAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseSetAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = setOr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseSetAnd() (setNode, error) {
+	left, err := p.parseSetUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseSetUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = setAnd{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseSetUnary() (setNode, error) {
+	if p.accept("!") {
+		node, err := p.parseSetUnary()
+		if err != nil {
+			return nil, err
+		}
+		return setNot{node}, nil
+	}
+
+	if p.accept("(") {
+		node, err := p.parseSet()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+
+	tok := p.next()
+	if tok.kind != tokenIdent || (tok.value != "all" && tok.value != "any" && tok.value != "none") {
+		return nil, p.errorf(tok, "expected all(), any() or none()")
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	q := quantifier{name: tok.value}
+	predicate, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept(",") {
+		q.filter = predicate
+		if predicate, err = p.parseCondition(); err != nil {
+			return nil, err
+		}
+	}
+
+	q.predicate = predicate
+	return q, p.expect(")")
+}
+
+func (p *parser) parseCondition() (conditionNode, error) {
+	left, err := p.parseConditionAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseConditionAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionOr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseConditionAnd() (conditionNode, error) {
+	left, err := p.parseConditionUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseConditionUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionAnd{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseConditionUnary() (conditionNode, error) {
+	if p.accept("!") {
+		node, err := p.parseConditionUnary()
+		if err != nil {
+			return nil, err
+		}
+		return conditionNot{node}, nil
+	}
+
+	if p.accept("(") {
+		node, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+
+	field := p.next()
+	if field.kind != tokenIdent || (field.value != "type" && field.value != "status" && field.value != "reason" && field.value != "ref") {
+		return nil, p.errorf(field, "expected type, status, reason or ref")
+	}
+
+	node := comparison{field: field.value}
+	switch {
+	case p.accept("=="):
+	case p.accept("!="):
+		node.negate = true
+	default:
+		if tok := p.next(); tok.kind != tokenIdent || tok.value != "in" {
+			return nil, p.errorf(tok, "expected ==, != or in")
+		}
+
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+
+		for {
+			tok := p.next()
+			if tok.kind != tokenString {
+				return nil, p.errorf(tok, "expected a string")
+			}
+			node.values = append(node.values, tok.value)
+
+			if !p.accept(",") {
+				break
+			}
+		}
+
+		return node, p.expect("]")
+	}
+
+	tok := p.next()
+	if tok.kind != tokenString {
+		return nil, p.errorf(tok, "expected a string")
+	}
+
+	node.values = []string{tok.value}
+	return node, nil
+}
@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestExpressionEvaluate(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created"},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted},
+		{Type: ConditionType("Certificate"), Status: ConditionLocked, Ref: "cert-1"},
+	}
+
+	cases := map[string]bool{
+		`all(type in ["Bucket", "DNS"], status == "Completed") && none(status == "Error")`: true,
+		`all(status == "Completed")`:                                                    false,
+		`all(type == "Missing", status == "Completed")`:                                 true,
+		`any(type == "Missing")`:                                                        false,
+		`any(type == "Certificate", ref == "cert-1")`:                                   true,
+		`any(type == "Bucket", status in ["Created", "Completed"] && reason != "")`:     true,
+		`none(status != "Completed" && !(type == "Certificate"))`:                       true,
+		`!any(status == "Locked") || any(type == "DNS")`:                                true,
+		`(any(status == "Error") || any(status == "Locked")) && none(type == "Bucket")`: false,
+		"any(reason == \"Bucket \\\"created\\\"\")":                                     false,
+	}
+
+	for source, expected := range cases {
+		expr, err := CompileExpression(source)
+		if err != nil {
+			t.Errorf("Unexpected error compiling %s: %s", source, err)
+			continue
+		}
+
+		if actual := expr.Evaluate(conditions); actual != expected {
+			t.Errorf("Expected %s to be %t", source, expected)
+		}
+	}
+}
+
+func TestExpressionCompileErrors(t *testing.T) {
+	sources := []string{
+		``,
+		`all(`,
+		`all(status)`,
+		`all(status == Completed)`,
+		`all(color == "Blue")`,
+		`every(status == "Completed")`,
+		`all(status in ["Completed")`,
+		`all(status == "Completed") &&`,
+		`all(status == "Completed"))`,
+		`all(status == "Completed`,
+		`all(status == "Completed") & any(type == "DNS")`,
+	}
+
+	for _, source := range sources {
+		if _, err := CompileExpression(source); !errors.Is(err, InvalidExpressionErr) {
+			t.Errorf("Expected %q to be invalid, got: %v", source, err)
+		}
+	}
+}
+
+func TestExpressionJSON(t *testing.T) {
+	var spec struct {
+		Ready *Expression `json:"ready"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"ready": "none(status == \"Error\")"}`), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if !spec.Ready.Evaluate(Conditions{{Type: ConditionType("Bucket"), Status: ConditionCompleted}}) {
+		t.Error("Expected the unmarshaled expression to be compiled")
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"ready":"none(status == \"Error\")"}` {
+		t.Error("Unexpected JSON: ", string(data))
+	}
+
+	if err := json.Unmarshal([]byte(`{"ready": "none("}`), &spec); !errors.Is(err, InvalidExpressionErr) {
+		t.Error("Expected invalid expressions to fail to unmarshal, got: ", err)
+	}
+}
+
+func TestExpressionPredicate(t *testing.T) {
+	p := ExpressionPredicate(MustCompileExpression(`any(type == "Bucket", status == "Completed")`))
+
+	res := newTestResource("predicate")
+	if p.Create(event.CreateEvent{Object: res}) {
+		t.Error("Expected the resource to be filtered out")
+	}
+
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	if !p.Update(event.UpdateEvent{ObjectOld: res, ObjectNew: res}) {
+		t.Error("Expected the resource to be let through")
+	}
+}
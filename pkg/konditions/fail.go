@@ -0,0 +1,57 @@
+package konditions
+
+import (
+	"context"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FailAll sets every condition that isn't in a terminal status to ConditionError with the reason given, and returns
+// the types of the conditions that were failed. Conditions that are already terminal keep their status and reason.
+// Locked conditions are failed as well, even with the StrictTransitions feature enabled.
+//
+// It's meant for the catastrophic failures that affect the whole resource, bad credentials or a missing CRD, where
+// continuing to work on each condition is pointless.
+//
+//	if apierrors.IsUnauthorized(err) {
+//		res.Status.Conditions.FailAll("Credentials rejected by the provider")
+//	}
+func (c *Conditions) FailAll(reason string) []ConditionType {
+	if c == nil {
+		return nil
+	}
+
+	var failed []ConditionType
+	for _, condition := range *c {
+		if condition.IsTerminal() {
+			continue
+		}
+
+		condition.Status = ConditionError
+		condition.Reason = reason
+		condition.LastTransitionTime = meta.Time{}
+		if c.setCondition(condition, true) == nil {
+			failed = append(failed, condition.Type)
+		}
+	}
+
+	return failed
+}
+
+// FailObject fails every condition of the resource, see FailAll, and updates its status once. No lock is taken: the
+// conditions that are locked are failed as well, the tasks working on them are expected to find out when they
+// release their lock.
+//
+//	if err := konditions.FailObject(ctx, reconciler.Client, &res, "CRD buckets.s3.example.com is not installed"); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+// The status is written through the status subresource, like the default persister of the Lock.
+func FailObject(ctx context.Context, c client.Client, obj ConditionalResource, reason string) error {
+	if len(obj.Conditions().FailAll(reason)) == 0 {
+		return nil
+	}
+
+	return StatusPersister{Client: c}.Persist(ctx, obj)
+}
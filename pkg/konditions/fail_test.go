@@ -0,0 +1,54 @@
+package konditions
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestFailAll(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionLocked},
+		{Type: ConditionType("DNS"), Status: ConditionInitialized},
+		{Type: ConditionType("Certificate"), Status: ConditionTerminated, Reason: "Certificate revoked"},
+	}
+
+	failed := conditions.FailAll("Credentials rejected")
+	if !slices.Equal(failed, []ConditionType{"Bucket", "DNS"}) {
+		t.Error("Unexpected conditions failed: ", failed)
+	}
+
+	for _, ct := range failed {
+		if condition := conditions.MustType(ct); condition.Status != ConditionError || condition.Reason != "Credentials rejected" {
+			t.Error("Expected the condition to be failed, got: ", condition)
+		}
+	}
+
+	if condition := conditions.MustType(ConditionType("Certificate")); condition.Status != ConditionTerminated || condition.Reason != "Certificate revoked" {
+		t.Error("Expected the terminal condition to be kept, got: ", condition)
+	}
+}
+
+func TestFailObject(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("fail")
+	res.Status.Conditions = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionInitialized},
+	}
+	c := newTestClient(res)
+
+	if err := FailObject(ctx, c, res, "CRD not installed"); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionError) {
+		t.Error("Expected the condition to be failed, got: ", stored.Conditions())
+	}
+}
@@ -0,0 +1,180 @@
+package konditions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var FencedOutErr = errors.New("Condition's lock was taken over by another writer")
+
+// FencingTokenAttribute holds the fencing token of a locked condition, see WithFencing.
+const FencingTokenAttribute = "konditionner.io/fencing-token"
+
+// WithFencing makes the lock write a random token, a nonce, to the attributes of the condition when it is locked.
+// Releasing the lock is then a conditional patch of the status: the patch only applies if the condition stored in
+// the Kubernetes API still holds the token. If another writer took over the condition in the meantime, a second
+// lock that considered the first one stale for instance, Execute returns an error wrapping FencedOutErr and the
+// status isn't overwritten.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithFencing())
+//	err := lock.Execute(ctx, createBucket)
+//	if errors.Is(err, konditions.FencedOutErr) {
+//		// ... Someone else is working on the bucket, the result of this task was discarded ...
+//	}
+//
+// This strengthens the advisory lock without requiring a Lease. The conditional patch is a JSON patch that replaces
+// the condition of the lock and nothing else, the conditions of the resource need to be stored in
+// `status.conditions`. It is sent to the status subresource, or to the resource itself when the lock is configured
+// with the ObjectPersister. What other writers changed in the status while the task ran is kept, but so are the
+// changes the task made to the rest of the status: they aren't written by the release and the resource is replaced
+// by what the Kubernetes API stored. The token is removed from the condition when the lock is released.
+//
+// The token is tested where the condition was when it was locked. If another writer moved it, by removing or adding
+// conditions before it, the lock finds where it is now and sends the patch again.
+func WithFencing() LockOption {
+	return func(l *Lock) {
+		l.fencing = true
+	}
+}
+
+// Returns a new random fencing token.
+func newFencingToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// maxFencedAttempts is how many times the fenced patch is sent when the condition moved in the stored resource.
+const maxFencedAttempts = 3
+
+// fencedPersister writes the condition of the lock with a JSON patch that tests its fencing token before replacing
+// it.
+type fencedPersister struct {
+	client      client.Client
+	subresource bool
+	ct          ConditionType
+	index       int
+	token       string
+
+	// When set, the patch also tests the resourceVersion, see CommitWithResourceVersion.
+	resourceVersion string
+}
+
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+func (p fencedPersister) Persist(ctx context.Context, obj ConditionalResource) error {
+	if p.client == nil {
+		return fmt.Errorf("%w: WithFencing patches the resource", ClientRequiredErr)
+	}
+
+	o, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+
+	// Only the condition is written, the rest of the status may have been changed by other writers since the lock
+	// was acquired. A condition that isn't on the resource anymore was removed by the lock, see RemoveOnRelease.
+	var released *Condition
+	if condition, ok := obj.Conditions().GetType(p.ct); ok {
+		released = &condition
+	}
+
+	index := p.index
+	for attempt := 1; ; attempt++ {
+		err = p.patch(ctx, o, index, released)
+		if err == nil {
+			return nil
+		}
+
+		// The patch can fail for many reasons, the resource is fetched to find out if the lock was taken over.
+		existing := o.DeepCopyObject().(client.Object)
+		if getErr := p.client.Get(ctx, client.ObjectKeyFromObject(o), existing); getErr != nil {
+			return err
+		}
+
+		stored, fenced := p.find(existing)
+		if fenced {
+			return fmt.Errorf("%w: %s: %w", FencedOutErr, p.ct, err)
+		}
+
+		if p.resourceVersion != "" && existing.GetResourceVersion() != p.resourceVersion {
+			return fmt.Errorf("%w: %s was locked at resourceVersion %s: %w", ResourceModifiedErr, p.ct, p.resourceVersion, err)
+		}
+
+		if stored < 0 || stored == index || attempt >= maxFencedAttempts {
+			return err
+		}
+		index = stored
+	}
+}
+
+// Sends the JSON patch that replaces the condition at the index, or removes it when released is nil, if it holds the
+// token.
+func (p fencedPersister) patch(ctx context.Context, o client.Object, index int, released *Condition) error {
+	path := fmt.Sprintf("/status/conditions/%d", index)
+	operations := []jsonPatchOperation{
+		{
+			Op:    "test",
+			Path:  fmt.Sprintf("%s/attributes/%s", path, escapeJSONPointer(FencingTokenAttribute)),
+			Value: p.token,
+		},
+	}
+
+	if released != nil {
+		operations = append(operations, jsonPatchOperation{Op: "replace", Path: path, Value: released})
+	} else {
+		operations = append(operations, jsonPatchOperation{Op: "remove", Path: path})
+	}
+
+	if p.resourceVersion != "" {
+		operations = append([]jsonPatchOperation{{Op: "test", Path: "/metadata/resourceVersion", Value: p.resourceVersion}}, operations...)
+	}
+
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return err
+	}
+
+	if p.subresource {
+		return p.client.Status().Patch(ctx, o, client.RawPatch(types.JSONPatchType, patch))
+	}
+
+	return p.client.Patch(ctx, o, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// Returns the position of the condition holding the token in the stored resource, -1 if it can't tell. Fenced is
+// true if the condition doesn't hold the token anymore.
+func (p fencedPersister) find(existing client.Object) (index int, fenced bool) {
+	resource, ok := existing.(ConditionalResource)
+	if !ok {
+		return -1, false
+	}
+
+	for i, condition := range *resource.Conditions() {
+		if condition.Type == p.ct && condition.Attributes[FencingTokenAttribute] == p.token {
+			return i, false
+		}
+	}
+
+	return -1, true
+}
+
+// Escapes a key so it can be used as a segment of a JSON pointer, RFC 6901.
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
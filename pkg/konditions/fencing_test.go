@@ -0,0 +1,239 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLockWithFencing(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("fencing")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithFencing())
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		locked := stored.Conditions().MustType(ConditionType("Bucket"))
+		if locked.Attributes[FencingTokenAttribute] == "" {
+			t.Error("Expected the locked condition to hold a fencing token, got: ", locked.Attributes)
+		}
+
+		condition.Status = ConditionCompleted
+		condition.Reason = "Bucket created"
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := stored.Conditions().MustType(ConditionType("Bucket"))
+	if condition.Status != ConditionCompleted {
+		t.Error("Expected the condition to be completed, got: ", condition.Status)
+	}
+
+	if _, ok := condition.Attributes[FencingTokenAttribute]; ok {
+		t.Error("Expected the fencing token to be removed once the lock is released")
+	}
+}
+
+func TestLockWithFencingTakenOver(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("fencing")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithFencing())
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		// Another writer takes the condition over while the task runs.
+		var other testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &other); err != nil {
+			t.Fatal(err)
+		}
+
+		taken := other.Conditions().MustType(ConditionType("Bucket"))
+		taken.Attributes[FencingTokenAttribute] = "another-writer"
+		if err := other.Conditions().SetCondition(taken); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.Status().Update(ctx, &other); err != nil {
+			t.Fatal(err)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if !errors.Is(err, FencedOutErr) {
+		t.Fatal("Expected the release to be fenced out, got: ", err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := stored.Conditions().MustType(ConditionType("Bucket"))
+	if condition.Status != ConditionLocked || condition.Attributes[FencingTokenAttribute] != "another-writer" {
+		t.Error("Expected the condition of the other writer to be kept, got: ", condition)
+	}
+}
+
+func TestLockWithFencingConditionMoved(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("fencing")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Legacy"), Status: ConditionCompleted})
+
+	cases := map[string]struct {
+		move     func(conditions *Conditions)
+		expected error
+	}{
+		"removed": {
+			move: func(conditions *Conditions) {
+				conditions.RemoveConditionWith(ConditionType("Legacy"))
+			},
+		},
+		"taken": {
+			move: func(conditions *Conditions) {
+				conditions.RemoveConditionWith(ConditionType("Legacy"))
+				taken := conditions.MustType(ConditionType("Bucket"))
+				taken.Attributes[FencingTokenAttribute] = "another-writer"
+				conditions.SetConditionForce(taken)
+			},
+			expected: FencedOutErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := res.DeepCopyObject().(*testResource)
+			res.Name = name
+			res.ResourceVersion = ""
+			c := newTestClient(res)
+
+			lock := NewLock(res, c, ConditionType("Bucket"), WithFencing())
+			err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+				// Another writer moves the condition while the task runs.
+				var other testResource
+				if err := c.Get(ctx, client.ObjectKeyFromObject(res), &other); err != nil {
+					t.Fatal(err)
+				}
+
+				tc.move(other.Conditions())
+				if err := c.Status().Update(ctx, &other); err != nil {
+					t.Fatal(err)
+				}
+
+				condition.Status = ConditionCompleted
+				return condition, nil
+			})
+
+			if tc.expected != nil {
+				if !errors.Is(err, tc.expected) {
+					t.Error("Expected the release to be fenced out, got: ", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("Expected the condition to be found where it moved, got: ", err)
+			}
+
+			var stored testResource
+			if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+				t.Fatal(err)
+			}
+
+			if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+				t.Error("Expected the condition to be released, got: ", stored.Conditions())
+			}
+		})
+	}
+}
+
+func TestLockWithFencingConcurrentWriter(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("fencing")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Legacy"), Status: ConditionCompleted})
+
+	cases := map[string]struct {
+		write    func(conditions *Conditions)
+		expected func(conditions Conditions) bool
+	}{
+		"added": {
+			write: func(conditions *Conditions) {
+				conditions.SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted})
+			},
+			expected: func(conditions Conditions) bool {
+				return conditions.TypeHasStatus(ConditionType("DNS"), ConditionCompleted) && len(conditions) == 3
+			},
+		},
+		"removed": {
+			write: func(conditions *Conditions) {
+				conditions.RemoveConditionWith(ConditionType("Legacy"))
+			},
+			expected: func(conditions Conditions) bool {
+				_, ok := conditions.GetType(ConditionType("Legacy"))
+				return !ok && len(conditions) == 1
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			res := res.DeepCopyObject().(*testResource)
+			res.Name = name
+			res.ResourceVersion = ""
+			c := newTestClient(res)
+
+			lock := NewLock(res, c, ConditionType("Bucket"), WithFencing())
+			err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+				var other testResource
+				if err := c.Get(ctx, client.ObjectKeyFromObject(res), &other); err != nil {
+					t.Fatal(err)
+				}
+
+				tc.write(other.Conditions())
+				if err := c.Status().Update(ctx, &other); err != nil {
+					t.Fatal(err)
+				}
+
+				condition.Status = ConditionCompleted
+				return condition, nil
+			})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var stored testResource
+			if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+				t.Fatal(err)
+			}
+
+			if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+				t.Error("Expected the condition to be released, got: ", stored.Conditions())
+			}
+
+			if !tc.expected(*stored.Conditions()) {
+				t.Error("Expected the change of the other writer to survive the release, got: ", stored.Conditions())
+			}
+
+			if !tc.expected(*res.Conditions()) {
+				t.Error("Expected the resource to hold what was stored, got: ", res.Conditions())
+			}
+		})
+	}
+}
@@ -1,5 +1,12 @@
 package konditions
 
+import (
+	"errors"
+	"fmt"
+)
+
+var ConditionNotFoundErr = errors.New("Condition not found")
+
 // Find or initialize a condition for the type given.
 // If a condition exists for the type given, it will return a *copy* of the condition
 // If none exists, it will create a new condition for the type specified and the status
@@ -56,6 +63,9 @@ func (c Conditions) FindStatus(conditionStatus ConditionStatus) *Condition {
 // Even though a pointer is returned by the method, note that the value returned points to
 // a *copy* of the condition in Conditions. This is because FindStatus can return an empty result and
 // it's more explicit to return `nil` then it is to return a zered Condition.
+//
+// Deprecated: The pointer suggests that modifying the condition modifies the set, it doesn't. Use GetType,
+// which returns the condition by value, or MustType. FindType will be kept until the next major version.
 func (c Conditions) FindType(conditionType ConditionType) *Condition {
 	for i := range c {
 		if c[i].Type == conditionType {
@@ -66,6 +76,40 @@ func (c Conditions) FindType(conditionType ConditionType) *Condition {
 	return nil
 }
 
+// Returns a copy of the condition that matches `ConditionType`. The boolean is false if the set doesn't have
+// a condition of that type.
+//
+// The condition is returned by value: modifying it doesn't modify the set, the condition needs to be set back
+// with SetCondition.
+//
+//	condition, ok := conditions.GetType(ConditionType("Bucket"))
+//	if !ok {
+//		// ... The bucket condition was never set ...
+//	}
+func (c Conditions) GetType(conditionType ConditionType) (Condition, bool) {
+	for i := range c {
+		if c[i].Type == conditionType {
+			return *c[i].DeepCopy(), true
+		}
+	}
+
+	return Condition{}, false
+}
+
+// Returns a copy of the condition that matches `ConditionType`, like GetType, but panics with an error
+// wrapping ConditionNotFoundErr if the set doesn't have a condition of that type. It is meant for the code
+// paths where the condition is known to exist, tests for instance.
+//
+//	bucket := conditions.MustType(ConditionType("Bucket"))
+func (c Conditions) MustType(conditionType ConditionType) Condition {
+	condition, ok := c.GetType(conditionType)
+	if !ok {
+		panic(fmt.Errorf("%w: %s", ConditionNotFoundErr, conditionType))
+	}
+
+	return condition
+}
+
 // Check if the condition with ConditionType matches the status provided.
 //
 // This is an utility method that can be useful if the condition is not needed and the
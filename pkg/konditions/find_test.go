@@ -1,6 +1,7 @@
 package konditions
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -177,3 +178,51 @@ func TestAnyWithStatus(t *testing.T) {
 		t.Error("Expected to return false")
 	}
 }
+
+func TestGetType(t *testing.T) {
+	conditions := Conditions{
+		{
+			Type:       ConditionType("Bucket"),
+			Status:     ConditionCompleted,
+			Attributes: map[string]string{"region": "us-east-1"},
+		},
+	}
+
+	condition, ok := conditions.GetType(ConditionType("Bucket"))
+	if !ok || condition.Status != ConditionCompleted {
+		t.Error("Expected to find the completed condition, found: ", condition)
+	}
+
+	condition.Status = ConditionError
+	condition.Attributes["region"] = "eu-west-1"
+	if conditions[0].Status != ConditionCompleted || conditions[0].Attributes["region"] != "us-east-1" {
+		t.Error("Expected the condition returned to be a copy, the set was modified: ", conditions[0])
+	}
+
+	condition, ok = conditions.GetType(ConditionType("DNS"))
+	if ok || condition.Type != "" {
+		t.Error("Expected to find no condition, found: ", condition)
+	}
+}
+
+func TestMustType(t *testing.T) {
+	conditions := Conditions{
+		{
+			Type:   ConditionType("Bucket"),
+			Status: ConditionCompleted,
+		},
+	}
+
+	if condition := conditions.MustType(ConditionType("Bucket")); condition.Status != ConditionCompleted {
+		t.Error("Expected the completed condition, found: ", condition)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, ConditionNotFoundErr) {
+			t.Error("Expected MustType to panic with ConditionNotFoundErr, got: ", err)
+		}
+	}()
+
+	conditions.MustType(ConditionType("DNS"))
+}
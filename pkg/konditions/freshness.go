@@ -0,0 +1,103 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var StaleResourceErr = errors.New("Resource is stale")
+
+// FreshnessPolicy decides what Execute does when the resource it was given is behind the one stored by the
+// Kubernetes API, see WithFreshnessCheck.
+type FreshnessPolicy int
+
+const (
+	// RejectStale makes Execute return an error wrapping StaleResourceErr without locking the condition. The
+	// reconciler can return the error, the resource is reconciled again once the cache caught up.
+	RejectStale FreshnessPolicy = iota
+
+	// RefreshStale makes Execute replace the resource with the one read from the Kubernetes API and lock the
+	// condition as it is there. Resources that encode their conditions, like AnnotationConditions, can't be
+	// refreshed: Execute returns an error wrapping UnsupportedResourceErr.
+	RefreshStale
+)
+
+// WithFreshnessCheck configures the lock to read the resource from the reader before locking the condition, and to
+// compare its resourceVersion with the one of the resource given to the lock. The reader is meant to bypass the
+// cache of the manager, mgr.GetAPIReader() for instance:
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithFreshnessCheck(mgr.GetAPIReader(), konditions.RejectStale))
+//
+// A condition locked on a stale resource fails when it is persisted, with a conflict, but a resource that is stale
+// can also hold conditions that were released since: the task would run again on a condition that is completed. The
+// check costs a read to the Kubernetes API on every execution.
+func WithFreshnessCheck(reader client.Reader, policy FreshnessPolicy) LockOption {
+	return func(l *Lock) {
+		l.freshness = &freshnessCheck{reader: reader, policy: policy}
+	}
+}
+
+type freshnessCheck struct {
+	reader client.Reader
+	policy FreshnessPolicy
+}
+
+// Reads the resource from the reader of the freshness check, if the lock has one, and applies its policy when the
+// resource of the lock is stale.
+func (l *Lock) checkFreshness(ctx context.Context) error {
+	if l.freshness == nil {
+		return nil
+	}
+
+	if l.freshness.policy == RefreshStale {
+		return l.refresh(ctx, l.freshness.reader)
+	}
+
+	key := client.ObjectKeyFromObject(l.obj)
+	live := l.obj.DeepCopyObject().(client.Object)
+	if err := l.freshness.reader.Get(ctx, key, live); err != nil {
+		return err
+	}
+
+	if live.GetResourceVersion() != l.obj.GetResourceVersion() {
+		return fmt.Errorf("%w: %s is at resourceVersion %s, the Kubernetes API has %s", StaleResourceErr, key, l.obj.GetResourceVersion(), live.GetResourceVersion())
+	}
+
+	return nil
+}
+
+// Replaces the resource of the lock with the one read from the reader, if it changed, and picks the condition of the
+// lock from it.
+func (l *Lock) refresh(ctx context.Context, reader client.Reader) error {
+	if _, encoded := l.obj.(EncodedResource); encoded {
+		return fmt.Errorf("%w: %T can't be refreshed", UnsupportedResourceErr, l.obj)
+	}
+
+	live := emptyCopy(l.obj)
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(l.obj), live); err != nil {
+		return err
+	}
+
+	if live.GetResourceVersion() == l.obj.GetResourceVersion() {
+		return nil
+	}
+
+	reflect.ValueOf(l.obj).Elem().Set(reflect.ValueOf(live).Elem())
+	l.condition = l.obj.Conditions().FindOrInitializeFor(l.condition.Type)
+	l.tracker.MarkClean()
+
+	return nil
+}
+
+// Returns an empty resource of the same type as the one given. Resources are read into an empty one: the Kubernetes
+// API decodes into the object it's given without zeroing it, fields that aren't set anymore would stay set otherwise.
+func emptyCopy(obj client.Object) client.Object {
+	empty := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(client.Object)
+	empty.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+
+	return empty
+}
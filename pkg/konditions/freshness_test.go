@@ -0,0 +1,86 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLockWithFreshnessCheckRejectStale(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("freshness")
+	c := newTestClient(res)
+	stale := res.DeepCopyObject().(*testResource)
+
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	if err := c.Status().Update(ctx, res); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewLock(stale, c, ConditionType("Bucket"), WithFreshnessCheck(c, RejectStale)).Execute(ctx, func(condition Condition) (Condition, error) {
+		t.Error("Expected the task to not run on a stale resource")
+		return condition, nil
+	})
+
+	if !errors.Is(err, StaleResourceErr) {
+		t.Error("Expected StaleResourceErr, got: ", err)
+	}
+
+	err = NewLock(res, c, ConditionType("DNS"), WithFreshnessCheck(c, RejectStale)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Error("Expected a fresh resource to be locked, got: ", err)
+	}
+}
+
+func TestLockWithFreshnessCheckRefreshStale(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("freshness")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+	c := newTestClient(res)
+	stale := res.DeepCopyObject().(*testResource)
+
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created"})
+	if err := c.Status().Update(ctx, res); err != nil {
+		t.Fatal(err)
+	}
+
+	lock := NewLock(stale, c, ConditionType("Bucket"), WithFreshnessCheck(c, RefreshStale))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if condition.Status != ConditionCompleted {
+			t.Error("Expected the task to receive the condition of the fresh resource, got: ", condition.Status)
+		}
+
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stale.GetResourceVersion() == "" || !stale.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the resource to be refreshed, got: ", stale.Conditions())
+	}
+}
+
+func TestLockWithFreshnessCheckEncodedResource(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("freshness")
+	c := newTestClient(res)
+
+	annotated, err := NewAnnotationConditions(res, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLock(annotated, c, ConditionType("Bucket"), WithFreshnessCheck(c, RefreshStale)).Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if !errors.Is(err, UnsupportedResourceErr) {
+		t.Error("Expected UnsupportedResourceErr, got: ", err)
+	}
+}
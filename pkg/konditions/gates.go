@@ -0,0 +1,200 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var UnknownFeatureErr = errors.New("Unknown feature")
+var LockedConditionErr = errors.New("Condition is locked")
+
+// Feature is a change of behavior that consumers opt into through Gates. Konditionner is embedded in the semantics
+// of the CRDs that use it: a behavior that changes what is stored in the status of a resource is introduced behind
+// a feature, disabled by default, so operators can adopt it when they're ready.
+type Feature string
+
+const (
+	// SortedMarshal makes Conditions serialize to JSON sorted by type, regardless of the order of the conditions in
+	// memory. The order of the conditions depends on the order they were set, which can vary from one reconciliation
+	// to another. GitOps tools, Argo CD for instance, compare the serialized resources and flag reordered conditions
+	// as drift, sorting the conditions guarantees stable output.
+	SortedMarshal Feature = "SortedMarshal"
+
+	// StrictTransitions makes SetCondition refuse to change the status of a condition that is ConditionLocked,
+	// with LockedConditionErr. Only the Lock holding the condition can release it. SetConditionForce can still
+	// change it, to recover from a lock that was never released for instance.
+	StrictTransitions Feature = "StrictTransitions"
+
+	// NoOpDetection makes SetCondition keep the LastTransitionTime of a condition when its status doesn't change,
+	// as the Kubernetes API conventions expect. Setting the same status again, with a different reason for
+	// instance, isn't a transition.
+	NoOpDetection Feature = "NoOpDetection"
+
+	// LegacyConditions makes the conditions decoded from JSON accept the conventions of metav1.Condition: the
+	// True, False and Unknown statuses are mapped with DefaultLegacyMapping, and the message becomes the reason.
+	// See Conditions.Migrate.
+	LegacyConditions Feature = "LegacyConditions"
+)
+
+var knownFeatures = []Feature{SortedMarshal, StrictTransitions, NoOpDetection, LegacyConditions}
+
+// FeatureGates holds the features that are enabled. Gates is the instance used by Konditionner.
+type FeatureGates struct {
+	mu      sync.RWMutex
+	enabled map[Feature]bool
+}
+
+// Gates are the feature gates of Konditionner, every feature is disabled by default. They should be configured once,
+// before the conditions are used, usually from main or an init function:
+//
+//	func init() {
+//		konditions.Gates.Enable(konditions.SortedMarshal, konditions.NoOpDetection)
+//	}
+//
+// FeatureGates implements flag.Value, the gates can be configured from the command line of an operator:
+//
+//	flag.Var(konditions.Gates, "konditions-feature-gates", "Feature gates of Konditionner, e.g. SortedMarshal=true,NoOpDetection=true")
+var Gates = &FeatureGates{enabled: map[Feature]bool{}}
+
+// Enabled returns true if the feature is enabled.
+func (g *FeatureGates) Enabled(feature Feature) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.enabled[feature]
+}
+
+// Enable enables the features given. An error wrapping UnknownFeatureErr is returned if a feature isn't known, none
+// of the features are enabled in that case.
+func (g *FeatureGates) Enable(features ...Feature) error {
+	return g.set(features, true)
+}
+
+// Disable disables the features given. An error wrapping UnknownFeatureErr is returned if a feature isn't known,
+// none of the features are disabled in that case.
+func (g *FeatureGates) Disable(features ...Feature) error {
+	return g.set(features, false)
+}
+
+func (g *FeatureGates) set(features []Feature, enabled bool) error {
+	for _, feature := range features {
+		if !isKnownFeature(feature) {
+			return fmt.Errorf("%w: %s", UnknownFeatureErr, feature)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, feature := range features {
+		g.enabled[feature] = enabled
+	}
+
+	return nil
+}
+
+// Set configures the gates from a comma separated list of features, each with a boolean: SortedMarshal=true,NoOpDetection=false.
+// Features that aren't listed keep their value.
+func (g *FeatureGates) Set(value string) error {
+	settings := map[Feature]bool{}
+	for _, setting := range strings.Split(value, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+
+		name, raw, found := strings.Cut(setting, "=")
+		if !found {
+			return fmt.Errorf("%w: %q is missing a value, expected %s=true", UnknownFeatureErr, setting, name)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid value for feature %s: %w", name, err)
+		}
+
+		feature := Feature(strings.TrimSpace(name))
+		if !isKnownFeature(feature) {
+			return fmt.Errorf("%w: %s", UnknownFeatureErr, feature)
+		}
+
+		settings[feature] = enabled
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for feature, enabled := range settings {
+		g.enabled[feature] = enabled
+	}
+
+	return nil
+}
+
+// String returns the value of every known feature, in the format accepted by Set.
+func (g *FeatureGates) String() string {
+	if g == nil {
+		return ""
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	settings := make([]string, 0, len(knownFeatures))
+	for _, feature := range knownFeatures {
+		settings = append(settings, fmt.Sprintf("%s=%t", feature, g.enabled[feature]))
+	}
+	sort.Strings(settings)
+
+	return strings.Join(settings, ",")
+}
+
+// KnownFeatures returns the features that can be enabled.
+func KnownFeatures() []Feature {
+	return append([]Feature(nil), knownFeatures...)
+}
+
+func isKnownFeature(feature Feature) bool {
+	for _, known := range knownFeatures {
+		if known == feature {
+			return true
+		}
+	}
+
+	return false
+}
+
+const modulePath = "github.com/pier-oliviert/konditionner"
+
+// Version returns the version of Konditionner the binary was built with, as recorded by the Go toolchain, for
+// support tooling and bug reports. It returns "(devel)" when the version isn't known, when Konditionner is built
+// from a local checkout for instance.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+
+		return dep.Version
+	}
+
+	return "(devel)"
+}
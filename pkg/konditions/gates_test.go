@@ -0,0 +1,102 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func enableFeatures(t *testing.T, features ...Feature) {
+	t.Helper()
+
+	if err := Gates.Enable(features...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := Gates.Disable(features...); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestFeatureGates(t *testing.T) {
+	gates := &FeatureGates{enabled: map[Feature]bool{}}
+
+	if err := gates.Set("SortedMarshal=true, NoOpDetection=false"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gates.Enabled(SortedMarshal) || gates.Enabled(NoOpDetection) {
+		t.Error("Unexpected gates: ", gates)
+	}
+
+	if value := gates.String(); value != "LegacyConditions=false,NoOpDetection=false,SortedMarshal=true,StrictTransitions=false" {
+		t.Error("Unexpected string: ", value)
+	}
+
+	if err := gates.Set("Teleport=true"); !errors.Is(err, UnknownFeatureErr) {
+		t.Error("Expected an unknown feature error, got: ", err)
+	}
+
+	if err := gates.Enable(NoOpDetection, Feature("Teleport")); !errors.Is(err, UnknownFeatureErr) {
+		t.Error("Expected an unknown feature error, got: ", err)
+	}
+
+	if gates.Enabled(NoOpDetection) {
+		t.Error("Expected none of the features to be enabled when one is unknown")
+	}
+}
+
+func TestStrictTransitions(t *testing.T) {
+	enableFeatures(t, StrictTransitions)
+
+	ctx := context.Background()
+	res := newTestResource("strict")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		err := res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+		if !errors.Is(err, LockedConditionErr) {
+			t.Error("Expected the locked condition to be refused, got: ", err)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal("Expected the lock to release the condition, got: ", err)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed, got: ", res.Conditions())
+	}
+}
+
+func TestNoOpDetection(t *testing.T) {
+	enableFeatures(t, NoOpDetection)
+
+	transition := meta.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Created", LastTransitionTime: transition},
+	}
+
+	if err := conditions.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Still created"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if condition := conditions.MustType(ConditionType("Bucket")); !condition.LastTransitionTime.Equal(&transition) {
+		t.Error("Expected the transition time to be kept, got: ", condition.LastTransitionTime)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if Version() == "" {
+		t.Error("Expected a version")
+	}
+}
@@ -0,0 +1,61 @@
+package konditions
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Returns the conditions whose ObservedGeneration is behind the generation of the object, that is,
+// conditions that were reconciled before the latest change to the spec of the object. The conditions
+// returned are copies.
+//
+// This is the standard answer to "the spec changed, what needs to be reconciled again?". Contrary to
+// the Invalidator, which only looks at the inputs a condition depends on, StaleFor considers any change
+// to the spec.
+//
+//	for _, condition := range res.Status.Conditions.StaleFor(&res) {
+//		log.Info("Condition is stale", "type", condition.Type, "observed", condition.ObservedGeneration)
+//	}
+func (c Conditions) StaleFor(obj client.Object) Conditions {
+	stale := Conditions{}
+	for _, condition := range c {
+		if condition.ObservedGeneration < obj.GetGeneration() {
+			stale = append(stale, *condition.DeepCopy())
+		}
+	}
+
+	return stale
+}
+
+// Resets the stale conditions, see StaleFor, back to ConditionInitialized so they are reconciled
+// again. The types of the conditions that were reset are returned.
+//
+// Conditions that are already initialized are left untouched, as well as conditions that can't be
+// worked on at the moment: locked, suspended, terminated and exhausted conditions. Errored conditions are reset,
+// since the change to the spec may be what fixes the error.
+//
+// The changes are only made in memory, it is up to the caller to persist the resource.
+//
+//	if stale := res.Status.Conditions.MarkStale(&res, "Spec changed"); len(stale) > 0 {
+//		if err := reconciler.Status().Update(ctx, &res); err != nil {
+//			return ctrl.Result{}, err
+//		}
+//	}
+func (c *Conditions) MarkStale(obj client.Object, reason string) []ConditionType {
+	var reset []ConditionType
+
+	for _, condition := range c.StaleFor(obj) {
+		if condition.StatusIsOneOf(ConditionInitialized, ConditionLocked, ConditionSuspended, ConditionTerminated, ConditionExhausted) {
+			continue
+		}
+
+		c.SetConditionForce(Condition{
+			Type:               condition.Type,
+			Status:             ConditionInitialized,
+			Reason:             reason,
+			ObservedGeneration: condition.ObservedGeneration,
+		})
+		reset = append(reset, condition.Type)
+	}
+
+	return reset
+}
@@ -0,0 +1,69 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStaleFor(t *testing.T) {
+	res := newTestResource("stale")
+	res.SetGeneration(3)
+	res.Status.Conditions = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, ObservedGeneration: 3},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted, ObservedGeneration: 2},
+		{Type: ConditionType("Volume"), Status: ConditionInitialized},
+	}
+
+	stale := res.Conditions().StaleFor(res)
+	if len(stale) != 2 || stale[0].Type != ConditionType("DNS") || stale[1].Type != ConditionType("Volume") {
+		t.Error("Unexpected stale conditions: ", stale)
+	}
+}
+
+func TestMarkStale(t *testing.T) {
+	res := newTestResource("stale")
+	res.SetGeneration(3)
+	res.Status.Conditions = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, ObservedGeneration: 3},
+		{Type: ConditionType("DNS"), Status: ConditionError, ObservedGeneration: 2},
+		{Type: ConditionType("Volume"), Status: ConditionLocked, ObservedGeneration: 2},
+		{Type: ConditionType("Pod"), Status: ConditionTerminated, ObservedGeneration: 2},
+	}
+
+	reset := res.Conditions().MarkStale(res, "Spec changed")
+	if len(reset) != 1 || reset[0] != ConditionType("DNS") {
+		t.Error("Unexpected conditions reset: ", reset)
+	}
+
+	condition := res.Conditions().FindType(ConditionType("DNS"))
+	if condition.Status != ConditionInitialized || condition.Reason != "Spec changed" {
+		t.Error("Expected the condition to be reset, got: ", condition)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Volume"), ConditionLocked) {
+		t.Error("Expected the locked condition to be left untouched")
+	}
+}
+
+func TestLockSetsObservedGeneration(t *testing.T) {
+	res := newTestResource("stale")
+	res.SetGeneration(4)
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if condition := res.Conditions().FindType(ConditionType("Bucket")); condition.ObservedGeneration != 4 {
+		t.Error("Expected the observed generation to be set, got: ", condition.ObservedGeneration)
+	}
+
+	if stale := res.Conditions().StaleFor(res); len(stale) != 0 {
+		t.Error("Expected no stale conditions, got: ", stale)
+	}
+}
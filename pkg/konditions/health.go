@@ -0,0 +1,90 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var UnhealthyConditionErr = errors.New("Condition is unhealthy")
+
+// HealthRule validates the conditions of a resource for a HealthCheck. It returns an error describing
+// why the conditions aren't healthy, nil otherwise.
+type HealthRule func(conditions Conditions) error
+
+// RequireStatus is a HealthRule that requires the condition with the given type to exist and to have one
+// of the statuses given.
+func RequireStatus(ct ConditionType, statuses ...ConditionStatus) HealthRule {
+	return func(conditions Conditions) error {
+		condition := conditions.FindType(ct)
+		if condition == nil {
+			return fmt.Errorf("%w: %s doesn't exist", UnhealthyConditionErr, ct)
+		}
+
+		if !condition.StatusIsOneOf(statuses...) {
+			return fmt.Errorf("%w: %s is %s, expected one of %v", UnhealthyConditionErr, ct, condition.Status, statuses)
+		}
+
+		return nil
+	}
+}
+
+// ForbidStatus is a HealthRule that fails if any condition has the status given.
+func ForbidStatus(status ConditionStatus) HealthRule {
+	return func(conditions Conditions) error {
+		if condition := conditions.FindStatus(status); condition != nil {
+			return fmt.Errorf("%w: %s is %s: %s", UnhealthyConditionErr, condition.Type, status, condition.Reason)
+		}
+
+		return nil
+	}
+}
+
+// HealthCheck returns a healthz.Checker that reports healthy only when the conditions of the resource with
+// the given key satisfy every rule. The object given is only used to know the kind of the resource, it is
+// copied for every check.
+//
+// This is useful for operators whose readiness depends on a singleton resource, a configuration CR for instance:
+//
+//	check := konditions.HealthCheck(mgr.GetClient(), types.NamespacedName{Namespace: "operator", Name: "config"}, &Config{},
+//		konditions.RequireStatus(ConditionType("Credentials"), konditions.ConditionCompleted),
+//		konditions.ForbidStatus(konditions.ConditionError),
+//	)
+//
+//	err := mgr.AddReadyzCheck("config", check)
+//
+// The check fails if the resource can't be fetched.
+func HealthCheck(c client.Reader, key types.NamespacedName, obj ConditionalResource, rules ...HealthRule) healthz.Checker {
+	return func(req *http.Request) error {
+		res := obj.DeepCopyObject().(ConditionalResource)
+		if err := c.Get(req.Context(), key, res); err != nil {
+			return fmt.Errorf("could not fetch %s: %w", key, err)
+		}
+
+		var errs []error
+		for _, rule := range rules {
+			if err := rule(*res.Conditions()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// ExpressionRule is a HealthRule that requires the conditions to satisfy the expression, see Expression.
+//
+//	rule := konditions.ExpressionRule(konditions.MustCompileExpression(`none(status == "Error")`))
+func ExpressionRule(expr *Expression) HealthRule {
+	return func(conditions Conditions) error {
+		if !expr.Evaluate(conditions) {
+			return fmt.Errorf("%w: conditions don't satisfy %s", UnhealthyConditionErr, expr)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package konditions
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestHealthCheck(t *testing.T) {
+	res := newTestResource("config")
+	res.Status.Conditions = Conditions{
+		{Type: ConditionType("Credentials"), Status: ConditionCompleted},
+		{Type: ConditionType("Webhook"), Status: ConditionCreated},
+	}
+	c := newTestClient(res)
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	check := HealthCheck(c, client.ObjectKeyFromObject(res), &testResource{},
+		RequireStatus(ConditionType("Credentials"), ConditionCompleted),
+		ForbidStatus(ConditionError),
+	)
+
+	if err := check(req); err != nil {
+		t.Error("Expected the resource to be healthy, got: ", err)
+	}
+
+	check = HealthCheck(c, client.ObjectKeyFromObject(res), &testResource{},
+		RequireStatus(ConditionType("Webhook"), ConditionCompleted),
+		RequireStatus(ConditionType("Missing"), ConditionCompleted),
+	)
+
+	if err := check(req); !errors.Is(err, UnhealthyConditionErr) {
+		t.Error("Expected the resource to be unhealthy, got: ", err)
+	}
+
+	check = HealthCheck(c, client.ObjectKeyFromObject(newTestResource("missing")), &testResource{})
+	if err := check(req); err == nil {
+		t.Error("Expected the check to fail when the resource doesn't exist")
+	}
+}
+
+func TestExpressionRule(t *testing.T) {
+	rule := ExpressionRule(MustCompileExpression(`none(status == "Error")`))
+
+	if err := rule(Conditions{{Type: ConditionType("Bucket"), Status: ConditionCompleted}}); err != nil {
+		t.Error("Expected the rule to pass, got: ", err)
+	}
+
+	if err := rule(Conditions{{Type: ConditionType("Bucket"), Status: ConditionError}}); !errors.Is(err, UnhealthyConditionErr) {
+		t.Error("Expected the rule to fail, got: ", err)
+	}
+}
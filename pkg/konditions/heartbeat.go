@@ -0,0 +1,89 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Heartbeat sets the LastHeartbeatTime of the condition to now. The status, and the LastTransitionTime, are left
+// untouched: a heartbeat isn't a transition.
+func (c *Condition) Heartbeat() {
+	heartbeat := now()
+	c.LastHeartbeatTime = &heartbeat
+}
+
+// SilentFor returns how long the condition went without a heartbeat, or a transition if it never had a heartbeat.
+//
+//	if condition.Status == konditions.ConditionLocked && condition.SilentFor(time.Now()) > 10*time.Minute {
+//		// ... The task working on the condition is most likely stuck ...
+//	}
+func (c Condition) SilentFor(now time.Time) time.Duration {
+	last := c.LastTransitionTime.Time
+	if c.LastHeartbeatTime != nil && c.LastHeartbeatTime.After(last) {
+		last = c.LastHeartbeatTime.Time
+	}
+
+	return now.Sub(last)
+}
+
+// IsStuck returns true if the condition is still in progress and went without a transition, or a heartbeat, for
+// longer than the threshold. Conditions that are terminal, completed or suspended are never stuck, nor is any
+// condition when the threshold isn't positive.
+//
+//	if condition.IsStuck(time.Now(), 15*time.Minute) {
+//		log.Info("Condition is stuck", "type", condition.Type, "status", condition.Status)
+//	}
+func (c Condition) IsStuck(now time.Time, threshold time.Duration) bool {
+	if threshold <= 0 || c.Class() != ClassInProgress {
+		return false
+	}
+
+	return c.SilentFor(now) > threshold
+}
+
+// Progress reports that the task holding the lock is alive and making progress. The LastHeartbeatTime of the locked
+// condition is set and the resource is persisted. The reason of the condition is replaced when a reason is given,
+// the condition stays locked either way.
+//
+//	err := lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		for i, part := range parts {
+//			if err := upload(ctx, part); err != nil {
+//				return condition, err
+//			}
+//
+//			if err := lock.Progress(ctx, fmt.Sprintf("Uploaded %d/%d parts", i+1, len(parts))); err != nil {
+//				return condition, err
+//			}
+//		}
+//		// ...
+//	})
+//
+// Progress is meant for tasks that can report their progress, a Watchdog is the alternative for the ones that can't.
+// Both shouldn't be combined: the heartbeats of the watchdog change the resource behind the back of the task.
+func (l *Lock) Progress(ctx context.Context, reason string) error {
+	condition, ok := l.obj.Conditions().GetType(l.condition.Type)
+	if !ok || condition.Status != ConditionLocked {
+		return fmt.Errorf("%w: %s isn't locked", LockNotReleasedErr, l.condition.Type)
+	}
+
+	if reason != "" {
+		condition.Reason = reason
+	}
+	condition.Heartbeat()
+
+	if err := l.obj.Conditions().setCondition(condition, true); err != nil {
+		return err
+	}
+
+	if err := l.persistWith(ctx, l.releasePersister(condition.Type)); err != nil {
+		return err
+	}
+
+	// The write is the lock's own, the precondition of the release moves along.
+	if l.resourceVersion != "" {
+		l.resourceVersion = l.obj.GetResourceVersion()
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConditionHeartbeat(t *testing.T) {
+	transition := meta.NewTime(time.Now().Add(-2 * time.Hour))
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionLocked, LastTransitionTime: transition}
+
+	if silent := condition.SilentFor(time.Now()); silent < 2*time.Hour {
+		t.Error("Expected the condition to be silent since its transition, got: ", silent)
+	}
+
+	condition.Heartbeat()
+
+	if condition.LastHeartbeatTime == nil || !condition.LastTransitionTime.Equal(&transition) {
+		t.Error("Expected the heartbeat to be set without a transition, got: ", condition)
+	}
+
+	if silent := condition.SilentFor(time.Now()); silent > time.Minute {
+		t.Error("Expected the condition to be alive, silent for: ", silent)
+	}
+}
+
+func TestConditionIsStuck(t *testing.T) {
+	now := time.Now()
+	transition := meta.NewTime(now.Add(-time.Hour))
+
+	cases := map[ConditionStatus]bool{
+		ConditionLocked:      true,
+		ConditionInitialized: true,
+		ConditionCompleted:   false,
+		ConditionError:       false,
+		ConditionSuspended:   false,
+	}
+
+	for status, stuck := range cases {
+		condition := Condition{Type: ConditionType("Bucket"), Status: status, LastTransitionTime: transition}
+		if condition.IsStuck(now, 15*time.Minute) != stuck {
+			t.Errorf("Expected a %s condition stuck to be %t", status, stuck)
+		}
+	}
+
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionLocked, LastTransitionTime: transition}
+	if condition.IsStuck(now, 0) || condition.IsStuck(now, 2*time.Hour) {
+		t.Error("Expected the condition not to be stuck without a threshold, or under the threshold")
+	}
+
+	condition.Heartbeat()
+	if condition.IsStuck(time.Now(), 15*time.Minute) {
+		t.Error("Expected a heartbeat to keep the condition from being stuck")
+	}
+}
+
+func TestLockProgress(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("progress")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), CommitWithResourceVersion())
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := lock.Progress(ctx, "Uploaded 1/2 parts"); err != nil {
+			t.Fatal(err)
+		}
+
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		locked := stored.Conditions().MustType(ConditionType("Bucket"))
+		if locked.Status != ConditionLocked || locked.Reason != "Uploaded 1/2 parts" || locked.LastHeartbeatTime == nil {
+			t.Error("Expected the progress to be persisted on the locked condition, got: ", locked)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal("Expected the lock to be released after reporting progress, got: ", err)
+	}
+
+	if err := lock.Progress(ctx, ""); err == nil {
+		t.Error("Expected progress to be refused once the lock is released")
+	}
+}
@@ -0,0 +1,207 @@
+// Package hub propagates the conditions of resources to a hub cluster, so fleet managers can observe
+// the state of operators distributed across many clusters from a single place.
+//
+// The Syncer runs in every spoke cluster, next to the operator. For every resource of the kind it watches,
+// it copies the selected conditions to the corresponding resource on the hub cluster, and labels the hub
+// resource with the name of the spoke cluster:
+//
+//	syncer := hub.NewSyncer(mgr.GetClient(), hubClient, "us-east-1",
+//		func() konditions.ConditionalResource { return &Bucket{} },
+//		func() konditions.ConditionalResource { return &fleetv1.BucketStatus{} },
+//	)
+//	syncer.MapType = hub.PrefixTypes("us-east-1/")
+//
+//	if err := syncer.SetupWithManager(mgr); err != nil {
+//		return err
+//	}
+//
+// The resources on the hub are expected to exist, the syncer only writes their conditions. Resources are
+// resynced periodically, which makes the hub converge after it restarts or loses its state.
+package hub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ClusterLabel is set on the resources of the hub, with the name of the cluster their conditions come from.
+const ClusterLabel = "konditionner.io/cluster"
+
+// DefaultResyncPeriod is the period at which every resource is synced again, even if it didn't change.
+const DefaultResyncPeriod = 10 * time.Minute
+
+// TypeMapper returns the type a condition has on the hub. Conditions are only propagated when the
+// boolean is true.
+type TypeMapper func(ct konditions.ConditionType) (konditions.ConditionType, bool)
+
+// MapTypes only propagates the types of the mapping, renamed to the value they map to.
+func MapTypes(mapping map[konditions.ConditionType]konditions.ConditionType) TypeMapper {
+	return func(ct konditions.ConditionType) (konditions.ConditionType, bool) {
+		mapped, ok := mapping[ct]
+		return mapped, ok
+	}
+}
+
+// PrefixTypes propagates every condition, with the prefix added to its type. It lets conditions from multiple
+// clusters be stored on the same hub resource.
+func PrefixTypes(prefix string) TypeMapper {
+	return func(ct konditions.ConditionType) (konditions.ConditionType, bool) {
+		return konditions.ConditionType(prefix + string(ct)), true
+	}
+}
+
+// Syncer copies the conditions of the resources of a spoke cluster to their counterpart on the hub cluster.
+type Syncer struct {
+	// Spoke is the client of the cluster the resources are read from, Hub the client of the cluster they are
+	// propagated to.
+	Spoke client.Client
+	Hub   client.Client
+
+	// Cluster is the name of the spoke cluster, set on the ClusterLabel of the hub resources.
+	Cluster string
+
+	NewObject    func() konditions.ConditionalResource
+	NewHubObject func() konditions.ConditionalResource
+
+	// MapType selects and renames the conditions that are propagated. Every condition is propagated
+	// as is when it's nil.
+	MapType TypeMapper
+
+	// HubKey returns the key of the hub resource for a resource of the spoke. The hub resource has the same
+	// namespace and name when it's nil.
+	HubKey func(obj konditions.ConditionalResource) types.NamespacedName
+
+	// ResyncPeriod is the period at which resources are synced even if they didn't change, DefaultResyncPeriod if 0.
+	ResyncPeriod time.Duration
+}
+
+// NewSyncer returns a syncer for the kind returned by newObject, propagating the conditions to the kind
+// returned by newHubObject.
+func NewSyncer(spoke, hub client.Client, cluster string, newObject, newHubObject func() konditions.ConditionalResource) *Syncer {
+	return &Syncer{
+		Spoke:        spoke,
+		Hub:          hub,
+		Cluster:      cluster,
+		NewObject:    newObject,
+		NewHubObject: newHubObject,
+		ResyncPeriod: DefaultResyncPeriod,
+	}
+}
+
+// SetupWithManager registers the syncer with the manager of the spoke cluster.
+func (s *Syncer) SetupWithManager(mgr ctrl.Manager) error {
+	obj := s.NewObject()
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(fmt.Sprintf("konditions-hub-%s", strings.ToLower(gvk.Kind))).
+		For(obj).
+		Complete(s)
+}
+
+// Reconcile propagates the conditions of the resource to the hub. The hub resource is fetched again and the
+// write retried when it conflicts with another writer. Resources that don't exist on the hub are retried at the
+// next resync.
+func (s *Syncer) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	resync := reconcile.Result{RequeueAfter: s.resyncPeriod()}
+
+	obj := s.NewObject()
+	if err := s.Spoke.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	key := req.NamespacedName
+	if s.HubKey != nil {
+		key = s.HubKey(obj)
+	}
+
+	conditions := s.mapConditions(*obj.Conditions())
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		target := s.NewHubObject()
+		if err := s.Hub.Get(ctx, key, target); err != nil {
+			return err
+		}
+
+		if target.GetLabels()[ClusterLabel] != s.Cluster {
+			labels := target.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[ClusterLabel] = s.Cluster
+			target.SetLabels(labels)
+
+			if err := s.Hub.Update(ctx, target); err != nil {
+				return err
+			}
+		}
+
+		changed := false
+		for _, condition := range conditions {
+			if existing, ok := target.Conditions().GetType(condition.Type); ok && existing.Equal(condition) {
+				continue
+			}
+
+			// The hub mirrors the spoke, the spoke already enforced the transitions.
+			if err := target.Conditions().SetConditionForce(condition); err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+
+		return s.Hub.Status().Update(ctx, target)
+	})
+
+	if apierrors.IsNotFound(err) {
+		return resync, nil
+	}
+
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return resync, nil
+}
+
+func (s *Syncer) mapConditions(conditions konditions.Conditions) konditions.Conditions {
+	mapped := konditions.Conditions{}
+	for _, condition := range conditions {
+		if s.MapType != nil {
+			ct, ok := s.MapType(condition.Type)
+			if !ok {
+				continue
+			}
+			condition.Type = ct
+		}
+
+		mapped = append(mapped, *condition.DeepCopy())
+	}
+
+	return mapped
+}
+
+func (s *Syncer) resyncPeriod() time.Duration {
+	if s.ResyncPeriod == 0 {
+		return DefaultResyncPeriod
+	}
+
+	return s.ResyncPeriod
+}
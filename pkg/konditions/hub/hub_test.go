@@ -0,0 +1,117 @@
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest/envtest"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := envtest.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&envtest.Sample{}).WithObjects(objs...).Build()
+}
+
+func newSample(name string) *envtest.Sample {
+	return &envtest.Sample{ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: name}}
+}
+
+func newTestSyncer(spoke, hub client.Client) *Syncer {
+	return NewSyncer(spoke, hub, "us-east-1",
+		func() konditions.ConditionalResource { return &envtest.Sample{} },
+		func() konditions.ConditionalResource { return &envtest.Sample{} },
+	)
+}
+
+func TestSyncerReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	local := newSample("bucket")
+	local.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: konditions.ConditionCompleted, Reason: "Bucket created"})
+	local.Conditions().SetCondition(konditions.Condition{Type: "Internal", Status: konditions.ConditionCreated})
+
+	remote := newSample("bucket")
+	remote.Conditions().SetCondition(konditions.Condition{Type: "eu-west-1/Bucket", Status: konditions.ConditionLocked})
+
+	hubClient := konditionstest.NewFaultyClient(newTestClient(remote)).Inject(
+		konditionstest.Fault{Op: konditionstest.OpStatusUpdate, Call: 1, Err: konditionstest.Conflict()},
+	)
+
+	syncer := newTestSyncer(newTestClient(local), hubClient)
+	syncer.MapType = MapTypes(map[konditions.ConditionType]konditions.ConditionType{"Bucket": "us-east-1/Bucket"})
+	syncer.ResyncPeriod = time.Minute
+
+	result, err := syncer.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(local)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != time.Minute {
+		t.Error("Expected the resource to be resynced periodically, got: ", result.RequeueAfter)
+	}
+
+	if calls := hubClient.Calls(konditionstest.OpStatusUpdate); calls != 2 {
+		t.Error("Expected the conflict to be retried, got calls: ", calls)
+	}
+
+	var stored envtest.Sample
+	if err := hubClient.Get(ctx, client.ObjectKeyFromObject(remote), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.GetLabels()[ClusterLabel] != "us-east-1" {
+		t.Error("Expected the hub resource to be labeled with the cluster, got: ", stored.GetLabels())
+	}
+
+	if condition := stored.Conditions().FindType("us-east-1/Bucket"); condition == nil || condition.Status != konditions.ConditionCompleted || condition.Reason != "Bucket created" {
+		t.Error("Expected the condition to be propagated, got: ", stored.Conditions())
+	}
+
+	if stored.Conditions().FindType("Internal") != nil || stored.Conditions().FindType("us-east-1/Internal") != nil {
+		t.Error("Expected the types that aren't mapped to be left out, got: ", stored.Conditions())
+	}
+
+	if !stored.Conditions().TypeHasStatus("eu-west-1/Bucket", konditions.ConditionLocked) {
+		t.Error("Expected the conditions of other clusters to be left untouched, got: ", stored.Conditions())
+	}
+
+	if _, err := syncer.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(local)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := hubClient.Calls(konditionstest.OpStatusUpdate); calls != 2 {
+		t.Error("Expected no write when the hub is up to date, got calls: ", calls)
+	}
+}
+
+func TestSyncerMissingHubResource(t *testing.T) {
+	local := newSample("bucket")
+	local.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: konditions.ConditionCompleted})
+
+	syncer := newTestSyncer(newTestClient(local), newTestClient())
+	syncer.HubKey = func(obj konditions.ConditionalResource) types.NamespacedName {
+		return types.NamespacedName{Namespace: "fleet", Name: "us-east-1-" + obj.GetName()}
+	}
+
+	result, err := syncer.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(local)})
+	if err != nil {
+		t.Fatal("Expected the missing hub resource to be retried at the next resync, got: ", err)
+	}
+
+	if result.RequeueAfter != DefaultResyncPeriod {
+		t.Error("Unexpected requeue: ", result.RequeueAfter)
+	}
+}
@@ -0,0 +1,60 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// ConditionIndexer returns the function that extracts the status of the condition of the type from a resource, to be
+// registered as the field index ConditionIndexField(ct). A resource without the condition is indexed with the status
+// ConditionInitialized, like FindOrInitializeFor returns it, so the index selects the same resources as List does.
+//
+// The resources need to be ConditionalResources, or unstructured objects with their conditions stored in
+// DefaultConditionsFields. Resources that can't be read aren't indexed.
+//
+// RegisterConditionIndex registers the index on a manager, ConditionIndexer is useful when the indexer isn't a
+// manager, like the fake client in tests:
+//
+//	c := fake.NewClientBuilder().
+//		WithIndex(&MyCRD{}, konditions.ConditionIndexField(ConditionType("Bucket")), konditions.ConditionIndexer(ConditionType("Bucket"))).
+//		Build()
+func ConditionIndexer(ct ConditionType) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		conditions, err := conditionsOf(obj)
+		if err != nil {
+			return nil
+		}
+
+		return []string{string(conditions.FindOrInitializeFor(ct).Status)}
+	}
+}
+
+// RegisterConditionIndex installs a field index on the cache of the manager for each of the condition types of the
+// resource. Operators can then select resources by the status of their conditions from the cache, without writing the
+// extraction functions themselves:
+//
+//	if err := konditions.RegisterConditionIndex(ctx, mgr, &MyCRD{}, ConditionType("Bucket")); err != nil {
+//		return err
+//	}
+//
+//	var crds MyCRDList
+//	err := konditions.List(ctx, mgr.GetClient(), &crds,
+//		konditions.WithConditionFilter(ConditionType("Bucket"), konditions.ConditionError),
+//		konditions.UseConditionIndex(),
+//	)
+//
+// The index of a type is named after ConditionIndexField, see ConditionIndexer for the values indexed. Indexes need to
+// be registered before the manager is started.
+func RegisterConditionIndex(ctx context.Context, mgr manager.Manager, obj client.Object, types ...ConditionType) error {
+	indexer := mgr.GetFieldIndexer()
+	for _, ct := range types {
+		if err := indexer.IndexField(ctx, obj, ConditionIndexField(ct), ConditionIndexer(ct)); err != nil {
+			return fmt.Errorf("could not index the conditions %s: %w", ct, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+type recordingIndexer struct {
+	indexes map[string]client.IndexerFunc
+	err     error
+}
+
+func (r *recordingIndexer) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.indexes[field] = extractValue
+	return nil
+}
+
+type indexerManager struct {
+	manager.Manager
+	indexer client.FieldIndexer
+}
+
+func (m indexerManager) GetFieldIndexer() client.FieldIndexer {
+	return m.indexer
+}
+
+func TestConditionIndexer(t *testing.T) {
+	indexer := ConditionIndexer(ConditionType("Bucket"))
+
+	for _, obj := range newQueryTestResources() {
+		values := indexer(obj)
+		expected := obj.(*testResource).Conditions().FindOrInitializeFor(ConditionType("Bucket")).Status
+		if len(values) != 1 || values[0] != string(expected) {
+			t.Errorf("Expected %s to be indexed with %s, got: %v", obj.GetName(), expected, values)
+		}
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	SetNestedConditions(obj, Conditions{{Type: ConditionType("Bucket"), Status: ConditionError}}, DefaultConditionsFields...)
+	if values := indexer(obj); len(values) != 1 || values[0] != string(ConditionError) {
+		t.Error("Expected the unstructured object to be indexed with its status, got: ", values)
+	}
+
+	invalid := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": "invalid"},
+	}}
+	if values := indexer(invalid); values != nil {
+		t.Error("Expected an invalid object not to be indexed, got: ", values)
+	}
+}
+
+func TestRegisterConditionIndex(t *testing.T) {
+	ctx := context.Background()
+	indexer := &recordingIndexer{indexes: map[string]client.IndexerFunc{}}
+	mgr := indexerManager{indexer: indexer}
+
+	if err := RegisterConditionIndex(ctx, mgr, &testResource{}, ConditionType("Bucket"), ConditionType("DNS")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(indexer.indexes) != 2 {
+		t.Fatal("Expected an index for each type, got: ", len(indexer.indexes))
+	}
+
+	extract, ok := indexer.indexes[ConditionIndexField(ConditionType("DNS"))]
+	if !ok {
+		t.Fatal("Expected the index to be named after ConditionIndexField")
+	}
+
+	failed := newQueryTestResources()[0]
+	if values := extract(failed); len(values) != 1 || values[0] != string(ConditionCompleted) {
+		t.Error("Expected the status of the condition to be indexed, got: ", values)
+	}
+
+	indexer.err = errors.New("cache started")
+	if err := RegisterConditionIndex(ctx, mgr, &testResource{}, ConditionType("Bucket")); !errors.Is(err, indexer.err) {
+		t.Error("Expected the error of the indexer to be returned, got: ", err)
+	}
+}
@@ -0,0 +1,31 @@
+package konditions
+
+// Returns the conditions that recorded an intent, with Lock.ExecuteWithIntent, that was never confirmed. Those
+// conditions are still locked and have a Ref: the task started but the condition was never released, usually because
+// the operator crashed or lost access to the Kubernetes API while the task was running.
+//
+// It is up to the caller to decide what to do with each of them: delete the external resource the Ref points to,
+// adopt it, or mark the condition as errored. Since a pending intent is locked, its status can be set directly.
+//
+//	for _, intent := range res.Status.Conditions.PendingIntents() {
+//		if err := s3.DeleteBucket(ctx, intent.Ref); err != nil {
+//			return ctrl.Result{}, err
+//		}
+//
+//		intent.Status = konditions.ConditionInitialized
+//		intent.Reason = "Orphaned bucket deleted"
+//		res.Status.Conditions.SetCondition(intent)
+//	}
+//
+// Keep in mind that a lock can be legitimately held by a task that is still running, in this reconciler
+// or in another replica. Checking the LastTransitionTime of the condition before acting on it is a good idea.
+func (c Conditions) PendingIntents() Conditions {
+	intents := Conditions{}
+	for _, condition := range c {
+		if condition.Status == ConditionLocked && condition.Ref != "" {
+			intents = append(intents, *condition.DeepCopy())
+		}
+	}
+
+	return intents
+}
@@ -0,0 +1,74 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestExecuteWithIntent(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("intent")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithIntent(ctx, "photos", func(condition Condition) (Condition, error) {
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		intents := stored.Conditions().PendingIntents()
+		if len(intents) != 1 || intents[0].Ref != "photos" {
+			t.Error("Expected the intent to be persisted before the task runs, got: ", intents)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := res.Conditions().FindType(ConditionType("Bucket"))
+	if condition.Status != ConditionCompleted || condition.Ref != "photos" {
+		t.Error("Expected the intent to be confirmed, got: ", condition)
+	}
+
+	if intents := res.Conditions().PendingIntents(); len(intents) != 0 {
+		t.Error("Expected no pending intents, got: ", intents)
+	}
+}
+
+func TestExecuteWithIntentError(t *testing.T) {
+	res := newTestResource("intent")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithIntent(context.Background(), "photos", func(condition Condition) (Condition, error) {
+		return condition, errors.New("Access denied")
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	condition := res.Conditions().FindType(ConditionType("Bucket"))
+	if condition.Status != ConditionError || condition.Ref != "photos" {
+		t.Error("Expected the errored condition to keep its ref, got: ", condition)
+	}
+}
+
+func TestPendingIntents(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionLocked, Ref: "photos"},
+		{Type: ConditionType("DNS"), Status: ConditionLocked},
+		{Type: ConditionType("Volume"), Status: ConditionCompleted, Ref: "vol-1"},
+	}
+
+	intents := conditions.PendingIntents()
+	if len(intents) != 1 || intents[0].Type != ConditionType("Bucket") {
+		t.Error("Unexpected intents: ", intents)
+	}
+}
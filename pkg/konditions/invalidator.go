@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// InputExtractor returns the inputs a condition depends on, for a given resource. The value
+// returned is hashed and stored on the condition, it can be anything as long as it changes
+// when the inputs change. HashOf can be used to build one from the fields of a resource.
+type InputExtractor func(obj ConditionalResource) string
+
+// Invalidator flips conditions back to ConditionInitialized when the inputs they were
+// reconciled with change. This is the standard answer to "the user edited the spec, redo this step".
+//
+// Each condition type that depends on inputs is registered with an InputExtractor. Conditions are
+// stamped with the hash of their inputs once they are reconciled, and Invalidate compares the hash
+// stored on every condition with the hash of the current inputs.
+//
+//	invalidator := konditions.NewInvalidator().
+//		Register(ConditionType("Bucket"), func(obj konditions.ConditionalResource) string {
+//			res := obj.(*MyCRD)
+//			return konditions.HashOf(res.Spec.BucketName, res.Spec.Region)
+//		})
+//
+//	// At the beginning of the reconciliation loop
+//	if invalidated := invalidator.Invalidate(&res); len(invalidated) > 0 {
+//		if err := reconciler.Status().Update(ctx, &res); err != nil {
+//			return ctrl.Result{}, err
+//		}
+//	}
+//
+//	// Within the task, when the bucket is created
+//	condition.Status = konditions.ConditionCompleted
+//	return invalidator.Stamp(&res, condition), nil
+type Invalidator struct {
+	extractors map[ConditionType]InputExtractor
+}
+
+// NewInvalidator returns an Invalidator without any condition type registered.
+func NewInvalidator() *Invalidator {
+	return &Invalidator{
+		extractors: map[ConditionType]InputExtractor{},
+	}
+}
+
+// Register the extractor for the condition type given. Registering a type twice replaces the
+// previous extractor.
+func (i *Invalidator) Register(ct ConditionType, extractor InputExtractor) *Invalidator {
+	i.extractors[ct] = extractor
+	return i
+}
+
+// Stamp returns the condition with its InputHash set to the hash of the current inputs of
+// the resource. Conditions with a type that isn't registered are returned untouched.
+func (i *Invalidator) Stamp(obj ConditionalResource, condition Condition) Condition {
+	if extractor, ok := i.extractors[condition.Type]; ok {
+		condition.InputHash = hash(extractor(obj))
+	}
+
+	return condition
+}
+
+// Invalidate resets every condition whose inputs changed since it was stamped and returns the types
+// of the conditions that were reset. Conditions that were never stamped, and conditions
+// that are locked or exhausted, are left untouched.
+//
+// The changes are only made in memory, it is up to the caller to persist the resource.
+func (i *Invalidator) Invalidate(obj ConditionalResource) []ConditionType {
+	var invalidated []ConditionType
+
+	for ct, extractor := range i.extractors {
+		condition := obj.Conditions().FindType(ct)
+		if condition == nil || condition.InputHash == "" || condition.StatusIsOneOf(ConditionLocked, ConditionExhausted) {
+			continue
+		}
+
+		if condition.InputHash == hash(extractor(obj)) {
+			continue
+		}
+
+		obj.Conditions().Reset(ct, "Inputs changed since the condition was reconciled")
+		invalidated = append(invalidated, ct)
+	}
+
+	return invalidated
+}
+
+// HashOf is a convenience function to build an InputExtractor from any number of values. The values
+// are serialized to JSON before being hashed, which means maps and structs can be passed as well.
+func HashOf(values ...any) string {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return hash(fmt.Sprint(values...))
+	}
+
+	return hash(string(data))
+}
+
+func hash(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package konditions
+
+import (
+	"testing"
+)
+
+func TestInvalidator(t *testing.T) {
+	res := newTestResource("invalidator")
+	res.Annotations = map[string]string{"bucket": "photos"}
+
+	invalidator := NewInvalidator().
+		Register(ConditionType("Bucket"), func(obj ConditionalResource) string {
+			return HashOf(obj.GetAnnotations()["bucket"])
+		}).
+		Register(ConditionType("Unstamped"), func(obj ConditionalResource) string {
+			return HashOf(obj.GetAnnotations()["bucket"])
+		})
+
+	stamped := invalidator.Stamp(res, Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	if stamped.InputHash == "" {
+		t.Fatal("Expected the condition to be stamped")
+	}
+
+	if other := invalidator.Stamp(res, Condition{Type: ConditionType("DNS")}); other.InputHash != "" {
+		t.Error("Expected conditions that aren't registered to be left untouched")
+	}
+
+	res.Conditions().SetCondition(stamped)
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Unstamped"), Status: ConditionCompleted})
+
+	if invalidated := invalidator.Invalidate(res); len(invalidated) != 0 {
+		t.Error("Expected nothing to be invalidated, got: ", invalidated)
+	}
+
+	res.Annotations["bucket"] = "videos"
+
+	invalidated := invalidator.Invalidate(res)
+	if len(invalidated) != 1 || invalidated[0] != ConditionType("Bucket") {
+		t.Error("Expected the bucket to be invalidated, got: ", invalidated)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionInitialized) {
+		t.Error("Expected the condition to be reset")
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Unstamped"), ConditionCompleted) {
+		t.Error("Expected the unstamped condition to be left untouched")
+	}
+}
+
+func TestHashOf(t *testing.T) {
+	if HashOf("a", 1) == HashOf("a", 2) {
+		t.Error("Expected different values to have different hashes")
+	}
+
+	if HashOf(map[string]string{"a": "b"}) != HashOf(map[string]string{"a": "b"}) {
+		t.Error("Expected the same values to have the same hash")
+	}
+}
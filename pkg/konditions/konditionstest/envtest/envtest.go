@@ -0,0 +1,224 @@
+// Package envtest provides utilities to run integration tests of condition-driven controllers against
+// a real API server, started with the envtest package of controller-runtime.
+//
+// The package installs a sample CRD, Sample, that carries a konditions.Conditions in its status, with
+// the status subresource enabled. It is enough to exercise the semantics of a Lock against a real API server:
+//
+//	func TestBucket(t *testing.T) {
+//		c := envtest.Start(t)
+//
+//		sample, err := envtest.NewSample(ctx, c, "default", "bucket")
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//
+//		// ... Run the controller ...
+//
+//		err = envtest.EventuallyConditionReaches(ctx, c, client.ObjectKeyFromObject(sample), &envtest.Sample{}, ConditionType("Bucket"), konditions.ConditionCompleted, 10*time.Second)
+//	}
+//
+// The binaries of the API server and etcd need to be installed, with setup-envtest for instance, and
+// KUBEBUILDER_ASSETS needs to point to them. Start skips the test otherwise.
+package envtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crenvtest "sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// GroupVersion of the sample CRD.
+var GroupVersion = schema.GroupVersion{Group: "konditionner.io", Version: "v1alpha1"}
+
+var ConditionNotReachedErr = errors.New("Condition did not reach the expected status")
+
+// Sample is the custom resource installed by Start. Its spec is free-form and its status holds the conditions.
+type Sample struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   map[string]string `json:"spec,omitempty"`
+	Status SampleStatus      `json:"status,omitempty"`
+}
+
+type SampleStatus struct {
+	Conditions konditions.Conditions `json:"conditions,omitempty"`
+}
+
+func (s *Sample) Conditions() *konditions.Conditions {
+	return &s.Status.Conditions
+}
+
+func (s *Sample) DeepCopyObject() runtime.Object {
+	out := &Sample{}
+	out.TypeMeta = s.TypeMeta
+	s.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if s.Spec != nil {
+		out.Spec = make(map[string]string, len(s.Spec))
+		for key, value := range s.Spec {
+			out.Spec[key] = value
+		}
+	}
+	out.Status.Conditions = s.Status.Conditions.DeepCopy()
+	return out
+}
+
+// SampleList is the list type of Sample.
+type SampleList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	Items []Sample `json:"items"`
+}
+
+func (l *SampleList) DeepCopyObject() runtime.Object {
+	out := &SampleList{}
+	out.TypeMeta = l.TypeMeta
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	if l.Items != nil {
+		out.Items = make([]Sample, len(l.Items))
+		for i := range l.Items {
+			out.Items[i] = *l.Items[i].DeepCopyObject().(*Sample)
+		}
+	}
+	return out
+}
+
+// AddToScheme registers Sample and SampleList with the scheme.
+func AddToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion, &Sample{}, &SampleList{})
+	meta.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
+
+// SampleCRD returns the definition of the Sample custom resource, with the status subresource enabled.
+func SampleCRD() *apiextensionsv1.CustomResourceDefinition {
+	preserve := true
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: meta.ObjectMeta{Name: "samples." + GroupVersion.Group},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: GroupVersion.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     "Sample",
+				ListKind: "SampleList",
+				Plural:   "samples",
+				Singular: "sample",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    GroupVersion.Version,
+				Served:  true,
+				Storage: true,
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type:                   "object",
+								XPreserveUnknownFields: &preserve,
+							},
+							"status": {
+								Type:                   "object",
+								XPreserveUnknownFields: &preserve,
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// Start starts an API server with the Sample CRD installed and returns a client for it. The API server is
+// stopped when the test completes. The test is skipped if KUBEBUILDER_ASSETS isn't set.
+func Start(t testing.TB) client.Client {
+	t.Helper()
+
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, skipping the integration test")
+	}
+
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &crenvtest.Environment{
+		CRDs:                  []*apiextensionsv1.CustomResourceDefinition{SampleCRD()},
+		ErrorIfCRDPathMissing: false,
+	}
+
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("could not start the API server: %s", err)
+	}
+
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("could not stop the API server: %s", err)
+		}
+	})
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatalf("could not create the client: %s", err)
+	}
+
+	return c
+}
+
+// NewSample creates a Sample with the given name in the namespace.
+func NewSample(ctx context.Context, c client.Client, namespace, name string) (*Sample, error) {
+	sample := &Sample{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+
+	if err := c.Create(ctx, sample); err != nil {
+		return nil, err
+	}
+
+	return sample, nil
+}
+
+// EventuallyConditionReaches polls the resource with the given key, fetched into obj, until its condition
+// with the given type has the status given. An error wrapping ConditionNotReachedErr is returned if the
+// condition didn't reach the status before the timeout.
+//
+// Errors returned by the API server while polling, the resource not existing yet for instance, are retried.
+func EventuallyConditionReaches(ctx context.Context, c client.Client, key client.ObjectKey, obj konditions.ConditionalResource, ct konditions.ConditionType, status konditions.ConditionStatus, timeout time.Duration) error {
+	var last konditions.ConditionStatus
+
+	err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, obj); err != nil {
+			return false, nil
+		}
+
+		last = obj.Conditions().FindOrInitializeFor(ct).Status
+		return last == status, nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("%w: %s of %s is %s, expected %s: %w", ConditionNotReachedErr, ct, key, last, status, err)
+	}
+
+	return nil
+}
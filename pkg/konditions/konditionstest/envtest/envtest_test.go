@@ -0,0 +1,67 @@
+package envtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEventuallyConditionReaches(t *testing.T) {
+	scheme := runtime.NewScheme()
+	AddToScheme(scheme)
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&Sample{}).Build()
+	ctx := context.Background()
+
+	sample, err := NewSample(ctx, c, "default", "sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		konditions.NewLock(sample, c, konditions.ConditionType("Bucket")).Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+			condition.Status = konditions.ConditionCompleted
+			return condition, nil
+		})
+	}()
+
+	key := client.ObjectKeyFromObject(sample)
+	if err := EventuallyConditionReaches(ctx, c, key, &Sample{}, konditions.ConditionType("Bucket"), konditions.ConditionCompleted, 5*time.Second); err != nil {
+		t.Error(err)
+	}
+
+	err = EventuallyConditionReaches(ctx, c, key, &Sample{}, konditions.ConditionType("DNS"), konditions.ConditionCompleted, 200*time.Millisecond)
+	if !errors.Is(err, ConditionNotReachedErr) {
+		t.Error("Expected ConditionNotReachedErr, got: ", err)
+	}
+}
+
+func TestLockAgainstAPIServer(t *testing.T) {
+	c := Start(t)
+	ctx := context.Background()
+
+	sample, err := NewSample(ctx, c, "default", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = konditions.NewLock(sample, c, konditions.ConditionType("Bucket")).Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+		condition.Status = konditions.ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EventuallyConditionReaches(ctx, c, client.ObjectKeyFromObject(sample), &Sample{}, konditions.ConditionType("Bucket"), konditions.ConditionCompleted, 5*time.Second); err != nil {
+		t.Error(err)
+	}
+}
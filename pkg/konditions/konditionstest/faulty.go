@@ -0,0 +1,221 @@
+package konditionstest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Operation identifies a call made to a client.Client.
+type Operation string
+
+const (
+	OpGet          Operation = "Get"
+	OpList         Operation = "List"
+	OpCreate       Operation = "Create"
+	OpUpdate       Operation = "Update"
+	OpPatch        Operation = "Patch"
+	OpDelete       Operation = "Delete"
+	OpDeleteAllOf  Operation = "DeleteAllOf"
+	OpStatusUpdate Operation = "Status.Update"
+	OpStatusPatch  Operation = "Status.Patch"
+	OpStatusCreate Operation = "Status.Create"
+)
+
+// Fault describes what happens to a call made through a FaultyClient. The latency is applied first, if the
+// context is done before the latency elapsed, the call returns the error of the context. Then, if Err is set,
+// it is returned without calling the underlying client.
+type Fault struct {
+	// Op is the operation the fault applies to.
+	Op Operation
+
+	// Call is the call, starting at 1, of the operation the fault applies to. When it is 0, the fault
+	// applies to every call of the operation.
+	Call int
+
+	Err     error
+	Latency time.Duration
+}
+
+// FaultyClient wraps a client.Client and injects faults in the calls made through it. It is meant to unit test
+// how reconcilers behave around a Lock when the Kubernetes API misbehaves, without envtest.
+//
+//	c := konditionstest.NewFaultyClient(fakeClient).Inject(
+//		konditionstest.Fault{Op: konditionstest.OpStatusUpdate, Call: 2, Err: konditionstest.Conflict()},
+//		konditionstest.Fault{Op: konditionstest.OpGet, Latency: 50 * time.Millisecond},
+//	)
+//
+// Only the status subresource is wrapped, calls to other subresources go straight to the underlying client.
+type FaultyClient struct {
+	client.Client
+
+	mu     sync.Mutex
+	faults []Fault
+	calls  map[Operation]int
+}
+
+// NewFaultyClient returns a FaultyClient wrapping the client given, without any fault.
+func NewFaultyClient(c client.Client) *FaultyClient {
+	return &FaultyClient{
+		Client: c,
+		calls:  map[Operation]int{},
+	}
+}
+
+// Inject adds the faults to the client. When multiple faults apply to the same call, their latencies
+// add up and the first error is returned.
+func (f *FaultyClient) Inject(faults ...Fault) *FaultyClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = append(f.faults, faults...)
+	return f
+}
+
+// Calls returns the number of calls made for the operation, including the ones that failed.
+func (f *FaultyClient) Calls(op Operation) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[op]
+}
+
+// Conflict returns the error the Kubernetes API returns when the resource was modified by another writer.
+func Conflict() error {
+	return apierrors.NewConflict(schema.GroupResource{}, "", nil)
+}
+
+// NotFound returns the error the Kubernetes API returns when the resource doesn't exist.
+func NotFound() error {
+	return apierrors.NewNotFound(schema.GroupResource{}, "")
+}
+
+// Timeout returns the error the Kubernetes API returns when it couldn't complete the request in time.
+func Timeout() error {
+	return apierrors.NewTimeoutError("injected timeout", 1)
+}
+
+func (f *FaultyClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := f.fault(ctx, OpGet); err != nil {
+		return err
+	}
+	return f.Client.Get(ctx, key, obj, opts...)
+}
+
+func (f *FaultyClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := f.fault(ctx, OpList); err != nil {
+		return err
+	}
+	return f.Client.List(ctx, list, opts...)
+}
+
+func (f *FaultyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := f.fault(ctx, OpCreate); err != nil {
+		return err
+	}
+	return f.Client.Create(ctx, obj, opts...)
+}
+
+func (f *FaultyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := f.fault(ctx, OpUpdate); err != nil {
+		return err
+	}
+	return f.Client.Update(ctx, obj, opts...)
+}
+
+func (f *FaultyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := f.fault(ctx, OpPatch); err != nil {
+		return err
+	}
+	return f.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (f *FaultyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := f.fault(ctx, OpDelete); err != nil {
+		return err
+	}
+	return f.Client.Delete(ctx, obj, opts...)
+}
+
+func (f *FaultyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := f.fault(ctx, OpDeleteAllOf); err != nil {
+		return err
+	}
+	return f.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (f *FaultyClient) Status() client.SubResourceWriter {
+	return &faultyStatusClient{SubResourceClient: f.Client.SubResource("status"), client: f}
+}
+
+func (f *FaultyClient) SubResource(subResource string) client.SubResourceClient {
+	if subResource != "status" {
+		return f.Client.SubResource(subResource)
+	}
+
+	return &faultyStatusClient{SubResourceClient: f.Client.SubResource(subResource), client: f}
+}
+
+// Returns the error the call should fail with, after applying the latency of the faults.
+func (f *FaultyClient) fault(ctx context.Context, op Operation) error {
+	f.mu.Lock()
+	f.calls[op]++
+	call := f.calls[op]
+
+	var latency time.Duration
+	var err error
+	for _, fault := range f.faults {
+		if fault.Op != op || (fault.Call != 0 && fault.Call != call) {
+			continue
+		}
+
+		latency += fault.Latency
+		if err == nil {
+			err = fault.Err
+		}
+	}
+	f.mu.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
+
+type faultyStatusClient struct {
+	client.SubResourceClient
+	client *FaultyClient
+}
+
+func (s *faultyStatusClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := s.client.fault(ctx, OpStatusCreate); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (s *faultyStatusClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := s.client.fault(ctx, OpStatusUpdate); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (s *faultyStatusClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := s.client.fault(ctx, OpStatusPatch); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
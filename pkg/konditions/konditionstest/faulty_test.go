@@ -0,0 +1,75 @@
+package konditionstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFaultyClient(objs ...client.Object) *FaultyClient {
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(objs...).
+		Build()
+
+	return NewFaultyClient(c)
+}
+
+func TestFaultyClientConflict(t *testing.T) {
+	res := newTestResource("faulty")
+	c := newFaultyClient(res).Inject(Fault{Op: OpStatusUpdate, Call: 2, Err: Conflict()})
+
+	err := konditions.NewLock(res, c, konditions.ConditionType("Bucket")).Execute(context.Background(), func(condition konditions.Condition) (konditions.Condition, error) {
+		condition.Status = konditions.ConditionCompleted
+		return condition, nil
+	})
+
+	if !apierrors.IsConflict(err) {
+		t.Error("Expected a conflict, got: ", err)
+	}
+
+	if calls := c.Calls(OpStatusUpdate); calls != 2 {
+		t.Error("Expected 2 status updates, got: ", calls)
+	}
+}
+
+func TestFaultyClientEveryCall(t *testing.T) {
+	res := newTestResource("faulty")
+	c := newFaultyClient(res).Inject(Fault{Op: OpGet, Err: NotFound()})
+
+	for i := 0; i < 3; i++ {
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(res), &testResource{}); !apierrors.IsNotFound(err) {
+			t.Error("Expected NotFound, got: ", err)
+		}
+	}
+}
+
+func TestFaultyClientLatency(t *testing.T) {
+	res := newTestResource("faulty")
+	c := newFaultyClient(res).Inject(Fault{Op: OpGet, Latency: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &testResource{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected the deadline to be exceeded, got: ", err)
+	}
+
+	c = newFaultyClient(res).Inject(Fault{Op: OpGet, Latency: 10 * time.Millisecond, Err: Timeout()})
+
+	start := time.Now()
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(res), &testResource{}); !apierrors.IsTimeout(err) {
+		t.Error("Expected a timeout, got: ", err)
+	}
+
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("Expected the latency to be injected")
+	}
+}
@@ -0,0 +1,134 @@
+// Package gen generates random, valid, conditions and checks the invariants Konditionner relies on. It is meant
+// to be used with testing/quick, or native fuzzing, so types embedding Conditions can test their own mutation logic.
+//
+//	err := quick.Check(func(conditions gen.ValidConditions, update gen.ValidCondition) bool {
+//		after := konditions.Conditions(conditions)
+//		before := after.DeepCopy()
+//		myMutation(&after, konditions.Condition(update))
+//
+//		return gen.CheckInvariants(after) == nil && gen.CheckTransitions(before, after) == nil
+//	}, nil)
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var InvariantErr = errors.New("Invariant violated")
+
+// Statuses are the statuses the generators pick from.
+var Statuses = []konditions.ConditionStatus{
+	konditions.ConditionInitialized,
+	konditions.ConditionCreated,
+	konditions.ConditionCompleted,
+	konditions.ConditionTerminating,
+	konditions.ConditionTerminated,
+	konditions.ConditionError,
+	konditions.ConditionLocked,
+	konditions.ConditionExhausted,
+}
+
+// Types are the condition types the generators pick from. The list is short on purpose, so generated
+// conditions often share types with each other.
+var Types = []konditions.ConditionType{"Bucket", "DNS", "Volume", "Secret", "example.com/Certificate"}
+
+// Same pattern as the validation marker of Condition.Type.
+var typePattern = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$`)
+
+// Epoch is the earliest LastTransitionTime generated.
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Condition returns a random, valid, condition.
+func Condition(r *rand.Rand) konditions.Condition {
+	return konditions.Condition{
+		Type:               Types[r.Intn(len(Types))],
+		Status:             Statuses[r.Intn(len(Statuses))],
+		LastTransitionTime: meta.NewTime(Epoch.Add(time.Duration(r.Intn(365*24)) * time.Hour)),
+		Reason:             fmt.Sprintf("Generated reason %d", r.Intn(1000)),
+	}
+}
+
+// Conditions returns a random, valid, conditions set of up to `size` conditions. The types are unique.
+func Conditions(r *rand.Rand, size int) konditions.Conditions {
+	conditions := konditions.Conditions{}
+	if size <= 0 {
+		return conditions
+	}
+
+	for _, i := range r.Perm(len(Types))[:min(r.Intn(size+1), len(Types))] {
+		condition := Condition(r)
+		condition.Type = Types[i]
+		conditions = append(conditions, condition)
+	}
+
+	return conditions
+}
+
+// ValidCondition implements quick.Generator and generates valid conditions.
+type ValidCondition konditions.Condition
+
+func (ValidCondition) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(ValidCondition(Condition(r)))
+}
+
+// ValidConditions implements quick.Generator and generates valid conditions sets.
+type ValidConditions konditions.Conditions
+
+func (ValidConditions) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(ValidConditions(Conditions(r, size)))
+}
+
+// CheckInvariants returns an error wrapping InvariantErr if the conditions don't hold the invariants Konditionner
+// relies on: types are valid and unique, statuses are set and the LastTransitionTime is set.
+func CheckInvariants(conditions konditions.Conditions) error {
+	seen := map[konditions.ConditionType]bool{}
+	var errs []error
+
+	for _, condition := range conditions {
+		if !typePattern.MatchString(string(condition.Type)) {
+			errs = append(errs, fmt.Errorf("%w: %q is not a valid type", InvariantErr, condition.Type))
+		}
+
+		if seen[condition.Type] {
+			errs = append(errs, fmt.Errorf("%w: %s appears more than once", InvariantErr, condition.Type))
+		}
+		seen[condition.Type] = true
+
+		if condition.Status == "" || len(condition.Status) > 128 {
+			errs = append(errs, fmt.Errorf("%w: %s has an invalid status %q", InvariantErr, condition.Type, condition.Status))
+		}
+
+		if condition.LastTransitionTime.IsZero() {
+			errs = append(errs, fmt.Errorf("%w: %s has no LastTransitionTime", InvariantErr, condition.Type))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckTransitions returns an error wrapping InvariantErr if a condition present in both sets has a
+// LastTransitionTime that went back in time.
+func CheckTransitions(before, after konditions.Conditions) error {
+	var errs []error
+
+	for _, condition := range after {
+		previous, ok := before.GetType(condition.Type)
+		if !ok {
+			continue
+		}
+
+		if condition.LastTransitionTime.Before(&previous.LastTransitionTime) {
+			errs = append(errs, fmt.Errorf("%w: LastTransitionTime of %s went from %s to %s", InvariantErr, condition.Type, previous.LastTransitionTime, condition.LastTransitionTime))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"errors"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGeneratedConditionsHoldInvariants(t *testing.T) {
+	err := quick.Check(func(conditions ValidConditions) bool {
+		return CheckInvariants(konditions.Conditions(conditions)) == nil
+	}, nil)
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSetConditionHoldsInvariants(t *testing.T) {
+	err := quick.Check(func(conditions ValidConditions, update ValidCondition) bool {
+		after := konditions.Conditions(conditions)
+		before := after.DeepCopy()
+
+		condition := konditions.Condition(update)
+		condition.LastTransitionTime = meta.Time{}
+		after.SetConditionForce(condition)
+
+		return CheckInvariants(after) == nil && CheckTransitions(before, after) == nil
+	}, nil)
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckInvariants(t *testing.T) {
+	now := meta.NewTime(time.Now())
+	conditions := konditions.Conditions{
+		{Type: "Bucket", Status: konditions.ConditionCompleted, LastTransitionTime: now},
+		{Type: "Bucket", Status: konditions.ConditionCompleted, LastTransitionTime: now},
+		{Type: "not a type", Status: "", LastTransitionTime: now},
+	}
+
+	if err := CheckInvariants(conditions); !errors.Is(err, InvariantErr) {
+		t.Error("Expected the invariants to be violated, got: ", err)
+	}
+
+	before := konditions.Conditions{{Type: "Bucket", Status: konditions.ConditionCompleted, LastTransitionTime: now}}
+	after := konditions.Conditions{{Type: "Bucket", Status: konditions.ConditionCompleted, LastTransitionTime: meta.NewTime(now.Add(-time.Hour))}}
+	if err := CheckTransitions(before, after); !errors.Is(err, InvariantErr) {
+		t.Error("Expected the transition to be invalid, got: ", err)
+	}
+}
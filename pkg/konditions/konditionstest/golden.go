@@ -0,0 +1,149 @@
+package konditionstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty value, makes AssertGolden
+// write the golden files instead of comparing against them.
+const UpdateGoldenEnv = "KONDITIONS_UPDATE_GOLDEN"
+
+// RedactedTime replaces the timestamps of the conditions serialized by MarshalGolden.
+const RedactedTime = "<redacted>"
+
+// MarshalGolden serializes the conditions deterministically: the conditions are sorted by type, the keys are
+// sorted and the timestamps are replaced by RedactedTime, so the output only changes when the conditions do.
+func MarshalGolden(conditions konditions.Conditions) ([]byte, error) {
+	sorted := conditions.DeepCopy()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		if _, ok := value["lastTransitionTime"]; ok {
+			value["lastTransitionTime"] = RedactedTime
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(values); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AssertGolden compares the conditions, serialized with MarshalGolden, with the content of the golden file
+// at path. The test fails with a line diff when they differ.
+//
+//	konditionstest.AssertGolden(t, "testdata/bucket.golden.json", res.Status.Conditions)
+//
+// The golden files are written, instead of compared, when the test binary runs with the `-update` flag, or
+// when UpdateGoldenEnv is set. The package doesn't register the flag itself, to not conflict with yours, define
+// it in your tests if you want to use it:
+//
+//	var _ = flag.Bool("update", false, "update golden files")
+//
+//	go test ./... -update
+func AssertGolden(t testing.TB, path string, conditions konditions.Conditions) {
+	t.Helper()
+
+	actual, err := MarshalGolden(conditions)
+	if err != nil {
+		t.Fatalf("could not serialize conditions: %s", err)
+		return
+	}
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("could not create the directory of %s: %s", path, err)
+			return
+		}
+
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("could not write %s: %s", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read %s, run the tests with -update to create it: %s", path, err)
+		return
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("conditions don't match %s (-expected +actual):\n%s", path, diffLines(string(expected), string(actual)))
+	}
+}
+
+func shouldUpdateGolden() bool {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return true
+	}
+
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// Returns a line diff of the two strings, computed from their longest common subsequence.
+func diffLines(expected, actual string) string {
+	a := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			diff.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+
+	return diff.String()
+}
@@ -0,0 +1,74 @@
+package konditionstest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordingTB captures the failures of a test instead of failing it.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestMarshalGolden(t *testing.T) {
+	conditions := konditions.Conditions{
+		{Type: konditions.ConditionType("DNS"), Status: konditions.ConditionCreated, LastTransitionTime: meta.NewTime(time.Now())},
+		{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCompleted, LastTransitionTime: meta.NewTime(time.Now())},
+	}
+
+	data, err := MarshalGolden(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Index(string(data), "Bucket") > strings.Index(string(data), "DNS") {
+		t.Error("Expected the conditions to be sorted by type:\n", string(data))
+	}
+
+	if strings.Count(string(data), RedactedTime) != 2 {
+		t.Error("Expected the timestamps to be redacted:\n", string(data))
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conditions.golden.json")
+	conditions := konditions.Conditions{
+		{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCompleted, LastTransitionTime: meta.NewTime(time.Now())},
+	}
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGolden(t, path, conditions)
+
+	t.Setenv(UpdateGoldenEnv, "")
+	conditions[0].LastTransitionTime = meta.NewTime(time.Now().Add(time.Hour))
+	AssertGolden(t, path, conditions)
+
+	recorder := &recordingTB{TB: t}
+	conditions[0].Status = konditions.ConditionError
+	AssertGolden(recorder, path, conditions)
+
+	if len(recorder.failures) != 1 {
+		t.Fatal("Expected the assertion to fail, got: ", recorder.failures)
+	}
+
+	if !strings.Contains(recorder.failures[0], `-     "status": "Completed"`) || !strings.Contains(recorder.failures[0], `+     "status": "Error"`) {
+		t.Error("Expected a diff of the status, got: ", recorder.failures[0])
+	}
+}
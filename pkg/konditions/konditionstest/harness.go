@@ -0,0 +1,144 @@
+// Package konditionstest provides utilities to test controllers built with Konditionner, without
+// having to build custom fake clients for every project.
+package konditionstest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// LockHarness wires a controller-runtime fake client, with the status subresource enabled for the objects
+// given, and records every conditions set written through the status subresource. Failures can be injected
+// on specific status updates to exercise the error paths of a Lock.
+//
+//	harness := konditionstest.NewLockHarness(t, scheme, res).ConflictOnStatusUpdate(2)
+//
+//	err := konditions.NewLock(res, harness.Client, ConditionType("Bucket")).Execute(ctx, task)
+//	if !apierrors.IsConflict(err) {
+//		t.Error("Expected a conflict, got: ", err)
+//	}
+//
+//	harness.AssertStatuses(ConditionType("Bucket"), konditions.ConditionLocked)
+type LockHarness struct {
+	Client client.Client
+
+	t        testing.TB
+	mu       sync.Mutex
+	updates  int
+	failures map[int]error
+	writes   []konditions.Conditions
+}
+
+// NewLockHarness returns a harness with the objects given stored in its fake client. The scheme needs to
+// know about the types of the objects.
+func NewLockHarness(t testing.TB, scheme *runtime.Scheme, objs ...client.Object) *LockHarness {
+	h := &LockHarness{
+		t:        t,
+		failures: map[int]error{},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(objs...).
+		WithObjects(objs...).
+		Build()
+
+	h.Client = interceptor.NewClient(c, interceptor.Funcs{
+		SubResourceUpdate: h.statusUpdate,
+	})
+
+	return h
+}
+
+// FailStatusUpdate makes the nth status update, starting at 1, return the error given. The update
+// isn't written to the fake client.
+func (h *LockHarness) FailStatusUpdate(n int, err error) *LockHarness {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failures[n] = err
+	return h
+}
+
+// ConflictOnStatusUpdate makes the nth status update, starting at 1, return a Conflict error, as if
+// another writer updated the resource first.
+func (h *LockHarness) ConflictOnStatusUpdate(n int) *LockHarness {
+	return h.FailStatusUpdate(n, apierrors.NewConflict(schema.GroupResource{}, "", nil))
+}
+
+// Writes returns a copy of every conditions set that was written through the status subresource, in order.
+// Failed updates aren't included.
+func (h *LockHarness) Writes() []konditions.Conditions {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writes := make([]konditions.Conditions, len(h.writes))
+	for i := range h.writes {
+		writes[i] = h.writes[i].DeepCopy()
+	}
+
+	return writes
+}
+
+// Statuses returns the statuses the condition with the given type had in each of the writes. Writes
+// that don't include the condition are skipped.
+func (h *LockHarness) Statuses(ct konditions.ConditionType) []konditions.ConditionStatus {
+	var statuses []konditions.ConditionStatus
+	for _, conditions := range h.Writes() {
+		if condition, ok := conditions.GetType(ct); ok {
+			statuses = append(statuses, condition.Status)
+		}
+	}
+
+	return statuses
+}
+
+// AssertStatuses fails the test if the statuses written for the condition type aren't exactly the ones given.
+func (h *LockHarness) AssertStatuses(ct konditions.ConditionType, expected ...konditions.ConditionStatus) {
+	h.t.Helper()
+
+	statuses := h.Statuses(ct)
+	if len(statuses) != len(expected) {
+		h.t.Errorf("Expected %s to be written with %v, got %v", ct, expected, statuses)
+		return
+	}
+
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			h.t.Errorf("Expected %s to be written with %v, got %v", ct, expected, statuses)
+			return
+		}
+	}
+}
+
+func (h *LockHarness) statusUpdate(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	h.mu.Lock()
+	h.updates++
+	err, fail := h.failures[h.updates]
+	h.mu.Unlock()
+
+	if fail {
+		return err
+	}
+
+	if err := c.SubResource(subResource).Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	if res, ok := obj.(konditions.ConditionalResource); ok && subResource == "status" {
+		h.mu.Lock()
+		h.writes = append(h.writes, res.Conditions().DeepCopy())
+		h.mu.Unlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package konditionstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// testResource is a minimal custom resource used by the tests of this package.
+type testResource struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Status struct {
+		Conditions konditions.Conditions `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+func (r *testResource) Conditions() *konditions.Conditions {
+	return &r.Status.Conditions
+}
+
+func (r *testResource) DeepCopyObject() runtime.Object {
+	out := &testResource{}
+	out.TypeMeta = r.TypeMeta
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status.Conditions = r.Status.Conditions.DeepCopy()
+	return out
+}
+
+func newTestScheme() *runtime.Scheme {
+	gv := schema.GroupVersion{Group: "konditionner.test", Version: "v1"}
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(gv.WithKind("TestResource"), &testResource{})
+	meta.AddToGroupVersion(scheme, gv)
+	return scheme
+}
+
+func newTestResource(name string) *testResource {
+	return &testResource{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+func TestLockHarness(t *testing.T) {
+	res := newTestResource("harness")
+	harness := NewLockHarness(t, newTestScheme(), res)
+
+	err := konditions.NewLock(res, harness.Client, konditions.ConditionType("Bucket")).Execute(context.Background(), func(condition konditions.Condition) (konditions.Condition, error) {
+		condition.Status = konditions.ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	harness.AssertStatuses(konditions.ConditionType("Bucket"), konditions.ConditionLocked, konditions.ConditionCompleted)
+}
+
+func TestLockHarnessConflict(t *testing.T) {
+	res := newTestResource("harness")
+	harness := NewLockHarness(t, newTestScheme(), res).ConflictOnStatusUpdate(2)
+
+	err := konditions.NewLock(res, harness.Client, konditions.ConditionType("Bucket")).Execute(context.Background(), func(condition konditions.Condition) (konditions.Condition, error) {
+		condition.Status = konditions.ConditionCompleted
+		return condition, nil
+	})
+
+	if !apierrors.IsConflict(err) {
+		t.Error("Expected a conflict, got: ", err)
+	}
+
+	harness.AssertStatuses(konditions.ConditionType("Bucket"), konditions.ConditionLocked)
+}
+
+func TestLockHarnessFailure(t *testing.T) {
+	res := newTestResource("harness")
+	failure := errors.New("Server unavailable")
+	harness := NewLockHarness(t, newTestScheme(), res).FailStatusUpdate(1, failure)
+
+	err := konditions.NewLock(res, harness.Client, konditions.ConditionType("Bucket")).Execute(context.Background(), func(condition konditions.Condition) (konditions.Condition, error) {
+		t.Error("Expected the task to not run")
+		return condition, nil
+	})
+
+	if !errors.Is(err, failure) {
+		t.Error("Expected the failure to be returned, got: ", err)
+	}
+
+	if len(harness.Writes()) != 0 {
+		t.Error("Expected no writes, got: ", harness.Writes())
+	}
+}
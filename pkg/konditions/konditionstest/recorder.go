@@ -0,0 +1,118 @@
+package konditionstest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// Recorder captures every transition of the conditions of resources, in order, so tests can assert the full
+// sequence a condition went through (Initialized → Locked → Created → Completed) rather than only its final state.
+//
+// A Recorder can be plugged in as a Listener, on a Lock or a Tracker:
+//
+//	recorder := konditionstest.NewRecorder()
+//	lock := konditions.NewLock(res, c, ConditionType("Bucket"), konditions.WithListener(recorder.Listener()))
+//
+// Or as a Persister, when the code under test creates its own locks and accepts lock options:
+//
+//	lock := konditions.NewLock(res, c, ConditionType("Bucket"), konditions.WithPersister(recorder.Persister(konditions.StatusPersister{Client: c})))
+//
+//	recorder.AssertSequence(t, ConditionType("Bucket"), konditions.ConditionLocked, konditions.ConditionCompleted)
+type Recorder struct {
+	mu          sync.Mutex
+	transitions []konditions.Transition
+	persisted   map[string]konditions.Conditions
+}
+
+// NewRecorder returns an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		persisted: map[string]konditions.Conditions{},
+	}
+}
+
+// Listener returns a Listener that records the transitions it is notified of.
+func (r *Recorder) Listener() konditions.Listener {
+	return func(obj konditions.ConditionalResource, transition konditions.Transition) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.transitions = append(r.transitions, transition)
+	}
+}
+
+// Persister returns a Persister that records the transitions between each write of a resource, and then
+// delegates to the persister given. If the persister given is nil, nothing is written.
+//
+// Transitions are only recorded once the write succeeds. The first write of a resource is compared
+// to an empty conditions set.
+func (r *Recorder) Persister(next konditions.Persister) konditions.Persister {
+	return konditions.PersisterFunc(func(ctx context.Context, obj konditions.ConditionalResource) error {
+		if next != nil {
+			if err := next.Persist(ctx, obj); err != nil {
+				return err
+			}
+		}
+
+		key := obj.GetNamespace() + "/" + obj.GetName()
+		conditions := obj.Conditions().DeepCopy()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.transitions = append(r.transitions, conditions.Diff(r.persisted[key])...)
+		r.persisted[key] = conditions
+		return nil
+	})
+}
+
+// Transitions returns the transitions recorded, in order.
+func (r *Recorder) Transitions() []konditions.Transition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]konditions.Transition(nil), r.transitions...)
+}
+
+// Sequence returns the statuses the condition with the given type went through. The sequence starts with the
+// status the condition had before its first recorded transition, if it existed. A removal doesn't add a status.
+func (r *Recorder) Sequence(ct konditions.ConditionType) []konditions.ConditionStatus {
+	var sequence []konditions.ConditionStatus
+
+	for _, transition := range r.Transitions() {
+		if transition.Type != ct {
+			continue
+		}
+
+		if len(sequence) == 0 && transition.Old != nil {
+			sequence = append(sequence, transition.Old.Status)
+		}
+
+		if transition.New != nil {
+			sequence = append(sequence, transition.New.Status)
+		}
+	}
+
+	return sequence
+}
+
+// AssertSequence fails the test if the sequence of statuses of the condition isn't exactly the one given.
+func (r *Recorder) AssertSequence(t testing.TB, ct konditions.ConditionType, expected ...konditions.ConditionStatus) {
+	t.Helper()
+
+	sequence := r.Sequence(ct)
+	if len(sequence) != len(expected) {
+		t.Errorf("Expected %s to go through %v, got %v", ct, expected, sequence)
+		return
+	}
+
+	for i := range expected {
+		if sequence[i] != expected[i] {
+			t.Errorf("Expected %s to go through %v, got %v", ct, expected, sequence)
+			return
+		}
+	}
+}
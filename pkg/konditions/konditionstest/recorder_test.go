@@ -0,0 +1,41 @@
+package konditionstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+func TestRecorderListener(t *testing.T) {
+	res := newTestResource("recorder")
+	res.Status.Conditions = konditions.Conditions{{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionInitialized}}
+
+	recorder := NewRecorder()
+	tracker := konditions.NewTracker(res, recorder.Listener())
+	tracker.SetCondition(konditions.Condition{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCreated})
+	tracker.SetCondition(konditions.Condition{Type: konditions.ConditionType("Bucket"), Status: konditions.ConditionCompleted})
+
+	recorder.AssertSequence(t, konditions.ConditionType("Bucket"), konditions.ConditionInitialized, konditions.ConditionCreated, konditions.ConditionCompleted)
+}
+
+func TestRecorderPersister(t *testing.T) {
+	res := newTestResource("recorder")
+	recorder := NewRecorder()
+
+	lock := konditions.NewLock(res, nil, konditions.ConditionType("Bucket"), konditions.WithPersister(recorder.Persister(nil)))
+	err := lock.Execute(context.Background(), func(condition konditions.Condition) (konditions.Condition, error) {
+		condition.Status = konditions.ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.AssertSequence(t, konditions.ConditionType("Bucket"), konditions.ConditionLocked, konditions.ConditionCompleted)
+
+	if len(recorder.Transitions()) != 2 {
+		t.Error("Unexpected transitions: ", recorder.Transitions())
+	}
+}
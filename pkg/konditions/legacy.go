@@ -0,0 +1,145 @@
+package konditions
+
+import (
+	"encoding/json"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LegacyMapping maps the statuses of metav1.Condition to the statuses of Konditionner, see Conditions.Migrate.
+type LegacyMapping struct {
+	True    ConditionStatus
+	False   ConditionStatus
+	Unknown ConditionStatus
+}
+
+// DefaultLegacyMapping is the mapping used to migrate conditions stored with the conventions of metav1.Condition.
+//
+// A True condition is ConditionCompleted. A False condition is as often in progress as it is failed, a Ready
+// condition is False while the resource is provisioned for instance: mapping it to ConditionError would make it
+// terminal and the controller would never reconcile it again. False and Unknown conditions are ConditionInitialized,
+// so the controller reconciles them and sets their actual status.
+//
+// Operators whose legacy conditions had a different meaning can replace the mapping before the conditions are
+// decoded, usually from an init function.
+var DefaultLegacyMapping = LegacyMapping{
+	True:    ConditionCompleted,
+	False:   ConditionInitialized,
+	Unknown: ConditionInitialized,
+}
+
+// Status returns the status the legacy status maps to, and false if the status isn't one of metav1.Condition.
+func (m LegacyMapping) Status(status ConditionStatus) (ConditionStatus, bool) {
+	switch meta.ConditionStatus(status) {
+	case meta.ConditionTrue:
+		return m.True, true
+	case meta.ConditionFalse:
+		return m.False, true
+	case meta.ConditionUnknown:
+		return m.Unknown, true
+	default:
+		return status, false
+	}
+}
+
+// Condition returns the metav1.Condition as a Condition: the status is mapped, the message becomes the reason, or
+// the reason of the metav1.Condition if it doesn't have a message.
+func (m LegacyMapping) Condition(condition meta.Condition) Condition {
+	status, _ := m.Status(ConditionStatus(condition.Status))
+
+	reason := condition.Message
+	if reason == "" {
+		reason = condition.Reason
+	}
+
+	return Condition{
+		Type:               ConditionType(condition.Type),
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: condition.LastTransitionTime,
+		ObservedGeneration: condition.ObservedGeneration,
+	}
+}
+
+// MigrateMeta returns the metav1.Conditions given as Conditions, mapped with DefaultLegacyMapping. It is meant for
+// the resources that are moving a field from []metav1.Condition to Conditions:
+//
+//	if len(res.Status.Conditions) == 0 && len(res.Status.LegacyConditions) > 0 {
+//		conditions, err := konditions.MigrateMeta(res.Status.LegacyConditions)
+//		if err != nil {
+//			log.Info("Legacy conditions were normalized", "anomalies", err.Error())
+//		}
+//
+//		res.Status.Conditions = conditions
+//		res.Status.LegacyConditions = nil
+//	}
+//
+// The conditions returned are normalized, see Conditions.Normalize. The only error returned is the *NormalizedError
+// that reports the anomalies fixed, the conditions are usable either way.
+func MigrateMeta(conditions []meta.Condition) (Conditions, error) {
+	migrated := make(Conditions, 0, len(conditions))
+	for _, condition := range conditions {
+		migrated = append(migrated, DefaultLegacyMapping.Condition(condition))
+	}
+
+	return migrated, normalizeDecoded(&migrated)
+}
+
+// Migrate maps the conditions that still have the True, False or Unknown status of metav1.Condition with
+// DefaultLegacyMapping, and returns the types of the conditions that were migrated. A controller can migrate the
+// conditions of the resources stored before it used Konditionner, and persist them, as part of its reconciliation:
+//
+//	if migrated := res.Status.Conditions.Migrate(); len(migrated) > 0 {
+//		if err := reconciler.Status().Update(ctx, &res); err != nil {
+//			return ctrl.Result{}, err
+//		}
+//	}
+//
+// The message of a metav1.Condition is dropped when it's decoded into a Condition, the reason of the migrated
+// condition is the machine readable reason of the legacy condition. Enabling the LegacyConditions feature keeps the
+// message: the conditions are migrated as they are decoded, the message becoming their reason.
+func (c *Conditions) Migrate() []ConditionType {
+	if c == nil {
+		return nil
+	}
+
+	var migrated []ConditionType
+	for i := range *c {
+		condition := &(*c)[i]
+		if status, ok := DefaultLegacyMapping.Status(condition.Status); ok {
+			condition.Status = status
+			migrated = append(migrated, condition.Type)
+		}
+	}
+
+	return migrated
+}
+
+// Used to decode a condition without calling UnmarshalJSON recursively.
+type conditionJSON Condition
+
+// UnmarshalJSON decodes the condition. When the LegacyConditions feature is enabled, a condition serialized with
+// the conventions of metav1.Condition is migrated as it is decoded, see Conditions.Migrate.
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	if !Gates.Enabled(LegacyConditions) {
+		return json.Unmarshal(data, (*conditionJSON)(c))
+	}
+
+	var legacy struct {
+		conditionJSON
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	*c = Condition(legacy.conditionJSON)
+	if status, ok := DefaultLegacyMapping.Status(c.Status); ok {
+		c.Status = status
+		if legacy.Message != "" {
+			c.Reason = legacy.Message
+		}
+	}
+
+	return nil
+}
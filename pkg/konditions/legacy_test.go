@@ -0,0 +1,109 @@
+package konditions
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const legacyConditionsJSON = `[
+	{"type": "Ready", "status": "True", "reason": "Provisioned", "message": "Bucket is ready", "lastTransitionTime": "2024-01-01T09:00:00Z", "observedGeneration": 3},
+	{"type": "DNS", "status": "False", "reason": "ZoneMissing", "lastTransitionTime": "2024-01-01T09:00:00Z"},
+	{"type": "Bucket", "status": "Locked", "reason": "Resource locked", "lastTransitionTime": "2024-01-01T09:00:00Z"}
+]`
+
+func TestConditionsMigrate(t *testing.T) {
+	var conditions Conditions
+	if err := json.Unmarshal([]byte(legacyConditionsJSON), &conditions); err != nil {
+		t.Fatal(err)
+	}
+
+	if conditions.MustType(ConditionType("Ready")).Status != ConditionStatus("True") {
+		t.Fatal("Expected the legacy status to be decoded as is without the feature")
+	}
+
+	migrated := conditions.Migrate()
+	if len(migrated) != 2 || migrated[0] != ConditionType("Ready") || migrated[1] != ConditionType("DNS") {
+		t.Error("Expected the legacy conditions to be migrated, got: ", migrated)
+	}
+
+	ready := conditions.MustType(ConditionType("Ready"))
+	if ready.Status != ConditionCompleted || ready.Reason != "Provisioned" || ready.ObservedGeneration != 3 {
+		t.Error("Unexpected migrated condition: ", ready)
+	}
+
+	if dns := conditions.MustType(ConditionType("DNS")); dns.Status != ConditionInitialized {
+		t.Error("Expected a False condition to be reconciled again, got: ", dns.Status)
+	}
+
+	if bucket := conditions.MustType(ConditionType("Bucket")); bucket.Status != ConditionLocked {
+		t.Error("Expected the conditions of Konditionner to be left as they are, got: ", bucket.Status)
+	}
+
+	if migrated := conditions.Migrate(); len(migrated) != 0 {
+		t.Error("Expected the conditions to be migrated once, got: ", migrated)
+	}
+}
+
+func TestLegacyConditionsFeature(t *testing.T) {
+	enableFeatures(t, LegacyConditions)
+
+	var conditions Conditions
+	if err := json.Unmarshal([]byte(legacyConditionsJSON), &conditions); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := conditions.MustType(ConditionType("Ready"))
+	if ready.Status != ConditionCompleted || ready.Reason != "Bucket is ready" {
+		t.Error("Expected the condition to be migrated as it's decoded, with its message as reason, got: ", ready)
+	}
+
+	if dns := conditions.MustType(ConditionType("DNS")); dns.Status != ConditionInitialized || dns.Reason != "ZoneMissing" {
+		t.Error("Expected the reason to be kept without a message, got: ", dns)
+	}
+
+	if bucket := conditions.MustType(ConditionType("Bucket")); bucket.Status != ConditionLocked || bucket.Reason != "Resource locked" {
+		t.Error("Expected the conditions of Konditionner to be decoded as they are, got: ", bucket)
+	}
+
+	if len(conditions.Migrate()) != 0 {
+		t.Error("Expected the conditions to already be migrated")
+	}
+}
+
+func TestMigrateMeta(t *testing.T) {
+	transition := meta.NewTime(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	conditions, err := MigrateMeta([]meta.Condition{
+		{Type: "Ready", Status: meta.ConditionTrue, Reason: "Provisioned", Message: "Bucket is ready", LastTransitionTime: transition, ObservedGeneration: 2},
+		{Type: "DNS", Status: meta.ConditionUnknown, Reason: "Pending", LastTransitionTime: transition},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conditions) != 2 || conditions[0].Type != ConditionType("DNS") {
+		t.Fatal("Expected the conditions to be normalized, got: ", conditions)
+	}
+
+	ready := conditions.MustType(ConditionType("Ready"))
+	if ready.Status != ConditionCompleted || ready.Reason != "Bucket is ready" || !ready.LastTransitionTime.Equal(&transition) || ready.ObservedGeneration != 2 {
+		t.Error("Unexpected migrated condition: ", ready)
+	}
+
+	if dns := conditions.MustType(ConditionType("DNS")); dns.Status != ConditionInitialized || dns.Reason != "Pending" {
+		t.Error("Unexpected migrated condition: ", dns)
+	}
+
+	conditions, err = MigrateMeta([]meta.Condition{
+		{Type: "Ready", Status: meta.ConditionTrue, LastTransitionTime: transition},
+		{Type: "Ready", Status: meta.ConditionFalse, LastTransitionTime: meta.NewTime(transition.Add(time.Hour))},
+	})
+
+	var normalized *NormalizedError
+	if !errors.As(err, &normalized) || !errors.Is(err, DuplicateConditionTypeErr) || len(conditions) != 1 {
+		t.Error("Expected the duplicates to be reported along with the normalized conditions, got: ", conditions, err)
+	}
+}
@@ -3,7 +3,11 @@ package konditions
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -75,6 +79,18 @@ var LockNotReleasedErr = errors.New("Condition's lock was not released")
 //		return condition, reconciler.Status().Update(ctx, &res)
 //	})
 //
+// When the Task writes the resource itself, with the condition it returns already stored in it, the Lock doesn't
+// write the resource again to release the condition:
+//
+//	lock.Execute(ctx, func(condition Condition) (Condition, error) {
+//		// ...
+//		condition.Status = konditions.ConditionCreated
+//		condition.ObservedGeneration = res.Generation
+//		res.Status.Conditions.SetCondition(condition)
+//
+//		return condition, reconciler.Status().Update(ctx, &res)
+//	})
+//
 // The condition with type "Bucket" will have its Status go through a few stages:
 //   - Initialized
 //   - Locked
@@ -83,8 +99,62 @@ type Lock struct {
 	client    client.Client
 	obj       ConditionalResource
 	condition Condition
+
+	persister Persister
+	mirror    bool
+	summarize bool
+
+	listeners []Listener
+	tracker   *Tracker
+
+	waitQueue   *WaitQueue
+	degraded    *DegradedDetector
+	retryBudget *RetryBudget
+	commitRetry *CommitRetry
+	breaker     *CircuitBreaker
+
+	deletionPolicy *DeletionPolicy
+	manager        string
+	sanitizer      Sanitizer
+
+	fencing bool
+	token   string
+	index   int
+
+	preconditioned  bool
+	resourceVersion string
+
+	lockedBehavior LockedBehavior
+	unlockInterval time.Duration
+	unlockTimeout  time.Duration
+
+	restore       bool
+	persistFailed bool
+
+	removeOnRelease bool
+
+	watchdog  *Watchdog
+	observers []Observer
+
+	result  ExecuteResult
+	written client.Object
+
+	pending        string
+	pendingVersion string
+
+	freshness *freshnessCheck
+	reader    client.Reader
+	verifier  client.Reader
+
+	deletedBehavior DeletedBehavior
 }
 
+// LockOption configures optional behavior of a Lock. Options are passed to NewLock
+// and are applied in order, after the lock is initialized.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.MirrorToMeta())
+type LockOption func(*Lock)
+
 // Task is a unit of work on a given Condition as specified by the lock.
 // The condition is a copy of the condition *before* the lock was obtained. This is useful
 // as the status can be useful to make a decision.
@@ -164,14 +234,34 @@ type ConditionalResource interface {
 // The Client interface is usually the reconciler controller you are within.
 //
 //	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"))
-func NewLock(obj ConditionalResource, c client.Client, ct ConditionType) *Lock {
+//
+// Options can be passed to modify how the lock behaves, see LockOption.
+//
+// The client is used by the default persister. When the lock is configured with a Persister that
+// doesn't use controller-runtime, DynamicStatusPersister for instance, the client can be nil. The options
+// that read or patch the resource still need it: WithFencing, WithWatchdog and WaitForUnlock, unless
+// WithAPIReader gives the lock a reader. Execute returns an error wrapping ClientRequiredErr when one of
+// them is used without a client, before the condition is locked. RecordPendingOutcome needs it too.
+//
+// The package itself still depends on controller-runtime: a ConditionalResource is a client.Object and
+// the lock holds a client.Client. Only the writes can go through client-go.
+func NewLock(obj ConditionalResource, c client.Client, ct ConditionType, opts ...LockOption) *Lock {
 	condition := obj.Conditions().FindOrInitializeFor(ct)
 
-	return &Lock{
+	lock := &Lock{
 		client:    c,
 		condition: condition,
 		obj:       obj,
+		persister: StatusPersister{Client: c},
+		tracker:   NewTracker(obj),
+		sanitizer: DefaultSanitizer,
+	}
+
+	for _, opt := range opts {
+		opt(lock)
 	}
+
+	return lock
 }
 
 // Execute the task after successfully setting the condition to ConditionLocked.
@@ -203,49 +293,474 @@ func NewLock(obj ConditionalResource, c client.Client, ct ConditionType) *Lock {
 // a bunch of pros/cons to consider and at this time, I (P-O) just don't know which direction
 // is the more user friendly.
 //
+// A condition in a terminal status (see Condition.IsTerminal) can't be locked, Execute returns
+// TerminalConditionErr without running the Task.
+//
+// If the condition type is paused (see PausedAnnotation), the condition is suspended and
+// Execute returns PausedConditionErr without running the Task. Once the pause is lifted, the
+// condition resumes with the status it had before it was suspended.
+//
 // It is *required* that the Task changes the status of the Condition to its final value.
 // If the condition still has the status ConditionLocked when the task returns, the
 // Execute method will set the Condition to ConditionError with the Error
 // set to `LockNotReleasedErr`.
+//
+// If the resource is deleted while the Task runs, the condition can't be released and Execute returns the error
+// of the Task, see WithDeletedBehavior.
+//
+// Use ExecuteWithResult to find out what the lock did, beside the error.
 func (l *Lock) Execute(ctx context.Context, task Task) (err error) {
-	if l.condition.Status == ConditionLocked {
-		return LockNotReleasedErr
-	}
+	return l.execute(ctx, "", task)
+}
 
-	l.obj.Conditions().SetCondition(Condition{
-		Type:   l.condition.Type,
-		Status: ConditionLocked,
-		Reason: "Resource locked",
+// ExecuteWithIntent behaves like Execute but records an intent on the condition before the task
+// runs. The intent is the identifier of the external resource the task is about to create, stored
+// in the Ref of the condition while it is locked.
+//
+// This is the first phase of a two-phase commit: if the operator crashes after the external
+// resource is created, but before the condition is released, the condition stays locked with
+// the Ref of the external resource. Recovery code can then find the intents that were never
+// confirmed with PendingIntents and decide what to do with the orphaned resources.
+//
+// The second phase, the confirmation, happens when the condition is released. The Ref is kept
+// on the condition, unless the task sets a different one.
+//
+//	name := fmt.Sprintf("%s-%s", res.Namespace, res.Name)
+//	err := lock.ExecuteWithIntent(ctx, name, func(condition konditions.Condition) (konditions.Condition, error) {
+//		if err := s3.CreateBucket(ctx, name); err != nil {
+//			return condition, err
+//		}
+//
+//		condition.Status = konditions.ConditionCompleted
+//		return condition, nil
+//	})
+func (l *Lock) ExecuteWithIntent(ctx context.Context, ref string, task Task) error {
+	return l.execute(ctx, ref, func(condition Condition) (Condition, error) {
+		condition, err := task(condition)
+		if condition.Ref == "" {
+			condition.Ref = ref
+		}
+		return condition, err
 	})
+}
+
+func (l *Lock) execute(ctx context.Context, ref string, task Task) error {
+	l.result = ExecuteResult{}
+	l.pending = ""
+	l.pendingVersion = ""
+	defer func() {
+		if condition, ok := l.obj.Conditions().GetType(l.condition.Type); ok {
+			l.result.Status = condition.Status
+		}
+	}()
+
+	acquired := l.observe(Observer.ObserveAcquire)
+	if err := l.checkClient(); err != nil {
+		acquired(err)
+		return err
+	}
 
-	if err := l.client.Status().Update(ctx, l.obj); err != nil {
+	if err := l.checkFreshness(ctx); err != nil {
+		acquired(err)
 		return err
 	}
 
-	l.condition, err = task(l.condition)
+	if l.restore {
+		snapshot := takeConditionsSnapshot(l.obj)
+		defer func() {
+			if l.persistFailed {
+				snapshot.restoreInto(l.obj)
+			}
+		}()
+	}
+
+	if l.deletionPolicy != nil && !l.obj.GetDeletionTimestamp().IsZero() {
+		termination, err := l.deletionPolicy.taskFor(l.condition.Type)
+		if err != nil {
+			acquired(err)
+			return err
+		}
+		task = termination
+	}
+
+	snapshot, err := l.acquire(ctx, ref)
+	acquired(err)
+
+	if errors.Is(err, skipLockedErr) {
+		return nil
+	}
 
 	if err != nil {
-		l.condition.Status = ConditionError
-		l.condition.Reason = err.Error()
-		l.obj.Conditions().SetCondition(l.condition)
+		return err
+	}
+	l.result.Acquired = true
+
+	stopWatchdog := l.startWatchdog(ctx)
+	ran := l.observe(Observer.ObserveTask)
+	resourceVersion := l.obj.GetResourceVersion()
+	condition, err := task(snapshot.Condition)
+	l.result.TaskRan = true
+	ran(err)
+	l.recordTaskWrite(resourceVersion)
+	stopWatchdog()
+
+	condition.ObservedGeneration = snapshot.Generation
+	condition = l.settlePendingOutcome(condition, err)
+
+	if err != nil {
+		condition.Status = ConditionError
+		condition.Reason = err.Error()
+	}
+
+	committed := l.observe(Observer.ObserveCommit)
+	if err == nil && l.removeOnRelease {
+		removeErr := l.remove(ctx, condition.Type)
+		committed(removeErr)
+		if isDeleted(removeErr) {
+			return l.releaseDeleted(removeErr, nil)
+		}
+
+		if removeErr != nil {
+			return removeErr
+		}
+
+		return l.verifyCommit(ctx)
+	}
+
+	commitErr := l.commit(ctx, condition)
+	committed(commitErr)
+	if isDeleted(commitErr) {
+		return l.releaseDeleted(commitErr, err)
+	}
+
+	if commitErr != nil {
+		return commitErr
 	}
 
-	l.obj.Conditions().SetCondition(l.condition)
+	if verifyErr := l.verifyCommit(ctx); verifyErr != nil {
+		return verifyErr
+	}
+
+	return err
+}
+
+// Sets the condition to ConditionLocked and persists it. The snapshot returned holds
+// the condition as it was before it was locked.
+func (l *Lock) acquire(ctx context.Context, ref string) (Snapshot, error) {
+	if l.condition.Status == ConditionLocked {
+		if err := l.handleLocked(ctx); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	if l.condition.IsTerminal() {
+		return Snapshot{}, fmt.Errorf("%w: %s is %s", TerminalConditionErr, l.condition.Type, l.condition.Status)
+	}
+
+	if IsPaused(l.obj, l.condition.Type) {
+		if l.condition.Status == ConditionSuspended {
+			return Snapshot{}, PausedConditionErr
+		}
+
+		l.obj.Conditions().Suspend(l.condition.Type, "Paused with the "+PausedAnnotation+" annotation")
+		if err := l.persist(ctx); err != nil {
+			return Snapshot{}, err
+		}
+
+		l.condition = l.obj.Conditions().FindOrInitializeFor(l.condition.Type)
+		return Snapshot{}, PausedConditionErr
+	}
+
+	if l.condition.Status == ConditionSuspended {
+		l.obj.Conditions().Resume(l.condition.Type)
+		l.condition = l.obj.Conditions().FindOrInitializeFor(l.condition.Type)
+	}
+
+	if l.breaker != nil {
+		if err := l.breaker.Allow(l.condition.Type); err != nil {
+			return Snapshot{}, l.shortCircuit(ctx, err)
+		}
+	}
+
+	// The task sees the status the condition had before the dependency became unavailable.
+	if l.condition.Status == ConditionDependencyUnavailable {
+		l.condition.Status = l.condition.ResumeStatus
+		l.condition.ResumeStatus = ""
+	}
+
+	// Attributes are kept while the condition is locked, they are checkpoints the task may need
+	// if it is interrupted.
+	locked := Condition{
+		Type:       l.condition.Type,
+		Status:     ConditionLocked,
+		Reason:     "Resource locked",
+		Attributes: l.condition.DeepCopy().Attributes,
+		Manager:    l.condition.Manager,
+	}
 
-	if c := l.obj.Conditions().FindType(l.condition.Type); c.Status == ConditionLocked {
-		l.condition.Status = ConditionError
-		l.condition.Reason = LockNotReleasedErr.Error()
-		l.obj.Conditions().SetCondition(l.condition)
+	if l.manager != "" {
+		locked.Manager = l.manager
+	}
+
+	if ref != "" {
+		locked.Ref = ref
+		locked.Reason = "Intent recorded for " + ref
+	}
+
+	if l.fencing {
+		token, err := newFencingToken()
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		if locked.Attributes == nil {
+			locked.Attributes = map[string]string{}
+		}
+		locked.Attributes[FencingTokenAttribute] = token
+		l.token = token
+	}
+
+	if err := l.obj.Conditions().SetCondition(locked); err != nil {
+		return Snapshot{}, err
+	}
+
+	if err := l.persist(ctx); err != nil {
+		return Snapshot{}, err
+	}
+
+	if l.preconditioned {
+		l.resourceVersion = l.obj.GetResourceVersion()
+	}
+
+	// The position of the condition, as it was stored, is where the fencing token is checked on release.
+	for i, condition := range *l.obj.Conditions() {
+		if condition.Type == l.condition.Type {
+			l.index = i
+		}
+	}
+
+	return Snapshot{
+		Condition:  *l.condition.DeepCopy(),
+		Generation: l.obj.GetGeneration(),
+	}, nil
+}
+
+// Stores the condition, releasing the lock, and persists it. If the condition is still
+// locked, it is set to ConditionError and LockNotReleasedErr is returned once persisted.
+func (l *Lock) commit(ctx context.Context, condition Condition) (err error) {
+	if l.waitQueue != nil {
+		// Waiters are woken up even if the commit fails, so they can find out for themselves.
+		defer l.waitQueue.Release(l.obj, condition.Type)
+	}
+
+	if l.manager != "" {
+		condition.Manager = l.manager
+	}
+
+	delete(condition.Attributes, FencingTokenAttribute)
+
+	if condition.Status == ConditionLocked {
+		condition.Status = ConditionError
+		condition.Reason = LockNotReleasedErr.Error()
 		err = LockNotReleasedErr
 	}
 
-	if updateErr := l.client.Status().Update(ctx, l.obj); updateErr != nil {
+	// The condition handed to the task carries the transition time it had before it was locked,
+	// releasing the lock is a transition of its own. If the task stored the condition already, the
+	// transition happened then.
+	if existing := l.obj.Conditions().FindType(condition.Type); existing != nil && existing.Status != condition.Status {
+		condition.LastTransitionTime = meta.Time{}
+	} else if existing != nil && condition.LastTransitionTime.Before(&existing.LastTransitionTime) {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+
+	if l.degraded != nil {
+		condition = l.degraded.Observe(l.obj, condition)
+	}
+
+	if l.retryBudget != nil {
+		condition = l.retryBudget.Observe(condition, now().Time)
+	}
+
+	if l.breaker != nil {
+		l.breaker.Record(condition.Type, condition.Class() == ClassError)
+	}
+
+	l.condition = condition
+	if setErr := l.obj.Conditions().setCondition(condition, true); setErr != nil {
+		return setErr
+	}
+
+	if l.degraded != nil {
+		l.degraded.Update(l.obj)
+	}
+
+	if updateErr := l.persistRelease(ctx, l.releasePersister(condition.Type)); updateErr != nil {
 		return updateErr
 	}
 
 	return err
 }
 
+// Returns the persister that releases the lock: the conditional patch of WithFencing, the persister of the
+// lock otherwise. The resourceVersion recorded by CommitWithResourceVersion is restored on the resource.
+func (l *Lock) releasePersister(ct ConditionType) Persister {
+	if l.resourceVersion != "" {
+		l.obj.SetResourceVersion(l.resourceVersion)
+	}
+
+	persister := l.persister
+	if l.token != "" {
+		_, object := l.persister.(ObjectPersister)
+		persister = fencedPersister{
+			client:          l.client,
+			subresource:     !object,
+			ct:              ct,
+			index:           l.index,
+			token:           l.token,
+			resourceVersion: l.resourceVersion,
+		}
+	}
+
+	if l.resourceVersion == "" {
+		return persister
+	}
+
+	return PersisterFunc(func(ctx context.Context, obj ConditionalResource) error {
+		return resourceModified(ct, l.resourceVersion, persister.Persist(ctx, obj))
+	})
+}
+
+// Sets the condition to ConditionDependencyUnavailable, unless it already is, and returns the error of the breaker.
+// The status the condition had before is kept in its ResumeStatus, like a suspended condition.
+func (l *Lock) shortCircuit(ctx context.Context, err error) error {
+	if l.condition.Status == ConditionDependencyUnavailable {
+		return err
+	}
+
+	unavailable := Condition{
+		Type:         l.condition.Type,
+		Status:       ConditionDependencyUnavailable,
+		Reason:       err.Error(),
+		Attributes:   l.condition.DeepCopy().Attributes,
+		ResumeStatus: l.condition.Status,
+	}
+
+	if setErr := l.obj.Conditions().SetCondition(unavailable); setErr != nil {
+		return setErr
+	}
+
+	if persistErr := l.persist(ctx); persistErr != nil {
+		return persistErr
+	}
+
+	l.condition = unavailable
+	return err
+}
+
+// persist sends the in-memory state of the object to the Kubernetes API. Every write
+// the lock makes goes through here so that options operating on the object before it is
+// sent (MirrorToMeta, etc.) are applied consistently.
+func (l *Lock) persist(ctx context.Context) error {
+	return l.persistWith(ctx, l.persister)
+}
+
+func (l *Lock) persistWith(ctx context.Context, persister Persister) error {
+	if l.sanitizer != nil {
+		conditions := *l.obj.Conditions()
+		for i := range conditions {
+			if conditions[i].Type == l.condition.Type {
+				conditions[i] = SanitizeCondition(l.sanitizer, conditions[i])
+			}
+		}
+	}
+
+	if l.mirror || l.summarize {
+		obj, ok := l.obj.(MetaConditionalResource)
+		if !ok {
+			return MetaConditionsNotSupportedErr
+		}
+
+		if l.mirror {
+			obj.Conditions().MirrorInto(obj.MetaConditions(), obj.GetGeneration())
+		}
+
+		if l.summarize {
+			obj.Conditions().SummarizeInto(obj.MetaConditions(), obj.GetGeneration())
+		}
+	}
+
+	written := l.written
+	l.written = nil
+
+	// When the task already wrote the resource as it is, writing it again would only risk a conflict.
+	if written == nil || !equality.Semantic.DeepEqual(written, l.obj) {
+		if err := persister.Persist(ctx, l.obj); err != nil {
+			l.persistFailed = true
+			return err
+		}
+		l.result.Writes++
+	}
+
+	previous := l.tracker.clean
+	l.tracker.MarkClean()
+	notify(l.obj, l.listeners, l.obj.Conditions().Diff(previous))
+
+	return nil
+}
+
+// Keeps a copy of the resource if the task wrote it, which is the case when its resourceVersion changed while the
+// task ran. The copy is what the Kubernetes API returned to the task, unless the task changed the resource after
+// writing it, and the release isn't persisted if it doesn't change anything to it.
+//
+// The release is always persisted when it needs to check something on the way: the fencing token or the
+// resourceVersion recorded by CommitWithResourceVersion. Resources that encode their conditions, and custom
+// persisters, are always persisted too since the lock can't tell what the task wrote.
+func (l *Lock) recordTaskWrite(resourceVersion string) {
+	if l.obj.GetResourceVersion() == resourceVersion || l.obj.GetResourceVersion() == l.pendingVersion || l.token != "" || l.preconditioned {
+		return
+	}
+
+	if _, encoded := l.obj.(EncodedResource); encoded {
+		return
+	}
+
+	switch l.persister.(type) {
+	case StatusPersister, ObjectPersister:
+		l.written = l.obj.DeepCopyObject().(client.Object)
+	}
+}
+
+// WithStatusSubresource configures whether the lock writes the conditions through the status subresource
+// of the resource, which is the default. CRDs that don't have the status subresource enabled need to
+// set this to false, the lock will then update the whole object instead.
+//
+// When the status subresource is used but the CRD doesn't have it enabled, Execute returns
+// an error wrapping StatusSubresourceMissingErr.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithStatusSubresource(false))
+//
+// This is a shorthand for WithPersister(ObjectPersister{Client: c}) when set to false.
+func WithStatusSubresource(enabled bool) LockOption {
+	return func(l *Lock) {
+		if enabled {
+			l.persister = StatusPersister{Client: l.client}
+		} else {
+			l.persister = ObjectPersister{Client: l.client}
+		}
+	}
+}
+
+// WithPersister configures the lock to write the resource with the Persister given instead of
+// the default StatusPersister.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithPersister(myPersister))
+func WithPersister(p Persister) LockOption {
+	return func(l *Lock) {
+		l.persister = p
+	}
+}
+
 // Returns a copy of the condition for which the lock has been created
 //
 // This is a helper method to allow creator of locks to easily retrieve
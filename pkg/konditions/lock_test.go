@@ -1,8 +1,203 @@
 package konditions
 
-// I would love to have tests here, but since the Lock interacts with a Kubernetes client
-// the amount of mocking to get a test up and running is borderline crazy. Until I find a way to get
-// a solid testsuite running for the lock, this will remain empty, unfortunately.
-//
-// I'm hoping to find a library that can easily work with kubernetes mocks or I find enough time
-// to build a suite that makes it possible to run different scenarios with sane expectations.
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLockExecute(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if condition.Status != ConditionInitialized {
+			t.Error("Expected the task to receive the condition before it was locked, got: ", condition.Status)
+		}
+
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionLocked) {
+			t.Error("Expected the condition to be locked while the task runs")
+		}
+
+		condition.Status = ConditionCreated
+		condition.Reason = "Bucket created"
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCreated) {
+		t.Error("Expected the condition to be created, got: ", stored.Conditions())
+	}
+}
+
+func TestLockExecuteTaskError(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClient(res)
+
+	taskErr := errors.New("Bucket already exists")
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, taskErr
+	})
+
+	if !errors.Is(err, taskErr) {
+		t.Error("Expected the task error to be returned, got: ", err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := stored.Conditions().FindType(ConditionType("Bucket"))
+	if condition == nil || condition.Status != ConditionError || condition.Reason != taskErr.Error() {
+		t.Error("Expected the condition to be errored, got: ", condition)
+	}
+}
+
+func TestLockExecuteNotReleased(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionLocked
+		return condition, nil
+	})
+
+	if !errors.Is(err, LockNotReleasedErr) {
+		t.Error("Expected LockNotReleasedErr, got: ", err)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionError) {
+		t.Error("Expected the condition to be errored")
+	}
+
+}
+
+func TestLockWithoutStatusSubresource(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClientWithoutStatusSubresource(res)
+
+	task := func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	}
+
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, task)
+	if !errors.Is(err, StatusSubresourceMissingErr) {
+		t.Error("Expected StatusSubresourceMissingErr, got: ", err)
+	}
+
+	res = newTestResource("lock")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), res); err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLock(res, c, ConditionType("Bucket"), WithStatusSubresource(false)).Execute(ctx, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be stored with the object, got: ", stored.Conditions())
+	}
+}
+
+func TestLockStatusSubresourceDeletedObject(t *testing.T) {
+	res := newTestResource("deleted")
+	c := newTestClient()
+
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if err == nil || errors.Is(err, StatusSubresourceMissingErr) {
+		t.Error("Expected a NotFound error, got: ", err)
+	}
+}
+
+func TestLockSkipsReleaseWrittenByTask(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClient(res)
+
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		condition.Reason = "Bucket created"
+		condition.ObservedGeneration = res.Generation
+		if err := res.Conditions().SetCondition(condition); err != nil {
+			return condition, err
+		}
+
+		return condition, c.Status().Update(ctx, res)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Writes != 1 {
+		t.Error("Expected the lock to only write the locked condition, got: ", result.Writes)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition written by the task to be stored, got: ", stored.Conditions())
+	}
+}
+
+func TestLockReleasesAfterTaskWrite(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("lock")
+	c := newTestClient(res)
+
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, c.Status().Update(ctx, res)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Writes != 2 {
+		t.Error("Expected the lock to release the condition, got: ", result.Writes)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be released, got: ", stored.Conditions())
+	}
+}
@@ -0,0 +1,113 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LockedBehavior decides what Execute does when the condition it is about to lock is already ConditionLocked,
+// see WithLockedBehavior.
+type LockedBehavior int
+
+const (
+	// RefuseLocked makes Execute return LockNotReleasedErr without running the task. This is the default.
+	RefuseLocked LockedBehavior = iota
+
+	// SkipLocked makes Execute return nil without running the task: another task is working on the
+	// condition, there's nothing to do until it releases it.
+	SkipLocked
+
+	// TakeOverLocked locks the condition again and runs the task with the condition as it was, ConditionLocked.
+	// It's meant for tasks that know how to recover from a task that was interrupted, the operator crashed
+	// while the condition was locked for instance.
+	TakeOverLocked
+)
+
+// WithLockedBehavior configures what Execute does when the condition is already locked. Without this option,
+// Execute refuses to run the task and returns LockNotReleasedErr.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithLockedBehavior(konditions.TakeOverLocked))
+//	err := lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		if condition.Status == konditions.ConditionLocked {
+//			// ... The previous task was interrupted, the bucket may already exist ...
+//		}
+//		// ...
+//	})
+//
+// When the lock is configured with a WaitQueue, the resource is only parked when the task is refused.
+func WithLockedBehavior(behavior LockedBehavior) LockOption {
+	return func(l *Lock) {
+		l.lockedBehavior = behavior
+	}
+}
+
+// WaitForUnlock makes Execute wait for a locked condition to be released before locking it, fetching the
+// resource every interval, with the API reader of the lock if it has one, see WithAPIReader. If the condition is still locked after the timeout, the locked behavior applies,
+// see WithLockedBehavior.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WaitForUnlock(time.Second, 30*time.Second))
+//
+// Waiting blocks the reconciliation, it's only suitable for short tasks. A WaitQueue is the better option
+// when tasks run for long.
+func WaitForUnlock(interval, timeout time.Duration) LockOption {
+	return func(l *Lock) {
+		l.unlockInterval = interval
+		l.unlockTimeout = timeout
+	}
+}
+
+// Fetches the resource until its condition isn't locked anymore, or the timeout expires. The condition
+// held by the lock is refreshed from the resource fetched.
+func (l *Lock) waitForUnlock(ctx context.Context) error {
+	err := wait.PollUntilContextTimeout(ctx, l.unlockInterval, l.unlockTimeout, false, func(ctx context.Context) (bool, error) {
+		live := emptyCopy(l.obj)
+		if err := l.apiReader().Get(ctx, client.ObjectKeyFromObject(l.obj), live); err != nil {
+			return false, err
+		}
+		reflect.ValueOf(l.obj).Elem().Set(reflect.ValueOf(live).Elem())
+		l.tracker.MarkClean()
+
+		l.condition = l.obj.Conditions().FindOrInitializeFor(l.condition.Type)
+		return l.condition.Status != ConditionLocked, nil
+	})
+
+	if wait.Interrupted(err) && ctx.Err() == nil {
+		return nil
+	}
+
+	return err
+}
+
+// Returns the error Execute returns for a condition that is locked, nil if the task can run.
+func (l *Lock) handleLocked(ctx context.Context) error {
+	if l.unlockTimeout > 0 {
+		if err := l.waitForUnlock(ctx); err != nil {
+			return err
+		}
+
+		if l.condition.Status != ConditionLocked {
+			return nil
+		}
+	}
+
+	switch l.lockedBehavior {
+	case SkipLocked:
+		return skipLockedErr
+	case TakeOverLocked:
+		return nil
+	default:
+		if l.waitQueue != nil {
+			l.waitQueue.Park(l.obj, l.condition.Type)
+		}
+		return LockNotReleasedErr
+	}
+}
+
+// Returned by acquire when the task is skipped, Execute returns nil. It wraps LockNotReleasedErr for
+// AcquireCondition.
+var skipLockedErr = fmt.Errorf("%w: task skipped", LockNotReleasedErr)
@@ -0,0 +1,140 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newLockedTestResource() *testResource {
+	res := newTestResource("locked")
+	res.Status.Conditions = Conditions{
+		{
+			Type:   ConditionType("Bucket"),
+			Status: ConditionLocked,
+			Reason: "Resource locked",
+		},
+	}
+
+	return res
+}
+
+func TestLockedBehavior(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[LockedBehavior]struct {
+		err error
+		ran bool
+	}{
+		RefuseLocked:   {err: LockNotReleasedErr},
+		SkipLocked:     {},
+		TakeOverLocked: {ran: true},
+	}
+
+	for behavior, expected := range tests {
+		res := newLockedTestResource()
+		c := newTestClient(res)
+
+		ran := false
+		err := NewLock(res, c, ConditionType("Bucket"), WithLockedBehavior(behavior)).Execute(ctx, func(condition Condition) (Condition, error) {
+			ran = true
+			if condition.Status != ConditionLocked {
+				t.Error("Expected the task to receive the locked condition, got: ", condition.Status)
+			}
+
+			condition.Status = ConditionCompleted
+			return condition, nil
+		})
+
+		if !errors.Is(err, expected.err) || (expected.err == nil && err != nil) {
+			t.Errorf("Behavior %d: expected error %v, got %v", behavior, expected.err, err)
+		}
+
+		if ran != expected.ran {
+			t.Errorf("Behavior %d: expected the task to run: %t", behavior, expected.ran)
+		}
+	}
+}
+
+func TestWaitForUnlock(t *testing.T) {
+	ctx := context.Background()
+	res := newLockedTestResource()
+	c := newTestClient(res)
+
+	// The resource belongs to the waiting lock, the other writer releases the condition with its own copy.
+	key := client.ObjectKeyFromObject(res)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		var other testResource
+		if err := c.Get(ctx, key, &other); err != nil {
+			t.Error(err)
+			return
+		}
+
+		other.Status.Conditions[0].Status = ConditionCompleted
+		if err := c.Status().Update(ctx, &other); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WaitForUnlock(10*time.Millisecond, 5*time.Second))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if condition.Status != ConditionCompleted {
+			t.Error("Expected the task to receive the condition once released, got: ", condition.Status)
+		}
+
+		condition.Status = ConditionCreated
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForUnlockPrunedField(t *testing.T) {
+	ctx := context.Background()
+	res := newLockedTestResource()
+	res.Status.Conditions[0].Attributes = map[string]string{"owner": "another-writer"}
+	c := newTestClient(res)
+
+	// The Kubernetes API returns the condition released, without the attributes it had while locked.
+	reader := &decodingReader{Reader: c, mutate: func(obj client.Object) {
+		condition := &(*obj.(*testResource).Conditions())[0]
+		condition.Status = ConditionCompleted
+		condition.Attributes = nil
+	}}
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithAPIReader(reader, ConsistencyCached), WaitForUnlock(10*time.Millisecond, time.Second))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if _, ok := condition.Attributes["owner"]; ok {
+			t.Error("Expected the attributes removed by the Kubernetes API to be gone, got: ", condition.Attributes)
+		}
+
+		condition.Status = ConditionCreated
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitForUnlockTimeout(t *testing.T) {
+	ctx := context.Background()
+	res := newLockedTestResource()
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket"), WaitForUnlock(10*time.Millisecond, 50*time.Millisecond)).Execute(ctx, func(condition Condition) (Condition, error) {
+		t.Error("Expected the task not to run")
+		return condition, nil
+	})
+
+	if !errors.Is(err, LockNotReleasedErr) {
+		t.Error("Expected LockNotReleasedErr, got: ", err)
+	}
+}
@@ -0,0 +1,32 @@
+package konditions
+
+// WithManager records the name given as the Manager of the condition every time the lock writes it, when
+// the condition is locked and when it is released. In clusters where multiple controllers, or humans, write
+// to the same status, it tells who is responsible for the current state of a condition.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithManager("bucket-controller"))
+//
+// Without this option, the lock keeps whatever Manager the condition had.
+func WithManager(name string) LockOption {
+	return func(l *Lock) {
+		l.manager = name
+	}
+}
+
+// Returns a copy of the conditions that were last written by the manager given, see WithManager.
+//
+//	for _, condition := range res.Status.Conditions.ManagedBy("bucket-controller") {
+//		log.Info("Condition owned by the bucket controller", "type", condition.Type, "status", condition.Status)
+//	}
+//
+// Conditions that don't have a Manager can be found by passing an empty name.
+func (c Conditions) ManagedBy(name string) Conditions {
+	managed := Conditions{}
+	for _, condition := range c {
+		if condition.Manager == name {
+			managed = append(managed, *condition.DeepCopy())
+		}
+	}
+
+	return managed
+}
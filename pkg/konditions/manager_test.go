@@ -0,0 +1,62 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLockWithManager(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("managed")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), WithManager("bucket-controller"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		if locked := stored.Conditions().FindType(ConditionType("Bucket")); locked == nil || locked.Manager != "bucket-controller" {
+			t.Error("Expected the locked condition to record its manager, got: ", locked)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if managed := stored.Conditions().ManagedBy("bucket-controller"); len(managed) != 1 || managed[0].Status != ConditionCompleted {
+		t.Error("Expected the released condition to record its manager, got: ", stored.Conditions())
+	}
+}
+
+func TestConditionsManagedBy(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, Manager: "bucket-controller"},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted, Manager: "dns-controller"},
+		{Type: ConditionType("Volume"), Status: ConditionInitialized},
+	}
+
+	if managed := conditions.ManagedBy("dns-controller"); len(managed) != 1 || managed[0].Type != ConditionType("DNS") {
+		t.Error("Unexpected conditions: ", managed)
+	}
+
+	if unmanaged := conditions.ManagedBy(""); len(unmanaged) != 1 || unmanaged[0].Type != ConditionType("Volume") {
+		t.Error("Expected conditions without a manager to be found, got: ", unmanaged)
+	}
+
+	if managed := conditions.ManagedBy("unknown"); len(managed) != 0 {
+		t.Error("Expected no conditions, got: ", managed)
+	}
+}
@@ -0,0 +1,25 @@
+package konditions
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Used to serialize the conditions without calling MarshalJSON recursively.
+type conditionsJSON []Condition
+
+// MarshalJSON serializes the conditions, sorted by type when the SortedMarshal feature is enabled. The conditions in
+// memory aren't modified.
+func (c Conditions) MarshalJSON() ([]byte, error) {
+	if !Gates.Enabled(SortedMarshal) || sort.SliceIsSorted(c, func(i, j int) bool { return c[i].Type < c[j].Type }) {
+		return json.Marshal(conditionsJSON(c))
+	}
+
+	conditions := make(conditionsJSON, len(c))
+	copy(conditions, c)
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return conditions[i].Type < conditions[j].Type
+	})
+
+	return json.Marshal(conditions)
+}
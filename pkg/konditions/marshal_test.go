@@ -0,0 +1,49 @@
+package konditions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSONSorted(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("DNS"), Status: ConditionCompleted},
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+	}
+
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Index(string(data), "Bucket") < strings.Index(string(data), "DNS") {
+		t.Error("Expected the conditions to keep their order by default: ", string(data))
+	}
+
+	enableFeatures(t, SortedMarshal)
+
+	data, err = json.Marshal(struct {
+		Conditions Conditions `json:"conditions"`
+	}{conditions})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Index(string(data), "Bucket") > strings.Index(string(data), "DNS") {
+		t.Error("Expected the conditions to be sorted: ", string(data))
+	}
+
+	if conditions[0].Type != ConditionType("DNS") {
+		t.Error("Expected the conditions in memory to be left untouched")
+	}
+
+	var decoded Conditions
+	if err := json.Unmarshal(data[len(`{"conditions":`):len(data)-1], &decoded); err != nil || len(decoded) != 2 {
+		t.Error("Expected the conditions to be decoded, got: ", decoded, err)
+	}
+
+	if data, _ := json.Marshal(Conditions(nil)); string(data) != "null" {
+		t.Error("Expected nil conditions to serialize to null, got: ", string(data))
+	}
+}
@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// Kind is a kind of resources shown on a dashboard, with the types of conditions its resources have.
+type Kind struct {
+	Name  string
+	Types []konditions.ConditionType
+}
+
+// Dashboard generates a Grafana dashboard for the metrics of the package: each kind has a row, with the transition
+// rates, the errors, the lock durations and the stuck conditions of each of its condition types.
+//
+//	dashboard := metrics.NewDashboard("Buckets", metrics.Kind{Name: "Bucket", Types: []konditions.ConditionType{"Bucket", "DNS"}})
+//	data, err := dashboard.JSON()
+//
+// The JSON can be imported in Grafana, or provisioned from a file or a ConfigMap. The dashboard asks for the
+// Prometheus datasource to use when it's imported.
+type Dashboard struct {
+	// Title of the dashboard.
+	Title string
+
+	// UID identifies the dashboard in Grafana, importing a dashboard with the same UID replaces it.
+	UID string
+
+	Kinds []Kind
+}
+
+// NewDashboard returns a dashboard for the kinds given, with an UID derived from the title.
+func NewDashboard(title string, kinds ...Kind) Dashboard {
+	return Dashboard{
+		Title: title,
+		UID:   uidFor(title),
+		Kinds: kinds,
+	}
+}
+
+var nonUIDCharacters = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Grafana limits UIDs to 40 characters.
+func uidFor(title string) string {
+	uid := strings.Trim(nonUIDCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	uid = "konditionner-" + uid
+	if len(uid) > 40 {
+		uid = strings.TrimRight(uid[:40], "-")
+	}
+
+	return uid
+}
+
+// The JSON model of Grafana, limited to what the dashboard uses.
+type grafanaDashboard struct {
+	UID           string            `json:"uid"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags"`
+	SchemaVersion int               `json:"schemaVersion"`
+	Time          grafanaTime       `json:"time"`
+	Templating    grafanaTemplating `json:"templating"`
+	Panels        []grafanaPanel    `json:"panels"`
+}
+
+type grafanaTime struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type grafanaTemplating struct {
+	List []grafanaVariable `json:"list"`
+}
+
+type grafanaVariable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type grafanaPanel struct {
+	ID          int                 `json:"id"`
+	Type        string              `json:"type"`
+	Title       string              `json:"title"`
+	GridPos     grafanaGridPos      `json:"gridPos"`
+	Datasource  *grafanaDatasource  `json:"datasource,omitempty"`
+	Targets     []grafanaTarget     `json:"targets,omitempty"`
+	FieldConfig *grafanaFieldConfig `json:"fieldConfig,omitempty"`
+}
+
+type grafanaGridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type grafanaDatasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type grafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+type grafanaFieldConfig struct {
+	Defaults grafanaFieldDefaults `json:"defaults"`
+}
+
+type grafanaFieldDefaults struct {
+	Unit string `json:"unit,omitempty"`
+}
+
+// Height of the panels of a condition type.
+const panelHeight = 8
+
+// JSON returns the Grafana JSON model of the dashboard.
+func (d Dashboard) JSON() ([]byte, error) {
+	dashboard := grafanaDashboard{
+		UID:           d.UID,
+		Title:         d.Title,
+		Tags:          []string{"konditionner"},
+		SchemaVersion: 39,
+		Time:          grafanaTime{From: "now-6h", To: "now"},
+		Templating: grafanaTemplating{List: []grafanaVariable{
+			{Name: "datasource", Label: "Datasource", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: []grafanaPanel{},
+	}
+
+	y := 0
+	for _, kind := range d.Kinds {
+		dashboard.Panels = append(dashboard.Panels, grafanaPanel{
+			Type:    "row",
+			Title:   kind.Name,
+			GridPos: grafanaGridPos{H: 1, W: 24, Y: y},
+		})
+		y++
+
+		for _, ct := range kind.Types {
+			dashboard.Panels = append(dashboard.Panels, typePanels(kind.Name, ct, y)...)
+			y += panelHeight
+		}
+	}
+
+	for i := range dashboard.Panels {
+		dashboard.Panels[i].ID = i + 1
+	}
+
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// typePanels returns the panels of a condition type of a kind, on a single line at the height y.
+func typePanels(kind string, ct konditions.ConditionType, y int) []grafanaPanel {
+	datasource := &grafanaDatasource{Type: "prometheus", UID: "${datasource}"}
+	selector := fmt.Sprintf("kind=%s, type=%s", strconv.Quote(kind), strconv.Quote(string(ct)))
+
+	return []grafanaPanel{
+		{
+			Type:       "timeseries",
+			Title:      fmt.Sprintf("%s transitions", ct),
+			GridPos:    grafanaGridPos{H: panelHeight, W: 6, X: 0, Y: y},
+			Datasource: datasource,
+			Targets: []grafanaTarget{{
+				RefID:        "A",
+				Expr:         fmt.Sprintf("sum by (status) (rate(%s{%s}[$__rate_interval]))", TransitionsTotalName, selector),
+				LegendFormat: "{{status}}",
+			}},
+			FieldConfig: &grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "ops"}},
+		},
+		{
+			Type:       "stat",
+			Title:      fmt.Sprintf("%s errors", ct),
+			GridPos:    grafanaGridPos{H: panelHeight, W: 6, X: 6, Y: y},
+			Datasource: datasource,
+			Targets: []grafanaTarget{{
+				RefID: "A",
+				Expr:  fmt.Sprintf("sum(increase(%s{%s, status=%q}[$__range]))", TransitionsTotalName, selector, konditions.ConditionError),
+			}},
+		},
+		{
+			Type:       "timeseries",
+			Title:      fmt.Sprintf("%s lock duration", ct),
+			GridPos:    grafanaGridPos{H: panelHeight, W: 6, X: 12, Y: y},
+			Datasource: datasource,
+			Targets: []grafanaTarget{
+				{
+					RefID:        "A",
+					Expr:         fmt.Sprintf("histogram_quantile(0.5, sum by (le) (rate(%s_bucket{%s}[$__rate_interval])))", LockDurationSecondsName, selector),
+					LegendFormat: "p50",
+				},
+				{
+					RefID:        "B",
+					Expr:         fmt.Sprintf("histogram_quantile(0.95, sum by (le) (rate(%s_bucket{%s}[$__rate_interval])))", LockDurationSecondsName, selector),
+					LegendFormat: "p95",
+				},
+			},
+			FieldConfig: &grafanaFieldConfig{Defaults: grafanaFieldDefaults{Unit: "s"}},
+		},
+		{
+			Type:       "timeseries",
+			Title:      fmt.Sprintf("%s stuck", ct),
+			GridPos:    grafanaGridPos{H: panelHeight, W: 6, X: 18, Y: y},
+			Datasource: datasource,
+			Targets: []grafanaTarget{{
+				RefID:        "A",
+				Expr:         fmt.Sprintf("sum by (status, threshold) (%s{%s} > 0)", ConditionsStuckName, selector),
+				LegendFormat: "{{status}} > {{threshold}}",
+			}},
+		},
+	}
+}
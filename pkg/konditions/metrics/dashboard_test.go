@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+func TestDashboardJSON(t *testing.T) {
+	dashboard := NewDashboard("Storage Operator",
+		Kind{Name: "Bucket", Types: []konditions.ConditionType{"Bucket", "DNS"}},
+		Kind{Name: "Quota", Types: []konditions.ConditionType{"Quota"}},
+	)
+
+	if dashboard.UID != "konditionner-storage-operator" {
+		t.Error("Expected the UID to be derived from the title, got: ", dashboard.UID)
+	}
+
+	data, err := dashboard.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var model grafanaDashboard
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatal(err)
+	}
+
+	// A row per kind, and 4 panels per type.
+	if len(model.Panels) != 2+3*4 {
+		t.Fatal("Unexpected number of panels: ", len(model.Panels))
+	}
+
+	if model.Panels[0].Type != "row" || model.Panels[0].Title != "Bucket" || model.Panels[9].Type != "row" || model.Panels[9].Title != "Quota" {
+		t.Error("Expected a row for each kind, got: ", model.Panels[0].Title, model.Panels[9].Title)
+	}
+
+	ids := map[int]bool{}
+	for _, panel := range model.Panels {
+		if ids[panel.ID] {
+			t.Error("Expected the panels to have unique IDs, got twice: ", panel.ID)
+		}
+		ids[panel.ID] = true
+	}
+
+	errors := model.Panels[6]
+	if errors.Title != "DNS errors" || !strings.Contains(errors.Targets[0].Expr, `konditionner_condition_transitions_total{kind="Bucket", type="DNS", status="Error"}`) {
+		t.Errorf("Unexpected errors panel %q: %s", errors.Title, errors.Targets[0].Expr)
+	}
+
+	if model.Panels[8].GridPos.Y != model.Panels[7].GridPos.Y || model.Panels[8].GridPos.X != 18 {
+		t.Error("Expected the panels of a type to be on the same line, got: ", model.Panels[8].GridPos)
+	}
+
+	stuck := model.Panels[8]
+	if stuck.Title != "DNS stuck" || !strings.Contains(stuck.Targets[0].Expr, ConditionsStuckName) {
+		t.Errorf("Unexpected stuck panel %q: %s", stuck.Title, stuck.Targets[0].Expr)
+	}
+}
+
+func TestDashboardUID(t *testing.T) {
+	uid := uidFor("A very long title for the dashboard of an operator")
+	if len(uid) > 40 || strings.HasSuffix(uid, "-") {
+		t.Error("Expected the UID to fit in 40 characters, got: ", uid)
+	}
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the gauges of the RegistryCollector, used by the queries of the dashboard.
+const (
+	ConditionsName      = "konditionner_conditions"
+	ConditionsStuckName = "konditionner_conditions_stuck"
+)
+
+// DefaultStuckThresholds are the thresholds of the stuck gauge when none are given to NewRegistryCollector.
+var DefaultStuckThresholds = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour}
+
+var (
+	conditionsDesc = prometheus.NewDesc(ConditionsName,
+		"Number of conditions, by kind of the resource, type and status of the condition.",
+		[]string{"kind", "type", "status"}, nil)
+
+	conditionsStuckDesc = prometheus.NewDesc(ConditionsStuckName,
+		"Number of conditions in progress without a transition, or a heartbeat, for longer than the threshold.",
+		[]string{"kind", "type", "status", "threshold"}, nil)
+)
+
+// RegistryCollector exports gauges computed from the conditions of the resources a konditions.Registry indexes: the
+// number of conditions by type and status, and the number of them that are stuck, see Condition.IsStuck, for each of
+// the thresholds of the collector. The registry is built from the informer cache, collecting the gauges doesn't issue
+// any call to the Kubernetes API.
+//
+//	registry := konditions.NewRegistry(&Bucket{})
+//	if err := registry.Register(ctx, mgr.GetCache()); err != nil {
+//		return err
+//	}
+//
+//	ctrlmetrics.Registry.MustRegister(metrics.NewRegistryCollector("Bucket", registry))
+//
+// Alerting on more than 5 Buckets locked for longer than 15 minutes is then a single query:
+//
+//	konditionner_conditions_stuck{kind="Bucket", status="Locked", threshold="15m"} > 5
+type RegistryCollector struct {
+	kind       string
+	registry   *konditions.Registry
+	thresholds []time.Duration
+
+	// now is the clock of the collector, tests replace it.
+	now func() time.Time
+}
+
+// NewRegistryCollector returns a collector for the resources of the kind indexed by the registry. The stuck gauge
+// has a series for each threshold given, or for DefaultStuckThresholds if none are.
+func NewRegistryCollector(kind string, registry *konditions.Registry, thresholds ...time.Duration) *RegistryCollector {
+	if len(thresholds) == 0 {
+		thresholds = DefaultStuckThresholds
+	}
+
+	return &RegistryCollector{
+		kind:       kind,
+		registry:   registry,
+		thresholds: thresholds,
+		now:        time.Now,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *RegistryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- conditionsDesc
+	ch <- conditionsStuckDesc
+}
+
+type gaugeKey struct {
+	ct     konditions.ConditionType
+	status konditions.ConditionStatus
+}
+
+// Collect implements prometheus.Collector.
+func (c *RegistryCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	counts := map[gaugeKey]int{}
+	stuck := map[gaugeKey][]int{}
+
+	for _, key := range c.registry.List() {
+		conditions, ok := c.registry.Get(key)
+		if !ok {
+			continue
+		}
+
+		for _, condition := range conditions {
+			k := gaugeKey{ct: condition.Type, status: condition.Status}
+			counts[k]++
+
+			if _, ok := stuck[k]; !ok {
+				stuck[k] = make([]int, len(c.thresholds))
+			}
+
+			for i, threshold := range c.thresholds {
+				if condition.IsStuck(now, threshold) {
+					stuck[k][i]++
+				}
+			}
+		}
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(conditionsDesc, prometheus.GaugeValue, float64(count), c.kind, string(k.ct), string(k.status))
+
+		for i, threshold := range c.thresholds {
+			ch <- prometheus.MustNewConstMetric(conditionsStuckDesc, prometheus.GaugeValue, float64(stuck[k][i]), c.kind, string(k.ct), string(k.status), thresholdLabel(threshold))
+		}
+	}
+}
+
+// thresholdLabel formats the threshold the way it's written in a query: 15m instead of 15m0s.
+func thresholdLabel(threshold time.Duration) string {
+	label := threshold.String()
+	if strings.HasSuffix(label, "m0s") {
+		label = strings.TrimSuffix(label, "0s")
+	}
+
+	if strings.HasSuffix(label, "h0m") {
+		label = strings.TrimSuffix(label, "0m")
+	}
+
+	return label
+}
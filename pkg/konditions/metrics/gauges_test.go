@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+var bucketGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"}
+
+func newBucket(t *testing.T, name string, conditions ...konditions.Condition) *konditions.UnstructuredConditions {
+	t.Helper()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(bucketGVK)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+
+	if err := konditions.SetNestedConditions(obj, conditions, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := konditions.NewUnstructuredConditions(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func TestRegistryCollector(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	informers := &informertest.FakeInformers{Scheme: runtime.NewScheme()}
+
+	registry := konditions.NewRegistry(newBucket(t, ""))
+	if err := registry.Register(ctx, informers); err != nil {
+		t.Fatal(err)
+	}
+
+	informer, err := informers.FakeInformerFor(ctx, newBucket(t, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := func(age time.Duration) konditions.Condition {
+		condition := konditions.Condition{Type: "Bucket", Status: konditions.ConditionLocked}
+		condition.LastTransitionTime.Time = now.Add(-age)
+		return condition
+	}
+
+	completed := konditions.Condition{Type: "DNS", Status: konditions.ConditionCompleted}
+	completed.LastTransitionTime.Time = now.Add(-48 * time.Hour)
+
+	informer.Add(newBucket(t, "logs", locked(time.Minute), completed))
+	informer.Add(newBucket(t, "images", locked(20*time.Minute)))
+	informer.Add(newBucket(t, "backups", locked(2*time.Hour)))
+
+	collector := NewRegistryCollector("Bucket", registry, 15*time.Minute, time.Hour)
+	collector.now = func() time.Time { return now }
+
+	expected := `
+		# HELP konditionner_conditions Number of conditions, by kind of the resource, type and status of the condition.
+		# TYPE konditionner_conditions gauge
+		konditionner_conditions{kind="Bucket",status="Completed",type="DNS"} 1
+		konditionner_conditions{kind="Bucket",status="Locked",type="Bucket"} 3
+		# HELP konditionner_conditions_stuck Number of conditions in progress without a transition, or a heartbeat, for longer than the threshold.
+		# TYPE konditionner_conditions_stuck gauge
+		konditionner_conditions_stuck{kind="Bucket",status="Completed",threshold="15m",type="DNS"} 0
+		konditionner_conditions_stuck{kind="Bucket",status="Completed",threshold="1h",type="DNS"} 0
+		konditionner_conditions_stuck{kind="Bucket",status="Locked",threshold="15m",type="Bucket"} 2
+		konditionner_conditions_stuck{kind="Bucket",status="Locked",threshold="1h",type="Bucket"} 1
+	`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestThresholdLabel(t *testing.T) {
+	cases := map[time.Duration]string{
+		30 * time.Second:                "30s",
+		15 * time.Minute:                "15m",
+		time.Hour:                       "1h",
+		90 * time.Minute:                "1h30m",
+		15*time.Minute + 30*time.Second: "15m30s",
+	}
+
+	for threshold, label := range cases {
+		if got := thresholdLabel(threshold); got != label {
+			t.Errorf("Expected %s to be labeled %q, got %q", threshold, label, got)
+		}
+	}
+}
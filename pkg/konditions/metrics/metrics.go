@@ -0,0 +1,75 @@
+// Package metrics exports the conditions of resources as Prometheus metrics. The metrics are registered with the
+// metrics registry of controller-runtime, and exposed by the manager with the other metrics of the controller.
+//
+// The transitions persisted by a lock are recorded by the Listener of the package:
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithListener(metrics.Listener("Bucket")))
+//
+// The gauges of the conditions currently held by the resources are collected from a konditions.Registry, see
+// RegistryCollector. The time conditions take to complete, and how often they fail, is tracked by an SLOTracker.
+// A Grafana dashboard for these metrics can be generated with NewDashboard.
+//
+// The metrics of the transition times skewed between replicas are only registered when asked to, see
+// RegisterTransitionTimeSkew.
+package metrics
+
+import (
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Names of the metrics of the package, used by the queries of the dashboard.
+const (
+	TransitionsTotalName    = "konditionner_condition_transitions_total"
+	LockDurationSecondsName = "konditionner_lock_duration_seconds"
+)
+
+// TransitionsTotal counts the transitions of conditions, by kind of the resource, type of the condition and the
+// status it transitioned to. The transitions to ConditionError are the errors of the conditions.
+var TransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: TransitionsTotalName,
+	Help: "Number of transitions of conditions, by the status they transitioned to.",
+}, []string{"kind", "type", "status"})
+
+// LockDurationSeconds observes how long conditions stay locked, by kind of the resource, type of the condition and
+// the status the lock released the condition to.
+var LockDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    LockDurationSecondsName,
+	Help:    "How long conditions stayed locked, by the status they were released to.",
+	Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900, 3600},
+}, []string{"kind", "type", "status"})
+
+func init() {
+	metrics.Registry.MustRegister(TransitionsTotal, LockDurationSeconds, TimeToCompletedSeconds)
+}
+
+// Listener returns a Listener that records the transitions of the resources of the kind given.
+func Listener(kind string) konditions.Listener {
+	return func(_ konditions.ConditionalResource, transition konditions.Transition) {
+		Observe(kind, transition)
+	}
+}
+
+// Observe records the transition of a condition of a resource of the kind given. Listener calls it for every
+// transition, it can also be called with the transitions streamed by konditions.Watch.
+//
+// Removing a condition isn't a transition to a status, it's not recorded.
+func Observe(kind string, transition konditions.Transition) {
+	if transition.New == nil {
+		return
+	}
+
+	TransitionsTotal.WithLabelValues(kind, string(transition.Type), string(transition.New.Status)).Inc()
+
+	if transition.Old == nil || transition.Old.Status != konditions.ConditionLocked {
+		return
+	}
+
+	locked := transition.New.LastTransitionTime.Sub(transition.Old.LastTransitionTime.Time)
+	if locked < 0 || transition.Old.LastTransitionTime.IsZero() {
+		return
+	}
+
+	LockDurationSeconds.WithLabelValues(kind, string(transition.Type), string(transition.New.Status)).Observe(locked.Seconds())
+}
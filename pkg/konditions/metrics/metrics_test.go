@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListener(t *testing.T) {
+	TransitionsTotal.Reset()
+	LockDurationSeconds.Reset()
+
+	locked := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	listener := Listener("Bucket")
+
+	listener(nil, konditions.Transition{
+		Type: "Bucket",
+		New:  &konditions.Condition{Type: "Bucket", Status: konditions.ConditionLocked, LastTransitionTime: meta.NewTime(locked)},
+	})
+	listener(nil, konditions.Transition{
+		Type: "Bucket",
+		Old:  &konditions.Condition{Type: "Bucket", Status: konditions.ConditionLocked, LastTransitionTime: meta.NewTime(locked)},
+		New:  &konditions.Condition{Type: "Bucket", Status: konditions.ConditionError, LastTransitionTime: meta.NewTime(locked.Add(3 * time.Second))},
+	})
+	listener(nil, konditions.Transition{
+		Type: "Bucket",
+		Old:  &konditions.Condition{Type: "Bucket", Status: konditions.ConditionError},
+	})
+
+	if count := testutil.ToFloat64(TransitionsTotal.WithLabelValues("Bucket", "Bucket", "Locked")); count != 1 {
+		t.Error("Expected one transition to Locked, got: ", count)
+	}
+
+	if count := testutil.ToFloat64(TransitionsTotal.WithLabelValues("Bucket", "Bucket", "Error")); count != 1 {
+		t.Error("Expected one transition to Error, got: ", count)
+	}
+
+	if count := testutil.CollectAndCount(TransitionsTotal); count != 2 {
+		t.Error("Expected the removal not to be recorded, got series: ", count)
+	}
+
+	if count := testutil.CollectAndCount(LockDurationSeconds); count != 1 {
+		t.Fatal("Expected the lock duration to be observed once, got series: ", count)
+	}
+
+	var metric dto.Metric
+	if err := LockDurationSeconds.WithLabelValues("Bucket", "Bucket", "Error").(prometheus.Histogram).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+
+	if sum := metric.GetHistogram().GetSampleSum(); sum != 3 {
+		t.Error("Expected the lock to have lasted 3 seconds, got: ", sum)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TransitionTimeSkewTotal counts, per condition type, the transitions whose time was earlier than the previous
+// transition of the condition, see konditions.MonotonicTransitionTimes.
+var TransitionTimeSkewTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "konditionner_transition_time_skew_total",
+	Help: "Number of condition transitions whose time was earlier than the previous transition, usually because of clock skew between replicas.",
+}, []string{"type"})
+
+// TransitionTimeSkewSeconds observes, per condition type, how far back in time the skewed transitions were.
+var TransitionTimeSkewSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "konditionner_transition_time_skew_seconds",
+	Help:    "How far back in time the skewed condition transitions were.",
+	Buckets: []float64{0.001, 0.01, 0.1, 1, 5, 15, 60, 300},
+}, []string{"type"})
+
+// RegisterTransitionTimeSkew registers the metrics of the transition times skewed between replicas with the
+// registerer, and records the skews SetCondition detects in them:
+//
+//	if err := metrics.RegisterTransitionTimeSkew(ctrlmetrics.Registry); err != nil {
+//		return err
+//	}
+//
+// The skews are recorded through konditions.TransitionTimeSkewObserver, which is replaced. An error is returned if
+// the metrics are already registered with the registerer, the skews are recorded in either case.
+func RegisterTransitionTimeSkew(registerer prometheus.Registerer) error {
+	konditions.TransitionTimeSkewObserver = ObserveTransitionTimeSkew
+
+	for _, collector := range []prometheus.Collector{TransitionTimeSkewTotal, TransitionTimeSkewSeconds} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ObserveTransitionTimeSkew records a transition of a condition of the type that was skewed by the duration given.
+func ObserveTransitionTimeSkew(ct konditions.ConditionType, skew time.Duration) {
+	TransitionTimeSkewTotal.WithLabelValues(string(ct)).Inc()
+	TransitionTimeSkewSeconds.WithLabelValues(string(ct)).Observe(skew.Seconds())
+}
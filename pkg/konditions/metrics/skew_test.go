@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRegisterTransitionTimeSkew(t *testing.T) {
+	TransitionTimeSkewTotal.Reset()
+	t.Cleanup(func() { konditions.TransitionTimeSkewObserver = nil })
+
+	registry := prometheus.NewRegistry()
+	if err := RegisterTransitionTimeSkew(registry); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterTransitionTimeSkew(registry); err == nil {
+		t.Error("Expected the second registration to return an error")
+	}
+
+	later := meta.NewTime(time.Now().Add(time.Minute).Truncate(time.Second))
+	conditions := konditions.Conditions{{Type: "Skewed", Status: konditions.ConditionLocked, LastTransitionTime: later}}
+	if err := conditions.SetCondition(konditions.Condition{Type: "Skewed", Status: konditions.ConditionCompleted}); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := testutil.ToFloat64(TransitionTimeSkewTotal.WithLabelValues("Skewed")); count != 1 {
+		t.Error("Expected the skew to be counted, got: ", count)
+	}
+
+	if count, err := testutil.GatherAndCount(registry, "konditionner_transition_time_skew_seconds"); err != nil || count != 1 {
+		t.Error("Expected the skew to be observed, got: ", count, err)
+	}
+}
@@ -0,0 +1,267 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Names of the SLO metrics, used by the queries of the dashboard.
+const (
+	TimeToCompletedSecondsName = "konditionner_time_to_completed_seconds"
+	SLOSuccessRatioName        = "konditionner_slo_success_ratio"
+	SLODurationSecondsName     = "konditionner_slo_duration_seconds"
+)
+
+// DefaultSLOWindow is the window of an SLOTracker when none is given.
+const DefaultSLOWindow = 24 * time.Hour
+
+// TimeToCompletedSeconds observes, by kind of the resource and type of the condition, how long conditions took to
+// go from ConditionInitialized to ConditionCompleted. It's observed by the SLOTrackers.
+var TimeToCompletedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    TimeToCompletedSecondsName,
+	Help:    "How long conditions took to go from Initialized to Completed.",
+	Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200},
+}, []string{"kind", "type"})
+
+var (
+	sloSuccessRatioDesc = prometheus.NewDesc(SLOSuccessRatioName,
+		"Ratio of the conditions that completed, over the conditions that completed or failed, within the window of the tracker.",
+		[]string{"kind", "type"}, nil)
+
+	sloDurationDesc = prometheus.NewDesc(SLODurationSecondsName,
+		"Quantiles of the time conditions took to go from Initialized to Completed, within the window of the tracker.",
+		[]string{"kind", "type", "quantile"}, nil)
+)
+
+// SLOReport is the service level of a condition type over the window of an SLOTracker.
+type SLOReport struct {
+	Type konditions.ConditionType
+
+	// Completed and Failed are the number of conditions that reached ConditionCompleted, or a terminal status,
+	// within the window.
+	Completed int
+	Failed    int
+
+	// SuccessRate is Completed over Completed and Failed, 1 when no condition completed nor failed.
+	SuccessRate float64
+
+	// P50 and P95 are the median and 95th percentile of the time conditions took to go from ConditionInitialized
+	// to ConditionCompleted. They're 0 when no duration was measured.
+	P50 time.Duration
+	P95 time.Duration
+}
+
+type outcome struct {
+	at       time.Time
+	success  bool
+	duration time.Duration
+	measured bool
+}
+
+type sloKey struct {
+	key types.NamespacedName
+	ct  konditions.ConditionType
+}
+
+// SLOTracker answers how long provisioning takes: it follows the transitions of the conditions of a kind and
+// computes, by condition type, the time conditions take to go from ConditionInitialized to ConditionCompleted and the
+// rate of conditions that complete instead of failing, over a sliding window.
+//
+//	tracker := metrics.NewSLOTracker("Bucket", 24*time.Hour)
+//	registry.Subscribe(tracker.Subscriber())
+//	ctrlmetrics.Registry.MustRegister(tracker)
+//
+//	report := tracker.Report(ConditionType("Bucket"))
+//	log.Info("Provisioning", "p95", report.P95, "successRate", report.SuccessRate)
+//
+// The tracker is a prometheus.Collector that exports the success rate and the quantiles of its window, and it observes
+// TimeToCompletedSeconds for every condition that completes.
+//
+// The history is kept in memory: the time a condition was initialized is only known if the tracker observed the
+// transition, conditions initialized before the tracker started count toward the success rate, not the durations.
+type SLOTracker struct {
+	kind   string
+	window time.Duration
+
+	mu       sync.Mutex
+	started  map[sloKey]time.Time
+	outcomes map[konditions.ConditionType][]outcome
+
+	// now is the clock of the tracker, tests replace it.
+	now func() time.Time
+}
+
+// NewSLOTracker returns a tracker for the conditions of the kind given, over the window given, or DefaultSLOWindow
+// if the window is 0.
+func NewSLOTracker(kind string, window time.Duration) *SLOTracker {
+	if window <= 0 {
+		window = DefaultSLOWindow
+	}
+
+	return &SLOTracker{
+		kind:     kind,
+		window:   window,
+		started:  map[sloKey]time.Time{},
+		outcomes: map[konditions.ConditionType][]outcome{},
+		now:      time.Now,
+	}
+}
+
+// Listener returns the Listener that records the transitions of the resource of a lock.
+func (s *SLOTracker) Listener() konditions.Listener {
+	return func(obj konditions.ConditionalResource, transition konditions.Transition) {
+		s.Observe(client.ObjectKeyFromObject(obj), transition)
+	}
+}
+
+// Subscriber returns the function that records the transitions observed by a Registry, see Registry.Subscribe.
+func (s *SLOTracker) Subscriber() func(konditions.RegistryEvent) {
+	return func(event konditions.RegistryEvent) {
+		s.Observe(event.Key, event.Transition)
+	}
+}
+
+// Observe records the transition of a condition of the resource with the key given.
+func (s *SLOTracker) Observe(key types.NamespacedName, transition konditions.Transition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := sloKey{key: key, ct: transition.Type}
+	condition := transition.New
+	if condition == nil {
+		delete(s.started, k)
+		return
+	}
+
+	at := condition.LastTransitionTime.Time
+	if at.IsZero() {
+		at = s.now()
+	}
+
+	switch {
+	case condition.Status == konditions.ConditionInitialized:
+		s.started[k] = at
+		return
+	case condition.Class() == konditions.ClassCompleted:
+		o := outcome{at: at, success: true}
+		if started, ok := s.started[k]; ok && !at.Before(started) {
+			o.duration = at.Sub(started)
+			o.measured = true
+			TimeToCompletedSeconds.WithLabelValues(s.kind, string(transition.Type)).Observe(o.duration.Seconds())
+		}
+		s.record(transition.Type, o)
+	case condition.IsTerminal():
+		s.record(transition.Type, outcome{at: at})
+	default:
+		return
+	}
+
+	delete(s.started, k)
+}
+
+// record adds the outcome to the history of the type, and forgets the outcomes that fell out of the window.
+func (s *SLOTracker) record(ct konditions.ConditionType, o outcome) {
+	s.outcomes[ct] = append(s.outcomes[ct], o)
+	s.prune(ct)
+}
+
+func (s *SLOTracker) prune(ct konditions.ConditionType) {
+	since := s.now().Add(-s.window)
+
+	outcomes := s.outcomes[ct][:0]
+	for _, o := range s.outcomes[ct] {
+		if !o.at.Before(since) {
+			outcomes = append(outcomes, o)
+		}
+	}
+	s.outcomes[ct] = outcomes
+}
+
+// Report returns the service level of the condition type over the window of the tracker.
+func (s *SLOTracker) Report(ct konditions.ConditionType) SLOReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.report(ct)
+}
+
+// Reports returns the service level of every condition type the tracker observed an outcome for, sorted by type.
+func (s *SLOTracker) Reports() []SLOReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make([]SLOReport, 0, len(s.outcomes))
+	for ct := range s.outcomes {
+		reports = append(reports, s.report(ct))
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Type < reports[j].Type
+	})
+
+	return reports
+}
+
+func (s *SLOTracker) report(ct konditions.ConditionType) SLOReport {
+	s.prune(ct)
+
+	report := SLOReport{Type: ct, SuccessRate: 1}
+	var durations []time.Duration
+	for _, o := range s.outcomes[ct] {
+		if !o.success {
+			report.Failed++
+			continue
+		}
+
+		report.Completed++
+		if o.measured {
+			durations = append(durations, o.duration)
+		}
+	}
+
+	if total := report.Completed + report.Failed; total > 0 {
+		report.SuccessRate = float64(report.Completed) / float64(total)
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	report.P50 = percentile(durations, 0.5)
+	report.P95 = percentile(durations, 0.95)
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+}
+
+// Describe implements prometheus.Collector.
+func (s *SLOTracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sloSuccessRatioDesc
+	ch <- sloDurationDesc
+}
+
+// Collect implements prometheus.Collector.
+func (s *SLOTracker) Collect(ch chan<- prometheus.Metric) {
+	for _, report := range s.Reports() {
+		ch <- prometheus.MustNewConstMetric(sloSuccessRatioDesc, prometheus.GaugeValue, report.SuccessRate, s.kind, string(report.Type))
+		ch <- prometheus.MustNewConstMetric(sloDurationDesc, prometheus.GaugeValue, report.P50.Seconds(), s.kind, string(report.Type), "0.5")
+		ch <- prometheus.MustNewConstMetric(sloDurationDesc, prometheus.GaugeValue, report.P95.Seconds(), s.kind, string(report.Type), "0.95")
+	}
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSLOTracker(t *testing.T) {
+	TimeToCompletedSeconds.Reset()
+
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	tracker := NewSLOTracker("Bucket", time.Hour)
+	tracker.now = func() time.Time { return now }
+
+	transition := func(name string, status konditions.ConditionStatus, at time.Time) {
+		tracker.Observe(types.NamespacedName{Namespace: "default", Name: name}, konditions.Transition{
+			Type: "Bucket",
+			New:  &konditions.Condition{Type: "Bucket", Status: status, LastTransitionTime: meta.NewTime(at)},
+		})
+	}
+
+	start := now.Add(-30 * time.Minute)
+	for i, duration := range []time.Duration{10 * time.Second, 20 * time.Second, 100 * time.Second} {
+		name := []string{"logs", "images", "backups"}[i]
+		transition(name, konditions.ConditionInitialized, start)
+		transition(name, konditions.ConditionLocked, start.Add(time.Second))
+		transition(name, konditions.ConditionCompleted, start.Add(duration))
+	}
+
+	// Failed, completed without a known start, and completed out of the window.
+	transition("videos", konditions.ConditionInitialized, start)
+	transition("videos", konditions.ConditionError, start.Add(time.Minute))
+	transition("archives", konditions.ConditionCompleted, start)
+	transition("old", konditions.ConditionInitialized, now.Add(-3*time.Hour))
+	transition("old", konditions.ConditionCompleted, now.Add(-2*time.Hour))
+
+	report := tracker.Report("Bucket")
+	if report.Completed != 4 || report.Failed != 1 || report.SuccessRate != 0.8 {
+		t.Errorf("Expected 4 completed and 1 failed in the window, got %d, %d, %f", report.Completed, report.Failed, report.SuccessRate)
+	}
+
+	if report.P50 != 20*time.Second || report.P95 != 100*time.Second {
+		t.Errorf("Expected a p50 of 20s and a p95 of 100s, got %s and %s", report.P50, report.P95)
+	}
+
+	if count := testutil.CollectAndCount(TimeToCompletedSeconds); count != 1 {
+		t.Error("Expected the time to completed to be observed, got series: ", count)
+	}
+
+	expected := `
+		# HELP konditionner_slo_duration_seconds Quantiles of the time conditions took to go from Initialized to Completed, within the window of the tracker.
+		# TYPE konditionner_slo_duration_seconds gauge
+		konditionner_slo_duration_seconds{kind="Bucket",quantile="0.5",type="Bucket"} 20
+		konditionner_slo_duration_seconds{kind="Bucket",quantile="0.95",type="Bucket"} 100
+		# HELP konditionner_slo_success_ratio Ratio of the conditions that completed, over the conditions that completed or failed, within the window of the tracker.
+		# TYPE konditionner_slo_success_ratio gauge
+		konditionner_slo_success_ratio{kind="Bucket",type="Bucket"} 0.8
+	`
+	if err := testutil.CollectAndCompare(tracker, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	// The window slides, the outcomes fall out of it.
+	now = now.Add(time.Hour)
+	if reports := tracker.Reports(); len(reports) != 1 || reports[0].Completed != 0 || reports[0].SuccessRate != 1 || reports[0].P95 != 0 {
+		t.Error("Expected the outcomes to be out of the window, got: ", reports)
+	}
+}
+
+func TestSLOTrackerRemovedCondition(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	tracker := NewSLOTracker("Bucket", 0)
+	tracker.now = func() time.Time { return now }
+
+	key := types.NamespacedName{Namespace: "default", Name: "logs"}
+	initialized := &konditions.Condition{Type: "Bucket", Status: konditions.ConditionInitialized, LastTransitionTime: meta.NewTime(now.Add(-time.Minute))}
+	tracker.Observe(key, konditions.Transition{Type: "Bucket", New: initialized})
+	tracker.Observe(key, konditions.Transition{Type: "Bucket", Old: initialized})
+	tracker.Observe(key, konditions.Transition{Type: "Bucket", New: &konditions.Condition{Type: "Bucket", Status: konditions.ConditionCompleted, LastTransitionTime: meta.NewTime(now)}})
+
+	if report := tracker.Report("Bucket"); report.Completed != 1 || report.P50 != 0 {
+		t.Error("Expected the start to be forgotten when the condition is removed, got: ", report)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if p := percentile(durations, 0.5); p != 5 {
+		t.Error("Expected the median to be 5, got: ", p)
+	}
+
+	if p := percentile(durations, 0.95); p != 10 {
+		t.Error("Expected the p95 to be 10, got: ", p)
+	}
+
+	if p := percentile(nil, 0.95); p != 0 {
+		t.Error("Expected no percentile without durations, got: ", p)
+	}
+}
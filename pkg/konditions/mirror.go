@@ -0,0 +1,116 @@
+package konditions
+
+import (
+	"errors"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var MetaConditionsNotSupportedErr = errors.New("Resource does not implement MetaConditionalResource")
+
+// MetaConditionalResource is a ConditionalResource that also exposes a list of
+// metav1.Condition. That list is owned by Konditionner: it is kept in sync with the
+// Conditions of the resource so tools that only understand the Kubernetes conventions,
+// `kubectl wait` being the most common one, can be used with your custom resource.
+//
+//	type MyCRDStatus struct {
+//		Conditions     konditions.Conditions `json:"conditions"`
+//		MetaConditions []metav1.Condition    `json:"metaConditions,omitempty"`
+//	}
+//
+//	func (m *MyCRD) MetaConditions() *[]metav1.Condition {
+//		return &m.Status.MetaConditions
+//	}
+//
+// With the lock configured with MirrorToMeta(), the following works out of the box:
+//
+//	kubectl wait mycrd/example --for=condition=Bucket
+type MetaConditionalResource interface {
+	ConditionalResource
+
+	MetaConditions() *[]meta.Condition
+}
+
+// MirrorToMeta configures the lock to mirror the resource's Conditions into its
+// metav1.Condition list every time the lock writes to the Kubernetes API. The resource
+// needs to implement MetaConditionalResource, if it doesn't, Execute will return
+// MetaConditionsNotSupportedErr.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.MirrorToMeta())
+func MirrorToMeta() LockOption {
+	return func(l *Lock) {
+		l.mirror = true
+	}
+}
+
+// Mirror the conditions into a list of metav1.Condition. The list is expected to be owned
+// by Konditionner, as such, any condition in the list that doesn't have a counterpart
+// in Conditions is removed, except for the Reconciling and Stalled conditions managed by SummarizeInto.
+//
+// Each condition is mapped to a metav1.Condition of the same type where:
+//   - The Status is mapped with the ReadySemantics of the type, see RegisterReadySemantics
+//   - The Reason is the ConditionStatus, stripped of the characters Kubernetes doesn't allow in a reason
+//   - The Message is the Reason of the condition
+//
+// The generation is stored as the ObservedGeneration of every metav1.Condition, it should
+// be the generation of the object that holds the conditions.
+//
+//	res.Status.Conditions.MirrorInto(&res.Status.MetaConditions, res.GetGeneration())
+func (c Conditions) MirrorInto(conditions *[]meta.Condition, generation int64) {
+	if conditions == nil {
+		return
+	}
+
+	for _, condition := range c {
+		apimeta.SetStatusCondition(conditions, meta.Condition{
+			Type:               string(condition.Type),
+			Status:             ReadySemanticsFor(condition.Type).MetaStatus(condition.Status),
+			ObservedGeneration: generation,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             metaReasonFor(condition.Status),
+			Message:            condition.Reason,
+		})
+	}
+
+	for i := len(*conditions) - 1; i >= 0; i-- {
+		if isSummaryType((*conditions)[i].Type) {
+			continue
+		}
+
+		if c.FindType(ConditionType((*conditions)[i].Type)) == nil {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+		}
+	}
+}
+
+// MetaStatusFor returns the metav1.ConditionStatus that represents the ConditionStatus given, according
+// to the DefaultReadySemantics. A completed condition is True, a condition in error is False and every
+// other status, which means the condition is still in progress, is Unknown.
+//
+// Condition types can declare their own semantics, see RegisterReadySemantics.
+func MetaStatusFor(status ConditionStatus) meta.ConditionStatus {
+	return DefaultReadySemantics.MetaStatus(status)
+}
+
+// Kubernetes only allows a reason to contain letters, digits, `_`, `,` and `:` and it needs to
+// start with a letter. Since ConditionStatus are user-defined, anything that
+// wouldn't validate is dropped.
+func metaReasonFor(status ConditionStatus) string {
+	reason := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ',', r == ':':
+			return r
+		}
+		return -1
+	}, string(status))
+
+	reason = strings.TrimLeft(reason, "0123456789_,:")
+	reason = strings.TrimRight(reason, ",:")
+	if reason == "" {
+		return "Unknown"
+	}
+
+	return reason
+}
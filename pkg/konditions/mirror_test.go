@@ -0,0 +1,109 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMirrorInto(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created"},
+		{Type: ConditionType("DNS"), Status: ConditionError, Reason: "Zone not found"},
+		{Type: ConditionType("Volume"), Status: ConditionStatus("In progress")},
+	}
+
+	metaConditions := []meta.Condition{
+		{Type: "Obsolete", Status: meta.ConditionTrue, Reason: "Completed"},
+	}
+
+	conditions.MirrorInto(&metaConditions, 3)
+
+	if len(metaConditions) != 3 {
+		t.Fatalf("Expected 3 conditions, got %d", len(metaConditions))
+	}
+
+	if apimeta.FindStatusCondition(metaConditions, "Obsolete") != nil {
+		t.Error("Expected the condition not owned by Conditions to be removed")
+	}
+
+	bucket := apimeta.FindStatusCondition(metaConditions, "Bucket")
+	if bucket == nil || bucket.Status != meta.ConditionTrue || bucket.Reason != "Completed" || bucket.Message != "Bucket created" {
+		t.Error("Unexpected condition: ", bucket)
+	}
+
+	if bucket.ObservedGeneration != 3 {
+		t.Error("Expected the generation to be observed")
+	}
+
+	dns := apimeta.FindStatusCondition(metaConditions, "DNS")
+	if dns == nil || dns.Status != meta.ConditionFalse {
+		t.Error("Unexpected condition: ", dns)
+	}
+
+	volume := apimeta.FindStatusCondition(metaConditions, "Volume")
+	if volume == nil || volume.Status != meta.ConditionUnknown || volume.Reason != "Inprogress" {
+		t.Error("Unexpected condition: ", volume)
+	}
+}
+
+func TestMetaReasonFor(t *testing.T) {
+	expectations := map[ConditionStatus]string{
+		ConditionCompleted:                "Completed",
+		ConditionStatus("Waiting on DNS"): "WaitingonDNS",
+		ConditionStatus("2 steps left"):   "stepsleft",
+		ConditionStatus("!!"):             "Unknown",
+	}
+
+	for status, expected := range expectations {
+		if reason := metaReasonFor(status); reason != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, status, reason)
+		}
+	}
+}
+
+func TestLockMirrorToMeta(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("mirror")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"), MirrorToMeta())
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if apimeta.FindStatusCondition(res.Status.MetaConditions, "Bucket").Status != meta.ConditionUnknown {
+			t.Error("Expected the locked condition to be mirrored as Unknown")
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !apimeta.IsStatusConditionTrue(res.Status.MetaConditions, "Bucket") {
+		t.Error("Expected the completed condition to be mirrored as True")
+	}
+}
+
+type notMirrored struct {
+	*testResource
+}
+
+func (n notMirrored) MetaConditions() {}
+
+func TestLockMirrorToMetaNotSupported(t *testing.T) {
+	res := newTestResource("mirror")
+	c := newTestClient(res)
+
+	err := NewLock(notMirrored{res}, c, ConditionType("Bucket"), MirrorToMeta()).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if !errors.Is(err, MetaConditionsNotSupportedErr) {
+		t.Error("Expected MetaConditionsNotSupportedErr, got: ", err)
+	}
+}
@@ -0,0 +1,37 @@
+package konditions
+
+import "time"
+
+// MonotonicTransitionTimes keeps the LastTransitionTime of the conditions from going backwards. Replicas of a
+// controller don't share a clock: after a failover, the new leader can be a few seconds behind the previous one
+// and the timeline of the conditions would go back in time. When SetCondition stamps a transition with a time
+// that is earlier than the LastTransitionTime of the condition it replaces, the later time is kept and the skew
+// is reported to TransitionTimeSkewObserver.
+//
+// Only the times stamped by SetCondition, for the conditions given without a LastTransitionTime, are kept from
+// going backwards. A LastTransitionTime set by the caller is stored as given. Set it to false to store the times
+// SetCondition stamps as they are.
+var MonotonicTransitionTimes = true
+
+// TransitionTimeSkewObserver is called, when it's set, for every transition whose time was moved forward by
+// MonotonicTransitionTimes, with how far back in time it was. metrics.RegisterTransitionTimeSkew sets it to
+// record the skews as Prometheus metrics. It should be set once, before the conditions are used.
+var TransitionTimeSkewObserver func(ct ConditionType, skew time.Duration)
+
+// Keeps the transition time of the new condition from going before the one of the existing condition,
+// see MonotonicTransitionTimes.
+func monotonicTransitionTime(existing Condition, newCondition *Condition) {
+	if !MonotonicTransitionTimes || existing.LastTransitionTime.IsZero() {
+		return
+	}
+
+	skew := existing.LastTransitionTime.Sub(newCondition.LastTransitionTime.Time)
+	if skew <= 0 {
+		return
+	}
+
+	if TransitionTimeSkewObserver != nil {
+		TransitionTimeSkewObserver(newCondition.Type, skew)
+	}
+	newCondition.LastTransitionTime = existing.LastTransitionTime
+}
@@ -0,0 +1,93 @@
+package konditions
+
+import (
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditionKeepsTheLaterTransitionTime(t *testing.T) {
+	later := meta.NewTime(time.Now().Add(time.Minute).Truncate(time.Second))
+	conditions := Conditions{
+		{
+			Type:               ConditionType("Skewed"),
+			Status:             ConditionLocked,
+			LastTransitionTime: later,
+		},
+	}
+
+	var skews []time.Duration
+	TransitionTimeSkewObserver = func(ct ConditionType, skew time.Duration) {
+		skews = append(skews, skew)
+	}
+	t.Cleanup(func() { TransitionTimeSkewObserver = nil })
+
+	err := conditions.SetCondition(Condition{
+		Type:   ConditionType("Skewed"),
+		Status: ConditionCompleted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := conditions.MustType(ConditionType("Skewed"))
+	if !condition.LastTransitionTime.Equal(&later) {
+		t.Error("Expected the later transition time to be kept, got: ", condition.LastTransitionTime)
+	}
+
+	if len(skews) != 1 || skews[0] <= 0 {
+		t.Error("Expected the skew to be observed, got: ", skews)
+	}
+}
+
+func TestSetConditionKeepsTheTransitionTimeGiven(t *testing.T) {
+	later := meta.NewTime(time.Now().Add(time.Minute).Truncate(time.Second))
+	earlier := meta.NewTime(later.Add(-time.Hour))
+	conditions := Conditions{
+		{
+			Type:               ConditionType("Restored"),
+			Status:             ConditionCompleted,
+			LastTransitionTime: later,
+		},
+	}
+
+	err := conditions.SetCondition(Condition{
+		Type:               ConditionType("Restored"),
+		Status:             ConditionCompleted,
+		LastTransitionTime: earlier,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if condition := conditions.MustType(ConditionType("Restored")); !condition.LastTransitionTime.Equal(&earlier) {
+		t.Error("Expected the transition time to be set as given, got: ", condition.LastTransitionTime)
+	}
+}
+
+func TestSetConditionWithoutMonotonicTransitionTimes(t *testing.T) {
+	MonotonicTransitionTimes = false
+	t.Cleanup(func() { MonotonicTransitionTimes = true })
+
+	later := meta.NewTime(time.Now().Add(time.Minute).Truncate(time.Second))
+	conditions := Conditions{
+		{
+			Type:               ConditionType("Skewed"),
+			Status:             ConditionLocked,
+			LastTransitionTime: later,
+		},
+	}
+
+	err := conditions.SetCondition(Condition{
+		Type:   ConditionType("Skewed"),
+		Status: ConditionCompleted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if condition := conditions.MustType(ConditionType("Skewed")); !condition.LastTransitionTime.Before(&later) {
+		t.Error("Expected the transition time to be stamped as is, got: ", condition.LastTransitionTime)
+	}
+}
@@ -2,13 +2,12 @@ package konditions
 
 import (
 	"errors"
+	"fmt"
 	"slices"
-	"time"
-
-	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var NotInitializedConditionsErr = errors.New("Conditions is not initialized")
+var TerminalConditionErr = errors.New("Condition is in a terminal status")
 
 // Set the given condition into the Conditions.
 // The return value indicates whether the condition was changed in the stack or not.
@@ -26,13 +25,79 @@ var NotInitializedConditionsErr = errors.New("Conditions is not initialized")
 //	if err := reconciler.Status().Update(&myResource); err != nil {
 //		// ... deal with k8s error ...
 //	}
+//
+// Conditions that reached a terminal status (ConditionError, ConditionExhausted, ConditionTerminated) can't
+// transition to another status, SetCondition returns TerminalConditionErr in that case. This
+// prevents a condition from being resurrected by accident. If you want to retry a condition,
+// use Reset or SetConditionForce.
+//
+// When the StrictTransitions feature is enabled, a condition that is ConditionLocked can only be released by the
+// Lock holding it, SetCondition returns LockedConditionErr otherwise.
+//
+// A condition given without a LastTransitionTime is stamped with the current time. That time never goes before
+// the LastTransitionTime of the condition it replaces, the later time is kept when the clock of this replica is
+// behind, see MonotonicTransitionTimes. A LastTransitionTime given by the caller is stored as is, even if it's
+// earlier.
 func (c *Conditions) SetCondition(newCondition Condition) error {
+	return c.setCondition(newCondition, false)
+}
+
+// Sets the condition like SetCondition. Releasing is true when the caller holds the lock of the condition, the
+// StrictTransitions feature doesn't apply then.
+func (c *Conditions) setCondition(newCondition Condition, releasing bool) error {
+	if c == nil {
+		return NotInitializedConditionsErr
+	}
+
+	existing, ok := c.GetType(newCondition.Type)
+	if ok && existing.IsTerminal() && existing.Status != newCondition.Status {
+		return fmt.Errorf("%w: %s is %s", TerminalConditionErr, existing.Type, existing.Status)
+	}
+
+	if ok && !releasing && existing.Status == ConditionLocked && newCondition.Status != ConditionLocked && Gates.Enabled(StrictTransitions) {
+		return fmt.Errorf("%w: %s can only be released by its lock", LockedConditionErr, existing.Type)
+	}
+
+	return c.SetConditionForce(newCondition)
+}
+
+// Set the given condition into the Conditions, even if the existing condition is in a
+// terminal status. This is the escape hatch for the cases where a condition needs to transition
+// out of a terminal status deliberately. Otherwise, it behaves exactly like SetCondition.
+//
+//	// The user fixed the credentials, the bucket can be attempted again.
+//	myResource.conditions.SetConditionForce(konditions.Condition{
+//		Type:   ConditionType("Bucket"),
+//		Status: konditions.ConditionInitialized,
+//		Reason: "Credentials updated",
+//	})
+func (c *Conditions) SetConditionForce(newCondition Condition) error {
 	if c == nil {
 		return NotInitializedConditionsErr
 	}
 
-	if newCondition.LastTransitionTime.IsZero() {
-		newCondition.LastTransitionTime = meta.NewTime(time.Now())
+	if err := newCondition.ValidateAttributes(); err != nil {
+		return err
+	}
+
+	if err := ReadySemanticsFor(newCondition.Type).Validate(newCondition.Status); err != nil {
+		return err
+	}
+
+	if newCondition.LastTransitionTime.IsZero() && Gates.Enabled(NoOpDetection) {
+		// Setting the same status again isn't a transition.
+		if existing, ok := c.GetType(newCondition.Type); ok && existing.Status == newCondition.Status {
+			newCondition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	stamped := newCondition.LastTransitionTime.IsZero()
+	if stamped {
+		newCondition.LastTransitionTime = now()
+	}
+
+	if DefaultReasonFormatter != nil {
+		newCondition.Reason = DefaultReasonFormatter(newCondition.Reason)
 	}
 
 	var condition *Condition
@@ -50,10 +115,89 @@ func (c *Conditions) SetCondition(newCondition Condition) error {
 		return nil
 	}
 
+	if stamped {
+		monotonicTransitionTime(*condition, &newCondition)
+	}
 	*c = slices.Replace(*c, index, index+1, newCondition)
 	return nil
 }
 
+// Attributes kept by Reset.
+var retainedAttributes = []string{ConsecutiveErrorsAttribute, RetryBudgetAttemptsAttribute, RetryBudgetWindowAttribute}
+
+// Reset the condition with the given type back to ConditionInitialized, regardless of its current
+// status. The reason should explain why the condition is reset, since it will replace the reason
+// of the condition, which often is the error that made it terminal.
+//
+// The attributes of the condition are dropped, except for the attributes that track failures across
+// retries: the ConsecutiveErrorsAttribute of the DegradedDetector and the attributes of the RetryBudget.
+//
+//	myResource.conditions.Reset(ConditionType("Bucket"), "Retry requested by the user")
+func (c *Conditions) Reset(conditionType ConditionType, reason string) error {
+	condition := Condition{
+		Type:   conditionType,
+		Status: ConditionInitialized,
+		Reason: reason,
+	}
+
+	if c != nil {
+		if existing := c.FindType(conditionType); existing != nil {
+			for _, key := range retainedAttributes {
+				if value, ok := existing.GetAttr(key); ok {
+					condition.SetAttr(key, value)
+				}
+			}
+		}
+	}
+
+	return c.SetConditionForce(condition)
+}
+
+// Rename the condition with the old type to the new type. The condition keeps everything else: its status,
+// reason, timestamps, attributes and position in the set. The return value indicates whether a condition
+// was renamed or not.
+//
+// This is meant for operators that rename a step between releases: the resources created by the previous
+// releases keep their progress on the step instead of starting it over. Since there is nothing to rename once the
+// resource was migrated, it can be called on every reconciliation:
+//
+//	renamed, err := myResource.conditions.RenameType(ConditionType("S3 Bucket"), ConditionType("Bucket"))
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	if renamed {
+//		if err := reconciler.Status().Update(ctx, &myResource); err != nil {
+//			// ... deal with k8s error ...
+//		}
+//	}
+//
+// If the set has conditions with both types, neither is changed and DuplicateConditionTypeErr is returned: the
+// caller has to decide which one to keep.
+func (c *Conditions) RenameType(oldType, newType ConditionType) (renamed bool, err error) {
+	if c == nil {
+		return false, NotInitializedConditionsErr
+	}
+
+	if oldType == newType {
+		return false, nil
+	}
+
+	index := slices.IndexFunc(*c, func(condition Condition) bool {
+		return condition.Type == oldType
+	})
+	if index == -1 {
+		return false, nil
+	}
+
+	if _, ok := c.GetType(newType); ok {
+		return false, fmt.Errorf("%w: can't rename %s, %s already exists", DuplicateConditionTypeErr, oldType, newType)
+	}
+
+	(*c)[index].Type = newType
+	return true, nil
+}
+
 // Remove the conditionType from the conditions set.
 // The return value indicates whether a condition was removed or not.
 //
@@ -66,18 +210,56 @@ func (c *Conditions) SetCondition(newCondition Condition) error {
 //		// ... deal with k8s error ...
 //	}
 func (c *Conditions) RemoveConditionWith(conditionType ConditionType) (removed bool) {
+	return c.RemoveWhere(func(condition Condition) bool {
+		return condition.Type == conditionType
+	}) > 0
+}
+
+// Remove all the conditions with the given status from the conditions set and return how many were removed.
+// This is useful to clean up conditions that are done with once the resource is, like the conditions that
+// were terminated after a finalizer was removed:
+//
+//	myResource.conditions.RemoveAllWithStatus(konditions.ConditionTerminated)
+func (c *Conditions) RemoveAllWithStatus(status ConditionStatus) int {
+	return c.RemoveWhere(func(condition Condition) bool {
+		return condition.Status == status
+	})
+}
+
+// Remove the conditions for which the predicate returns true from the conditions set and return how many
+// were removed. The order of the conditions that are kept doesn't change.
+//
+//	// Drop the conditions that failed over a week ago.
+//	myResource.conditions.RemoveWhere(func(condition konditions.Condition) bool {
+//		return condition.Class() == konditions.ClassError && time.Since(condition.LastTransitionTime.Time) > 7*24*time.Hour
+//	})
+func (c *Conditions) RemoveWhere(predicate func(Condition) bool) int {
 	if c == nil || len(*c) == 0 {
-		return false
+		return 0
 	}
-	newConditions := make(Conditions, 0, len(*c)-1)
+
+	newConditions := make(Conditions, 0, len(*c))
 	for _, condition := range *c {
-		if condition.Type != conditionType {
+		if !predicate(condition) {
 			newConditions = append(newConditions, condition)
 		}
 	}
 
-	removed = len(*c) != len(newConditions)
+	removed := len(*c) - len(newConditions)
 	*c = newConditions
 
 	return removed
 }
+
+// Remove all the conditions from the conditions set and return how many were removed. The set is left empty,
+// not nil, so it can still be used to set conditions.
+func (c *Conditions) Clear() int {
+	if c == nil {
+		return 0
+	}
+
+	removed := len(*c)
+	*c = Conditions{}
+
+	return removed
+}
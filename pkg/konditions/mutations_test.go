@@ -1,6 +1,7 @@
 package konditions
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -56,7 +57,7 @@ func TestSetCondition(t *testing.T) {
 	// Add 2 more conditions for the next test step
 	status.conditions.SetCondition(Condition{
 		Type:   ConditionType("ToBeReplaced"),
-		Status: ConditionTerminated,
+		Status: ConditionCreated,
 	})
 
 	status.conditions.SetCondition(Condition{
@@ -96,6 +97,69 @@ func TestSetCondition(t *testing.T) {
 	}
 }
 
+func TestSetConditionTerminal(t *testing.T) {
+	conditions := Conditions{}
+
+	for _, status := range []ConditionStatus{ConditionError, ConditionTerminated} {
+		conditions.SetCondition(Condition{
+			Type:   ConditionType("Terminal"),
+			Status: status,
+			Reason: "Done",
+		})
+
+		err := conditions.SetCondition(Condition{
+			Type:   ConditionType("Terminal"),
+			Status: ConditionInitialized,
+		})
+
+		if !errors.Is(err, TerminalConditionErr) {
+			t.Error("Expected TerminalConditionErr, got: ", err)
+		}
+
+		if !conditions.TypeHasStatus(ConditionType("Terminal"), status) {
+			t.Error("Expected the condition to stay terminal")
+		}
+
+		err = conditions.SetCondition(Condition{
+			Type:   ConditionType("Terminal"),
+			Status: status,
+			Reason: "Updated reason",
+		})
+
+		if err != nil {
+			t.Error("Expected the reason of a terminal condition to be updatable, got: ", err)
+		}
+
+		if err := conditions.SetConditionForce(Condition{Type: ConditionType("Terminal"), Status: ConditionCreated}); err != nil {
+			t.Error("Expected SetConditionForce to succeed, got: ", err)
+		}
+
+		if !conditions.TypeHasStatus(ConditionType("Terminal"), ConditionCreated) {
+			t.Error("Expected the condition to be forced to created")
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionError, Reason: "Access denied"},
+	}
+
+	if err := conditions.Reset(ConditionType("Bucket"), "Credentials updated"); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := conditions.FindType(ConditionType("Bucket"))
+	if condition.Status != ConditionInitialized || condition.Reason != "Credentials updated" {
+		t.Error("Unexpected condition: ", condition)
+	}
+
+	var uninitialized *Conditions
+	if err := uninitialized.Reset(ConditionType("Bucket"), ""); !errors.Is(err, NotInitializedConditionsErr) {
+		t.Error("Expected NotInitializedConditionsErr, got: ", err)
+	}
+}
+
 func TestRemoveCondition(t *testing.T) {
 	var conditions *Conditions
 
@@ -143,3 +207,106 @@ func TestRemoveCondition(t *testing.T) {
 		t.Error("Expected the condition to still be present")
 	}
 }
+
+func TestRemoveWhere(t *testing.T) {
+	var uninitialized *Conditions
+	if removed := uninitialized.RemoveWhere(func(Condition) bool { return true }); removed != 0 {
+		t.Error("Conditions not initialized, should have not removed anything")
+	}
+
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionTerminated},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted},
+		{Type: ConditionType("Certificate"), Status: ConditionTerminated},
+		{Type: ConditionType("Route"), Status: ConditionError},
+	}
+
+	if removed := conditions.RemoveAllWithStatus(ConditionTerminated); removed != 2 {
+		t.Error("Expected 2 conditions to be removed, got: ", removed)
+	}
+
+	if len(conditions) != 2 || conditions[0].Type != ConditionType("DNS") || conditions[1].Type != ConditionType("Route") {
+		t.Error("Expected the other conditions to be kept in order, got: ", conditions)
+	}
+
+	removed := conditions.RemoveWhere(func(condition Condition) bool {
+		return condition.Class() == ClassError
+	})
+	if removed != 1 || len(conditions) != 1 {
+		t.Error("Expected the errored condition to be removed, got: ", removed, conditions)
+	}
+
+	if removed := conditions.RemoveAllWithStatus(ConditionTerminated); removed != 0 {
+		t.Error("Expected nothing to be removed, got: ", removed)
+	}
+}
+
+func TestClear(t *testing.T) {
+	var uninitialized *Conditions
+	if removed := uninitialized.Clear(); removed != 0 {
+		t.Error("Conditions not initialized, should have not removed anything")
+	}
+
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+		{Type: ConditionType("DNS"), Status: ConditionCompleted},
+	}
+
+	if removed := conditions.Clear(); removed != 2 {
+		t.Error("Expected 2 conditions to be removed, got: ", removed)
+	}
+
+	if conditions == nil || len(conditions) != 0 {
+		t.Error("Expected the conditions to be empty, got: ", conditions)
+	}
+
+	if err := conditions.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionInitialized}); err != nil {
+		t.Error("Expected the conditions to still be usable, got: ", err)
+	}
+}
+
+func TestRenameType(t *testing.T) {
+	var uninitialized *Conditions
+	if _, err := uninitialized.RenameType(ConditionType("S3 Bucket"), ConditionType("Bucket")); !errors.Is(err, NotInitializedConditionsErr) {
+		t.Error("Expected NotInitializedConditionsErr, got: ", err)
+	}
+
+	transition := meta.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	conditions := Conditions{
+		{Type: ConditionType("DNS"), Status: ConditionCompleted, LastTransitionTime: transition},
+		{Type: ConditionType("S3 Bucket"), Status: ConditionCreated, Reason: "Bucket created", LastTransitionTime: transition},
+	}
+	conditions[1].SetAttr("region", "ca-central-1")
+
+	renamed, err := conditions.RenameType(ConditionType("S3 Bucket"), ConditionType("Bucket"))
+	if err != nil || !renamed {
+		t.Fatal("Expected the condition to be renamed, got: ", renamed, err)
+	}
+
+	if _, ok := conditions.GetType(ConditionType("S3 Bucket")); ok {
+		t.Error("Expected the old type to be gone")
+	}
+
+	condition := conditions[1]
+	if condition.Type != ConditionType("Bucket") || condition.Status != ConditionCreated || condition.Reason != "Bucket created" || !condition.LastTransitionTime.Equal(&transition) {
+		t.Error("Expected the condition to be kept as is, got: ", condition)
+	}
+
+	if region, _ := condition.GetAttr("region"); region != "ca-central-1" {
+		t.Error("Expected the attributes to be kept, got: ", region)
+	}
+
+	renamed, err = conditions.RenameType(ConditionType("S3 Bucket"), ConditionType("Bucket"))
+	if err != nil || renamed {
+		t.Error("Expected nothing to rename once migrated, got: ", renamed, err)
+	}
+
+	_, err = conditions.RenameType(ConditionType("DNS"), ConditionType("Bucket"))
+	if !errors.Is(err, DuplicateConditionTypeErr) {
+		t.Error("Expected DuplicateConditionTypeErr, got: ", err)
+	}
+
+	if conditions[0].Type != ConditionType("DNS") {
+		t.Error("Expected the conditions to be left alone, got: ", conditions)
+	}
+}
@@ -0,0 +1,117 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var DuplicateConditionTypeErr = errors.New("Duplicate condition type")
+var EmptyConditionTypeErr = errors.New("Condition without a type")
+
+// Normalize the conditions so they hold the invariants the rest of Konditionner relies on, and
+// returns the anomalies that were fixed, joined in a single error. The error is nil if the
+// conditions were already valid.
+//
+// Conditions read back from the Kubernetes API were not necessarily written by Konditionner. A
+// buggy client can store the same type twice, which silently breaks FindType since it assumes types
+// are unique. Normalize:
+//   - Removes the conditions without a type, reported with EmptyConditionTypeErr;
+//   - Deduplicates the conditions that share a type, keeping the one with the latest LastTransitionTime,
+//     reported with DuplicateConditionTypeErr;
+//   - Sorts the conditions by type.
+//
+// The helpers that decode conditions from external data (NewAnnotationConditions, Store.Load,
+// NestedConditions, etc.) normalize the conditions they decode. The anomalies can be inspected
+// with errors.Is:
+//
+//	if err := res.Status.Conditions.Normalize(); errors.Is(err, konditions.DuplicateConditionTypeErr) {
+//		log.Error(err, "Conditions were normalized")
+//	}
+func (c *Conditions) Normalize() error {
+	if c == nil {
+		return NotInitializedConditionsErr
+	}
+
+	var anomalies []error
+	counts := map[ConditionType]int{}
+	latest := map[ConditionType]Condition{}
+
+	for _, condition := range *c {
+		if condition.Type == "" {
+			anomalies = append(anomalies, EmptyConditionTypeErr)
+			continue
+		}
+
+		counts[condition.Type]++
+		if existing, ok := latest[condition.Type]; ok && condition.LastTransitionTime.Before(&existing.LastTransitionTime) {
+			continue
+		}
+		latest[condition.Type] = condition
+	}
+
+	normalized := make(Conditions, 0, len(latest))
+	for _, condition := range latest {
+		normalized = append(normalized, condition)
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		return normalized[i].Type < normalized[j].Type
+	})
+
+	for _, condition := range normalized {
+		if count := counts[condition.Type]; count > 1 {
+			anomalies = append(anomalies, fmt.Errorf("%w: %s appears %d times", DuplicateConditionTypeErr, condition.Type, count))
+		}
+	}
+
+	*c = normalized
+
+	return errors.Join(anomalies...)
+}
+
+// NormalizedError is returned, along with the conditions, by the helpers that decode conditions from external data when
+// Normalize had to fix anomalies in them. The conditions returned with the error are normalized and usable, the error
+// reports what was fixed so that the data, or the client that wrote it, can be corrected:
+//
+//	conditions, _, err := konditions.NestedConditions(obj, "status", "conditions")
+//	var normalized *konditions.NormalizedError
+//	if errors.As(err, &normalized) {
+//		log.Info("Conditions were normalized", "anomalies", normalized.Anomalies.Error())
+//	} else if err != nil {
+//		return err
+//	}
+//
+// The anomalies are wrapped, errors.Is finds DuplicateConditionTypeErr and EmptyConditionTypeErr through the error.
+type NormalizedError struct {
+	Anomalies error
+}
+
+func (e *NormalizedError) Error() string {
+	return fmt.Sprintf("Conditions were normalized: %s", e.Anomalies)
+}
+
+func (e *NormalizedError) Unwrap() error {
+	return e.Anomalies
+}
+
+// Normalizes conditions decoded from external data and returns the anomalies fixed as a *NormalizedError, nil if
+// there were none.
+func normalizeDecoded(c *Conditions) error {
+	if err := c.Normalize(); err != nil {
+		return &NormalizedError{Anomalies: err}
+	}
+
+	return nil
+}
+
+// Splits the error of a helper that decodes conditions: the anomalies Normalize fixed, and the error that made the
+// decoding fail.
+func splitNormalized(err error) (anomalies error, failure error) {
+	var normalized *NormalizedError
+	if errors.As(err, &normalized) {
+		return err, nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,145 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNormalize(t *testing.T) {
+	now := time.Now()
+	conditions := Conditions{
+		{Type: ConditionType("DNS"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(now)},
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(now)},
+		{Type: ConditionType("Bucket"), Status: ConditionCreated, LastTransitionTime: meta.NewTime(now.Add(-time.Minute))},
+		{Status: ConditionCreated},
+	}
+
+	err := conditions.Normalize()
+	if !errors.Is(err, DuplicateConditionTypeErr) || !errors.Is(err, EmptyConditionTypeErr) {
+		t.Error("Expected the anomalies to be reported, got: ", err)
+	}
+
+	if len(conditions) != 2 || conditions[0].Type != ConditionType("Bucket") || conditions[1].Type != ConditionType("DNS") {
+		t.Fatal("Unexpected conditions: ", conditions)
+	}
+
+	if conditions[0].Status != ConditionCompleted {
+		t.Error("Expected the latest condition to be kept, got: ", conditions[0])
+	}
+
+	if err := conditions.Normalize(); err != nil {
+		t.Error("Expected normalized conditions to be valid, got: ", err)
+	}
+}
+
+func TestNewAnnotationConditionsNormalizes(t *testing.T) {
+	res := newTestResource("normalize")
+	res.SetAnnotations(map[string]string{
+		DefaultConditionsAnnotation: `[{"type":"Bucket","status":"Created"},{"type":"Bucket","status":"Completed","lastTransitionTime":"2024-01-01T00:00:00Z"}]`,
+	})
+
+	annotated, err := NewAnnotationConditions(res, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*annotated.Conditions()) != 1 || !annotated.TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the conditions to be normalized, got: ", annotated.Conditions())
+	}
+
+	var normalized *NormalizedError
+	if err := annotated.Anomalies(); !errors.As(err, &normalized) || !errors.Is(err, DuplicateConditionTypeErr) {
+		t.Error("Expected the duplicates to be reported, got: ", err)
+	}
+}
+
+const duplicatedConditionsJSON = `[{"type":"Bucket","status":"Created"},{"type":"Bucket","status":"Completed","lastTransitionTime":"2024-01-01T00:00:00Z"},{"status":"Error"}]`
+
+func TestNestedConditionsNormalizes(t *testing.T) {
+	var value []interface{}
+	if err := json.Unmarshal([]byte(duplicatedConditionsJSON), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"conditions": value},
+	}}
+
+	conditions, found, err := NestedConditions(obj, DefaultConditionsFields...)
+	if !found || len(conditions) != 1 || !errors.Is(err, DuplicateConditionTypeErr) || !errors.Is(err, EmptyConditionTypeErr) {
+		t.Error("Expected the normalized conditions along with their anomalies, got: ", conditions, err)
+	}
+
+	u, err := NewUnstructuredConditions(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var normalized *NormalizedError
+	if !errors.As(u.Anomalies(), &normalized) || !u.TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the anomalies to be kept, got: ", u.Anomalies())
+	}
+}
+
+func TestStoreLoadNormalizes(t *testing.T) {
+	ctx := context.Background()
+	name := types.NamespacedName{Namespace: "operator", Name: "migrations"}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+		Data:       map[string]string{DefaultStoreKey: duplicatedConditionsJSON},
+	}
+
+	stored, err := NewConfigMapStore(fake.NewClientBuilder().WithObjects(configMap).Build(), name, "").Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*stored.Conditions()) != 1 || !errors.Is(stored.Anomalies(), DuplicateConditionTypeErr) {
+		t.Error("Expected the anomalies of the stored conditions to be reported, got: ", stored.Anomalies())
+	}
+}
+
+func TestRegistryNormalizes(t *testing.T) {
+	ctx := context.Background()
+	informers := &informertest.FakeInformers{Scheme: newTestScheme()}
+
+	registry := NewRegistry(&testResource{})
+	if err := registry.Register(ctx, informers); err != nil {
+		t.Fatal(err)
+	}
+
+	informer, err := informers.FakeInformerFor(ctx, &testResource{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := newTestResource("duplicated")
+	*res.Conditions() = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCreated},
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+	}
+	informer.Add(res)
+
+	key := types.NamespacedName{Namespace: res.Namespace, Name: res.Name}
+	if err := registry.Anomalies(key); !errors.Is(err, DuplicateConditionTypeErr) {
+		t.Error("Expected the anomalies of the resource to be reported, got: ", err)
+	}
+
+	fixed := res.DeepCopyObject().(*testResource)
+	*fixed.Conditions() = Conditions{{Type: ConditionType("Bucket"), Status: ConditionCompleted}}
+	informer.Update(res, fixed)
+
+	if err := registry.Anomalies(key); err != nil {
+		t.Error("Expected the anomalies to be cleared once the conditions are valid, got: ", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package notifier sends the transitions of conditions to external systems: a generic webhook, Slack or
+// PagerDuty. It is built on top of konditions.Watch:
+//
+//	events, err := konditions.Watch(ctx, mgr.GetCache(), &Bucket{})
+//	if err != nil {
+//		return err
+//	}
+//
+//	n := notifier.New(notifier.SlackSink{WebhookURL: os.Getenv("SLACK_WEBHOOK_URL")},
+//		notifier.OnlyStatuses(konditions.ConditionError),
+//		notifier.OnlyTypes(ConditionType("Bucket")),
+//	)
+//
+//	go n.Run(ctx, events)
+//
+// Transitions are rendered with a template, see DefaultTemplate, and sent in batches to limit the number of
+// calls made to the sink.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// DefaultTemplate renders a transition on a single line, e.g. `default/bucket: Bucket Locked → Error: Access denied`.
+const DefaultTemplate = `{{ .Key }}: {{ .Type }} {{ status .Old }} → {{ status .New }}{{ with .Reason }}: {{ . }}{{ end }}`
+
+const (
+	// DefaultBatchSize is the maximum number of messages sent to a sink at once.
+	DefaultBatchSize = 20
+
+	// DefaultBatchInterval is the maximum time a message waits before being sent.
+	DefaultBatchInterval = 5 * time.Second
+)
+
+var DeliveryErr = errors.New("Notification could not be delivered")
+
+// Message is a transition rendered by the template of the notifier.
+type Message struct {
+	Event konditions.TransitionEvent
+	Text  string
+}
+
+// Sink delivers messages to an external system.
+type Sink interface {
+	Send(ctx context.Context, messages []Message) error
+}
+
+// SinkFunc is a function that implements Sink.
+type SinkFunc func(ctx context.Context, messages []Message) error
+
+func (fn SinkFunc) Send(ctx context.Context, messages []Message) error {
+	return fn(ctx, messages)
+}
+
+// Filter returns whether the event should be sent.
+type Filter func(event konditions.TransitionEvent) bool
+
+// OnlyStatuses only lets through the transitions to one of the statuses given.
+func OnlyStatuses(statuses ...konditions.ConditionStatus) Filter {
+	return func(event konditions.TransitionEvent) bool {
+		return event.New != nil && event.New.StatusIsOneOf(statuses...)
+	}
+}
+
+// OnlyTypes only lets through the transitions of the condition types given.
+func OnlyTypes(types ...konditions.ConditionType) Filter {
+	return func(event konditions.TransitionEvent) bool {
+		return slices.Contains(types, event.Type)
+	}
+}
+
+// Notifier renders the events that pass its filters and sends them to its sink, in batches.
+type Notifier struct {
+	Sink    Sink
+	Filters []Filter
+
+	// Template renders the text of the messages, DefaultTemplate is used when it's nil.
+	Template *template.Template
+
+	BatchSize     int
+	BatchInterval time.Duration
+
+	// OnError is called when a batch can't be delivered. Batches that fail are dropped.
+	OnError func(err error, messages []Message)
+}
+
+// New returns a notifier that sends the events that pass all the filters to the sink.
+func New(sink Sink, filters ...Filter) *Notifier {
+	return &Notifier{
+		Sink:          sink,
+		Filters:       filters,
+		BatchSize:     DefaultBatchSize,
+		BatchInterval: DefaultBatchInterval,
+	}
+}
+
+// Templates need the status helper to render the conditions of the events, which can be nil.
+var funcs = template.FuncMap{
+	"status": func(condition *konditions.Condition) string {
+		if condition == nil {
+			return "None"
+		}
+		return string(condition.Status)
+	},
+}
+
+// ParseTemplate parses a template for the messages of a notifier. The template is executed with a
+// konditions.TransitionEvent, and has access to the `status` function to render the status of a condition that
+// may be nil.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("message").Funcs(funcs).Parse(text)
+}
+
+var defaultTemplate = template.Must(ParseTemplate(DefaultTemplate))
+
+// Run consumes the events until the channel is closed or the context is done. The messages that are pending
+// are sent before Run returns.
+func (n *Notifier) Run(ctx context.Context, events <-chan konditions.TransitionEvent) error {
+	interval := n.BatchInterval
+	if interval <= 0 {
+		interval = DefaultBatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var batch []Message
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		// The context may already be done, the pending messages still get a chance to be delivered.
+		if err := n.Sink.Send(context.WithoutCancel(ctx), batch); err != nil && n.OnError != nil {
+			n.OnError(err, batch)
+		}
+		batch = nil
+	}
+	defer flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			flush()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			message, ok, err := n.render(event)
+			if err != nil {
+				if n.OnError != nil {
+					n.OnError(err, nil)
+				}
+				continue
+			}
+
+			if !ok {
+				continue
+			}
+
+			batch = append(batch, message)
+			if n.BatchSize <= 0 || len(batch) >= n.BatchSize {
+				flush()
+			}
+		}
+	}
+}
+
+func (n *Notifier) render(event konditions.TransitionEvent) (Message, bool, error) {
+	for _, filter := range n.Filters {
+		if !filter(event) {
+			return Message{}, false, nil
+		}
+	}
+
+	tmpl := n.Template
+	if tmpl == nil {
+		tmpl = defaultTemplate
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return Message{}, false, err
+	}
+
+	return Message{Event: event, Text: buf.String()}, true, nil
+}
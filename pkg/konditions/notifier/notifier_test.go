@@ -0,0 +1,84 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newEvent(ct konditions.ConditionType, from, to konditions.ConditionStatus, reason string) konditions.TransitionEvent {
+	return konditions.TransitionEvent{
+		Key:    types.NamespacedName{Namespace: "default", Name: "bucket"},
+		Type:   ct,
+		Old:    &konditions.Condition{Type: ct, Status: from},
+		New:    &konditions.Condition{Type: ct, Status: to, Reason: reason},
+		Reason: reason,
+	}
+}
+
+func TestNotifierRun(t *testing.T) {
+	var batches [][]Message
+	n := New(SinkFunc(func(ctx context.Context, messages []Message) error {
+		batches = append(batches, messages)
+		return nil
+	}), OnlyStatuses(konditions.ConditionError), OnlyTypes("Bucket", "DNS"))
+	n.BatchSize = 2
+
+	events := make(chan konditions.TransitionEvent, 10)
+	events <- newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionError, "Access denied")
+	events <- newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionCompleted, "")
+	events <- newEvent("Internal", konditions.ConditionLocked, konditions.ConditionError, "Filtered out")
+	events <- newEvent("DNS", konditions.ConditionLocked, konditions.ConditionError, "")
+	events <- konditions.TransitionEvent{Key: types.NamespacedName{Namespace: "default", Name: "bucket"}, Type: "DNS", New: &konditions.Condition{Type: "DNS", Status: konditions.ConditionError}}
+	close(events)
+
+	if err := n.Run(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatal("Unexpected batches: ", batches)
+	}
+
+	expected := []string{
+		"default/bucket: Bucket Locked → Error: Access denied",
+		"default/bucket: DNS Locked → Error",
+		"default/bucket: DNS None → Error",
+	}
+
+	for i, message := range append(batches[0], batches[1]...) {
+		if message.Text != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], message.Text)
+		}
+	}
+}
+
+func TestNotifierTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{ .Type }} is {{ status .New }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	n := New(SinkFunc(func(ctx context.Context, messages []Message) error {
+		for _, message := range messages {
+			texts = append(texts, message.Text)
+		}
+		return nil
+	}))
+	n.Template = tmpl
+
+	events := make(chan konditions.TransitionEvent, 1)
+	events <- newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionCompleted, "")
+	close(events)
+
+	if err := n.Run(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(texts) != 1 || texts[0] != "Bucket is Completed" {
+		t.Error("Unexpected messages: ", texts)
+	}
+}
@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+// WebhookSink posts the messages to a URL, as JSON:
+//
+//	{"messages": [{"text": "default/bucket: Bucket Locked → Error", "namespace": "default", "name": "bucket", "type": "Bucket", "old": {...}, "new": {...}}]}
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+
+	// Client is used to send the requests, http.DefaultClient when it's nil.
+	Client *http.Client
+}
+
+type webhookMessage struct {
+	Text      string                   `json:"text"`
+	Namespace string                   `json:"namespace"`
+	Name      string                   `json:"name"`
+	Type      konditions.ConditionType `json:"type"`
+	Old       *konditions.Condition    `json:"old,omitempty"`
+	New       *konditions.Condition    `json:"new,omitempty"`
+}
+
+func (s WebhookSink) Send(ctx context.Context, messages []Message) error {
+	payload := struct {
+		Messages []webhookMessage `json:"messages"`
+	}{}
+
+	for _, message := range messages {
+		payload.Messages = append(payload.Messages, webhookMessage{
+			Text:      message.Text,
+			Namespace: message.Event.Key.Namespace,
+			Name:      message.Event.Key.Name,
+			Type:      message.Event.Type,
+			Old:       message.Event.Old,
+			New:       message.Event.New,
+		})
+	}
+
+	return post(ctx, s.Client, s.URL, s.Headers, payload)
+}
+
+// SlackSink posts the messages to a Slack incoming webhook, as a single Slack message.
+type SlackSink struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+func (s SlackSink) Send(ctx context.Context, messages []Message) error {
+	lines := make([]string, len(messages))
+	for i, message := range messages {
+		lines[i] = message.Text
+	}
+
+	return post(ctx, s.Client, s.WebhookURL, nil, map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+// DefaultPagerDutyEndpoint is the endpoint of the Events API v2 of PagerDuty.
+const DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutySink triggers a PagerDuty incident for every transition to ConditionError, and resolves it once the
+// condition transitions to any other status. Incidents are deduplicated per resource and condition type.
+type PagerDutySink struct {
+	RoutingKey string
+
+	// Endpoint is DefaultPagerDutyEndpoint when it's empty.
+	Endpoint string
+
+	// Severity of the incidents, "error" when it's empty.
+	Severity string
+
+	Client *http.Client
+}
+
+func (s PagerDutySink) Send(ctx context.Context, messages []Message) error {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyEndpoint
+	}
+
+	severity := s.Severity
+	if severity == "" {
+		severity = "error"
+	}
+
+	for _, message := range messages {
+		event := map[string]any{
+			"routing_key":  s.RoutingKey,
+			"event_action": "resolve",
+			"dedup_key":    fmt.Sprintf("%s/%s", message.Event.Key, message.Event.Type),
+		}
+
+		if message.Event.New != nil && message.Event.New.Status == konditions.ConditionError {
+			event["event_action"] = "trigger"
+			event["payload"] = map[string]string{
+				"summary":  message.Text,
+				"source":   message.Event.Key.String(),
+				"severity": severity,
+			}
+		}
+
+		if err := post(ctx, s.Client, endpoint, nil, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func post(ctx context.Context, c *http.Client, url string, headers map[string]string, payload any) error {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", DeliveryErr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s responded with %s", DeliveryErr, url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+)
+
+func newTestEndpoint(t *testing.T, status int) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+
+		if r.Header.Get("Authorization") != "" {
+			payload["authorization"] = r.Header.Get("Authorization")
+		}
+
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &payloads
+}
+
+func TestWebhookSink(t *testing.T) {
+	server, payloads := newTestEndpoint(t, http.StatusOK)
+	sink := WebhookSink{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+
+	err := sink.Send(context.Background(), []Message{{Event: newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionError, ""), Text: "Bucket failed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := (*payloads)[0]["messages"].([]any)
+	if len(messages) != 1 || messages[0].(map[string]any)["text"] != "Bucket failed" || messages[0].(map[string]any)["name"] != "bucket" {
+		t.Error("Unexpected payload: ", *payloads)
+	}
+
+	if (*payloads)[0]["authorization"] != "Bearer token" {
+		t.Error("Expected the headers to be sent")
+	}
+}
+
+func TestSlackSink(t *testing.T) {
+	server, payloads := newTestEndpoint(t, http.StatusOK)
+
+	err := SlackSink{WebhookURL: server.URL}.Send(context.Background(), []Message{{Text: "first"}, {Text: "second"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if (*payloads)[0]["text"] != "first\nsecond" {
+		t.Error("Unexpected payload: ", *payloads)
+	}
+}
+
+func TestPagerDutySink(t *testing.T) {
+	server, payloads := newTestEndpoint(t, http.StatusAccepted)
+	sink := PagerDutySink{RoutingKey: "key", Endpoint: server.URL}
+
+	err := sink.Send(context.Background(), []Message{
+		{Event: newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionError, ""), Text: "Bucket failed"},
+		{Event: newEvent("Bucket", konditions.ConditionLocked, konditions.ConditionCompleted, ""), Text: "Bucket recovered"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*payloads) != 2 {
+		t.Fatal("Expected an event per message, got: ", *payloads)
+	}
+
+	trigger, resolve := (*payloads)[0], (*payloads)[1]
+	if trigger["event_action"] != "trigger" || trigger["payload"].(map[string]any)["summary"] != "Bucket failed" {
+		t.Error("Unexpected trigger: ", trigger)
+	}
+
+	if resolve["event_action"] != "resolve" || resolve["dedup_key"] != trigger["dedup_key"] {
+		t.Error("Expected the incident to be resolved, got: ", resolve)
+	}
+}
+
+func TestSinkDeliveryError(t *testing.T) {
+	server, _ := newTestEndpoint(t, http.StatusInternalServerError)
+
+	err := SlackSink{WebhookURL: server.URL}.Send(context.Background(), []Message{{Text: "first"}})
+	if !errors.Is(err, DeliveryErr) {
+		t.Error("Expected a delivery error, got: ", err)
+	}
+}
@@ -0,0 +1,55 @@
+package konditions
+
+import "time"
+
+// Observer receives the duration of each phase of a Lock's execution, with the error the phase returned, if any.
+// It lets operators feed the telemetry system of their choice without Konditionner picking one for them.
+//
+//	type tracer struct{ span trace.Span }
+//
+//	func (t tracer) ObserveAcquire(d time.Duration, err error) { t.span.AddEvent("acquired", ...) }
+//	func (t tracer) ObserveTask(d time.Duration, err error)    { t.span.AddEvent("task", ...) }
+//	func (t tracer) ObserveCommit(d time.Duration, err error)  { t.span.AddEvent("committed", ...) }
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithObserver(tracer{span}))
+//
+// The phases are:
+//   - Acquire: from the call to Execute until the condition is locked. Waiting on a locked condition is part of it,
+//     as are the errors that keep the task from running: TerminalConditionErr, PausedConditionErr, etc. A task
+//     skipped because the condition is locked reports an error wrapping LockNotReleasedErr.
+//   - Task: the time the Task ran and the error it returned. It's only observed if the condition was acquired.
+//   - Commit: the time it took to release the condition and persist it, or to remove it with RemoveOnRelease.
+//     It's only observed if the Task ran.
+//
+// The methods are called synchronously, from the goroutine that calls Execute, and should return quickly.
+type Observer interface {
+	ObserveAcquire(duration time.Duration, err error)
+	ObserveTask(duration time.Duration, err error)
+	ObserveCommit(duration time.Duration, err error)
+}
+
+// WithObserver configures the lock to report the timing of its phases to the observer. It can be used more than once,
+// the observers are called in the order they were configured.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithObserver(observer))
+func WithObserver(observer Observer) LockOption {
+	return func(l *Lock) {
+		l.observers = append(l.observers, observer)
+	}
+}
+
+// Returns a function that reports the time elapsed since it was created, and the error given, to the observers
+// with the method given by observe.
+func (l *Lock) observe(observe func(Observer, time.Duration, error)) func(error) {
+	if len(l.observers) == 0 {
+		return func(error) {}
+	}
+
+	start := time.Now()
+	return func(err error) {
+		elapsed := time.Since(start)
+		for _, observer := range l.observers {
+			observe(observer, elapsed, err)
+		}
+	}
+}
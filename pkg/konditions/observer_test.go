@@ -0,0 +1,103 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type phase struct {
+	name     string
+	duration time.Duration
+	err      error
+}
+
+type recordingObserver struct {
+	phases []phase
+}
+
+func (r *recordingObserver) ObserveAcquire(d time.Duration, err error) {
+	r.phases = append(r.phases, phase{"acquire", d, err})
+}
+
+func (r *recordingObserver) ObserveTask(d time.Duration, err error) {
+	r.phases = append(r.phases, phase{"task", d, err})
+}
+
+func (r *recordingObserver) ObserveCommit(d time.Duration, err error) {
+	r.phases = append(r.phases, phase{"commit", d, err})
+}
+
+func TestLockWithObserver(t *testing.T) {
+	res := newTestResource("observer")
+	c := newTestClient(res)
+
+	observer := &recordingObserver{}
+	taskErr := errors.New("Bucket already exists")
+	err := NewLock(res, c, ConditionType("Bucket"), WithObserver(observer)).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		time.Sleep(10 * time.Millisecond)
+		return condition, taskErr
+	})
+
+	if !errors.Is(err, taskErr) {
+		t.Fatal("Expected the error of the task, got: ", err)
+	}
+
+	if len(observer.phases) != 3 {
+		t.Fatal("Expected the 3 phases to be observed, got: ", observer.phases)
+	}
+
+	acquire, task, commit := observer.phases[0], observer.phases[1], observer.phases[2]
+	if acquire.name != "acquire" || acquire.err != nil {
+		t.Error("Unexpected acquire phase: ", acquire)
+	}
+
+	if task.name != "task" || task.err != taskErr || task.duration < 10*time.Millisecond {
+		t.Error("Unexpected task phase: ", task)
+	}
+
+	if commit.name != "commit" || commit.err != nil {
+		t.Error("Unexpected commit phase: ", commit)
+	}
+}
+
+func TestLockWithObserverNotAcquired(t *testing.T) {
+	res := newTestResource("observer")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError, Reason: "Failed"})
+	c := newTestClient(res)
+
+	observer := &recordingObserver{}
+	err := NewLock(res, c, ConditionType("Bucket"), WithObserver(observer)).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		t.Error("Expected the task to not run")
+		return condition, nil
+	})
+
+	if !errors.Is(err, TerminalConditionErr) {
+		t.Fatal("Expected TerminalConditionErr, got: ", err)
+	}
+
+	if len(observer.phases) != 1 || observer.phases[0].name != "acquire" || !errors.Is(observer.phases[0].err, TerminalConditionErr) {
+		t.Error("Expected only the acquire phase to be observed, got: ", observer.phases)
+	}
+}
+
+func TestLockWithObserverDeletionRefused(t *testing.T) {
+	res := newDeletingTestResource("observer")
+	c := newTestClient(res)
+
+	observer := &recordingObserver{}
+	lock := NewLock(res, c, ConditionType("Bucket"), WithObserver(observer), WithDeletionPolicy(NewDeletionPolicy()))
+	err := lock.Execute(context.Background(), func(condition Condition) (Condition, error) {
+		t.Error("Expected the task to not run")
+		return condition, nil
+	})
+
+	if !errors.Is(err, ResourceDeletingErr) {
+		t.Fatal("Expected ResourceDeletingErr, got: ", err)
+	}
+
+	if len(observer.phases) != 1 || observer.phases[0].name != "acquire" || !errors.Is(observer.phases[0].err, ResourceDeletingErr) {
+		t.Error("Expected the refusal to be observed as the acquire phase, got: ", observer.phases)
+	}
+}
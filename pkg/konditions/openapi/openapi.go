@@ -0,0 +1,124 @@
+// Package openapi provides the OpenAPI v3 schema of konditions.Conditions, for the CRDs that aren't generated by
+// controller-gen or that need to be patched after the fact.
+//
+// The schema declares the conditions as a list of type map, keyed by the type of the condition. This is what lets
+// server-side apply merge the conditions entry by entry, instead of treating the list as atomic and having every
+// writer overwrite the conditions of the others:
+//
+//	crd := loadMyCRD()
+//	if err := openapi.Inject(crd, "status", "conditions"); err != nil {
+//		return err
+//	}
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var InvalidPathErr = errors.New("Invalid schema path")
+
+// TypePattern is the pattern a condition type needs to match, the same as the validation marker of Condition.Type.
+const TypePattern = `^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])$`
+
+// Schema returns the schema of konditions.Conditions. A new value is returned every time, it can be modified.
+func Schema() apiextensionsv1.JSONSchemaProps {
+	mapType := "map"
+
+	return apiextensionsv1.JSONSchemaProps{
+		Type:         "array",
+		XListType:    &mapType,
+		XListMapKeys: []string{"type"},
+		Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+			Schema: &apiextensionsv1.JSONSchemaProps{
+				Type:     "object",
+				Required: []string{"type", "status", "lastTransitionTime"},
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"type":               {Type: "string", Pattern: TypePattern, MaxLength: int64Ptr(316)},
+					"status":             {Type: "string", MaxLength: int64Ptr(128)},
+					"lastTransitionTime": {Type: "string", Format: "date-time"},
+					"reason":             {Type: "string", MinLength: int64Ptr(1), MaxLength: int64Ptr(1024)},
+					"inputHash":          {Type: "string", MaxLength: int64Ptr(64)},
+					"resumeStatus":       {Type: "string", MaxLength: int64Ptr(128)},
+					"ref":                {Type: "string", MaxLength: int64Ptr(1024)},
+					"attributes": {
+						Type:          "object",
+						MaxProperties: int64Ptr(16),
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+							Allows: true,
+							Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+						},
+					},
+					"observedGeneration": {Type: "integer", Format: "int64", Minimum: float64Ptr(0)},
+					"manager":            {Type: "string", MaxLength: int64Ptr(128)},
+					"lastHeartbeatTime":  {Type: "string", Format: "date-time"},
+				},
+			},
+		},
+	}
+}
+
+// YAML returns the schema as YAML, for the consumers of controller-gen that maintain patches of
+// their CRDs, with kustomize for instance.
+func YAML() ([]byte, error) {
+	return yaml.Marshal(Schema())
+}
+
+// Inject sets the schema of the conditions at the path given, for every version of the CRD. Every
+// object along the path needs to exist, except for the last one which is replaced, or added.
+//
+//	err := openapi.Inject(crd, "status", "conditions")
+func Inject(crd *apiextensionsv1.CustomResourceDefinition, path ...string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("%w: the path is empty", InvalidPathErr)
+	}
+
+	for i := range crd.Spec.Versions {
+		version := &crd.Spec.Versions[i]
+		if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			return fmt.Errorf("%w: version %s doesn't have a schema", InvalidPathErr, version.Name)
+		}
+
+		if err := inject(version.Schema.OpenAPIV3Schema, path); err != nil {
+			return fmt.Errorf("%w: %s in version %s", err, strings.Join(path, "."), version.Name)
+		}
+	}
+
+	return nil
+}
+
+func inject(schema *apiextensionsv1.JSONSchemaProps, path []string) error {
+	if len(path) == 1 {
+		if schema.Properties == nil {
+			schema.Properties = map[string]apiextensionsv1.JSONSchemaProps{}
+		}
+
+		schema.Properties[path[0]] = Schema()
+		return nil
+	}
+
+	property, ok := schema.Properties[path[0]]
+	if !ok {
+		return fmt.Errorf("%w: %s doesn't exist", InvalidPathErr, path[0])
+	}
+
+	if err := inject(&property, path[1:]); err != nil {
+		return err
+	}
+
+	// Properties are stored by value.
+	schema.Properties[path[0]] = property
+	return nil
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest/envtest"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validate(t *testing.T, conditions any) []string {
+	t.Helper()
+
+	schema := Schema()
+	var internal apiextensions.JSONSchemaProps
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(&schema, &internal, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	validator, _, err := validation.NewSchemaValidator(&internal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []string
+	for _, err := range validation.ValidateCustomResource(nil, value, validator) {
+		errs = append(errs, err.Error())
+	}
+
+	return errs
+}
+
+func TestSchema(t *testing.T) {
+	conditions := konditions.Conditions{{
+		Type:               konditions.ConditionType("example.com/Bucket"),
+		Status:             konditions.ConditionCompleted,
+		LastTransitionTime: meta.NewTime(time.Now()),
+		Reason:             "Bucket created",
+		Attributes:         map[string]string{"konditionner.io/attempts": "1"},
+		ObservedGeneration: 2,
+		Manager:            "bucket-controller",
+	}}
+
+	if errs := validate(t, conditions); len(errs) != 0 {
+		t.Error("Expected the conditions to be valid, got: ", errs)
+	}
+
+	invalid := []map[string]any{{"type": "Not a valid type", "status": "Completed"}}
+	if errs := validate(t, invalid); len(errs) != 2 {
+		t.Error("Expected the type and the missing lastTransitionTime to be rejected, got: ", errs)
+	}
+
+	schema := Schema()
+	if *schema.XListType != "map" || len(schema.XListMapKeys) != 1 || schema.XListMapKeys[0] != "type" {
+		t.Error("Expected the conditions to be a list map keyed by type")
+	}
+}
+
+func TestYAML(t *testing.T) {
+	data, err := YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"x-kubernetes-list-type: map", "x-kubernetes-list-map-keys:\n- type"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected the YAML to contain %q, got:\n%s", expected, data)
+		}
+	}
+}
+
+func TestInject(t *testing.T) {
+	crd := envtest.SampleCRD()
+	if err := Inject(crd, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["status"]
+	conditions, ok := status.Properties["conditions"]
+	if !ok || conditions.Type != "array" || *conditions.XListType != "map" {
+		t.Error("Expected the conditions to be injected in the status, got: ", status)
+	}
+
+	if err := Inject(crd, "missing", "conditions"); !errors.Is(err, InvalidPathErr) {
+		t.Error("Expected a missing object to be rejected, got: ", err)
+	}
+
+	if err := Inject(crd); !errors.Is(err, InvalidPathErr) {
+		t.Error("Expected an empty path to be rejected, got: ", err)
+	}
+}
@@ -0,0 +1,179 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PendingOutcomeAttribute holds the outcome a task recorded, with Lock.RecordPendingOutcome, before it called an
+// external system. The attribute is removed when the condition is released successfully.
+const PendingOutcomeAttribute = "konditionner.io/pending-outcome"
+
+var PendingOutcomeOutsideTaskErr = errors.New("Pending outcome can only be recorded while the task runs")
+
+// RecordPendingOutcome stores the outcome on the locked condition, and persists it, before the task calls an external
+// system. The outcome describes what the call is about to do, so the next reconciliation can find out if it happened
+// when the operator crashes before the condition is released: the ID of a request, the name of the resource created,
+// etc.
+//
+//	err := lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		requestID := uuid.NewString()
+//		if err := lock.RecordPendingOutcome(ctx, requestID); err != nil {
+//			return condition, err
+//		}
+//
+//		if err := payments.Charge(ctx, requestID, res.Spec.Amount); err != nil {
+//			return condition, err
+//		}
+//
+//		condition.Status = konditions.ConditionCompleted
+//		return condition, nil
+//	})
+//
+// The outcome is kept in the PendingOutcomeAttribute of the condition until the lock is released without an error.
+// When the task fails, or the release can't be persisted, the outcome stays on the condition for RecoverPendingOutcomes
+// to find. Recording an outcome replaces the one recorded before. Unlike ExecuteWithIntent, which records its intent
+// when the lock is acquired, outcomes are recorded by the task, right before each external call.
+//
+// The outcome is written with a JSON patch that only applies if the condition is still locked by this lock, the
+// resourceVersion of the resource is updated so the release doesn't conflict with it. Only resources that store their
+// conditions in `status.conditions` are supported.
+func (l *Lock) RecordPendingOutcome(ctx context.Context, outcome string) error {
+	if !l.result.Acquired || l.result.TaskRan {
+		return PendingOutcomeOutsideTaskErr
+	}
+
+	if l.client == nil {
+		return fmt.Errorf("%w: the pending outcome is patched on the resource", ClientRequiredErr)
+	}
+
+	if _, encoded := l.obj.(EncodedResource); encoded {
+		return fmt.Errorf("%w: pending outcomes are patched in status.conditions", UnsupportedResourceErr)
+	}
+
+	conditions := *l.obj.Conditions()
+	if l.index >= len(conditions) || conditions[l.index].Type != l.condition.Type {
+		return fmt.Errorf("%w: %s is not where it was locked", ConditionNotFoundErr, l.condition.Type)
+	}
+
+	locked := conditions[l.index].DeepCopy()
+	if err := locked.SetAttr(PendingOutcomeAttribute, outcome); err != nil {
+		return err
+	}
+
+	// The task may modify the resource while it runs, the patch is sent with a copy.
+	obj := l.obj.DeepCopyObject().(client.Object)
+	path := fmt.Sprintf("/status/conditions/%d/attributes", l.index)
+	if err := l.patchLocked(ctx, obj, l.condition.Type, jsonPatchOperation{Op: "add", Path: path, Value: locked.Attributes}); err != nil {
+		return err
+	}
+	l.result.Writes++
+
+	conditions[l.index].Attributes = locked.Attributes
+	l.obj.SetResourceVersion(obj.GetResourceVersion())
+
+	if l.resourceVersion != "" {
+		l.resourceVersion = obj.GetResourceVersion()
+	}
+
+	l.pending = outcome
+	l.pendingVersion = obj.GetResourceVersion()
+
+	return nil
+}
+
+// Returns the condition the task returned with its pending outcome settled: removed when the task succeeded, kept
+// when it failed.
+func (l *Lock) settlePendingOutcome(condition Condition, err error) Condition {
+	if err == nil && condition.Status != ConditionLocked {
+		condition.DeleteAttr(PendingOutcomeAttribute)
+		return condition
+	}
+
+	if l.pending != "" {
+		// The outcome was stored on the locked condition already, it fits in its attributes.
+		_ = condition.SetAttr(PendingOutcomeAttribute, l.pending)
+	}
+
+	return condition
+}
+
+// Returns the conditions that carry a pending outcome, see Lock.RecordPendingOutcome. Those are the conditions whose task
+// recorded an outcome but failed, or never released the condition, usually because the operator crashed while the task
+// was running.
+//
+// Keep in mind that a lock can be legitimately held by a task that is still running, in this reconciler or in another
+// replica. Checking the LastTransitionTime of the locked conditions before acting on them is a good idea.
+func (c Conditions) PendingOutcomes() Conditions {
+	outcomes := Conditions{}
+	for _, condition := range c {
+		if _, ok := condition.GetAttr(PendingOutcomeAttribute); ok {
+			outcomes = append(outcomes, *condition.DeepCopy())
+		}
+	}
+
+	return outcomes
+}
+
+// OutcomeRecovery finds out what happened to the pending outcome of the condition and returns the condition as it
+// should be stored: ConditionCompleted if the external call went through, ConditionInitialized so the task runs again,
+// etc.
+type OutcomeRecovery func(ctx context.Context, condition Condition, outcome string) (Condition, error)
+
+// RecoverPendingOutcomes hands each condition that carries a pending outcome to the recovery, stores the condition it
+// returns without the outcome, and persists the resource. It returns the number of conditions recovered.
+//
+//	recovered, err := konditions.RecoverPendingOutcomes(ctx, &res, konditions.StatusPersister{Client: c},
+//		func(ctx context.Context, condition konditions.Condition, requestID string) (konditions.Condition, error) {
+//			charged, err := payments.Exists(ctx, requestID)
+//			if err != nil {
+//				return condition, err
+//			}
+//
+//			condition.Status = konditions.ConditionInitialized
+//			if charged {
+//				condition.Status = konditions.ConditionCompleted
+//			}
+//			return condition, nil
+//		})
+//
+// This is meant to run at the start of a reconciliation, before the locks of the resource are executed. The recovery
+// releases the conditions that are still locked, see Conditions.PendingOutcomes for the locks that are still held, and
+// moves the errored conditions out of their terminal status.
+//
+// The recovery stops at the first error, the conditions recovered before it are persisted and the error is returned.
+func RecoverPendingOutcomes(ctx context.Context, obj ConditionalResource, persister Persister, recovery OutcomeRecovery) (int, error) {
+	recovered := 0
+	var recoveryErr error
+
+	for _, condition := range obj.Conditions().PendingOutcomes() {
+		outcome, _ := condition.GetAttr(PendingOutcomeAttribute)
+
+		settled, err := recovery(ctx, condition, outcome)
+		if err != nil {
+			recoveryErr = err
+			break
+		}
+
+		settled.Type = condition.Type
+		settled.DeleteAttr(PendingOutcomeAttribute)
+
+		// A task that failed left its condition errored, which is terminal. The recovery is what settles it.
+		if err := obj.Conditions().SetConditionForce(settled); err != nil {
+			recoveryErr = err
+			break
+		}
+		recovered++
+	}
+
+	if recovered > 0 {
+		if err := persister.Persist(ctx, obj); err != nil {
+			return 0, err
+		}
+	}
+
+	return recovered, recoveryErr
+}
@@ -0,0 +1,217 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func storedTestResource(t *testing.T, c client.Client, res *testResource) *testResource {
+	t.Helper()
+
+	stored := &testResource{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(res), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	return stored
+}
+
+func TestLockRecordPendingOutcome(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("outbox")
+	c := newTestClient(res)
+
+	for _, opts := range [][]LockOption{nil, {WithFencing()}} {
+		lock := NewLock(res, c, ConditionType("Bucket"), opts...)
+		result, err := lock.ExecuteWithResult(ctx, func(condition Condition) (Condition, error) {
+			if err := lock.RecordPendingOutcome(ctx, "request-1"); err != nil {
+				t.Fatal(err)
+			}
+
+			stored := storedTestResource(t, c, res)
+			if outcomes := stored.Conditions().PendingOutcomes(); len(outcomes) != 1 || outcomes[0].Status != ConditionLocked {
+				t.Error("Expected the outcome to be persisted on the locked condition, got: ", stored.Conditions())
+			}
+
+			condition.Status = ConditionCompleted
+			return condition, nil
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Writes != 3 {
+			t.Error("Expected the outcome to be written between the acquisition and the release, got: ", result.Writes)
+		}
+
+		if outcomes := storedTestResource(t, c, res).Conditions().PendingOutcomes(); len(outcomes) != 0 || len(res.Conditions().PendingOutcomes()) != 0 {
+			t.Error("Expected the outcome to be cleared once released, got: ", outcomes)
+		}
+
+		res.Conditions().SetConditionForce(Condition{Type: ConditionType("Bucket"), Status: ConditionInitialized})
+	}
+}
+
+func TestLockRecordPendingOutcomeOutsideTask(t *testing.T) {
+	res := newTestResource("outbox")
+	lock := NewLock(res, newTestClient(res), ConditionType("Bucket"))
+
+	if err := lock.RecordPendingOutcome(context.Background(), "request-1"); !errors.Is(err, PendingOutcomeOutsideTaskErr) {
+		t.Error("Expected an error before the task runs, got: ", err)
+	}
+}
+
+func TestLockPendingOutcomeKeptOnFailure(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("outbox")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := lock.RecordPendingOutcome(ctx, "request-1"); err != nil {
+			t.Fatal(err)
+		}
+
+		return condition, errors.New("Payment gateway unreachable")
+	})
+
+	if err == nil {
+		t.Fatal("Expected the error of the task")
+	}
+
+	outcomes := storedTestResource(t, c, res).Conditions().PendingOutcomes()
+	if len(outcomes) != 1 || outcomes[0].Status != ConditionError {
+		t.Fatal("Expected the outcome to be kept on the errored condition, got: ", outcomes)
+	}
+
+	if outcome, _ := outcomes[0].GetAttr(PendingOutcomeAttribute); outcome != "request-1" {
+		t.Error("Unexpected outcome: ", outcome)
+	}
+
+	stored := storedTestResource(t, c, res)
+	recovered, err := RecoverPendingOutcomes(ctx, stored, StatusPersister{Client: c}, func(ctx context.Context, condition Condition, outcome string) (Condition, error) {
+		condition.Status = ConditionInitialized
+		condition.Reason = "Payment not found"
+		return condition, nil
+	})
+
+	if err != nil || recovered != 1 {
+		t.Fatalf("Expected the errored condition to be recovered, got %v with %d recovered", err, recovered)
+	}
+
+	stored = storedTestResource(t, c, res)
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionInitialized) || len(stored.Conditions().PendingOutcomes()) != 0 {
+		t.Error("Expected the recovered condition to be stored without its outcome, got: ", stored.Conditions())
+	}
+}
+
+func TestRecoverPendingOutcomesAfterPreviousFailure(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("outbox")
+	c := newTestClient(res)
+
+	// The task fails before it records anything, the next attempt records its outcome and fails too.
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	if err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, errors.New("Credentials missing")
+	}); err == nil {
+		t.Fatal("Expected the error of the task")
+	}
+
+	res.Conditions().SetConditionForce(Condition{Type: ConditionType("Bucket"), Status: ConditionInitialized})
+	lock = NewLock(res, c, ConditionType("Bucket"))
+	if err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := lock.RecordPendingOutcome(ctx, "request-2"); err != nil {
+			t.Fatal(err)
+		}
+		return condition, errors.New("Payment gateway unreachable")
+	}); err == nil {
+		t.Fatal("Expected the error of the task")
+	}
+
+	stored := storedTestResource(t, c, res)
+	recovered, err := RecoverPendingOutcomes(ctx, stored, StatusPersister{Client: c}, func(ctx context.Context, condition Condition, outcome string) (Condition, error) {
+		if condition.Status != ConditionError || outcome != "request-2" {
+			t.Error("Expected the errored condition and its outcome, got: ", condition, outcome)
+		}
+
+		condition.Status = ConditionCompleted
+		condition.Reason = "Payment found"
+		return condition, nil
+	})
+
+	if err != nil || recovered != 1 {
+		t.Fatalf("Expected the outcome to be recovered, got %v with %d recovered", err, recovered)
+	}
+
+	if !storedTestResource(t, c, res).Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the recovered condition to be stored")
+	}
+}
+
+func TestRecoverPendingOutcomes(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("outbox")
+
+	// The release fails, like it would if the operator crashed once the external call was made.
+	var writes int
+	c := newCommitRetryTestClient(res, 1, apierrors.NewBadRequest("invalid"), &writes)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		if err := lock.RecordPendingOutcome(ctx, "request-1"); err != nil {
+			t.Fatal(err)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if !apierrors.IsBadRequest(err) {
+		t.Fatal("Expected the release to fail, got: ", err)
+	}
+
+	stored := storedTestResource(t, c, res)
+	persister := StatusPersister{Client: c}
+
+	failure := errors.New("Payment gateway unreachable")
+	recovered, err := RecoverPendingOutcomes(ctx, stored, persister, func(ctx context.Context, condition Condition, outcome string) (Condition, error) {
+		return condition, failure
+	})
+	if !errors.Is(err, failure) || recovered != 0 {
+		t.Errorf("Expected the error of the recovery, got %v with %d recovered", err, recovered)
+	}
+
+	recovered, err = RecoverPendingOutcomes(ctx, stored, persister, func(ctx context.Context, condition Condition, outcome string) (Condition, error) {
+		if condition.Status != ConditionLocked || outcome != "request-1" {
+			t.Error("Expected the locked condition and its outcome, got: ", condition, outcome)
+		}
+
+		condition.Status = ConditionCompleted
+		condition.Reason = "Payment found"
+		return condition, nil
+	})
+
+	if err != nil || recovered != 1 {
+		t.Fatalf("Expected the outcome to be recovered, got %v with %d recovered", err, recovered)
+	}
+
+	stored = storedTestResource(t, c, res)
+	condition := stored.Conditions().FindOrInitializeFor(ConditionType("Bucket"))
+	if condition.Status != ConditionCompleted || len(stored.Conditions().PendingOutcomes()) != 0 {
+		t.Error("Expected the recovered condition to be stored without its outcome, got: ", condition)
+	}
+
+	recovered, err = RecoverPendingOutcomes(ctx, stored, persister, func(ctx context.Context, condition Condition, outcome string) (Condition, error) {
+		t.Error("Expected no outcome to recover")
+		return condition, nil
+	})
+	if err != nil || recovered != 0 {
+		t.Errorf("Expected nothing to recover, got %v with %d recovered", err, recovered)
+	}
+}
@@ -0,0 +1,137 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OverrideAnnotation lets a human set the status of conditions on a resource, as a JSON object
+// mapping condition types to statuses. The operator applies the override with ApplyOverrides, or
+// ProcessOverrides, and clears the annotation once applied.
+//
+//	kubectl annotate mycrd/example konditionner.io/override='{"Bucket":"Completed"}' konditionner.io/override-by=jdoe
+//
+// This is the safe alternative to editing the status of a resource by hand, which bypasses the
+// locks the operator may hold on the conditions.
+const OverrideAnnotation = "konditionner.io/override"
+
+// OverrideByAnnotation identifies who requested the override. When it isn't set, the field manager
+// that wrote the OverrideAnnotation is used instead.
+const OverrideByAnnotation = "konditionner.io/override-by"
+
+var InvalidOverrideErr = errors.New("Invalid override")
+
+// ApplyOverrides applies the overrides requested through the OverrideAnnotation to the conditions
+// of the resource and removes the annotations from the resource. The reason of every condition
+// overridden records who requested the override. The types of the conditions overridden are returned, sorted.
+//
+// Overrides are validated before any of them is applied: the annotation needs to be valid JSON, statuses can't
+// be empty or ConditionLocked, and a condition that is currently locked can't be overridden. If any of the overrides
+// is invalid, nothing is applied, the annotation is left in place and an error wrapping InvalidOverrideErr is returned.
+//
+// The changes are only made in memory, see ProcessOverrides to apply and persist overrides.
+func ApplyOverrides(obj ConditionalResource) ([]ConditionType, error) {
+	annotations := obj.GetAnnotations()
+	value, ok := annotations[OverrideAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var overrides map[ConditionType]ConditionStatus
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidOverrideErr, err)
+	}
+
+	types := make([]ConditionType, 0, len(overrides))
+	for ct, status := range overrides {
+		if status == "" || status == ConditionLocked {
+			return nil, fmt.Errorf("%w: %s can't be set to %q", InvalidOverrideErr, ct, status)
+		}
+
+		if obj.Conditions().TypeHasStatus(ct, ConditionLocked) {
+			return nil, fmt.Errorf("%w: %s is locked", InvalidOverrideErr, ct)
+		}
+
+		types = append(types, ct)
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	author := overrideAuthor(obj)
+	for _, ct := range types {
+		if err := obj.Conditions().SetConditionForce(Condition{
+			Type:    ct,
+			Status:  overrides[ct],
+			Reason:  fmt.Sprintf("Manually overridden to %s by %s", overrides[ct], author),
+			Manager: author,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	clearOverrides(obj)
+
+	return types, nil
+}
+
+// ProcessOverrides applies the overrides of the resource, see ApplyOverrides, and persists the changes:
+// the status is updated first, then the resource is updated to clear the annotations.
+//
+//	if _, err := konditions.ProcessOverrides(ctx, reconciler.Client, &res); err != nil {
+//		return ctrl.Result{}, err
+//	}
+func ProcessOverrides(ctx context.Context, c client.Client, obj ConditionalResource) ([]ConditionType, error) {
+	types, err := ApplyOverrides(obj)
+	if err != nil || len(types) == 0 {
+		return types, err
+	}
+
+	if err := (StatusPersister{Client: c}).Persist(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	// Updating the status refreshes the object with what's stored in Kubernetes, annotations included.
+	clearOverrides(obj)
+	if err := c.Update(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
+
+// Returns who requested the override, either from the OverrideByAnnotation or from the managed fields
+// of the resource.
+func overrideAuthor(obj ConditionalResource) string {
+	if author := obj.GetAnnotations()[OverrideByAnnotation]; author != "" {
+		return author
+	}
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+
+		var fields map[string]map[string]map[string]any
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		if _, ok := fields["f:metadata"]["f:annotations"]["f:"+OverrideAnnotation]; ok {
+			return entry.Manager
+		}
+	}
+
+	return "unknown"
+}
+
+func clearOverrides(obj ConditionalResource) {
+	annotations := obj.GetAnnotations()
+	delete(annotations, OverrideAnnotation)
+	delete(annotations, OverrideByAnnotation)
+	obj.SetAnnotations(annotations)
+}
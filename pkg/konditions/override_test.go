@@ -0,0 +1,100 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	res := newTestResource("override")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError})
+	res.Annotations = map[string]string{
+		OverrideAnnotation:   `{"Bucket":"Completed","DNS":"Initialized"}`,
+		OverrideByAnnotation: "jdoe",
+	}
+
+	types, err := ApplyOverrides(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(types) != 2 || types[0] != ConditionType("Bucket") || types[1] != ConditionType("DNS") {
+		t.Error("Unexpected types: ", types)
+	}
+
+	bucket := res.Conditions().FindType(ConditionType("Bucket"))
+	if bucket.Status != ConditionCompleted || bucket.Reason != "Manually overridden to Completed by jdoe" {
+		t.Error("Unexpected condition: ", bucket)
+	}
+
+	if _, ok := res.Annotations[OverrideAnnotation]; ok {
+		t.Error("Expected the annotation to be cleared")
+	}
+
+	if types, err := ApplyOverrides(res); err != nil || len(types) != 0 {
+		t.Error("Expected nothing to be applied without the annotation, got: ", types, err)
+	}
+}
+
+func TestApplyOverridesInvalid(t *testing.T) {
+	res := newTestResource("override")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionLocked})
+
+	for _, value := range []string{`{`, `{"DNS":"Locked"}`, `{"DNS":""}`, `{"Bucket":"Completed"}`} {
+		res.Annotations = map[string]string{OverrideAnnotation: value}
+
+		if _, err := ApplyOverrides(res); !errors.Is(err, InvalidOverrideErr) {
+			t.Errorf("Expected InvalidOverrideErr for %s, got: %v", value, err)
+		}
+
+		if res.Annotations[OverrideAnnotation] != value {
+			t.Error("Expected the annotation to be left in place")
+		}
+	}
+}
+
+func TestOverrideAuthorFromManagedFields(t *testing.T) {
+	res := newTestResource("override")
+	res.Annotations = map[string]string{OverrideAnnotation: `{"Bucket":"Completed"}`}
+	res.ManagedFields = []meta.ManagedFieldsEntry{
+		{Manager: "operator", FieldsV1: &meta.FieldsV1{Raw: []byte(`{"f:status":{}}`)}},
+		{Manager: "kubectl-annotate", FieldsV1: &meta.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:konditionner.io/override":{}}}}`)}},
+	}
+
+	if author := overrideAuthor(res); author != "kubectl-annotate" {
+		t.Error("Unexpected author: ", author)
+	}
+
+	res.ManagedFields = nil
+	if author := overrideAuthor(res); author != "unknown" {
+		t.Error("Unexpected author: ", author)
+	}
+}
+
+func TestProcessOverrides(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("override")
+	res.Annotations = map[string]string{OverrideAnnotation: `{"Bucket":"Completed"}`}
+	c := newTestClient(res)
+
+	if _, err := ProcessOverrides(ctx, c, res); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the override to be persisted, got: ", stored.Conditions())
+	}
+
+	if _, ok := stored.Annotations[OverrideAnnotation]; ok {
+		t.Error("Expected the annotation to be cleared")
+	}
+}
@@ -0,0 +1,122 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var OwnerNotFoundErr = errors.New("Resource does not have a controller owner of the kind given")
+
+// PropagationMapping selects the conditions of a child that are propagated to its owner, and the type they have on the
+// owner. A condition is only propagated when the boolean is true.
+type PropagationMapping func(child ConditionalResource, ct ConditionType) (ConditionType, bool)
+
+// PrefixWithChild propagates the conditions of the types given, or every condition if no type is given, with the
+// name of the child as the prefix of their type: the Bucket condition of the child my-bucket is my-bucket/Bucket
+// on the owner. The owner can hold the conditions of all its children that way.
+func PrefixWithChild(types ...ConditionType) PropagationMapping {
+	return func(child ConditionalResource, ct ConditionType) (ConditionType, bool) {
+		if len(types) > 0 && !slices.Contains(types, ct) {
+			return "", false
+		}
+
+		return ConditionType(child.GetName() + "/" + string(ct)), true
+	}
+}
+
+// PropagateToOwner reflects the conditions of the child onto its controller owner, the resource that created it, so
+// the failures of the children bubble up to the resource users interact with. The owner is read into the owner given,
+// which needs to be of the kind of the controller owner of the child:
+//
+//	func (r *BucketReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+//		// ...
+//		_, err := konditions.PropagateToOwner(ctx, r.Client, &bucket, &Storage{}, konditions.PrefixWithChild(ConditionType("Bucket")))
+//		if err != nil {
+//			return ctrl.Result{}, err
+//		}
+//	}
+//
+// The conditions are copied as they are on the child, with SetConditionForce: the child already enforced their
+// transitions. They're written together with a single update of the status of the owner, and the owner is fetched
+// again when the update conflicts. The owner isn't written if its conditions are up to date, the return value
+// indicates whether it was written or not.
+//
+// An error wrapping OwnerNotFoundErr is returned if the child doesn't have a controller owner of the kind of the owner
+// given, or if the owner was replaced by a resource with the same name.
+func PropagateToOwner(ctx context.Context, c client.Client, child ConditionalResource, owner ConditionalResource, mapping PropagationMapping) (propagated bool, err error) {
+	ref := meta.GetControllerOf(child)
+	if ref == nil {
+		return false, fmt.Errorf("%w: %s/%s has no controller", OwnerNotFoundErr, child.GetNamespace(), child.GetName())
+	}
+
+	gvk, err := apiutil.GVKForObject(owner, c.Scheme())
+	if err != nil {
+		return false, err
+	}
+
+	if ref.Kind != gvk.Kind || ref.APIVersion != gvk.GroupVersion().String() {
+		return false, fmt.Errorf("%w: %s/%s is controlled by %s %s", OwnerNotFoundErr, child.GetNamespace(), child.GetName(), ref.Kind, ref.Name)
+	}
+
+	key := client.ObjectKey{Name: ref.Name}
+	if namespaced, err := c.IsObjectNamespaced(owner); err != nil {
+		return false, err
+	} else if namespaced {
+		key.Namespace = child.GetNamespace()
+	}
+
+	conditions := Conditions{}
+	for _, condition := range *child.Conditions() {
+		ct, ok := mapping(child, condition.Type)
+		if !ok {
+			continue
+		}
+
+		condition = *condition.DeepCopy()
+		condition.Type = ct
+		conditions = append(conditions, condition)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		propagated = false
+		if err := c.Get(ctx, key, owner); err != nil {
+			return err
+		}
+
+		if owner.GetUID() != ref.UID {
+			return fmt.Errorf("%w: %s was replaced", OwnerNotFoundErr, key)
+		}
+
+		changed := false
+		for _, condition := range conditions {
+			if existing, ok := owner.Conditions().GetType(condition.Type); ok && existing.Equal(condition) {
+				continue
+			}
+
+			if err := owner.Conditions().SetConditionForce(condition); err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+
+		if err := (StatusPersister{Client: c}).Persist(ctx, owner); err != nil {
+			return err
+		}
+
+		propagated = true
+		return nil
+	})
+
+	return propagated, err
+}
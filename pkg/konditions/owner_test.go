@@ -0,0 +1,94 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newOwnedTestResources(t *testing.T) (*testResource, *testResource, client.Client) {
+	t.Helper()
+
+	owner := newTestResource("storage")
+	owner.UID = types.UID("storage-uid")
+	owner.SetGroupVersionKind(testGroupVersion.WithKind("TestResource"))
+
+	child := newTestResource("my-bucket")
+	if err := controllerutil.SetControllerReference(owner, child, newTestScheme()); err != nil {
+		t.Fatal(err)
+	}
+
+	child.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError, Reason: "Quota exceeded"})
+	child.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted})
+
+	return owner, child, newOwnerTestClient(owner, child)
+}
+
+// The owner is looked up with the REST mapping of its kind, which the default client of the tests doesn't have.
+func newOwnerTestClient(objs ...client.Object) client.Client {
+	mapper := apimeta.NewDefaultRESTMapper([]schema.GroupVersion{testGroupVersion})
+	mapper.Add(testGroupVersion.WithKind("TestResource"), apimeta.RESTScopeNamespace)
+
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithRESTMapper(mapper).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(objs...).
+		Build()
+}
+
+func TestPropagateToOwner(t *testing.T) {
+	ctx := context.Background()
+	owner, child, c := newOwnedTestResources(t)
+
+	propagated, err := PropagateToOwner(ctx, c, child, &testResource{}, PrefixWithChild(ConditionType("Bucket")))
+	if err != nil || !propagated {
+		t.Fatal("Expected the conditions to be propagated, got: ", propagated, err)
+	}
+
+	stored := &testResource{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(owner), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition, ok := stored.Conditions().GetType(ConditionType("my-bucket/Bucket"))
+	if !ok || condition.Status != ConditionError || condition.Reason != "Quota exceeded" {
+		t.Error("Expected the condition of the child on the owner, got: ", stored.Conditions())
+	}
+
+	if _, ok := stored.Conditions().GetType(ConditionType("my-bucket/DNS")); ok {
+		t.Error("Expected only the selected conditions to be propagated")
+	}
+
+	propagated, err = PropagateToOwner(ctx, c, child, &testResource{}, PrefixWithChild(ConditionType("Bucket")))
+	if err != nil || propagated {
+		t.Error("Expected the owner to be up to date, got: ", propagated, err)
+	}
+}
+
+func TestPropagateToOwnerWithoutOwner(t *testing.T) {
+	ctx := context.Background()
+	child := newTestResource("my-bucket")
+	c := newOwnerTestClient(child)
+
+	if _, err := PropagateToOwner(ctx, c, child, &testResource{}, PrefixWithChild()); !errors.Is(err, OwnerNotFoundErr) {
+		t.Error("Expected OwnerNotFoundErr, got: ", err)
+	}
+}
+
+func TestPropagateToOwnerReplaced(t *testing.T) {
+	ctx := context.Background()
+	_, child, c := newOwnedTestResources(t)
+	child.OwnerReferences[0].UID = types.UID("previous-storage-uid")
+
+	if _, err := PropagateToOwner(ctx, c, child, &testResource{}, PrefixWithChild()); !errors.Is(err, OwnerNotFoundErr) {
+		t.Error("Expected OwnerNotFoundErr, got: ", err)
+	}
+}
@@ -0,0 +1,81 @@
+package konditions
+
+import (
+	"errors"
+	"strings"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PausedAnnotation lists the condition types that are paused on a resource, separated by commas.
+// The value `*` pauses every condition of the resource.
+//
+//	kubectl annotate mycrd/example konditionner.io/paused="Bucket,DNS"
+const PausedAnnotation = "konditionner.io/paused"
+
+var PausedConditionErr = errors.New("Condition is paused")
+
+// Returns true if the condition type is paused on the object through the PausedAnnotation.
+// Pausing conditions is useful during incident response or migrations, when the operator
+// shouldn't touch some external resources for a while.
+func IsPaused(obj client.Object, ct ConditionType) bool {
+	value, ok := obj.GetAnnotations()[PausedAnnotation]
+	if !ok {
+		return false
+	}
+
+	for _, paused := range strings.Split(value, ",") {
+		paused = strings.TrimSpace(paused)
+		if paused == "*" || ConditionType(paused) == ct {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suspend the condition with the given type. The status of the condition is stored in
+// its ResumeStatus so it can be restored with Resume. Suspending a condition that is
+// already suspended only updates its reason.
+//
+// Conditions in a terminal status can't be suspended, TerminalConditionErr is returned.
+func (c *Conditions) Suspend(ct ConditionType, reason string) error {
+	if c == nil {
+		return NotInitializedConditionsErr
+	}
+
+	condition := c.FindOrInitializeFor(ct)
+	if condition.Status != ConditionSuspended {
+		condition.ResumeStatus = condition.Status
+	}
+
+	condition.Status = ConditionSuspended
+	condition.Reason = reason
+	condition.LastTransitionTime = meta.Time{}
+
+	return c.SetCondition(condition)
+}
+
+// Resume a suspended condition, restoring the status it had before it was suspended. Returns
+// true if the condition was suspended.
+func (c *Conditions) Resume(ct ConditionType) bool {
+	if c == nil {
+		return false
+	}
+
+	condition := c.FindType(ct)
+	if condition == nil || condition.Status != ConditionSuspended {
+		return false
+	}
+
+	condition.Status = condition.ResumeStatus
+	if condition.Status == "" {
+		condition.Status = ConditionInitialized
+	}
+	condition.ResumeStatus = ""
+	condition.Reason = "Resumed"
+	condition.LastTransitionTime = meta.Time{}
+
+	return c.SetCondition(*condition) == nil
+}
@@ -0,0 +1,107 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIsPaused(t *testing.T) {
+	res := newTestResource("paused")
+	if IsPaused(res, ConditionType("Bucket")) {
+		t.Error("Expected the condition not to be paused without the annotation")
+	}
+
+	res.Annotations = map[string]string{PausedAnnotation: "Bucket, DNS"}
+	if !IsPaused(res, ConditionType("Bucket")) || !IsPaused(res, ConditionType("DNS")) {
+		t.Error("Expected the conditions to be paused")
+	}
+
+	if IsPaused(res, ConditionType("Volume")) {
+		t.Error("Expected the volume not to be paused")
+	}
+
+	res.Annotations[PausedAnnotation] = "*"
+	if !IsPaused(res, ConditionType("Volume")) {
+		t.Error("Expected every condition to be paused")
+	}
+}
+
+func TestSuspendResume(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCreated},
+		{Type: ConditionType("DNS"), Status: ConditionError},
+	}
+
+	if err := conditions.Suspend(ConditionType("Bucket"), "Incident"); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := conditions.FindType(ConditionType("Bucket"))
+	if condition.Status != ConditionSuspended || condition.ResumeStatus != ConditionCreated {
+		t.Error("Unexpected condition: ", condition)
+	}
+
+	conditions.Suspend(ConditionType("Bucket"), "Still an incident")
+	if condition := conditions.FindType(ConditionType("Bucket")); condition.ResumeStatus != ConditionCreated {
+		t.Error("Expected suspending twice to keep the original status, got: ", condition)
+	}
+
+	if err := conditions.Suspend(ConditionType("DNS"), "Incident"); !errors.Is(err, TerminalConditionErr) {
+		t.Error("Expected terminal conditions not to be suspended, got: ", err)
+	}
+
+	if !conditions.Resume(ConditionType("Bucket")) {
+		t.Error("Expected the condition to be resumed")
+	}
+
+	condition = conditions.FindType(ConditionType("Bucket"))
+	if condition.Status != ConditionCreated || condition.ResumeStatus != "" {
+		t.Error("Unexpected condition: ", condition)
+	}
+
+	if conditions.Resume(ConditionType("Bucket")) {
+		t.Error("Expected a condition that isn't suspended not to be resumed")
+	}
+}
+
+func TestLockPaused(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("paused")
+	res.Annotations = map[string]string{PausedAnnotation: "Bucket"}
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+	c := newTestClient(res)
+
+	task := func(condition Condition) (Condition, error) {
+		if condition.Status != ConditionCreated {
+			t.Error("Expected the task to receive the status prior to the suspension, got: ", condition.Status)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	}
+
+	if err := NewLock(res, c, ConditionType("Bucket")).Execute(ctx, task); !errors.Is(err, PausedConditionErr) {
+		t.Fatal("Expected PausedConditionErr, got: ", err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionSuspended) {
+		t.Error("Expected the condition to be suspended, got: ", stored.Conditions())
+	}
+
+	delete(stored.Annotations, PausedAnnotation)
+	if err := NewLock(&stored, c, ConditionType("Bucket")).Execute(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed, got: ", stored.Conditions())
+	}
+}
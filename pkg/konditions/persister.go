@@ -0,0 +1,112 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var StatusSubresourceMissingErr = errors.New("Status subresource is not enabled on the resource, enable it on the CRD or use WithStatusSubresource(false)")
+
+// Persister is responsible for writing a ConditionalResource to the Kubernetes API. The Lock
+// uses a Persister every time it needs to store the conditions, which makes it possible to use
+// Konditionner with conditions that are stored somewhere else than the status of a CRD.
+//
+// Konditionner comes with two persisters:
+//   - StatusPersister, the default, updates the status subresource of the resource;
+//   - ObjectPersister updates the whole resource. This is useful when conditions are stored
+//     outside of the status (in the spec, metadata, etc.) or when the CRD doesn't have a status subresource.
+//
+// Custom persisters can be written for any other storage location, PersisterFunc is a convenient
+// way to do so:
+//
+//	persister := konditions.PersisterFunc(func(ctx context.Context, obj konditions.ConditionalResource) error {
+//		return reconciler.Patch(ctx, obj, client.Merge)
+//	})
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithPersister(persister))
+type Persister interface {
+	Persist(ctx context.Context, obj ConditionalResource) error
+}
+
+// PersisterFunc is an adapter to use an ordinary function as a Persister.
+type PersisterFunc func(ctx context.Context, obj ConditionalResource) error
+
+func (f PersisterFunc) Persist(ctx context.Context, obj ConditionalResource) error {
+	return f(ctx, obj)
+}
+
+// EncodedResource is a ConditionalResource that doesn't store its conditions in a Go field of the
+// object sent to Kubernetes. This is the case when conditions are serialized in an annotation
+// or stored in an unstructured object, for instance.
+//
+// Before writing the resource, persisters call Encode so the conditions are serialized
+// back to where they belong. The object returned is the one that will be sent to the Kubernetes API.
+type EncodedResource interface {
+	ConditionalResource
+
+	Encode() (client.Object, error)
+}
+
+// StatusPersister writes the resource through its status subresource. It is the default Persister
+// used by the Lock.
+//
+// When the status subresource isn't enabled on the CRD, the Kubernetes API returns a NotFound error
+// which can easily be confused with the resource being deleted. StatusPersister detects this case
+// and returns an error wrapping StatusSubresourceMissingErr.
+type StatusPersister struct {
+	Client client.Client
+}
+
+func (p StatusPersister) Persist(ctx context.Context, obj ConditionalResource) error {
+	if p.Client == nil {
+		return fmt.Errorf("%w: StatusPersister writes through it", ClientRequiredErr)
+	}
+
+	o, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+
+	err = p.Client.Status().Update(ctx, o)
+	if apierrors.IsNotFound(err) {
+		// The API server returns NotFound when the status subresource isn't enabled for the CRD, which
+		// is indistinguishable from the object being deleted. Fetching the object tells both apart.
+		existing := o.DeepCopyObject().(client.Object)
+		if getErr := p.Client.Get(ctx, client.ObjectKeyFromObject(o), existing); getErr == nil {
+			return fmt.Errorf("%w: %w", StatusSubresourceMissingErr, err)
+		}
+	}
+
+	return err
+}
+
+// ObjectPersister writes the whole resource, which includes the conditions, wherever they are
+// stored in the object.
+type ObjectPersister struct {
+	Client client.Client
+}
+
+func (p ObjectPersister) Persist(ctx context.Context, obj ConditionalResource) error {
+	if p.Client == nil {
+		return fmt.Errorf("%w: ObjectPersister writes through it", ClientRequiredErr)
+	}
+
+	o, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+
+	return p.Client.Update(ctx, o)
+}
+
+// Returns the object that needs to be sent to the Kubernetes API for the resource.
+func encodeResource(obj ConditionalResource) (client.Object, error) {
+	if encoded, ok := obj.(EncodedResource); ok {
+		return encoded.Encode()
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,87 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPersisterFunc(t *testing.T) {
+	res := newTestResource("persister")
+	c := newTestClient(res)
+
+	var calls []ConditionStatus
+	persister := PersisterFunc(func(ctx context.Context, obj ConditionalResource) error {
+		calls = append(calls, obj.Conditions().FindOrInitializeFor(ConditionType("Bucket")).Status)
+		return nil
+	})
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithPersister(persister)).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 || calls[0] != ConditionLocked || calls[1] != ConditionCompleted {
+		t.Error("Unexpected calls to the persister: ", calls)
+	}
+}
+
+// annotatedResource stores its conditions in an annotation of the underlying testResource.
+type annotatedResource struct {
+	*testResource
+	conditions Conditions
+}
+
+func (a *annotatedResource) Conditions() *Conditions {
+	return &a.conditions
+}
+
+func (a *annotatedResource) Encode() (client.Object, error) {
+	data, err := json.Marshal(a.conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	a.SetAnnotations(map[string]string{"conditions": string(data)})
+	return a.testResource, nil
+}
+
+func TestObjectPersisterEncodedResource(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("encoded")
+	c := newTestClient(res)
+
+	annotated := &annotatedResource{testResource: res}
+	err := NewLock(annotated, c, ConditionType("Bucket"), WithPersister(ObjectPersister{Client: c})).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored.Status.Conditions) != 0 {
+		t.Error("Expected the status to be left untouched")
+	}
+
+	var conditions Conditions
+	if err := json.Unmarshal([]byte(stored.GetAnnotations()["conditions"]), &conditions); err != nil {
+		t.Fatal(err)
+	}
+
+	if !conditions.TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the conditions to be stored in the annotation, got: ", conditions)
+	}
+}
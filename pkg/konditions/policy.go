@@ -0,0 +1,93 @@
+package konditions
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+type errorPolicyKind int
+
+const (
+	errorPolicyFailFast errorPolicyKind = iota
+	errorPolicyContinue
+	errorPolicyRetry
+)
+
+// ErrorPolicy defines how a ConditionReconciler reacts when a Handler returns an error.
+// See FailFast, ContinueOnError and Retry.
+type ErrorPolicy struct {
+	kind    errorPolicyKind
+	retries int
+	backoff Backoff
+}
+
+// FailFast is the default ErrorPolicy: the condition is marked as errored and the error is
+// returned by Reconcile. No other handler runs for that reconciliation.
+func FailFast() ErrorPolicy {
+	return ErrorPolicy{kind: errorPolicyFailFast}
+}
+
+// ContinueOnError marks the condition as errored but doesn't stop the reconciliation:
+// the handlers registered after it still run, as if the condition was completed. This is
+// useful for conditions that aren't required for the resource to be functional.
+func ContinueOnError() ErrorPolicy {
+	return ErrorPolicy{kind: errorPolicyContinue}
+}
+
+// Retry retries the handler up to `retries` times before marking the condition as errored. Between
+// each attempt, the condition keeps the status it had before the handler ran and its reason describes the
+// error. The resource is requeued after a delay computed by the backoff.
+//
+// Attempts are counted in memory, by the reconciler, restarting the operator resets the count.
+//
+//	reconciler := konditions.NewReconciler[MyCRD](c).
+//		On(ConditionType("DNS"), dnsHandler, konditions.WithErrorPolicy(konditions.Retry(5, konditions.Backoff{Base: time.Second, Max: time.Minute}))).
+//		Build()
+func Retry(retries int, backoff Backoff) ErrorPolicy {
+	return ErrorPolicy{kind: errorPolicyRetry, retries: retries, backoff: backoff}
+}
+
+// Backoff computes exponentially increasing delays. The first attempt waits Base, each
+// subsequent attempt waits twice as long as the previous one, up to Max when Max is set.
+//
+// Jitter spreads the delays so resources that failed at the same time, after a dependency outage
+// for instance, don't all retry at the same time. See Jitter.
+//
+//	konditions.Backoff{Base: time.Second, Max: time.Minute, Jitter: 0.2}
+type Backoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter float64
+}
+
+// Duration returns the delay to wait before the attempt given. Attempts start at 1.
+// Max caps the delay before the jitter is added.
+func (b Backoff) Duration(attempt int) time.Duration {
+	d := b.Base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if b.Max > 0 && d >= b.Max {
+			d = b.Max
+			break
+		}
+	}
+
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+
+	return Jitter(d, b.Jitter)
+}
+
+// Jitter returns the duration with a random delay added to it, up to `factor` times the duration.
+// With a factor of 0.1, a duration of 10 seconds becomes anything between 10 and 11 seconds. The
+// delay is only ever added so the duration returned is never shorter than the one given.
+//
+// A factor of 0, or less, returns the duration untouched.
+func Jitter(d time.Duration, factor float64) time.Duration {
+	if factor <= 0 || d <= 0 {
+		return d
+	}
+
+	return d + time.Duration(rand.Float64()*factor*float64(d))
+}
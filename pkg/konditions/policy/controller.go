@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler loads the KonditionPolicy resources into a Store, and reports whether each policy is in use
+// with the AcceptedCondition of the policy.
+type Reconciler struct {
+	Client client.Client
+	Store  *Store
+}
+
+// NewReconciler returns a reconciler that loads the policies into the store given.
+func NewReconciler(c client.Client, store *Store) *Reconciler {
+	return &Reconciler{
+		Client: c,
+		Store:  store,
+	}
+}
+
+// SetupWithManager registers the reconciler with the manager. KonditionPolicy needs to be registered with
+// the scheme of the manager, see AddToScheme.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("konditions-policy").
+		For(&KonditionPolicy{}).
+		Complete(r)
+}
+
+// Reconcile loads the policy into the store, or removes it from the store once it is deleted.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var p KonditionPolicy
+	if err := r.Client.Get(ctx, req.NamespacedName, &p); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Store.Remove(req.Name)
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if !p.DeletionTimestamp.IsZero() {
+		r.Store.Remove(p.Name)
+		return reconcile.Result{}, nil
+	}
+
+	accepted := konditions.Condition{
+		Type:               AcceptedCondition,
+		Status:             konditions.ConditionCompleted,
+		Reason:             "Policy is enforced",
+		ObservedGeneration: p.Generation,
+	}
+
+	if err := r.Store.Set(&p); err != nil {
+		accepted.Status = konditions.ConditionError
+		accepted.Reason = err.Error()
+	}
+
+	existing, ok := p.Conditions().GetType(AcceptedCondition)
+	if ok && existing.Status == accepted.Status && existing.Reason == accepted.Reason && existing.ObservedGeneration == accepted.ObservedGeneration {
+		return reconcile.Result{}, nil
+	}
+
+	// The policy is re-evaluated on every change to its spec, the condition isn't terminal for it.
+	if err := p.Conditions().SetConditionForce(accepted); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.Client.Status().Update(ctx, &p)
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&KonditionPolicy{}).WithObjects(objs...).Build()
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	valid := newTestPolicy("valid")
+	invalid := newTestPolicy("invalid")
+	invalid.Spec.Target.Kind = ""
+
+	c := newTestClient(valid, invalid)
+	store := NewStore()
+	r := NewReconciler(c, store)
+
+	for _, name := range []string{"valid", "invalid"} {
+		if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stored KonditionPolicy
+	if err := c.Get(ctx, client.ObjectKeyFromObject(valid), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(AcceptedCondition, konditions.ConditionCompleted) {
+		t.Error("Expected the policy to be accepted, got: ", stored.Conditions())
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(invalid), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(AcceptedCondition, konditions.ConditionError) {
+		t.Error("Expected the policy to be rejected, got: ", stored.Conditions())
+	}
+
+	if len(store.RulesFor(bucketGVK)) != 1 {
+		t.Error("Expected only the valid policy to be stored")
+	}
+
+	if err := c.Delete(ctx, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "valid"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.RulesFor(bucketGVK)) != 0 {
+		t.Error("Expected the policy to be removed from the store once deleted")
+	}
+}
@@ -0,0 +1,198 @@
+// Package policy provides KonditionPolicy, a CRD that declares the rules the conditions of a kind need to
+// follow, and the components that enforce them. Platform teams can enforce condition hygiene across many
+// operators, without changes to their code:
+//
+//	apiVersion: konditionner.io/v1alpha1
+//	kind: KonditionPolicy
+//	metadata:
+//	  name: buckets
+//	spec:
+//	  target:
+//	    apiVersion: example.com/v1
+//	    kind: Bucket
+//	  requiredTypes: ["Bucket", "DNS"]
+//	  transitions:
+//	    - from: Completed
+//	      to: [Terminating]
+//	  stuck:
+//	    - status: Locked
+//	      after: 15m
+//	      actions: [Event, Reset]
+//
+// The Reconciler watches the policies and loads them into a Store. The Store is then consumed by the
+// Validator, an admission webhook that rejects the transitions the policies don't allow, and by the
+// remediation controller, through its Policies field:
+//
+//	store := policy.NewStore()
+//	if err := policy.NewReconciler(mgr.GetClient(), store).SetupWithManager(mgr); err != nil {
+//		return err
+//	}
+//
+//	r := remediation.NewReconciler(mgr.GetClient(), recorder, func() konditions.ConditionalResource { return &Bucket{} })
+//	r.Policies = store
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/remediation"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var InvalidPolicyErr = errors.New("Invalid policy")
+var ViolationErr = errors.New("Conditions violate a policy")
+
+// compiled is a policy that was validated and is ready to be enforced.
+type compiled struct {
+	name        string
+	gvk         schema.GroupVersionKind
+	required    []konditions.ConditionType
+	transitions map[konditions.ConditionStatus][]konditions.ConditionStatus
+	rules       []remediation.Rule
+}
+
+// compile validates the policy and returns it in a form the Store can enforce. An error wrapping
+// InvalidPolicyErr is returned if the policy is invalid.
+func compile(p *KonditionPolicy) (*compiled, error) {
+	gv, err := schema.ParseGroupVersion(p.Spec.Target.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", InvalidPolicyErr, err)
+	}
+
+	if p.Spec.Target.Kind == "" {
+		return nil, fmt.Errorf("%w: the kind of the target is missing", InvalidPolicyErr)
+	}
+
+	c := &compiled{
+		name:        p.Name,
+		gvk:         gv.WithKind(p.Spec.Target.Kind),
+		required:    p.Spec.RequiredTypes,
+		transitions: map[konditions.ConditionStatus][]konditions.ConditionStatus{},
+	}
+
+	for _, transition := range p.Spec.Transitions {
+		c.transitions[transition.From] = append(c.transitions[transition.From], transition.To...)
+	}
+
+	for i, stuck := range p.Spec.Stuck {
+		rule := remediation.Rule{
+			Status:  stuck.Status,
+			Types:   stuck.Types,
+			After:   stuck.After.Duration,
+			Actions: stuck.Actions,
+		}
+
+		if stuck.When != "" {
+			if rule.When, err = konditions.CompileExpression(stuck.When); err != nil {
+				return nil, fmt.Errorf("%w: stuck[%d].when: %w", InvalidPolicyErr, i, err)
+			}
+		}
+
+		c.rules = append(c.rules, rule)
+	}
+
+	return c, nil
+}
+
+// Store holds the policies that are in use, indexed by the kind they target. It is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	policies map[string]*compiled
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{
+		policies: map[string]*compiled{},
+	}
+}
+
+// Set validates the policy and stores it, replacing the policy with the same name. The policy isn't
+// stored if it is invalid, and the previous version of the policy, if any, is removed.
+func (s *Store) Set(p *KonditionPolicy) error {
+	c, err := compile(p)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		delete(s.policies, p.Name)
+		return err
+	}
+
+	s.policies[p.Name] = c
+	return nil
+}
+
+// Remove the policy with the given name.
+func (s *Store) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.policies, name)
+}
+
+// Check returns the violations of the policies of the kind given, between the previous conditions and the current
+// ones. Transitions that aren't allowed are returned as an error wrapping ViolationErr, missing required types
+// as warnings.
+//
+// Locking and resetting a condition, transitions to ConditionLocked and ConditionInitialized, are always allowed.
+func (s *Store) Check(gvk schema.GroupVersionKind, previous, current konditions.Conditions) (warnings []string, err error) {
+	var violations []error
+
+	for _, c := range s.policiesFor(gvk) {
+		for _, ct := range c.required {
+			if _, ok := current.GetType(ct); !ok {
+				warnings = append(warnings, fmt.Sprintf("condition %s is required by the policy %s", ct, c.name))
+			}
+		}
+
+		for _, transition := range current.Diff(previous) {
+			if transition.Old == nil || transition.New == nil {
+				continue
+			}
+
+			if transition.New.StatusIsOneOf(konditions.ConditionLocked, konditions.ConditionInitialized) {
+				continue
+			}
+
+			allowed, restricted := c.transitions[transition.Old.Status]
+			if restricted && !transition.New.StatusIsOneOf(allowed...) {
+				violations = append(violations, fmt.Errorf("%w %s: %s can't transition from %s to %s", ViolationErr, c.name, transition.Type, transition.Old.Status, transition.New.Status))
+			}
+		}
+	}
+
+	return warnings, errors.Join(violations...)
+}
+
+// RulesFor returns the remediation rules declared by the policies of the kind given. It implements
+// remediation.RuleSource.
+func (s *Store) RulesFor(gvk schema.GroupVersionKind) []remediation.Rule {
+	var rules []remediation.Rule
+	for _, c := range s.policiesFor(gvk) {
+		rules = append(rules, c.rules...)
+	}
+
+	return rules
+}
+
+// Returns the policies of the kind, sorted by name so they are always enforced in the same order.
+func (s *Store) policiesFor(gvk schema.GroupVersionKind) []*compiled {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var policies []*compiled
+	for _, c := range s.policies {
+		if c.gvk == gvk {
+			policies = append(policies, c)
+		}
+	}
+
+	sort.Slice(policies, func(i, j int) bool { return policies[i].name < policies[j].name })
+	return policies
+}
@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/remediation"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var bucketGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Bucket"}
+
+func newTestPolicy(name string) *KonditionPolicy {
+	return &KonditionPolicy{
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Spec: KonditionPolicySpec{
+			Target:        Target{APIVersion: "example.com/v1", Kind: "Bucket"},
+			RequiredTypes: []konditions.ConditionType{"Bucket", "DNS"},
+			Transitions: []AllowedTransition{
+				{From: konditions.ConditionCompleted, To: []konditions.ConditionStatus{konditions.ConditionTerminating}},
+			},
+			Stuck: []StuckRule{{
+				Status:  konditions.ConditionLocked,
+				After:   meta.Duration{Duration: 15 * time.Minute},
+				When:    `none(status == "Error")`,
+				Actions: []remediation.Action{remediation.ActionReset},
+			}},
+		},
+	}
+}
+
+func TestStoreCheck(t *testing.T) {
+	store := NewStore()
+	if err := store.Set(newTestPolicy("buckets")); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := konditions.Conditions{
+		{Type: "Bucket", Status: konditions.ConditionCompleted},
+		{Type: "DNS", Status: konditions.ConditionCreated},
+	}
+
+	current := konditions.Conditions{
+		{Type: "Bucket", Status: konditions.ConditionCreated},
+		{Type: "DNS", Status: konditions.ConditionCompleted},
+	}
+
+	warnings, err := store.Check(bucketGVK, previous, current)
+	if !errors.Is(err, ViolationErr) {
+		t.Error("Expected Completed to Created to be rejected, got: ", err)
+	}
+
+	if len(warnings) != 0 {
+		t.Error("Unexpected warnings: ", warnings)
+	}
+
+	current = konditions.Conditions{{Type: "Bucket", Status: konditions.ConditionLocked}}
+	warnings, err = store.Check(bucketGVK, previous, current)
+	if err != nil {
+		t.Error("Expected locking a condition to be allowed, got: ", err)
+	}
+
+	if len(warnings) != 1 {
+		t.Error("Expected a warning for the missing DNS condition, got: ", warnings)
+	}
+
+	if _, err := store.Check(schema.GroupVersionKind{Kind: "Other"}, previous, konditions.Conditions{}); err != nil {
+		t.Error("Expected kinds without a policy to be unrestricted, got: ", err)
+	}
+}
+
+func TestStoreRulesFor(t *testing.T) {
+	store := NewStore()
+	if err := store.Set(newTestPolicy("buckets")); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := store.RulesFor(bucketGVK)
+	if len(rules) != 1 || rules[0].After != 15*time.Minute || rules[0].When == nil {
+		t.Fatal("Unexpected rules: ", rules)
+	}
+
+	store.Remove("buckets")
+	if len(store.RulesFor(bucketGVK)) != 0 {
+		t.Error("Expected the rules to be gone once the policy is removed")
+	}
+}
+
+func TestStoreInvalidPolicy(t *testing.T) {
+	store := NewStore()
+	if err := store.Set(newTestPolicy("buckets")); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := newTestPolicy("buckets")
+	invalid.Spec.Stuck[0].When = "none("
+	if err := store.Set(invalid); !errors.Is(err, InvalidPolicyErr) {
+		t.Error("Expected the policy to be invalid, got: ", err)
+	}
+
+	if len(store.RulesFor(bucketGVK)) != 0 {
+		t.Error("Expected the previous version of the policy to be removed")
+	}
+
+	invalid = newTestPolicy("buckets")
+	invalid.Spec.Target.APIVersion = "example.com/v1/extra"
+	if err := store.Set(invalid); !errors.Is(err, InvalidPolicyErr) {
+		t.Error("Expected the policy to be invalid, got: ", err)
+	}
+}
@@ -0,0 +1,202 @@
+package policy
+
+import (
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/openapi"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/remediation"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion of the KonditionPolicy CRD.
+var GroupVersion = schema.GroupVersion{Group: "konditionner.io", Version: "v1alpha1"}
+
+// AcceptedCondition is the condition the Reconciler sets on every policy, Completed when the policy
+// is in use, Error when its spec is invalid.
+const AcceptedCondition konditions.ConditionType = "Accepted"
+
+// KonditionPolicy declares the rules the conditions of a kind need to follow. It is cluster scoped.
+type KonditionPolicy struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KonditionPolicySpec   `json:"spec,omitempty"`
+	Status KonditionPolicyStatus `json:"status,omitempty"`
+}
+
+type KonditionPolicySpec struct {
+	// Target is the kind the policy applies to.
+	Target Target `json:"target"`
+
+	// RequiredTypes are the condition types every resource of the kind is expected to have. Resources
+	// missing one of them are reported with a warning by the Validator.
+	// +optional
+	RequiredTypes []konditions.ConditionType `json:"requiredTypes,omitempty"`
+
+	// Transitions restricts the statuses a condition can transition to. A status that isn't listed
+	// as the origin of a transition isn't restricted.
+	// +optional
+	Transitions []AllowedTransition `json:"transitions,omitempty"`
+
+	// Stuck are the remediation rules of the conditions that are stuck in a status, see remediation.Rule.
+	// +optional
+	Stuck []StuckRule `json:"stuck,omitempty"`
+}
+
+// Target identifies a kind, e.g. {APIVersion: "example.com/v1", Kind: "Bucket"}.
+type Target struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// AllowedTransition lists the statuses a condition in the status From can transition to.
+type AllowedTransition struct {
+	From konditions.ConditionStatus   `json:"from"`
+	To   []konditions.ConditionStatus `json:"to"`
+}
+
+// StuckRule is the declarative version of remediation.Rule.
+type StuckRule struct {
+	Status konditions.ConditionStatus `json:"status"`
+
+	// +optional
+	Types []konditions.ConditionType `json:"types,omitempty"`
+
+	After meta.Duration `json:"after"`
+
+	// When is an expression, see konditions.Expression, the conditions of the resource need
+	// to satisfy for the rule to apply.
+	// +optional
+	When string `json:"when,omitempty"`
+
+	Actions []remediation.Action `json:"actions"`
+}
+
+type KonditionPolicyStatus struct {
+	// +optional
+	Conditions konditions.Conditions `json:"conditions,omitempty"`
+}
+
+func (p *KonditionPolicy) Conditions() *konditions.Conditions {
+	return &p.Status.Conditions
+}
+
+func (p *KonditionPolicy) DeepCopyObject() runtime.Object {
+	out := &KonditionPolicy{}
+	out.TypeMeta = p.TypeMeta
+	p.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+
+	out.Spec.Target = p.Spec.Target
+	if p.Spec.RequiredTypes != nil {
+		out.Spec.RequiredTypes = append([]konditions.ConditionType{}, p.Spec.RequiredTypes...)
+	}
+
+	if p.Spec.Transitions != nil {
+		out.Spec.Transitions = make([]AllowedTransition, len(p.Spec.Transitions))
+		for i, transition := range p.Spec.Transitions {
+			out.Spec.Transitions[i] = AllowedTransition{
+				From: transition.From,
+				To:   append([]konditions.ConditionStatus{}, transition.To...),
+			}
+		}
+	}
+
+	if p.Spec.Stuck != nil {
+		out.Spec.Stuck = make([]StuckRule, len(p.Spec.Stuck))
+		for i, rule := range p.Spec.Stuck {
+			out.Spec.Stuck[i] = rule
+			out.Spec.Stuck[i].Types = append([]konditions.ConditionType(nil), rule.Types...)
+			out.Spec.Stuck[i].Actions = append([]remediation.Action(nil), rule.Actions...)
+		}
+	}
+
+	out.Status.Conditions = p.Status.Conditions.DeepCopy()
+	return out
+}
+
+// KonditionPolicyList is the list type of KonditionPolicy.
+type KonditionPolicyList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	Items []KonditionPolicy `json:"items"`
+}
+
+func (l *KonditionPolicyList) DeepCopyObject() runtime.Object {
+	out := &KonditionPolicyList{}
+	out.TypeMeta = l.TypeMeta
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	if l.Items != nil {
+		out.Items = make([]KonditionPolicy, len(l.Items))
+		for i := range l.Items {
+			out.Items[i] = *l.Items[i].DeepCopyObject().(*KonditionPolicy)
+		}
+	}
+	return out
+}
+
+// AddToScheme registers KonditionPolicy and KonditionPolicyList with the scheme.
+func AddToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion, &KonditionPolicy{}, &KonditionPolicyList{})
+	meta.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
+
+// CRD returns the definition of the KonditionPolicy custom resource, to be installed in the cluster
+// alongside the operators the policies apply to.
+func CRD() *apiextensionsv1.CustomResourceDefinition {
+	preserve := true
+
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: meta.ObjectMeta{Name: "konditionpolicies." + GroupVersion.Group},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: GroupVersion.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     "KonditionPolicy",
+				ListKind: "KonditionPolicyList",
+				Plural:   "konditionpolicies",
+				Singular: "konditionpolicy",
+			},
+			Scope: apiextensionsv1.ClusterScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    GroupVersion.Version,
+				Served:  true,
+				Storage: true,
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type:                   "object",
+								Required:               []string{"target"},
+								XPreserveUnknownFields: &preserve,
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"target": {
+										Type:     "object",
+										Required: []string{"apiVersion", "kind"},
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"apiVersion": {Type: "string"},
+											"kind":       {Type: "string"},
+										},
+									},
+								},
+							},
+							"status": {
+								Type:                   "object",
+								XPreserveUnknownFields: &preserve,
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"conditions": openapi.Schema(),
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
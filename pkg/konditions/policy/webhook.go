@@ -0,0 +1,84 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Validator is an admission webhook that enforces the policies of a kind. It rejects the updates that make a
+// condition transition in a way the policies don't allow, and warns about the required condition types that
+// are missing.
+//
+//	err := ctrl.NewWebhookManagedBy(mgr).
+//		For(&Bucket{}).
+//		WithValidator(policy.NewValidator(store, bucketGVK)).
+//		Complete()
+//
+// Conditions are usually updated through the status subresource, the ValidatingWebhookConfiguration needs to
+// include the `status` subresource of the kind for the transitions to be validated.
+type Validator struct {
+	Store *Store
+	GVK   schema.GroupVersionKind
+}
+
+var _ admission.CustomValidator = &Validator{}
+
+// NewValidator returns a validator for the kind given, enforcing the policies of the store.
+func NewValidator(store *Store, gvk schema.GroupVersionKind) *Validator {
+	return &Validator{
+		Store: store,
+		GVK:   gvk,
+	}
+}
+
+// ValidateCreate only warns about the required condition types, a new resource doesn't have
+// any transition.
+func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	res, err := conditionalResource(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resources are usually created without a status, only warn once the operator started working on it.
+	if len(*res.Conditions()) == 0 {
+		return nil, nil
+	}
+
+	warnings, err := v.Store.Check(v.GVK, *res.Conditions(), *res.Conditions())
+	return warnings, err
+}
+
+// ValidateUpdate rejects the transitions that the policies don't allow.
+func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	previous, err := conditionalResource(oldObj)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := conditionalResource(newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := v.Store.Check(v.GVK, *previous.Conditions(), *current.Conditions())
+	return warnings, err
+}
+
+// ValidateDelete always allows the deletion.
+func (v *Validator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func conditionalResource(obj runtime.Object) (konditions.ConditionalResource, error) {
+	res, ok := obj.(konditions.ConditionalResource)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a konditions.ConditionalResource", obj)
+	}
+
+	return res, nil
+}
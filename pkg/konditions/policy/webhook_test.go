@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest/envtest"
+)
+
+func TestValidator(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	if err := store.Set(newTestPolicy("buckets")); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidator(store, bucketGVK)
+
+	previous := &envtest.Sample{}
+	previous.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: konditions.ConditionCompleted})
+	previous.Conditions().SetCondition(konditions.Condition{Type: "DNS", Status: konditions.ConditionCompleted})
+
+	current := previous.DeepCopyObject().(*envtest.Sample)
+	current.Conditions().SetConditionForce(konditions.Condition{Type: "Bucket", Status: konditions.ConditionError})
+
+	if _, err := v.ValidateUpdate(ctx, previous, current); !errors.Is(err, ViolationErr) {
+		t.Error("Expected the update to be rejected, got: ", err)
+	}
+
+	current = previous.DeepCopyObject().(*envtest.Sample)
+	current.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: konditions.ConditionTerminating})
+
+	if _, err := v.ValidateUpdate(ctx, previous, current); err != nil {
+		t.Error("Expected the update to be allowed, got: ", err)
+	}
+
+	if warnings, err := v.ValidateCreate(ctx, &envtest.Sample{}); err != nil || len(warnings) != 0 {
+		t.Error("Expected resources without conditions to be allowed without warnings, got: ", warnings, err)
+	}
+
+	created := &envtest.Sample{}
+	created.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: konditions.ConditionInitialized})
+	if warnings, _ := v.ValidateCreate(ctx, created); len(warnings) != 1 {
+		t.Error("Expected a warning for the missing DNS condition, got: ", warnings)
+	}
+}
@@ -0,0 +1,57 @@
+package konditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	backoff := Backoff{Base: time.Second, Max: 5 * time.Second}
+
+	expectations := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  5 * time.Second,
+		40: 5 * time.Second,
+	}
+
+	for attempt, expected := range expectations {
+		if d := backoff.Duration(attempt); d != expected {
+			t.Errorf("Expected %s for attempt %d, got %s", expected, attempt, d)
+		}
+	}
+
+	if d := (Backoff{Base: time.Second}).Duration(5); d != 16*time.Second {
+		t.Error("Expected an unbounded backoff, got: ", d)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	backoff := Backoff{Base: time.Second, Max: 4 * time.Second, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		if d := backoff.Duration(5); d < 4*time.Second || d > 6*time.Second {
+			t.Fatal("Expected the jitter to be within bounds, got: ", d)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	if d := Jitter(time.Second, 0); d != time.Second {
+		t.Error("Expected no jitter, got: ", d)
+	}
+
+	spread := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		d := Jitter(10*time.Second, 0.1)
+		if d < 10*time.Second || d > 11*time.Second {
+			t.Fatal("Expected the jitter to be within bounds, got: ", d)
+		}
+		spread[d] = true
+	}
+
+	if len(spread) < 2 {
+		t.Error("Expected the durations to be spread")
+	}
+}
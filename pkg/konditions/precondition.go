@@ -0,0 +1,39 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var ResourceModifiedErr = errors.New("Resource was modified while the condition was locked")
+
+// CommitWithResourceVersion records the resourceVersion of the resource once the condition is locked and releases
+// the lock with that resourceVersion as a precondition. If the resource was modified while the task ran, by another
+// controller or by a user editing it, the release fails with an error wrapping ResourceModifiedErr instead of
+// clobbering the concurrent edits.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.CommitWithResourceVersion())
+//	err := lock.Execute(ctx, createBucket)
+//	if errors.Is(err, konditions.ResourceModifiedErr) {
+//		// ... The bucket was created but the condition wasn't released, the resource needs to be fetched again ...
+//	}
+//
+// This matters for long tasks: the task usually refreshes the resource, and without this option the release would
+// silently overwrite the status with the one the task has in memory. Since any write bumps the resourceVersion, the
+// task itself must not write the resource when this option is used, the lock persists the condition on release.
+func CommitWithResourceVersion() LockOption {
+	return func(l *Lock) {
+		l.preconditioned = true
+	}
+}
+
+// Wraps the conflict returned by the Kubernetes API when the precondition on the resourceVersion failed.
+func resourceModified(ct ConditionType, resourceVersion string, err error) error {
+	if apierrors.IsConflict(err) {
+		return fmt.Errorf("%w: %s was locked at resourceVersion %s: %w", ResourceModifiedErr, ct, resourceVersion, err)
+	}
+
+	return err
+}
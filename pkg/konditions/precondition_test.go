@@ -0,0 +1,80 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCommitWithResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("precondition")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket"), CommitWithResourceVersion()).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed, got: ", stored.Conditions())
+	}
+}
+
+func TestCommitWithResourceVersionModified(t *testing.T) {
+	for name, opts := range map[string][]LockOption{
+		"update": {CommitWithResourceVersion()},
+		"fenced": {CommitWithResourceVersion(), WithFencing()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			res := newTestResource("precondition")
+			c := newTestClient(res)
+
+			err := NewLock(res, c, ConditionType("Bucket"), opts...).Execute(ctx, func(condition Condition) (Condition, error) {
+				// The resource is modified, and refreshed, while the task runs.
+				var other testResource
+				if err := c.Get(ctx, client.ObjectKeyFromObject(res), &other); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := other.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted}); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := c.Status().Update(ctx, &other); err != nil {
+					t.Fatal(err)
+				}
+
+				res.SetResourceVersion(other.GetResourceVersion())
+
+				condition.Status = ConditionCompleted
+				return condition, nil
+			})
+
+			if !errors.Is(err, ResourceModifiedErr) {
+				t.Fatal("Expected the release to fail, got: ", err)
+			}
+
+			var stored testResource
+			if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+				t.Fatal(err)
+			}
+
+			if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCompleted) {
+				t.Error("Expected the concurrent edit to be kept, got: ", stored.Conditions())
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package konditions
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ExpressionPredicate returns a predicate that only lets through the events of resources whose conditions
+// satisfy the expression. Objects that aren't a ConditionalResource are filtered out.
+//
+//	err := ctrl.NewControllerManagedBy(mgr).
+//		For(&MyCRD{}, builder.WithPredicates(konditions.ExpressionPredicate(
+//			konditions.MustCompileExpression(`any(type == "Bucket", status == "Completed")`),
+//		))).
+//		Complete(reconciler)
+func ExpressionPredicate(expr *Expression) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		res, ok := obj.(ConditionalResource)
+		if !ok {
+			return false
+		}
+
+		return expr.Evaluate(*res.Conditions())
+	})
+}
@@ -0,0 +1,59 @@
+package konditions
+
+import (
+	"sort"
+)
+
+// Priorities assigns a priority to condition types, types that aren't listed have a priority of 0. The order
+// in which conditions are worked on is usually implicit in the code of the reconciliation loop, which is easy to
+// get wrong, during deletion races in particular. Priorities make it explicit:
+//
+//	priorities := konditions.Priorities{
+//		ConditionType("Credentials"): 10,
+//		ConditionType("Bucket"):      5,
+//	}
+//
+//	if condition, ok := priorities.Next(res.Status.Conditions); ok {
+//		lock := konditions.NewLock(&res, c, condition.Type)
+//		// ...
+//	}
+type Priorities map[ConditionType]int
+
+// Sort returns a copy of the conditions sorted by decreasing priority. Conditions with the same priority keep
+// their order.
+func (p Priorities) Sort(conditions Conditions) Conditions {
+	sorted := conditions.DeepCopy()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return p[sorted[i].Type] > p[sorted[j].Type]
+	})
+
+	return sorted
+}
+
+// Next returns the actionable condition with the highest priority. A condition is actionable when it can be
+// locked and still has work to do: it isn't completed, locked, suspended nor in a terminal status.
+//
+// Conditions in ConditionTerminating come first, regardless of their priority: cleaning up after a resource
+// that is being deleted always takes precedence over creating more things for it.
+func (p Priorities) Next(conditions Conditions) (Condition, bool) {
+	var next *Condition
+	for _, condition := range p.Sort(conditions) {
+		if condition.Class() != ClassInProgress || condition.Status == ConditionLocked {
+			continue
+		}
+
+		if condition.Status == ConditionTerminating {
+			return condition, true
+		}
+
+		if next == nil {
+			next = &condition
+		}
+	}
+
+	if next == nil {
+		return Condition{}, false
+	}
+
+	return *next, true
+}
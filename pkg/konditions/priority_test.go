@@ -0,0 +1,70 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPrioritiesNext(t *testing.T) {
+	priorities := Priorities{
+		ConditionType("Credentials"): 10,
+		ConditionType("Bucket"):      5,
+	}
+
+	conditions := Conditions{
+		{Type: ConditionType("DNS"), Status: ConditionInitialized},
+		{Type: ConditionType("Bucket"), Status: ConditionCreated},
+		{Type: ConditionType("Credentials"), Status: ConditionCompleted},
+	}
+
+	if condition, ok := priorities.Next(conditions); !ok || condition.Type != ConditionType("Bucket") {
+		t.Error("Expected Bucket to be next, got: ", condition)
+	}
+
+	conditions = append(conditions, Condition{Type: ConditionType("Volume"), Status: ConditionTerminating})
+	if condition, ok := priorities.Next(conditions); !ok || condition.Type != ConditionType("Volume") {
+		t.Error("Expected the terminating condition to be next, got: ", condition)
+	}
+
+	conditions = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionLocked},
+		{Type: ConditionType("DNS"), Status: ConditionError},
+	}
+	if condition, ok := priorities.Next(conditions); ok {
+		t.Error("Expected no actionable condition, got: ", condition)
+	}
+
+	sorted := priorities.Sort(Conditions{{Type: "A"}, {Type: "Bucket"}, {Type: "B"}, {Type: "Credentials"}})
+	if sorted[0].Type != "Credentials" || sorted[1].Type != "Bucket" || sorted[2].Type != "A" || sorted[3].Type != "B" {
+		t.Error("Unexpected order: ", sorted)
+	}
+}
+
+func TestReconcilerWithPriority(t *testing.T) {
+	res := newTestResource("priority")
+	c := newTestClient(res)
+
+	var calls []ConditionType
+	handler := func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+		calls = append(calls, condition.Type)
+		condition.Status = ConditionCompleted
+		return condition, nil
+	}
+
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), handler).
+		On(ConditionType("DNS"), handler).
+		On(ConditionType("Credentials"), handler, WithPriority(10)).
+		Build()
+
+	if _, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 3 || calls[0] != ConditionType("Credentials") || calls[1] != ConditionType("Bucket") || calls[2] != ConditionType("DNS") {
+		t.Error("Unexpected order: ", calls)
+	}
+}
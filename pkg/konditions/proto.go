@@ -0,0 +1,286 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var InvalidProtobufErr = errors.New("Invalid protobuf encoding")
+
+// Field numbers of Condition, they match the protobuf struct tags and must never change. Field 3 is
+// reserved, it isn't used by Condition.
+const (
+	protoFieldType               protowire.Number = 1
+	protoFieldStatus             protowire.Number = 2
+	protoFieldLastTransitionTime protowire.Number = 4
+	protoFieldReason             protowire.Number = 5
+	protoFieldInputHash          protowire.Number = 6
+	protoFieldResumeStatus       protowire.Number = 7
+	protoFieldRef                protowire.Number = 8
+	protoFieldAttributes         protowire.Number = 9
+	protoFieldObservedGeneration protowire.Number = 10
+	protoFieldManager            protowire.Number = 11
+	protoFieldLastHeartbeatTime  protowire.Number = 12
+)
+
+// Field numbers of the entries of the Attributes map, as defined by the protobuf specification for maps.
+const (
+	protoFieldKey   protowire.Number = 1
+	protoFieldValue protowire.Number = 2
+)
+
+// Marshal implements the protobuf marshaling interface used by the generated code of Kubernetes types,
+// which means a type embedding Conditions can be served over protobuf by an aggregated API server. The
+// encoding is the one go-to-protobuf would generate from the struct tags of Condition.
+//
+// The attributes are encoded sorted by key so the output is deterministic. Like metav1.Time, the
+// LastTransitionTime is encoded with a precision of a second.
+func (m *Condition) Marshal() ([]byte, error) {
+	return m.appendProto(make([]byte, 0, m.Size())), nil
+}
+
+// MarshalTo implements the protobuf marshaling interface, data needs to be at least Size() long.
+func (m *Condition) MarshalTo(data []byte) (int, error) {
+	size := m.Size()
+	if len(data) < size {
+		return 0, fmt.Errorf("%w: buffer too small, %d bytes needed", InvalidProtobufErr, size)
+	}
+
+	return len(m.appendProto(data[:0])), nil
+}
+
+// MarshalToSizedBuffer implements the protobuf reverse marshaling interface: the condition is written
+// at the end of data.
+func (m *Condition) MarshalToSizedBuffer(data []byte) (int, error) {
+	size := m.Size()
+	if len(data) < size {
+		return 0, fmt.Errorf("%w: buffer too small, %d bytes needed", InvalidProtobufErr, size)
+	}
+
+	m.appendProto(data[len(data)-size : len(data)-size])
+	return size, nil
+}
+
+// Size implements the protobuf marshaling interface.
+func (m *Condition) Size() int {
+	if m == nil {
+		return 0
+	}
+
+	n := sizeProtoString(protoFieldType, string(m.Type), true)
+	n += sizeProtoString(protoFieldStatus, string(m.Status), true)
+	n += sizeProtoBytes(protoFieldLastTransitionTime, m.LastTransitionTime.Size())
+	n += sizeProtoString(protoFieldReason, m.Reason, false)
+	n += sizeProtoString(protoFieldInputHash, m.InputHash, false)
+	n += sizeProtoString(protoFieldResumeStatus, string(m.ResumeStatus), false)
+	n += sizeProtoString(protoFieldRef, m.Ref, false)
+
+	for key, value := range m.Attributes {
+		n += sizeProtoBytes(protoFieldAttributes, sizeProtoString(protoFieldKey, key, true)+sizeProtoString(protoFieldValue, value, true))
+	}
+
+	if m.ObservedGeneration != 0 {
+		n += protowire.SizeTag(protoFieldObservedGeneration) + protowire.SizeVarint(uint64(m.ObservedGeneration))
+	}
+
+	n += sizeProtoString(protoFieldManager, m.Manager, false)
+
+	if m.LastHeartbeatTime != nil {
+		n += sizeProtoBytes(protoFieldLastHeartbeatTime, m.LastHeartbeatTime.Size())
+	}
+
+	return n
+}
+
+func (m *Condition) appendProto(b []byte) []byte {
+	if m == nil {
+		return b
+	}
+
+	b = appendProtoString(b, protoFieldType, string(m.Type), true)
+	b = appendProtoString(b, protoFieldStatus, string(m.Status), true)
+
+	// metav1.Time never fails to marshal.
+	timestamp, _ := m.LastTransitionTime.Marshal()
+	b = protowire.AppendTag(b, protoFieldLastTransitionTime, protowire.BytesType)
+	b = protowire.AppendBytes(b, timestamp)
+
+	b = appendProtoString(b, protoFieldReason, m.Reason, false)
+	b = appendProtoString(b, protoFieldInputHash, m.InputHash, false)
+	b = appendProtoString(b, protoFieldResumeStatus, string(m.ResumeStatus), false)
+	b = appendProtoString(b, protoFieldRef, m.Ref, false)
+
+	keys := make([]string, 0, len(m.Attributes))
+	for key := range m.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b = protowire.AppendTag(b, protoFieldAttributes, protowire.BytesType)
+		b = protowire.AppendVarint(b, uint64(sizeProtoString(protoFieldKey, key, true)+sizeProtoString(protoFieldValue, m.Attributes[key], true)))
+		b = appendProtoString(b, protoFieldKey, key, true)
+		b = appendProtoString(b, protoFieldValue, m.Attributes[key], true)
+	}
+
+	if m.ObservedGeneration != 0 {
+		b = protowire.AppendTag(b, protoFieldObservedGeneration, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.ObservedGeneration))
+	}
+
+	b = appendProtoString(b, protoFieldManager, m.Manager, false)
+
+	if m.LastHeartbeatTime != nil {
+		heartbeat, _ := m.LastHeartbeatTime.Marshal()
+		b = protowire.AppendTag(b, protoFieldLastHeartbeatTime, protowire.BytesType)
+		b = protowire.AppendBytes(b, heartbeat)
+	}
+
+	return b
+}
+
+// Unmarshal implements the protobuf unmarshaling interface. Unknown fields are skipped, so conditions
+// encoded by a newer version of Konditionner can be decoded.
+func (m *Condition) Unmarshal(data []byte) error {
+	*m = Condition{}
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if num == protoFieldObservedGeneration && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+			}
+			m.ObservedGeneration = int64(v)
+			data = data[n:]
+			continue
+		}
+
+		if typ != protowire.BytesType || !isConditionBytesField(num) {
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch num {
+		case protoFieldType:
+			m.Type = ConditionType(v)
+		case protoFieldStatus:
+			m.Status = ConditionStatus(v)
+		case protoFieldLastTransitionTime:
+			if err := m.LastTransitionTime.Unmarshal(v); err != nil {
+				return fmt.Errorf("%w: %w", InvalidProtobufErr, err)
+			}
+		case protoFieldReason:
+			m.Reason = string(v)
+		case protoFieldInputHash:
+			m.InputHash = string(v)
+		case protoFieldResumeStatus:
+			m.ResumeStatus = ConditionStatus(v)
+		case protoFieldRef:
+			m.Ref = string(v)
+		case protoFieldAttributes:
+			key, value, err := unmarshalProtoMapEntry(v)
+			if err != nil {
+				return err
+			}
+
+			if m.Attributes == nil {
+				m.Attributes = map[string]string{}
+			}
+			m.Attributes[key] = value
+		case protoFieldManager:
+			m.Manager = string(v)
+		case protoFieldLastHeartbeatTime:
+			m.LastHeartbeatTime = &meta.Time{}
+			if err := m.LastHeartbeatTime.Unmarshal(v); err != nil {
+				return fmt.Errorf("%w: %w", InvalidProtobufErr, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isConditionBytesField(num protowire.Number) bool {
+	switch num {
+	case protoFieldType, protoFieldStatus, protoFieldLastTransitionTime, protoFieldReason, protoFieldInputHash,
+		protoFieldResumeStatus, protoFieldRef, protoFieldAttributes, protoFieldManager, protoFieldLastHeartbeatTime:
+		return true
+	}
+
+	return false
+}
+
+func unmarshalProtoMapEntry(data []byte) (key, value string, err error) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return "", "", fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType || (num != protoFieldKey && num != protoFieldValue) {
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return "", "", fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+			}
+			data = data[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeString(data)
+		if n < 0 {
+			return "", "", fmt.Errorf("%w: %w", InvalidProtobufErr, protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if num == protoFieldKey {
+			key = v
+		} else {
+			value = v
+		}
+	}
+
+	return key, value, nil
+}
+
+// Required fields are always written, even when empty, like the code generated by go-to-protobuf does.
+func appendProtoString(b []byte, num protowire.Number, value string, required bool) []byte {
+	if value == "" && !required {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func sizeProtoString(num protowire.Number, value string, required bool) int {
+	if value == "" && !required {
+		return 0
+	}
+
+	return sizeProtoBytes(num, len(value))
+}
+
+func sizeProtoBytes(num protowire.Number, size int) int {
+	return protowire.SizeTag(num) + protowire.SizeBytes(size)
+}
@@ -0,0 +1,137 @@
+package konditions
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConditionProtobufRoundTrip(t *testing.T) {
+	condition := Condition{
+		Type:               ConditionType("Bucket"),
+		Status:             ConditionCompleted,
+		LastTransitionTime: meta.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		Reason:             "Bucket created",
+		InputHash:          "abc",
+		ResumeStatus:       ConditionCreated,
+		Ref:                "arn:aws:s3:::bucket",
+		Attributes:         map[string]string{"konditionner.io/b": "2", "konditionner.io/a": "1"},
+		ObservedGeneration: 3,
+		Manager:            "bucket-controller",
+		LastHeartbeatTime:  &meta.Time{Time: time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)},
+	}
+
+	data, err := condition.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) != condition.Size() {
+		t.Errorf("Expected the size to be %d, got %d", len(data), condition.Size())
+	}
+
+	var decoded Condition
+	if err := decoded.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(condition) || !decoded.LastTransitionTime.Equal(&condition.LastTransitionTime) {
+		t.Errorf("Expected the condition to survive a round trip, got: %+v", decoded)
+	}
+
+	again, _ := decoded.Marshal()
+	if !bytes.Equal(data, again) {
+		t.Error("Expected the encoding to be deterministic")
+	}
+
+	buffer := make([]byte, len(data)+4)
+	n, err := condition.MarshalToSizedBuffer(buffer)
+	if err != nil || n != len(data) || !bytes.Equal(buffer[4:], data) {
+		t.Error("Expected the condition to be written at the end of the buffer, got: ", n, err)
+	}
+
+	if _, err := condition.MarshalTo(make([]byte, 2)); !errors.Is(err, InvalidProtobufErr) {
+		t.Error("Expected a buffer too small to be rejected, got: ", err)
+	}
+}
+
+func TestConditionProtobufMinimal(t *testing.T) {
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionInitialized}
+
+	data, err := condition.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Type, Status and an empty LastTransitionTime.
+	expected := []byte{0x0a, 0x06, 'B', 'u', 'c', 'k', 'e', 't', 0x12, 0x0b, 'I', 'n', 'i', 't', 'i', 'a', 'l', 'i', 'z', 'e', 'd', 0x22, 0x00}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Unexpected encoding: %x", data)
+	}
+}
+
+func TestConditionProtobufUnknownFields(t *testing.T) {
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted}
+	data, _ := condition.Marshal()
+
+	data = protowire.AppendTag(data, 42, protowire.VarintType)
+	data = protowire.AppendVarint(data, 7)
+	data = protowire.AppendTag(data, 43, protowire.BytesType)
+	data = protowire.AppendString(data, "from the future")
+
+	var decoded Condition
+	if err := decoded.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Type != condition.Type || decoded.Status != condition.Status {
+		t.Errorf("Unexpected condition: %+v", decoded)
+	}
+
+	if err := decoded.Unmarshal(data[:len(data)-3]); !errors.Is(err, InvalidProtobufErr) {
+		t.Error("Expected a truncated message to be rejected, got: ", err)
+	}
+}
+
+// The field numbers are part of the wire format, changing them breaks every client.
+func TestConditionProtobufFieldNumbers(t *testing.T) {
+	expected := map[string]protowire.Number{
+		"Type":               protoFieldType,
+		"Status":             protoFieldStatus,
+		"LastTransitionTime": protoFieldLastTransitionTime,
+		"Reason":             protoFieldReason,
+		"InputHash":          protoFieldInputHash,
+		"ResumeStatus":       protoFieldResumeStatus,
+		"Ref":                protoFieldRef,
+		"Attributes":         protoFieldAttributes,
+		"ObservedGeneration": protoFieldObservedGeneration,
+		"Manager":            protoFieldManager,
+		"LastHeartbeatTime":  protoFieldLastHeartbeatTime,
+	}
+
+	typ := reflect.TypeOf(Condition{})
+	if typ.NumField() != len(expected) {
+		t.Fatalf("Condition has %d fields, the protobuf encoding knows about %d", typ.NumField(), len(expected))
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		parts := strings.Split(field.Tag.Get("protobuf"), ",")
+		if len(parts) < 2 {
+			t.Errorf("%s doesn't have a protobuf tag", field.Name)
+			continue
+		}
+
+		number, err := strconv.Atoi(parts[1])
+		if err != nil || protowire.Number(number) != expected[field.Name] {
+			t.Errorf("Expected %s to be field %d, the tag says %s", field.Name, expected[field.Name], parts[1])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package konditions
+
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConditionIndexField returns the name of the field index of the status of the conditions of the type, see
+// UseConditionIndex.
+func ConditionIndexField(ct ConditionType) string {
+	return fmt.Sprintf(".status.conditions[%s].status", ct)
+}
+
+type query struct {
+	filters     []conditionFilter
+	listOptions []client.ListOption
+	index       bool
+}
+
+type conditionFilter struct {
+	conditionType ConditionType
+	statuses      []ConditionStatus
+}
+
+// QueryOption configures how List selects resources.
+type QueryOption func(*query)
+
+// WithConditionFilter only keeps the resources whose condition of the type has one of the statuses. A resource without
+// the condition has the status ConditionInitialized, like FindOrInitializeFor returns it. When no status is given, the
+// resources are kept if they have the condition, whatever its status.
+//
+// Filters are combined, a resource needs to match all of them to be kept.
+func WithConditionFilter(ct ConditionType, statuses ...ConditionStatus) QueryOption {
+	return func(q *query) {
+		q.filters = append(q.filters, conditionFilter{conditionType: ct, statuses: statuses})
+	}
+}
+
+// MatchingListOptions configures how the resources are listed before they're filtered: the namespace, the label
+// selector, etc.
+func MatchingListOptions(opts ...client.ListOption) QueryOption {
+	return func(q *query) {
+		q.listOptions = append(q.listOptions, opts...)
+	}
+}
+
+// UseConditionIndex lets List select the resources with the field indexes of the conditions registered on the cache
+// of the manager, see ConditionIndexField. Only the filters with a single status can use an index, the others are
+// applied once the resources are listed. Listing fails if the index of a filter isn't registered, see
+// RegisterConditionIndex.
+func UseConditionIndex() QueryOption {
+	return func(q *query) {
+		q.index = true
+	}
+}
+
+// List lists the resources into the list and only keeps the ones whose conditions match the filters. Controllers and
+// jobs can find the resources that need attention without going through every resource themselves:
+//
+//	var buckets BucketList
+//	err := konditions.List(ctx, c, &buckets,
+//		konditions.WithConditionFilter(ConditionType("Bucket"), konditions.ConditionError, konditions.ConditionExhausted),
+//		konditions.MatchingListOptions(client.InNamespace("default")),
+//	)
+//
+// The items of the list need to be ConditionalResources, or unstructured objects with their conditions stored in
+// DefaultConditionsFields. The resources are filtered once listed, unless the filters can use an index, see
+// UseConditionIndex.
+func List(ctx context.Context, c client.Reader, list client.ObjectList, opts ...QueryOption) error {
+	q := query{}
+	for _, opt := range opts {
+		opt(&q)
+	}
+
+	listOptions := q.listOptions
+	if q.index {
+		fields := client.MatchingFields{}
+		for _, filter := range q.filters {
+			if len(filter.statuses) == 1 {
+				fields[ConditionIndexField(filter.conditionType)] = string(filter.statuses[0])
+			}
+		}
+
+		if len(fields) > 0 {
+			listOptions = append(listOptions, fields)
+		}
+	}
+
+	if err := c.List(ctx, list, listOptions...); err != nil {
+		return err
+	}
+
+	if len(q.filters) == 0 {
+		return nil
+	}
+
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]runtime.Object, 0, len(items))
+	for _, item := range items {
+		conditions, err := conditionsOf(item)
+		if err != nil {
+			return err
+		}
+
+		if q.matches(conditions) {
+			kept = append(kept, item)
+		}
+	}
+
+	return apimeta.SetList(list, kept)
+}
+
+func (q query) matches(conditions Conditions) bool {
+	for _, filter := range q.filters {
+		condition, ok := conditions.GetType(filter.conditionType)
+		if len(filter.statuses) == 0 {
+			if !ok {
+				return false
+			}
+			continue
+		}
+
+		if !ok {
+			condition = conditions.FindOrInitializeFor(filter.conditionType)
+		}
+
+		if !condition.StatusIsOneOf(filter.statuses...) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the conditions of an item of a list.
+func conditionsOf(item runtime.Object) (Conditions, error) {
+	switch obj := item.(type) {
+	case ConditionalResource:
+		return *obj.Conditions(), nil
+	case *unstructured.Unstructured:
+		// The conditions are normalized, which is all the filters need: the anomalies are for the owner of the
+		// resource to act on, see UnstructuredConditions.Anomalies.
+		conditions, _, err := NestedConditions(obj, DefaultConditionsFields...)
+		_, err = splitNormalized(err)
+		return conditions, err
+	default:
+		return nil, fmt.Errorf("%w: %T is not a ConditionalResource", UnsupportedResourceErr, item)
+	}
+}
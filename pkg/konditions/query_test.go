@@ -0,0 +1,106 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newQueryTestResources() []client.Object {
+	failed := newTestResource("failed")
+	failed.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError})
+	failed.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted})
+
+	exhausted := newTestResource("exhausted")
+	exhausted.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionExhausted})
+
+	completed := newTestResource("completed")
+	completed.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+
+	return []client.Object{failed, exhausted, completed, newTestResource("new")}
+}
+
+func listedNames(list *testResourceList) []string {
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+
+	return names
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(newQueryTestResources()...)
+
+	var list testResourceList
+	if err := List(ctx, c, &list, WithConditionFilter(ConditionType("Bucket"), ConditionError, ConditionExhausted)); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := listedNames(&list); len(names) != 2 || names[0] != "exhausted" || names[1] != "failed" {
+		t.Error("Expected the failed resources, got: ", names)
+	}
+
+	if err := List(ctx, c, &list, WithConditionFilter(ConditionType("Bucket"), ConditionInitialized)); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := listedNames(&list); len(names) != 1 || names[0] != "new" {
+		t.Error("Expected the resource without the condition to be initialized, got: ", names)
+	}
+
+	err := List(ctx, c, &list,
+		WithConditionFilter(ConditionType("Bucket"), ConditionError),
+		WithConditionFilter(ConditionType("DNS")),
+		MatchingListOptions(client.InNamespace("default")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := listedNames(&list); len(names) != 1 || names[0] != "failed" {
+		t.Error("Expected the filters to be combined, got: ", names)
+	}
+}
+
+func TestListUnstructured(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(newQueryTestResources()...)
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(testGroupVersion.WithKind("TestResourceList"))
+
+	if err := List(ctx, c, list, WithConditionFilter(ConditionType("Bucket"), ConditionCompleted)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Items) != 1 || list.Items[0].GetName() != "completed" {
+		t.Error("Expected the completed resource, got: ", list.Items)
+	}
+}
+
+func TestListUseConditionIndex(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(newQueryTestResources()...).
+		WithIndex(&testResource{}, ConditionIndexField(ConditionType("Bucket")), ConditionIndexer(ConditionType("Bucket"))).
+		Build()
+
+	var list testResourceList
+	if err := List(ctx, c, &list, WithConditionFilter(ConditionType("Bucket"), ConditionError), UseConditionIndex()); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := listedNames(&list); len(names) != 1 || names[0] != "failed" {
+		t.Error("Expected the failed resource, got: ", names)
+	}
+
+	if err := List(ctx, c, &list, WithConditionFilter(ConditionType("DNS"), ConditionCompleted), UseConditionIndex()); err == nil {
+		t.Error("Expected an error when the index isn't registered")
+	}
+}
@@ -0,0 +1,107 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var UndeclaredStatusErr = errors.New("Status is not declared by the ReadySemantics of the condition type")
+
+// ReadySemantics declares what the statuses of a condition type mean in terms of the True/False/Unknown statuses
+// of the Kubernetes API conventions. They are used by MirrorInto to set the status of the metav1.Condition of the
+// type, the rich status being kept as the reason of the metav1.Condition.
+//
+// Statuses that are in neither list are Unknown.
+//
+//	func init() {
+//		konditions.RegisterReadySemantics(ConditionType("Certificate"), konditions.ReadySemantics{
+//			True:   []konditions.ConditionStatus{ConditionIssued, konditions.ConditionCompleted},
+//			False:  []konditions.ConditionStatus{ConditionRevoked, konditions.ConditionError},
+//			Strict: true,
+//		})
+//	}
+//
+// Teams that must conform strictly to the API conventions can make the semantics Strict: SetCondition then rejects
+// the statuses of the type that aren't declared, with UndeclaredStatusErr. The statuses Konditionner sets by itself,
+// ConditionInitialized, ConditionLocked, ConditionSuspended, ConditionDependencyUnavailable, ConditionError and
+// ConditionExhausted, are always allowed.
+type ReadySemantics struct {
+	True    []ConditionStatus
+	False   []ConditionStatus
+	Unknown []ConditionStatus
+	Strict  bool
+}
+
+// DefaultReadySemantics are the semantics of the condition types that don't have semantics registered: a completed
+// condition is True, a condition in error is False and every other status is Unknown.
+var DefaultReadySemantics = ReadySemantics{
+	True:  []ConditionStatus{ConditionCompleted},
+	False: []ConditionStatus{ConditionError},
+}
+
+// Statuses set by Konditionner, always allowed by strict semantics.
+var lifecycleStatuses = []ConditionStatus{
+	ConditionInitialized,
+	ConditionLocked,
+	ConditionSuspended,
+	ConditionDependencyUnavailable,
+	ConditionError,
+	ConditionExhausted,
+}
+
+var readySemantics = struct {
+	sync.RWMutex
+	types map[ConditionType]ReadySemantics
+}{types: map[ConditionType]ReadySemantics{}}
+
+// RegisterReadySemantics declares the semantics of the condition type, replacing the semantics registered before, if any.
+// It should be called before conditions of the type are set for the first time, usually from an init function.
+func RegisterReadySemantics(ct ConditionType, semantics ReadySemantics) {
+	readySemantics.Lock()
+	defer readySemantics.Unlock()
+
+	readySemantics.types[ct] = semantics
+}
+
+// ReadySemanticsFor returns the semantics registered for the condition type, DefaultReadySemantics otherwise.
+func ReadySemanticsFor(ct ConditionType) ReadySemantics {
+	readySemantics.RLock()
+	defer readySemantics.RUnlock()
+
+	if semantics, ok := readySemantics.types[ct]; ok {
+		return semantics
+	}
+
+	return DefaultReadySemantics
+}
+
+// MetaStatus returns the metav1.ConditionStatus of the status given.
+func (s ReadySemantics) MetaStatus(status ConditionStatus) meta.ConditionStatus {
+	condition := Condition{Status: status}
+
+	switch {
+	case condition.StatusIsOneOf(s.True...):
+		return meta.ConditionTrue
+	case condition.StatusIsOneOf(s.False...):
+		return meta.ConditionFalse
+	default:
+		return meta.ConditionUnknown
+	}
+}
+
+// Validate returns an error wrapping UndeclaredStatusErr if the semantics are strict and the status isn't declared.
+func (s ReadySemantics) Validate(status ConditionStatus) error {
+	if !s.Strict {
+		return nil
+	}
+
+	condition := Condition{Status: status}
+	if condition.StatusIsOneOf(s.True...) || condition.StatusIsOneOf(s.False...) || condition.StatusIsOneOf(s.Unknown...) || condition.StatusIsOneOf(lifecycleStatuses...) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", UndeclaredStatusErr, status)
+}
@@ -0,0 +1,67 @@
+package konditions
+
+import (
+	"errors"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func registerReadySemantics(t *testing.T, ct ConditionType, semantics ReadySemantics) {
+	RegisterReadySemantics(ct, semantics)
+	t.Cleanup(func() {
+		readySemantics.Lock()
+		defer readySemantics.Unlock()
+		delete(readySemantics.types, ct)
+	})
+}
+
+func TestReadySemantics(t *testing.T) {
+	issued := ConditionStatus("Issued")
+	revoked := ConditionStatus("Revoked")
+	registerReadySemantics(t, ConditionType("Certificate"), ReadySemantics{
+		True:  []ConditionStatus{issued},
+		False: []ConditionStatus{revoked, ConditionError},
+	})
+
+	conditions := Conditions{
+		{Type: ConditionType("Certificate"), Status: issued, Reason: "Certificate issued"},
+		{Type: ConditionType("Bucket"), Status: issued},
+	}
+
+	var metaConditions []meta.Condition
+	conditions.MirrorInto(&metaConditions, 1)
+
+	certificate := apimeta.FindStatusCondition(metaConditions, "Certificate")
+	if certificate == nil || certificate.Status != meta.ConditionTrue || certificate.Reason != "Issued" {
+		t.Error("Expected the semantics of the type to be used, got: ", certificate)
+	}
+
+	if bucket := apimeta.FindStatusCondition(metaConditions, "Bucket"); bucket == nil || bucket.Status != meta.ConditionUnknown {
+		t.Error("Expected the default semantics to be used for types without semantics, got: ", bucket)
+	}
+
+	if status := ReadySemanticsFor(ConditionType("Certificate")).MetaStatus(revoked); status != meta.ConditionFalse {
+		t.Error("Unexpected status: ", status)
+	}
+}
+
+func TestReadySemanticsStrict(t *testing.T) {
+	issued := ConditionStatus("Issued")
+	registerReadySemantics(t, ConditionType("StrictCertificate"), ReadySemantics{
+		True:   []ConditionStatus{issued},
+		Strict: true,
+	})
+
+	conditions := Conditions{}
+	if err := conditions.SetCondition(Condition{Type: ConditionType("StrictCertificate"), Status: ConditionStatus("Pending")}); !errors.Is(err, UndeclaredStatusErr) {
+		t.Error("Expected an undeclared status to be rejected, got: ", err)
+	}
+
+	for _, status := range []ConditionStatus{issued, ConditionInitialized, ConditionLocked, ConditionError} {
+		if err := conditions.SetConditionForce(Condition{Type: ConditionType("StrictCertificate"), Status: status}); err != nil {
+			t.Errorf("Expected %s to be allowed, got: %s", status, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package konditions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"unicode/utf8"
+)
+
+// MaxReasonLength is the maximum length, in bytes, of the reason of a condition. The API server
+// rejects the conditions with a longer reason.
+const MaxReasonLength = 1024
+
+// Redacted replaces the secrets found in reasons.
+const Redacted = "[REDACTED]"
+
+// ReasonFormatter rewrites the reason of a condition before it is stored. See DefaultReasonFormatter.
+type ReasonFormatter func(reason string) string
+
+// DefaultSecretPatterns are the patterns RedactReason uses when none are given. They match the secrets error
+// messages commonly leak: `password=...`, `token: ...`, bearer tokens and credentials in URLs. The capture groups
+// of a pattern are kept: the first one before Redacted, the second one after it.
+var DefaultSecretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)((?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']?)[^\s"'&,;]+`),
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+(@)`),
+}
+
+// DefaultReasonFormatter is applied to the reason of every condition set with SetCondition, or SetConditionForce. Since
+// the Lock stores the error returned by a task as the reason of the condition, and the status of a resource is
+// often world-readable, the default formatter redacts what looks like a secret, then makes sure the reason
+// isn't too long for the API server, see HashSuffixReason.
+//
+// It can be replaced, or set to nil to store reasons as they are:
+//
+//	func init() {
+//		konditions.DefaultReasonFormatter = konditions.ChainReasonFormatters(
+//			konditions.RedactReason(append(konditions.DefaultSecretPatterns, regexp.MustCompile(`sk_live_\w+`))...),
+//			konditions.TruncateReason(256),
+//		)
+//	}
+//
+// It should be set once, before conditions are set for the first time.
+var DefaultReasonFormatter ReasonFormatter = ChainReasonFormatters(RedactReason(), HashSuffixReason(MaxReasonLength))
+
+// ChainReasonFormatters returns a formatter that applies the formatters given, in order.
+func ChainReasonFormatters(formatters ...ReasonFormatter) ReasonFormatter {
+	return func(reason string) string {
+		for _, format := range formatters {
+			reason = format(reason)
+		}
+
+		return reason
+	}
+}
+
+// TruncateReason returns a formatter that truncates the reasons longer than max bytes. The truncated
+// reasons end with an ellipsis.
+func TruncateReason(max int) ReasonFormatter {
+	return func(reason string) string {
+		if len(reason) <= max {
+			return reason
+		}
+
+		return truncate(reason, max-len("…")) + "…"
+	}
+}
+
+// HashSuffixReason returns a formatter that truncates the reasons longer than max bytes and appends the
+// beginning of the SHA-256 of the full reason to them. Two long reasons that only differ past the truncation
+// stay different, which matters for the code comparing reasons, events deduplication for instance.
+func HashSuffixReason(max int) ReasonFormatter {
+	return func(reason string) string {
+		if len(reason) <= max {
+			return reason
+		}
+
+		sum := sha256.Sum256([]byte(reason))
+		suffix := "… (sha256:" + hex.EncodeToString(sum[:])[:12] + ")"
+
+		return truncate(reason, max-len(suffix)) + suffix
+	}
+}
+
+// RedactReason returns a formatter that replaces the matches of the patterns with Redacted. DefaultSecretPatterns
+// are used when no patterns are given.
+func RedactReason(patterns ...*regexp.Regexp) ReasonFormatter {
+	if len(patterns) == 0 {
+		patterns = DefaultSecretPatterns
+	}
+
+	return func(reason string) string {
+		for _, pattern := range patterns {
+			replacement := Redacted
+			switch {
+			case pattern.NumSubexp() > 1:
+				replacement = "${1}" + Redacted + "${2}"
+			case pattern.NumSubexp() > 0:
+				replacement = "${1}" + Redacted
+			}
+
+			reason = pattern.ReplaceAllString(reason, replacement)
+		}
+
+		return reason
+	}
+}
+
+// Returns the longest prefix of s, at most max bytes long, that doesn't split a rune.
+func truncate(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+
+	return s[:max]
+}
@@ -0,0 +1,84 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRedactReason(t *testing.T) {
+	redact := RedactReason()
+
+	cases := map[string]string{
+		"connection failed: password=hunter2, retrying":          "connection failed: password=[REDACTED], retrying",
+		`invalid config {"api_key": "abc123"}`:                   `invalid config {"api_key": "[REDACTED]"}`,
+		"401 Unauthorized: Authorization: Bearer eyJhbGciOi.xyz": "401 Unauthorized: Authorization: Bearer [REDACTED]",
+		"dial postgres://admin:s3cret@db:5432 failed":            "dial postgres://admin:[REDACTED]@db:5432 failed",
+		"Bucket created": "Bucket created",
+	}
+
+	for reason, expected := range cases {
+		if redacted := redact(reason); redacted != expected {
+			t.Errorf("Expected %q to be redacted as %q, got %q", reason, expected, redacted)
+		}
+	}
+
+	custom := RedactReason(regexp.MustCompile(`sk_live_\w+`))
+	if redacted := custom("charge failed with sk_live_abc"); redacted != "charge failed with "+Redacted {
+		t.Error("Unexpected reason: ", redacted)
+	}
+}
+
+func TestTruncateReason(t *testing.T) {
+	if reason := TruncateReason(10)("short"); reason != "short" {
+		t.Error("Expected short reasons to be left untouched, got: ", reason)
+	}
+
+	reason := TruncateReason(10)("ééééééééé")
+	if len(reason) > 10 || !strings.HasSuffix(reason, "…") || !strings.HasPrefix(reason, "ééé") {
+		t.Errorf("Expected the reason to be truncated without splitting a rune, got %q", reason)
+	}
+}
+
+func TestHashSuffixReason(t *testing.T) {
+	format := HashSuffixReason(40)
+	a := format(strings.Repeat("a", 50) + "1")
+	b := format(strings.Repeat("a", 50) + "2")
+
+	if len(a) > 40 || len(b) > 40 {
+		t.Errorf("Expected the reasons to fit, got %q and %q", a, b)
+	}
+
+	if a == b {
+		t.Error("Expected reasons that differ past the truncation to stay different")
+	}
+}
+
+func TestLockRedactsTaskErrors(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("redacted")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, errors.New("could not sign request with token=abc123 " + strings.Repeat("x", MaxReasonLength))
+	})
+
+	if err == nil {
+		t.Fatal("Expected the error of the task to be returned")
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := stored.Conditions().FindType(ConditionType("Bucket"))
+	if condition == nil || strings.Contains(condition.Reason, "abc123") || len(condition.Reason) > MaxReasonLength {
+		t.Error("Expected the reason to be redacted and truncated, got: ", condition)
+	}
+}
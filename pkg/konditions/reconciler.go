@@ -0,0 +1,376 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultFinalizer is the finalizer added to resources by a ConditionReconciler that has a
+// teardown function configured.
+const DefaultFinalizer = "konditionner.io/finalizer"
+
+// Handler does the work for a single ConditionType within a ConditionReconciler. It runs
+// within a Lock, which means the condition it receives is a copy of the condition *before* the
+// lock was acquired, and the condition it returns is the one that will be stored once the lock is released.
+//
+//	func bucketHandler(ctx context.Context, res *MyCRD, condition konditions.Condition) (konditions.Condition, error) {
+//		bucket, err := createBucketForResource(ctx, res)
+//		if err != nil {
+//			return condition, err
+//		}
+//
+//		res.Status.BucketName = bucket.Name
+//		condition.Status = konditions.ConditionCompleted
+//		condition.Reason = "Bucket created"
+//		return condition, nil
+//	}
+type Handler[PT ConditionalResource] func(ctx context.Context, obj PT, condition Condition) (Condition, error)
+
+// Teardown is called when a resource reconciled by a ConditionReconciler is being deleted. The
+// finalizer is removed from the resource once Teardown returns without error.
+type Teardown[PT ConditionalResource] func(ctx context.Context, obj PT) error
+
+type conditionHandler[PT ConditionalResource] struct {
+	conditionType ConditionType
+	handler       Handler[PT]
+	handlerOptions
+}
+
+type handlerOptions struct {
+	errorPolicy ErrorPolicy
+	priority    int
+}
+
+// HandlerOption configures how a ConditionReconciler runs a Handler.
+type HandlerOption func(*handlerOptions)
+
+// WithErrorPolicy configures the ErrorPolicy of a handler, FailFast is used otherwise.
+func WithErrorPolicy(policy ErrorPolicy) HandlerOption {
+	return func(o *handlerOptions) {
+		o.errorPolicy = policy
+	}
+}
+
+// WithPriority configures the priority of a handler. Handlers run by decreasing priority, handlers with the
+// same priority run in the order they were registered. The default priority is 0.
+//
+//	reconciler := konditions.NewReconciler[MyCRD](c).
+//		On(ConditionType("Bucket"), bucketHandler).
+//		On(ConditionType("Credentials"), credentialsHandler, konditions.WithPriority(10)).
+//		Build()
+func WithPriority(priority int) HandlerOption {
+	return func(o *handlerOptions) {
+		o.priority = priority
+	}
+}
+
+type attemptKey struct {
+	key           client.ObjectKey
+	conditionType ConditionType
+}
+
+// ReconcilerBuilder configures a ConditionReconciler. It is returned by NewReconciler.
+type ReconcilerBuilder[T any, PT interface {
+	*T
+	ConditionalResource
+}] struct {
+	reconciler *ConditionReconciler[T, PT]
+}
+
+// NewReconciler returns a builder for a reconciler of the custom resource T. The reconciler takes
+// care of the boilerplate every reconciliation loop built with Konditionner needs: fetching the resource,
+// adding and removing a finalizer, locking conditions before working on them and requeuing
+// the resource until every condition is completed.
+//
+//	reconciler := konditions.NewReconciler[MyCRD](mgr.GetClient()).
+//		On(ConditionType("Bucket"), bucketHandler).
+//		On(ConditionType("DNS"), dnsHandler).
+//		FinalizeWith(teardown).
+//		Build()
+//
+//	err := ctrl.NewControllerManagedBy(mgr).For(&MyCRD{}).Complete(reconciler)
+func NewReconciler[T any, PT interface {
+	*T
+	ConditionalResource
+}](c client.Client) *ReconcilerBuilder[T, PT] {
+	return &ReconcilerBuilder[T, PT]{
+		reconciler: &ConditionReconciler[T, PT]{
+			client:    c,
+			finalizer: DefaultFinalizer,
+		},
+	}
+}
+
+// On registers the handler for the condition type. Handlers are run in the order they are registered,
+// unless they have a priority, see WithPriority, and a handler only runs once the conditions of every
+// handler that comes before it are completed.
+//
+// Options can be passed to configure how the handler is run, see HandlerOption.
+func (b *ReconcilerBuilder[T, PT]) On(ct ConditionType, handler Handler[PT], opts ...HandlerOption) *ReconcilerBuilder[T, PT] {
+	h := conditionHandler[PT]{
+		conditionType: ct,
+		handler:       handler,
+	}
+
+	for _, opt := range opts {
+		opt(&h.handlerOptions)
+	}
+
+	b.reconciler.handlers = append(b.reconciler.handlers, h)
+
+	return b
+}
+
+// FinalizeWith configures the teardown function called when the resource is deleted. Configuring
+// a teardown makes the reconciler add a finalizer to the resource.
+func (b *ReconcilerBuilder[T, PT]) FinalizeWith(teardown Teardown[PT]) *ReconcilerBuilder[T, PT] {
+	b.reconciler.teardown = teardown
+	return b
+}
+
+// WithFinalizer changes the name of the finalizer, which is DefaultFinalizer otherwise.
+func (b *ReconcilerBuilder[T, PT]) WithFinalizer(name string) *ReconcilerBuilder[T, PT] {
+	b.reconciler.finalizer = name
+	return b
+}
+
+// RequeueAfter configures how long the reconciler waits before reconciling a resource that
+// still has conditions in progress. If it isn't set, the resource is requeued with the rate
+// limiter of the controller.
+func (b *ReconcilerBuilder[T, PT]) RequeueAfter(d time.Duration) *ReconcilerBuilder[T, PT] {
+	b.reconciler.requeueAfter = d
+	return b
+}
+
+// WithJitter spreads the requeues of resources that still have conditions in progress by adding up to
+// `factor` times the RequeueAfter delay to each requeue, see Jitter. It has no effect if RequeueAfter
+// isn't configured.
+//
+// The delays of a Retry ErrorPolicy are jittered through the Jitter of its Backoff.
+func (b *ReconcilerBuilder[T, PT]) WithJitter(factor float64) *ReconcilerBuilder[T, PT] {
+	b.reconciler.jitter = factor
+	return b
+}
+
+// WithWaitQueue parks the reconciliations that find a condition locked in the queue, instead of failing
+// them, until the lock is released or the TTL of the queue elapses. See WaitQueue.
+func (b *ReconcilerBuilder[T, PT]) WithWaitQueue(q *WaitQueue) *ReconcilerBuilder[T, PT] {
+	b.reconciler.waitQueue = q
+	b.reconciler.lockOptions = append(b.reconciler.lockOptions, WithWaitQueue(q))
+	return b
+}
+
+// WithRevalidation makes the reconciler revalidate the completed conditions of its handlers periodically: a
+// condition that is due is set to ConditionRevalidating, and its handler runs again, see Revalidation. The
+// resources are requeued for the next revalidation once all their conditions are completed.
+//
+//	reconciler := konditions.NewReconciler[MyCRD](c).
+//		On(ConditionType("DNSRecord"), dnsHandler).
+//		WithRevalidation(konditions.Revalidation{Type: ConditionType("DNSRecord"), Every: 30 * time.Minute}).
+//		Build()
+func (b *ReconcilerBuilder[T, PT]) WithRevalidation(revalidations ...Revalidation) *ReconcilerBuilder[T, PT] {
+	b.reconciler.revalidations = append(b.reconciler.revalidations, revalidations...)
+	return b
+}
+
+// WithLockOptions configures the options passed to every lock created by the reconciler.
+func (b *ReconcilerBuilder[T, PT]) WithLockOptions(opts ...LockOption) *ReconcilerBuilder[T, PT] {
+	b.reconciler.lockOptions = append(b.reconciler.lockOptions, opts...)
+	return b
+}
+
+// Build returns the configured reconciler.
+func (b *ReconcilerBuilder[T, PT]) Build() *ConditionReconciler[T, PT] {
+	sort.SliceStable(b.reconciler.handlers, func(i, j int) bool {
+		return b.reconciler.handlers[i].priority > b.reconciler.handlers[j].priority
+	})
+
+	return b.reconciler
+}
+
+// ConditionReconciler implements reconcile.Reconciler for a custom resource whose reconciliation
+// is split in handlers, one for each ConditionType. See NewReconciler.
+type ConditionReconciler[T any, PT interface {
+	*T
+	ConditionalResource
+}] struct {
+	client       client.Client
+	handlers     []conditionHandler[PT]
+	teardown     Teardown[PT]
+	finalizer    string
+	requeueAfter time.Duration
+	jitter       float64
+	lockOptions  []LockOption
+	waitQueue    *WaitQueue
+
+	revalidations []Revalidation
+
+	mu       sync.Mutex
+	attempts map[attemptKey]int
+}
+
+// Reconcile fetches the resource and runs the handlers that need to run. A condition is considered
+// done when its status is Completed, Error or Terminated, its handler won't be called again.
+//
+// What happens when a handler returns an error depends on its ErrorPolicy.
+func (r *ConditionReconciler[T, PT]) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := PT(new(T))
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) && r.waitQueue != nil {
+			r.waitQueue.Forget(req.NamespacedName)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if r.teardown != nil {
+		if !obj.GetDeletionTimestamp().IsZero() {
+			return reconcile.Result{}, r.finalize(ctx, obj)
+		}
+
+		if controllerutil.AddFinalizer(obj, r.finalizer) {
+			if err := r.client.Update(ctx, obj); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	if len(r.revalidations) > 0 {
+		if _, err := Revalidate(ctx, r.client, obj, r.revalidations, r.lockOptions...); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	for _, h := range r.handlers {
+		condition := obj.Conditions().FindOrInitializeFor(h.conditionType)
+
+		if condition.Class() != ClassCompleted && !condition.IsTerminal() {
+			var retryAfter time.Duration
+			lock := NewLock(obj, r.client, h.conditionType, r.lockOptions...)
+			executed, err := lock.ExecuteWithResult(ctx, func(condition Condition) (Condition, error) {
+				result, err := h.handler(ctx, obj, condition)
+				if err == nil {
+					r.resetAttempts(obj, h.conditionType)
+					return result, nil
+				}
+
+				if h.errorPolicy.kind == errorPolicyRetry {
+					if attempt := r.attempt(obj, h.conditionType); attempt <= h.errorPolicy.retries {
+						condition.Reason = fmt.Sprintf("Retrying (%d/%d): %s", attempt, h.errorPolicy.retries, err.Error())
+						retryAfter = h.errorPolicy.backoff.Duration(attempt)
+						return condition, nil
+					}
+					r.resetAttempts(obj, h.conditionType)
+				}
+
+				return result, err
+			})
+
+			if executed.Deleted {
+				// The resource is gone, there's nothing left to reconcile.
+				if r.waitQueue != nil {
+					r.waitQueue.Forget(req.NamespacedName)
+				}
+				return reconcile.Result{}, nil
+			}
+
+			if errors.Is(err, LockNotReleasedErr) && r.waitQueue != nil && condition.Status == ConditionLocked {
+				// The resource is parked, it is requeued when the lock is released. The lock may be held by
+				// another process that crashed, the resource is requeued after the TTL of the queue regardless.
+				return reconcile.Result{RequeueAfter: r.waitQueue.TTL}, nil
+			}
+
+			var unavailable *DependencyUnavailableError
+			if errors.As(err, &unavailable) {
+				return reconcile.Result{RequeueAfter: unavailable.RetryAfter}, nil
+			}
+
+			if errors.Is(err, ResourceDeletingErr) {
+				// Nothing to do for this resource until it's gone.
+				return reconcile.Result{}, nil
+			}
+
+			if errors.Is(err, PausedConditionErr) {
+				// Lifting the pause changes the resource's annotations which triggers a new reconciliation.
+				return reconcile.Result{}, nil
+			}
+
+			if err != nil && h.errorPolicy.kind != errorPolicyContinue {
+				return reconcile.Result{}, err
+			}
+
+			if retryAfter > 0 {
+				return reconcile.Result{RequeueAfter: retryAfter}, nil
+			}
+
+			condition = obj.Conditions().FindOrInitializeFor(h.conditionType)
+		}
+
+		if condition.Class() == ClassError && h.errorPolicy.kind == errorPolicyContinue {
+			continue
+		}
+
+		if condition.Class() != ClassCompleted {
+			if condition.IsTerminal() {
+				return reconcile.Result{}, nil
+			}
+
+			return r.requeue(), nil
+		}
+	}
+
+	// Every condition is completed, the handlers that revalidated are due again later.
+	_, revalidateAfter := obj.Conditions().DueForRevalidation(now().Time, r.revalidations...)
+	return reconcile.Result{RequeueAfter: revalidateAfter}, nil
+}
+
+// Increments and returns the number of attempts made for the condition of the resource.
+func (r *ConditionReconciler[T, PT]) attempt(obj PT, ct ConditionType) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.attempts == nil {
+		r.attempts = map[attemptKey]int{}
+	}
+
+	key := attemptKey{key: client.ObjectKeyFromObject(obj), conditionType: ct}
+	r.attempts[key]++
+
+	return r.attempts[key]
+}
+
+func (r *ConditionReconciler[T, PT]) resetAttempts(obj PT, ct ConditionType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, attemptKey{key: client.ObjectKeyFromObject(obj), conditionType: ct})
+}
+
+func (r *ConditionReconciler[T, PT]) finalize(ctx context.Context, obj PT) error {
+	if !controllerutil.ContainsFinalizer(obj, r.finalizer) {
+		return nil
+	}
+
+	if err := r.teardown(ctx, obj); err != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(obj, r.finalizer)
+	return r.client.Update(ctx, obj)
+}
+
+func (r *ConditionReconciler[T, PT]) requeue() reconcile.Result {
+	if r.requeueAfter == 0 {
+		return reconcile.Result{Requeue: true}
+	}
+
+	return reconcile.Result{RequeueAfter: Jitter(r.requeueAfter, r.jitter)}
+}
@@ -0,0 +1,237 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestConditionReconciler(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("reconciler")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var calls []ConditionType
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			calls = append(calls, condition.Type)
+			if condition.Status == ConditionInitialized {
+				condition.Status = ConditionCreated
+			} else {
+				condition.Status = ConditionCompleted
+			}
+			return condition, nil
+		}).
+		On(ConditionType("DNS"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			calls = append(calls, condition.Type)
+			condition.Status = ConditionCompleted
+			return condition, nil
+		}).
+		RequeueAfter(time.Second).
+		Build()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != time.Second {
+		t.Error("Expected the resource to be requeued while the bucket isn't completed")
+	}
+
+	if len(calls) != 1 {
+		t.Error("Expected the DNS handler to wait on the Bucket handler, got: ", calls)
+	}
+
+	result, err = reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.IsZero() {
+		t.Error("Expected the reconciliation to be done, got: ", result)
+	}
+
+	if len(calls) != 3 || calls[2] != ConditionType("DNS") {
+		t.Error("Unexpected calls: ", calls)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCompleted) {
+		t.Error("Expected the DNS condition to be completed, got: ", stored.Conditions())
+	}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil || len(calls) != 3 {
+		t.Error("Expected completed conditions to not run again, got: ", calls, err)
+	}
+}
+
+func TestConditionReconcilerHandlerError(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("reconciler")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	handlerErr := errors.New("Quota exceeded")
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			return condition, handlerErr
+		}).
+		Build()
+
+	if _, err := reconciler.Reconcile(ctx, req); !errors.Is(err, handlerErr) {
+		t.Error("Expected the handler error, got: ", err)
+	}
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil || !result.IsZero() {
+		t.Error("Expected errored conditions to stop the reconciliation, got: ", result, err)
+	}
+}
+
+func TestConditionReconcilerFinalizer(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("reconciler")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var tornDown bool
+	reconciler := NewReconciler[testResource](c).
+		FinalizeWith(func(ctx context.Context, obj *testResource) error {
+			tornDown = true
+			return nil
+		}).
+		Build()
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !controllerutil.ContainsFinalizer(&stored, DefaultFinalizer) {
+		t.Fatal("Expected the finalizer to be added")
+	}
+
+	if err := c.Delete(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if !tornDown {
+		t.Error("Expected the teardown to run")
+	}
+
+	if err := c.Get(ctx, req.NamespacedName, &stored); client.IgnoreNotFound(err) != nil || err == nil {
+		t.Error("Expected the resource to be deleted, got: ", err)
+	}
+}
+
+func TestConditionReconcilerErrorPolicies(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("reconciler")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var dnsCalls int
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Metrics"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			return condition, errors.New("Metrics unavailable")
+		}, WithErrorPolicy(ContinueOnError())).
+		On(ConditionType("DNS"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			dnsCalls++
+			if dnsCalls < 3 {
+				return condition, errors.New("Zone not ready")
+			}
+			condition.Status = ConditionCompleted
+			return condition, nil
+		}, WithErrorPolicy(Retry(2, Backoff{Base: time.Second}))).
+		Build()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != time.Second {
+		t.Error("Expected the DNS condition to be retried after a second, got: ", result)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Metrics"), ConditionError) {
+		t.Error("Expected the metrics condition to be errored")
+	}
+
+	dns := stored.Conditions().FindType(ConditionType("DNS"))
+	if dns == nil || dns.Status != ConditionInitialized || dns.Reason != "Retrying (1/2): Zone not ready" {
+		t.Error("Unexpected condition: ", dns)
+	}
+
+	result, err = reconciler.Reconcile(ctx, req)
+	if err != nil || result.RequeueAfter != 2*time.Second {
+		t.Error("Expected the DNS condition to be retried after 2 seconds, got: ", result, err)
+	}
+
+	result, err = reconciler.Reconcile(ctx, req)
+	if err != nil || !result.IsZero() {
+		t.Error("Expected the reconciliation to be done, got: ", result, err)
+	}
+
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCompleted) {
+		t.Error("Expected the DNS condition to be completed, got: ", stored.Conditions())
+	}
+}
+
+func TestConditionReconcilerRetryExhausted(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("reconciler")
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("DNS"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			return condition, errors.New("Zone not ready")
+		}, WithErrorPolicy(Retry(1, Backoff{Base: time.Second}))).
+		Build()
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := reconciler.Reconcile(ctx, req); err == nil {
+		t.Error("Expected the error to be returned once the retries are exhausted")
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionError) {
+		t.Error("Expected the DNS condition to be errored, got: ", stored.Conditions())
+	}
+}
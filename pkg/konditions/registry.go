@@ -0,0 +1,211 @@
+package konditions
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RegistryEvent is sent to the subscribers of a Registry every time a condition of
+// one of the resources transitions.
+type RegistryEvent struct {
+	Key types.NamespacedName
+	Transition
+}
+
+// Registry keeps an in-memory index of the conditions of every instance of a ConditionalResource kind,
+// across all namespaces the cache watches. It is built on top of the informers of controller-runtime, so
+// it doesn't issue any additional call to the Kubernetes API.
+//
+// The registry can be queried at any time, which makes it useful to power dashboards or controllers
+// that need to operate on a batch of resources:
+//
+//	registry := konditions.NewRegistry(&MyCRD{})
+//	if err := registry.Register(ctx, mgr.GetCache()); err != nil {
+//		return err
+//	}
+//
+//	// Later, once the cache is started
+//	errored := registry.WithStatus(konditions.ConditionError)
+//
+// Subscribers can also be notified of every transition that the registry observes, see Subscribe.
+type Registry struct {
+	obj ConditionalResource
+
+	mu          sync.RWMutex
+	index       map[types.NamespacedName]Conditions
+	anomalies   map[types.NamespacedName]error
+	subscribers []func(RegistryEvent)
+}
+
+// NewRegistry returns a registry for the kind of the object given. The object is only used to
+// identify the kind the registry will watch.
+func NewRegistry(obj ConditionalResource) *Registry {
+	return &Registry{
+		obj:       obj,
+		index:     map[types.NamespacedName]Conditions{},
+		anomalies: map[types.NamespacedName]error{},
+	}
+}
+
+// Register the registry against the informer for its kind. The registry starts receiving events
+// when the informers are started, which usually means when the manager starts.
+func (r *Registry) Register(ctx context.Context, informers cache.Informers) error {
+	informer, err := informers.GetInformer(ctx, r.obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.observe(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			r.observe(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if o, ok := obj.(client.Object); ok {
+				r.forget(client.ObjectKeyFromObject(o))
+			}
+		},
+	})
+
+	return err
+}
+
+// Subscribe registers a function that will be called for every transition the registry observes.
+// Subscribers are called synchronously, in the order they were registered, from the informer's
+// goroutine; they should return quickly.
+func (r *Registry) Subscribe(fn func(RegistryEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Get returns a copy of the conditions of the resource with the given key. The boolean is false
+// if the registry doesn't know about the resource.
+func (r *Registry) Get(key types.NamespacedName) (Conditions, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conditions, ok := r.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	return conditions.DeepCopy(), true
+}
+
+// Anomalies returns the *NormalizedError of the resource with the given key: the anomalies Normalize fixed in its
+// conditions when the registry indexed them. It is nil if the conditions were valid, or if the registry doesn't know
+// about the resource.
+func (r *Registry) Anomalies(key types.NamespacedName) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.anomalies[key]
+}
+
+// List returns the keys of all the resources known by the registry, sorted.
+func (r *Registry) List() []types.NamespacedName {
+	return r.filter(func(Conditions) bool { return true })
+}
+
+// Query returns the keys of the resources that have a condition with the given type and with
+// one of the statuses given. The keys are sorted.
+//
+//	pending := registry.Query(ConditionType("Bucket"), konditions.ConditionInitialized, konditions.ConditionLocked)
+func (r *Registry) Query(ct ConditionType, statuses ...ConditionStatus) []types.NamespacedName {
+	return r.filter(func(conditions Conditions) bool {
+		condition := conditions.FindType(ct)
+		return condition != nil && condition.StatusIsOneOf(statuses...)
+	})
+}
+
+// WithStatus returns the keys of the resources that have any condition with one of the
+// statuses given. The keys are sorted.
+//
+//	errored := registry.WithStatus(konditions.ConditionError)
+func (r *Registry) WithStatus(statuses ...ConditionStatus) []types.NamespacedName {
+	return r.filter(func(conditions Conditions) bool {
+		for _, status := range statuses {
+			if conditions.AnyWithStatus(status) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (r *Registry) filter(fn func(Conditions) bool) []types.NamespacedName {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []types.NamespacedName{}
+	for key, conditions := range r.index {
+		if fn(conditions) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	return keys
+}
+
+func (r *Registry) observe(obj interface{}) {
+	res, ok := obj.(ConditionalResource)
+	if !ok {
+		return
+	}
+
+	key := client.ObjectKeyFromObject(res)
+	conditions := res.Conditions().DeepCopy()
+	anomalies := normalizeDecoded(&conditions)
+
+	r.mu.Lock()
+	previous := r.index[key]
+	r.index[key] = conditions
+	if anomalies != nil {
+		r.anomalies[key] = anomalies
+	} else {
+		delete(r.anomalies, key)
+	}
+	subscribers := r.subscribers
+	r.mu.Unlock()
+
+	r.notify(key, conditions.Diff(previous), subscribers)
+}
+
+func (r *Registry) forget(key types.NamespacedName) {
+	r.mu.Lock()
+	previous, ok := r.index[key]
+	delete(r.index, key)
+	delete(r.anomalies, key)
+	subscribers := r.subscribers
+	r.mu.Unlock()
+
+	if ok {
+		r.notify(key, Conditions{}.Diff(previous), subscribers)
+	}
+}
+
+func (r *Registry) notify(key types.NamespacedName, transitions []Transition, subscribers []func(RegistryEvent)) {
+	for _, transition := range transitions {
+		for _, fn := range subscribers {
+			fn(RegistryEvent{Key: key, Transition: transition})
+		}
+	}
+}
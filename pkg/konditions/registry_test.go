@@ -0,0 +1,71 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	informers := &informertest.FakeInformers{Scheme: newTestScheme()}
+
+	registry := NewRegistry(&testResource{})
+	if err := registry.Register(ctx, informers); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []RegistryEvent
+	registry.Subscribe(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	informer, err := informers.FakeInformerFor(ctx, &testResource{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := newTestResource("bucket")
+	bucket.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionError})
+
+	dns := newTestResource("dns")
+	dns.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionLocked})
+
+	informer.Add(bucket)
+	informer.Add(dns)
+
+	if keys := registry.List(); len(keys) != 2 {
+		t.Error("Expected 2 resources, got: ", keys)
+	}
+
+	if keys := registry.WithStatus(ConditionError); len(keys) != 1 || keys[0].Name != "bucket" {
+		t.Error("Unexpected resources: ", keys)
+	}
+
+	updated := dns.DeepCopyObject().(*testResource)
+	updated.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCompleted})
+	informer.Update(dns, updated)
+
+	if keys := registry.Query(ConditionType("DNS"), ConditionCompleted); len(keys) != 1 || keys[0].Name != "dns" {
+		t.Error("Unexpected resources: ", keys)
+	}
+
+	if keys := registry.Query(ConditionType("DNS"), ConditionLocked); len(keys) != 0 {
+		t.Error("Unexpected resources: ", keys)
+	}
+
+	informer.Delete(bucket)
+	if _, ok := registry.Get(types.NamespacedName{Namespace: "default", Name: "bucket"}); ok {
+		t.Error("Expected the resource to be removed from the registry")
+	}
+
+	if len(events) != 4 {
+		t.Fatal("Expected 4 events, got: ", events)
+	}
+
+	if last := events[3]; last.Key.Name != "bucket" || last.New != nil || last.Old.Status != ConditionError {
+		t.Error("Unexpected event: ", last)
+	}
+}
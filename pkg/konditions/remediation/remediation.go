@@ -0,0 +1,262 @@
+// Package remediation provides a controller that looks for conditions stuck in a status, Locked
+// or Error usually, and remediates them with the actions configured.
+//
+// Operators can crash, or be rescheduled, while a condition is locked. When that happens, the condition
+// stays Locked until someone resets it, which is usually an SRE with kubectl. The remediation controller
+// automates that work for every kind it is registered with:
+//
+//	err := remediation.NewReconciler(mgr.GetClient(), mgr.GetEventRecorderFor("remediation"), func() konditions.ConditionalResource {
+//		return &MyCRD{}
+//	}, remediation.Rule{
+//		Status:  konditions.ConditionLocked,
+//		After:   15 * time.Minute,
+//		Actions: []remediation.Action{remediation.ActionEvent, remediation.ActionReset},
+//	}).SetupWithManager(mgr)
+package remediation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	konditions "github.com/pier-oliviert/konditionner/pkg/konditions"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RemediatedAtAnnotation is bumped on the resource by ActionRequeue.
+const RemediatedAtAnnotation = "konditionner.io/remediated-at"
+
+// Action is something the controller does to a condition that has been stuck for too long.
+type Action string
+
+const (
+	// ActionReset sets the condition back to ConditionInitialized so the operator
+	// can work on it again.
+	ActionReset Action = "Reset"
+
+	// ActionEvent emits a Warning event on the resource.
+	ActionEvent Action = "Event"
+
+	// ActionRequeue bumps the RemediatedAtAnnotation on the resource. Changing the resource
+	// triggers a reconciliation in the operator that owns it.
+	ActionRequeue Action = "Requeue"
+)
+
+// Rule describes when a condition is considered stuck and what to do about it. A condition
+// matches the rule when its status is Status, its type is one of Types (or Types is empty) and it went
+// without a transition, or a heartbeat, for longer than After, see Condition.SilentFor.
+//
+// When is optional, when set, the rule only applies to resources whose conditions satisfy the expression:
+//
+//	remediation.Rule{
+//		Status:  konditions.ConditionLocked,
+//		After:   15 * time.Minute,
+//		When:    konditions.MustCompileExpression(`none(type == "Database", status == "Error")`),
+//		Actions: []remediation.Action{remediation.ActionReset},
+//	}
+type Rule struct {
+	Status  konditions.ConditionStatus
+	Types   []konditions.ConditionType
+	After   time.Duration
+	When    *konditions.Expression
+	Actions []Action
+}
+
+func (r Rule) matches(condition konditions.Condition) bool {
+	if condition.Status != r.Status {
+		return false
+	}
+
+	if len(r.Types) == 0 {
+		return true
+	}
+
+	for _, ct := range r.Types {
+		if ct == condition.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RuleSource provides rules in addition to the rules the Reconciler is configured with, the policy
+// package provides one, for instance.
+type RuleSource interface {
+	RulesFor(gvk schema.GroupVersionKind) []Rule
+}
+
+// Reconciler remediates stuck conditions of a single kind. Register one reconciler for each
+// kind that needs to be watched.
+type Reconciler struct {
+	Client    client.Client
+	Recorder  record.EventRecorder
+	NewObject func() konditions.ConditionalResource
+	Rules     []Rule
+
+	// Policies provides additional rules for the kind, at every reconciliation. It is optional.
+	Policies RuleSource
+
+	// Deadlines are enforced on the conditions of the resource before the rules are applied, see
+	// konditions.EnforceDeadlines. A Warning event is emitted for every condition that misses its deadline.
+	Deadlines []konditions.Deadline
+
+	// Jitter spreads the requeues of resources with conditions that will be stuck at the same
+	// time, see konditions.Jitter. No jitter is added when it is 0.
+	Jitter float64
+
+	// Now returns the current time. It defaults to time.Now and exists for tests.
+	Now func() time.Time
+}
+
+// NewReconciler returns a reconciler for the kind returned by newObject, configured with the
+// rules given. The recorder can be nil if none of the rules emit events.
+func NewReconciler(c client.Client, recorder record.EventRecorder, newObject func() konditions.ConditionalResource, rules ...Rule) *Reconciler {
+	return &Reconciler{
+		Client:    c,
+		Recorder:  recorder,
+		NewObject: newObject,
+		Rules:     rules,
+		Now:       time.Now,
+	}
+}
+
+// SetupWithManager registers the reconciler with the manager. The controller is named after the
+// kind it watches so multiple reconcilers can be registered with the same manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	obj := r.NewObject()
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(fmt.Sprintf("konditions-remediation-%s", strings.ToLower(gvk.Kind))).
+		For(obj).
+		Complete(r)
+}
+
+// Reconcile looks at every condition of the resource and applies the actions of the rules that
+// match. If a condition will match a rule in the future, the request is requeued for that time.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := r.NewObject()
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := r.Now()
+	var requeueAfter time.Duration
+	var reset, requeue bool
+	var events []string
+
+	candidates := r.Rules
+	if r.Policies != nil {
+		gvk, err := apiutil.GVKForObject(obj, r.Client.Scheme())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		candidates = append(slices.Clip(candidates), r.Policies.RulesFor(gvk)...)
+	}
+
+	deadlines := obj.Conditions().EnforceDeadlines(now, r.Deadlines...)
+	reset = deadlines.Changed
+	if deadlines.RequeueAfter > 0 {
+		requeueAfter = deadlines.RequeueAfter
+	}
+
+	// Expressions are evaluated once the deadlines are enforced, before any action ran.
+	rules := make([]Rule, 0, len(candidates))
+	for _, rule := range candidates {
+		if rule.When == nil || rule.When.Evaluate(*obj.Conditions()) {
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, condition := range *obj.Conditions() {
+		var conditionReset bool
+		for _, rule := range rules {
+			if !rule.matches(condition) {
+				continue
+			}
+
+			stuckFor := condition.SilentFor(now)
+			if stuckFor < rule.After {
+				requeueAfter = minDuration(requeueAfter, rule.After-stuckFor)
+				continue
+			}
+
+			for _, action := range rule.Actions {
+				switch action {
+				case ActionReset:
+					condition.Status = konditions.ConditionInitialized
+					condition.Reason = fmt.Sprintf("Reset by remediation after being %s for %s", rule.Status, stuckFor.Round(time.Second))
+					condition.LastTransitionTime = meta.Time{}
+					if err := obj.Conditions().SetConditionForce(condition); err != nil {
+						return reconcile.Result{}, err
+					}
+					conditionReset = true
+					reset = true
+				case ActionEvent:
+					events = append(events, fmt.Sprintf("Condition %s has been %s for %s", condition.Type, rule.Status, stuckFor.Round(time.Second)))
+				case ActionRequeue:
+					requeue = true
+				}
+			}
+
+			// The condition is still stuck after the actions ran, check on it again later.
+			if !conditionReset && rule.After > 0 {
+				requeueAfter = minDuration(requeueAfter, rule.After)
+			}
+		}
+	}
+
+	if reset {
+		if err := r.Client.Status().Update(ctx, obj); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if requeue {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[RemediatedAtAnnotation] = now.UTC().Format(time.RFC3339)
+		obj.SetAnnotations(annotations)
+
+		if err := r.Client.Update(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if r.Recorder != nil {
+		for _, ct := range deadlines.Exceeded {
+			r.Recorder.Eventf(obj, corev1.EventTypeWarning, konditions.DeadlineExceededReason, "Condition %s missed its deadline", ct)
+		}
+
+		for _, message := range events {
+			r.Recorder.Event(obj, corev1.EventTypeWarning, "ConditionStuck", message)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: konditions.Jitter(requeueAfter, r.Jitter)}, nil
+}
+
+func minDuration(current, d time.Duration) time.Duration {
+	if current == 0 || d < current {
+		return d
+	}
+
+	return current
+}
@@ -0,0 +1,350 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	konditions "github.com/pier-oliviert/konditionner/pkg/konditions"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type testResource struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Status struct {
+		Conditions konditions.Conditions `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+func (r *testResource) Conditions() *konditions.Conditions {
+	return &r.Status.Conditions
+}
+
+func (r *testResource) DeepCopyObject() runtime.Object {
+	out := &testResource{TypeMeta: r.TypeMeta}
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status.Conditions = r.Status.Conditions.DeepCopy()
+	return out
+}
+
+func newTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	gv := schema.GroupVersion{Group: "konditionner.test", Version: "v1"}
+	scheme.AddKnownTypeWithName(gv.WithKind("TestResource"), &testResource{})
+	meta.AddToGroupVersion(scheme, gv)
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&testResource{}).WithObjects(objs...).Build()
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "stuck", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Bucket"),
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+	})
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("DNS"),
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-5 * time.Minute)),
+	})
+
+	c := newTestClient(res)
+	recorder := record.NewFakeRecorder(10)
+
+	r := NewReconciler(c, recorder, func() konditions.ConditionalResource { return &testResource{} }, Rule{
+		Status:  konditions.ConditionLocked,
+		After:   15 * time.Minute,
+		Actions: []Action{ActionEvent, ActionReset, ActionRequeue},
+	})
+	r.Now = func() time.Time { return now }
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "stuck"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != 10*time.Minute {
+		t.Error("Expected to requeue when the DNS condition becomes stuck, got: ", result.RequeueAfter)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("Bucket"), konditions.ConditionInitialized) {
+		t.Error("Expected the stuck condition to be reset, got: ", stored.Conditions())
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("DNS"), konditions.ConditionLocked) {
+		t.Error("Expected the DNS condition to be left untouched, got: ", stored.Conditions())
+	}
+
+	if stored.GetAnnotations()[RemediatedAtAnnotation] == "" {
+		t.Error("Expected the annotation to be bumped")
+	}
+
+	if len(recorder.Events) != 1 {
+		t.Error("Expected an event to be emitted")
+	}
+}
+
+func TestReconcileHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	heartbeat := meta.NewTime(now.Add(-time.Minute))
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "alive", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Bucket"),
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+		LastHeartbeatTime:  &heartbeat,
+	})
+
+	c := newTestClient(res)
+	r := NewReconciler(c, record.NewFakeRecorder(10), func() konditions.ConditionalResource { return &testResource{} }, Rule{
+		Status:  konditions.ConditionLocked,
+		After:   15 * time.Minute,
+		Actions: []Action{ActionReset},
+	})
+	r.Now = func() time.Time { return now }
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "alive"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != 14*time.Minute {
+		t.Error("Expected to requeue when the condition goes silent for too long, got: ", result.RequeueAfter)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("Bucket"), konditions.ConditionLocked) {
+		t.Error("Expected the condition with a recent heartbeat to be left untouched, got: ", stored.Conditions())
+	}
+}
+
+func TestReconcileResetInvalid(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	ct := konditions.ConditionType("Bucket")
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "invalid", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               ct,
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+	})
+	// Written by something that didn't go through the validation of the attributes.
+	(*res.Conditions())[0].Attributes = map[string]string{"not a valid key": "value"}
+
+	c := newTestClient(res)
+	r := NewReconciler(c, record.NewFakeRecorder(10), func() konditions.ConditionalResource { return &testResource{} }, Rule{
+		Status:  konditions.ConditionLocked,
+		After:   15 * time.Minute,
+		Actions: []Action{ActionReset},
+	})
+	r.Now = func() time.Time { return now }
+
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "invalid"}})
+	if err == nil {
+		t.Fatal("Expected the reset of a condition with invalid attributes to fail")
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ct, konditions.ConditionLocked) {
+		t.Error("Expected the condition to be left untouched, got: ", stored.Conditions())
+	}
+}
+
+func TestReconcileEventOnly(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "errored", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Bucket"),
+		Status:             konditions.ConditionError,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+	})
+
+	recorder := record.NewFakeRecorder(10)
+	r := NewReconciler(newTestClient(res), recorder, func() konditions.ConditionalResource { return &testResource{} }, Rule{
+		Status:  konditions.ConditionError,
+		Types:   []konditions.ConditionType{konditions.ConditionType("Bucket")},
+		After:   30 * time.Minute,
+		Actions: []Action{ActionEvent},
+	})
+	r.Now = func() time.Time { return now }
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "errored"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != 30*time.Minute {
+		t.Error("Expected to check on the condition again later, got: ", result.RequeueAfter)
+	}
+
+	if len(recorder.Events) != 1 {
+		t.Error("Expected an event to be emitted")
+	}
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "missing"}}); err != nil {
+		t.Error("Expected missing resources to be ignored, got: ", err)
+	}
+}
+
+func TestReconcileRuleExpression(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "expression", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Bucket"),
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+	})
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:   konditions.ConditionType("Database"),
+		Status: konditions.ConditionError,
+	})
+
+	c := newTestClient(res)
+	r := NewReconciler(c, nil, func() konditions.ConditionalResource { return &testResource{} }, Rule{
+		Status:  konditions.ConditionLocked,
+		After:   15 * time.Minute,
+		When:    konditions.MustCompileExpression(`none(type == "Database", status == "Error")`),
+		Actions: []Action{ActionReset},
+	})
+	r.Now = func() time.Time { return now }
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "expression"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("Bucket"), konditions.ConditionLocked) {
+		t.Error("Expected the rule not to apply while the expression isn't satisfied, got: ", stored.Conditions())
+	}
+}
+
+type ruleSource map[schema.GroupVersionKind][]Rule
+
+func (s ruleSource) RulesFor(gvk schema.GroupVersionKind) []Rule {
+	return s[gvk]
+}
+
+func TestReconcilePolicies(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "policies", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Bucket"),
+		Status:             konditions.ConditionLocked,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Hour)),
+	})
+
+	c := newTestClient(res)
+	r := NewReconciler(c, nil, func() konditions.ConditionalResource { return &testResource{} })
+	r.Policies = ruleSource{
+		schema.GroupVersionKind{Group: "konditionner.test", Version: "v1", Kind: "TestResource"}: {{
+			Status:  konditions.ConditionLocked,
+			After:   15 * time.Minute,
+			Actions: []Action{ActionReset},
+		}},
+	}
+	r.Now = func() time.Time { return now }
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "policies"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("Bucket"), konditions.ConditionInitialized) {
+		t.Error("Expected the rules of the policies to be applied, got: ", stored.Conditions())
+	}
+}
+
+func TestReconcileDeadlines(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	res := &testResource{ObjectMeta: meta.ObjectMeta{Name: "deadline", Namespace: "default"}}
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("Certificate"),
+		Status:             konditions.ConditionInitialized,
+		LastTransitionTime: meta.NewTime(now.Add(-15 * time.Minute)),
+	})
+	res.Conditions().SetCondition(konditions.Condition{
+		Type:               konditions.ConditionType("DNS"),
+		Status:             konditions.ConditionInitialized,
+		LastTransitionTime: meta.NewTime(now.Add(-time.Minute)),
+	})
+
+	c := newTestClient(res)
+	recorder := record.NewFakeRecorder(10)
+	r := NewReconciler(c, recorder, func() konditions.ConditionalResource { return &testResource{} })
+	r.Deadlines = []konditions.Deadline{
+		{Type: konditions.ConditionType("Certificate"), Within: 10 * time.Minute},
+		{Type: konditions.ConditionType("DNS"), Within: 10 * time.Minute},
+	}
+	r.Now = func() time.Time { return now }
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "deadline"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != 9*time.Minute {
+		t.Error("Expected to requeue when the DNS deadline is due, got: ", result.RequeueAfter)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(konditions.ConditionType("Certificate"), konditions.ConditionError) {
+		t.Error("Expected the certificate to miss its deadline, got: ", stored.Conditions())
+	}
+
+	if _, err := stored.Conditions().FindOrInitializeFor(konditions.ConditionType("DNS")).GetTime(konditions.DeadlineStartAttribute); err != nil {
+		t.Error("Expected the start of the DNS deadline to be persisted, got: ", err)
+	}
+
+	if len(recorder.Events) != 1 {
+		t.Error("Expected an event to be emitted")
+	}
+}
@@ -0,0 +1,43 @@
+package konditions
+
+import "context"
+
+// RemoveOnRelease marks the condition for removal: when the task returns without an error, the condition is removed
+// from the resource instead of being set to the condition the task returned. It's meant for the terminating flows
+// where the condition needs to be gone, not ConditionTerminated.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"))
+//	err := lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		if err := deleteBucketForResource(ctx, &res); err != nil {
+//			return condition, err
+//		}
+//
+//		lock.RemoveOnRelease()
+//		return condition, nil
+//	})
+//
+// Removing the condition releases the lock, the condition returned by the task doesn't need to have its status
+// changed. If the task returns an error, the condition is set to ConditionError as usual and isn't removed.
+func (l *Lock) RemoveOnRelease() {
+	l.removeOnRelease = true
+}
+
+// Removes the condition from the resource, releasing the lock, and persists it.
+func (l *Lock) remove(ctx context.Context, ct ConditionType) error {
+	if l.waitQueue != nil {
+		defer l.waitQueue.Release(l.obj, ct)
+	}
+
+	if l.breaker != nil {
+		l.breaker.Record(ct, false)
+	}
+
+	l.obj.Conditions().RemoveConditionWith(ct)
+	l.condition = l.obj.Conditions().FindOrInitializeFor(ct)
+
+	if l.degraded != nil {
+		l.degraded.Update(l.obj)
+	}
+
+	return l.persistRelease(ctx, l.releasePersister(ct))
+}
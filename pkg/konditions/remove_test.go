@@ -0,0 +1,66 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLockRemoveOnRelease(t *testing.T) {
+	ctx := context.Background()
+
+	for _, opts := range [][]LockOption{nil, {WithFencing()}} {
+		res := newTestResource("remove")
+		res.Status.Conditions = Conditions{
+			{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+			{Type: ConditionType("DNS"), Status: ConditionCompleted},
+		}
+		c := newTestClient(res)
+
+		lock := NewLock(res, c, ConditionType("Bucket"), opts...)
+		err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+			lock.RemoveOnRelease()
+			return condition, nil
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := stored.Conditions().GetType(ConditionType("Bucket")); ok {
+			t.Error("Expected the condition to be removed, got: ", stored.Conditions())
+		}
+
+		if !stored.Conditions().TypeHasStatus(ConditionType("DNS"), ConditionCompleted) {
+			t.Error("Expected the other conditions to be kept, got: ", stored.Conditions())
+		}
+	}
+}
+
+func TestLockRemoveOnReleaseTaskError(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("remove")
+	c := newTestClient(res)
+
+	taskErr := errors.New("Bucket is not empty")
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		lock.RemoveOnRelease()
+		return condition, taskErr
+	})
+
+	if !errors.Is(err, taskErr) {
+		t.Fatal("Expected the task error, got: ", err)
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionError) {
+		t.Error("Expected the condition to be kept in error, got: ", res.Conditions())
+	}
+}
@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testResource is a minimal custom resource used by the tests that need to
+// interact with a (fake) Kubernetes client.
+type testResource struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Status testStatus `json:"status,omitempty"`
+}
+
+type testStatus struct {
+	Conditions     Conditions       `json:"conditions,omitempty"`
+	MetaConditions []meta.Condition `json:"metaConditions,omitempty"`
+}
+
+func (r *testResource) Conditions() *Conditions {
+	return &r.Status.Conditions
+}
+
+func (r *testResource) MetaConditions() *[]meta.Condition {
+	return &r.Status.MetaConditions
+}
+
+func (r *testResource) DeepCopyObject() runtime.Object {
+	out := &testResource{}
+	out.TypeMeta = r.TypeMeta
+	r.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if r.Status.Conditions != nil {
+		out.Status.Conditions = r.Status.Conditions.DeepCopy()
+	}
+	if r.Status.MetaConditions != nil {
+		out.Status.MetaConditions = make([]meta.Condition, len(r.Status.MetaConditions))
+		for i := range r.Status.MetaConditions {
+			r.Status.MetaConditions[i].DeepCopyInto(&out.Status.MetaConditions[i])
+		}
+	}
+	return out
+}
+
+type testResourceList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+
+	Items []testResource `json:"items"`
+}
+
+func (l *testResourceList) DeepCopyObject() runtime.Object {
+	out := &testResourceList{}
+	out.TypeMeta = l.TypeMeta
+	l.ListMeta.DeepCopyInto(&out.ListMeta)
+	if l.Items != nil {
+		out.Items = make([]testResource, len(l.Items))
+		for i := range l.Items {
+			out.Items[i] = *l.Items[i].DeepCopyObject().(*testResource)
+		}
+	}
+	return out
+}
+
+var testGroupVersion = schema.GroupVersion{Group: "konditionner.test", Version: "v1"}
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(testGroupVersion.WithKind("TestResource"), &testResource{})
+	scheme.AddKnownTypeWithName(testGroupVersion.WithKind("TestResourceList"), &testResourceList{})
+	meta.AddToGroupVersion(scheme, testGroupVersion)
+	return scheme
+}
+
+// newTestClient returns a fake client with the status subresource enabled for
+// testResource and the objects given already stored.
+func newTestClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(objs...).
+		Build()
+}
+
+// newTestClientWithoutStatusSubresource returns a fake client where testResource behaves like
+// a CRD that doesn't have the status subresource enabled.
+func newTestClientWithoutStatusSubresource(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithObjects(objs...).
+		Build()
+}
+
+func newTestResource(name string) *testResource {
+	return &testResource{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+}
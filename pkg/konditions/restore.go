@@ -0,0 +1,52 @@
+package konditions
+
+import (
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestoreOnFailure makes Execute snapshot the whole condition set of the resource before the condition is locked.
+// If the resource can't be written to the Kubernetes API, when the condition is locked or when it is released, the
+// in-memory conditions of the resource are restored to the snapshot before the error is returned.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.RestoreOnFailure())
+//	if err := lock.Execute(ctx, createBucket); err != nil {
+//		// res.Status.Conditions is what it was before Execute was called.
+//		return ctrl.Result{}, err
+//	}
+//
+// Without this option, a failed write leaves the resource with the changes the lock made in memory: a condition
+// that is locked, or released, but that was never stored. Logic that runs later in the same reconciliation would
+// operate on that half-applied state. The metav1.Condition of a MetaConditionalResource are restored as well.
+func RestoreOnFailure() LockOption {
+	return func(l *Lock) {
+		l.restore = true
+	}
+}
+
+// conditionsSnapshot holds the conditions of a resource, see RestoreOnFailure.
+type conditionsSnapshot struct {
+	conditions     Conditions
+	metaConditions []meta.Condition
+	hasMeta        bool
+}
+
+func takeConditionsSnapshot(obj ConditionalResource) conditionsSnapshot {
+	snapshot := conditionsSnapshot{conditions: obj.Conditions().DeepCopy()}
+
+	if o, ok := obj.(MetaConditionalResource); ok {
+		snapshot.hasMeta = true
+		for _, condition := range *o.MetaConditions() {
+			snapshot.metaConditions = append(snapshot.metaConditions, *condition.DeepCopy())
+		}
+	}
+
+	return snapshot
+}
+
+func (s conditionsSnapshot) restoreInto(obj ConditionalResource) {
+	*obj.Conditions() = s.conditions
+
+	if o, ok := obj.(MetaConditionalResource); ok && s.hasMeta {
+		*o.MetaConditions() = s.metaConditions
+	}
+}
@@ -0,0 +1,66 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRestoreOnFailure(t *testing.T) {
+	ctx := context.Background()
+	writeErr := errors.New("API server unavailable")
+
+	for name, failOn := range map[string]int{"lock": 1, "release": 2} {
+		t.Run(name, func(t *testing.T) {
+			res := newTestResource("restore")
+			res.Status.Conditions = Conditions{
+				{Type: ConditionType("Bucket"), Status: ConditionInitialized, Reason: "Waiting"},
+				{Type: ConditionType("DNS"), Status: ConditionCompleted, Reason: "Record created"},
+			}
+			before := res.Status.Conditions.DeepCopy()
+
+			writes := 0
+			persister := PersisterFunc(func(ctx context.Context, obj ConditionalResource) error {
+				writes++
+				if writes == failOn {
+					return writeErr
+				}
+				return nil
+			})
+
+			err := NewLock(res, nil, ConditionType("Bucket"), WithPersister(persister), MirrorToMeta(), RestoreOnFailure()).Execute(ctx, func(condition Condition) (Condition, error) {
+				condition.Status = ConditionCompleted
+				return condition, nil
+			})
+
+			if !errors.Is(err, writeErr) {
+				t.Fatal("Expected the write error, got: ", err)
+			}
+
+			if !res.Status.Conditions.Equal(before) {
+				t.Error("Expected the conditions to be restored, got: ", res.Status.Conditions)
+			}
+
+			if len(res.Status.MetaConditions) != 0 {
+				t.Error("Expected the meta conditions to be restored, got: ", res.Status.MetaConditions)
+			}
+		})
+	}
+}
+
+func TestWithoutRestoreOnFailure(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("restore")
+
+	persister := PersisterFunc(func(ctx context.Context, obj ConditionalResource) error {
+		return errors.New("API server unavailable")
+	})
+
+	_ = NewLock(res, nil, ConditionType("Bucket"), WithPersister(persister)).Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if !res.Status.Conditions.TypeHasStatus(ConditionType("Bucket"), ConditionLocked) {
+		t.Error("Expected the condition to be left locked in memory, got: ", res.Status.Conditions)
+	}
+}
@@ -0,0 +1,49 @@
+package konditions
+
+import "context"
+
+// ExecuteResult describes what a Lock did during an execution, see ExecuteWithResult.
+type ExecuteResult struct {
+	// Acquired is true if the condition was locked. It's false when the execution stopped before: the
+	// condition is terminal, paused, locked by someone else, the dependency is unavailable, etc.
+	Acquired bool
+
+	// TaskRan is true if the Task was called.
+	TaskRan bool
+
+	// Status is the status of the condition once the execution returned, as the lock left it on the resource.
+	// It's empty if the condition was removed, see RemoveOnRelease.
+	Status ConditionStatus
+
+	// Deleted is true if the resource was deleted while the Task ran, the condition couldn't be released. See
+	// WithDeletedBehavior.
+	Deleted bool
+
+	// Writes is the number of writes the lock made to the Kubernetes API, heartbeats of the Watchdog and
+	// Progress included. The writes of the Task itself aren't counted.
+	Writes int
+}
+
+// Written returns true if the lock wrote to the Kubernetes API.
+func (r ExecuteResult) Written() bool {
+	return r.Writes > 0
+}
+
+// ExecuteWithResult behaves like Execute and returns what the lock did along with the error. This lets the
+// reconciler build its result, and its logs, from what actually happened:
+//
+//	result, err := lock.ExecuteWithResult(ctx, task)
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	if !result.Acquired {
+//		log.Info("Bucket wasn't worked on", "status", result.Status)
+//		return ctrl.Result{RequeueAfter: time.Minute}, nil
+//	}
+//
+// The result is filled even when an error is returned: a Task that fails still ran and its failure was written.
+func (l *Lock) ExecuteWithResult(ctx context.Context, task Task) (ExecuteResult, error) {
+	err := l.execute(ctx, "", task)
+	return l.result, err
+}
@@ -0,0 +1,83 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestExecuteWithResult(t *testing.T) {
+	res := newTestResource("result")
+	c := newTestClient(res)
+
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Acquired || !result.TaskRan || result.Status != ConditionCompleted || result.Writes != 2 || !result.Written() {
+		t.Error("Unexpected result: ", result)
+	}
+}
+
+func TestExecuteWithResultTaskFailed(t *testing.T) {
+	res := newTestResource("result")
+	c := newTestClient(res)
+
+	taskErr := errors.New("Bucket already exists")
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(context.Background(), func(condition Condition) (Condition, error) {
+		return condition, taskErr
+	})
+
+	if !errors.Is(err, taskErr) {
+		t.Fatal("Expected the error of the task, got: ", err)
+	}
+
+	if !result.Acquired || !result.TaskRan || result.Status != ConditionError || result.Writes != 2 {
+		t.Error("Unexpected result: ", result)
+	}
+}
+
+func TestExecuteWithResultNotAcquired(t *testing.T) {
+	res := newTestResource("result")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionTerminated})
+	c := newTestClient(res)
+
+	result, err := NewLock(res, c, ConditionType("Bucket")).ExecuteWithResult(context.Background(), func(condition Condition) (Condition, error) {
+		t.Error("Expected the task to not run")
+		return condition, nil
+	})
+
+	if !errors.Is(err, TerminalConditionErr) {
+		t.Fatal("Expected TerminalConditionErr, got: ", err)
+	}
+
+	if result.Acquired || result.TaskRan || result.Status != ConditionTerminated || result.Written() {
+		t.Error("Unexpected result: ", result)
+	}
+}
+
+func TestExecuteWithResultRemoved(t *testing.T) {
+	res := newTestResource("result")
+	c := newTestClient(res)
+
+	lock := NewLock(res, c, ConditionType("Bucket"))
+	lock.RemoveOnRelease()
+
+	result, err := lock.ExecuteWithResult(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.TaskRan || result.Status != "" || result.Writes != 2 {
+		t.Error("Unexpected result: ", result)
+	}
+}
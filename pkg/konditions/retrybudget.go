@@ -0,0 +1,71 @@
+package konditions
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// RetryBudgetAttemptsAttribute counts the errors of a condition within the current window of its RetryBudget.
+	RetryBudgetAttemptsAttribute = "konditionner.io/retry-budget-attempts"
+
+	// RetryBudgetWindowAttribute is the time the current window of the RetryBudget of a condition started.
+	RetryBudgetWindowAttribute = "konditionner.io/retry-budget-window"
+)
+
+// RetryBudget limits the number of times a condition can fail within a window. A condition that keeps
+// failing, and is reset every time, by the remediation controller for instance, churns against its
+// external dependency forever. Once the budget is spent, the condition is marked ConditionExhausted and
+// isn't attempted again until a human resets it.
+//
+//	budget := konditions.RetryBudget{Attempts: 5, Window: time.Hour}
+//	lock := konditions.NewLock(&res, c, ConditionType("Bucket"), konditions.WithRetryBudget(budget))
+//
+// The budget is tracked with the RetryBudgetAttemptsAttribute and RetryBudgetWindowAttribute attributes
+// of the condition, which survive Reset. The window starts with the first error and the budget is
+// replenished once the window elapsed.
+type RetryBudget struct {
+	Attempts int64
+	Window   time.Duration
+}
+
+// Observe returns the condition with its budget updated. The condition is expected to be the one that is
+// about to be stored on the resource, with the attributes of the condition it replaces. Errors spend the
+// budget, and the last error that fits in it marks the condition ConditionExhausted.
+func (b RetryBudget) Observe(condition Condition, now time.Time) Condition {
+	if condition.Status != ConditionError || b.Attempts <= 0 {
+		return condition
+	}
+
+	condition = *condition.DeepCopy()
+
+	attempts, _ := condition.GetInt(RetryBudgetAttemptsAttribute)
+	start, err := condition.GetTime(RetryBudgetWindowAttribute)
+	if err != nil || now.Sub(start) >= b.Window {
+		start = now
+		attempts = 0
+	}
+	attempts++
+
+	// A condition that can't hold any more attributes isn't tracked.
+	if condition.SetTime(RetryBudgetWindowAttribute, start) != nil || condition.SetInt(RetryBudgetAttemptsAttribute, attempts) != nil {
+		return condition
+	}
+
+	if attempts >= b.Attempts {
+		condition.Status = ConditionExhausted
+		condition.Reason = fmt.Sprintf("Retry budget exhausted, failed %d times in %s: %s", attempts, b.Window, condition.Reason)
+	}
+
+	return condition
+}
+
+// WithRetryBudget configures the lock to spend the budget of the condition every time the task fails,
+// see RetryBudget.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithRetryBudget(budget))
+func WithRetryBudget(budget RetryBudget) LockOption {
+	return func(l *Lock) {
+		l.retryBudget = &budget
+	}
+}
@@ -0,0 +1,69 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryBudgetObserve(t *testing.T) {
+	budget := RetryBudget{Attempts: 2, Window: time.Hour}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	condition := budget.Observe(Condition{Type: ConditionType("Bucket"), Status: ConditionError, Reason: "Timeout"}, now)
+	if condition.Status != ConditionError {
+		t.Fatal("Expected the first error to fit in the budget, got: ", condition)
+	}
+
+	// The window elapsed, the budget is replenished.
+	condition = budget.Observe(condition, now.Add(2*time.Hour))
+	if condition.Status != ConditionError {
+		t.Fatal("Expected the budget to be replenished, got: ", condition)
+	}
+
+	condition = budget.Observe(condition, now.Add(2*time.Hour+time.Minute))
+	if condition.Status != ConditionExhausted || condition.Reason != "Retry budget exhausted, failed 2 times in 1h0m0s: Timeout" {
+		t.Error("Expected the condition to be exhausted, got: ", condition)
+	}
+
+	if !condition.IsTerminal() {
+		t.Error("Expected an exhausted condition to be terminal")
+	}
+
+	completed := budget.Observe(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted}, now)
+	if completed.Attributes != nil {
+		t.Error("Expected conditions that didn't fail to be left untouched, got: ", completed)
+	}
+}
+
+func TestLockWithRetryBudget(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("budget")
+	c := newTestClient(res)
+	budget := WithRetryBudget(RetryBudget{Attempts: 2, Window: time.Hour})
+
+	fail := func(condition Condition) (Condition, error) {
+		return condition, errors.New("Provider unavailable")
+	}
+
+	for i := 0; i < 2; i++ {
+		res.Conditions().Reset(ConditionType("Bucket"), "Retry")
+		if err := NewLock(res, c, ConditionType("Bucket"), budget).Execute(ctx, fail); err == nil {
+			t.Fatal("Expected the task to fail")
+		}
+	}
+
+	if !res.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionExhausted) {
+		t.Fatal("Expected the budget to be spent, got: ", res.Status.Conditions)
+	}
+
+	res.SetGeneration(res.GetGeneration() + 1)
+	if stale := res.Conditions().MarkStale(res, "Spec changed"); len(stale) != 0 {
+		t.Error("Expected exhausted conditions not to be reset automatically, got: ", stale)
+	}
+
+	if err := NewLock(res, c, ConditionType("Bucket"), budget).Execute(ctx, fail); !errors.Is(err, TerminalConditionErr) {
+		t.Error("Expected the exhausted condition not to be attempted again, got: ", err)
+	}
+}
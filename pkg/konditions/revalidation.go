@@ -0,0 +1,93 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConditionRevalidating means a completed condition is due to be verified again, see Revalidation. The handler
+// of the condition is expected to check that what it completed still holds and set the condition back to
+// ConditionCompleted, or to redo the work.
+const ConditionRevalidating ConditionStatus = "Revalidating"
+
+// Revalidation declares that the completed conditions of a type need to be verified again periodically, once they
+// have been completed for Every. Drift detection loops are usually bespoke to each operator, revalidations make them
+// part of the lifecycle of the conditions.
+//
+//	// DNS records are checked every 30 minutes.
+//	revalidation := konditions.Revalidation{Type: ConditionType("DNSRecord"), Every: 30 * time.Minute}
+type Revalidation struct {
+	Type  ConditionType
+	Every time.Duration
+}
+
+// DueForRevalidation returns the types of the completed conditions that are due to be revalidated, sorted, and how long
+// until the next condition is due, 0 if none will be.
+//
+//	due, requeueAfter := res.Status.Conditions.DueForRevalidation(time.Now(), revalidations...)
+func (c Conditions) DueForRevalidation(now time.Time, revalidations ...Revalidation) ([]ConditionType, time.Duration) {
+	var due []ConditionType
+	var next time.Duration
+
+	for _, revalidation := range revalidations {
+		condition, ok := c.GetType(revalidation.Type)
+		if !ok || condition.Status != ConditionCompleted || revalidation.Every <= 0 {
+			continue
+		}
+
+		remaining := condition.LastTransitionTime.Add(revalidation.Every).Sub(now)
+		if remaining <= 0 {
+			due = append(due, condition.Type)
+			continue
+		}
+
+		if next == 0 || remaining < next {
+			next = remaining
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i] < due[j] })
+
+	return due, next
+}
+
+// Revalidate sets the completed conditions that are due to be revalidated to ConditionRevalidating, each of them under
+// a Lock configured with the options given. It returns how long until the next condition is due, which should be used
+// as the RequeueAfter of the reconciliation.
+//
+//	requeueAfter, err := konditions.Revalidate(ctx, reconciler.Client, &res, revalidations)
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+// The ConditionReconciler does it for its handlers when configured with WithRevalidation.
+func Revalidate(ctx context.Context, c client.Client, obj ConditionalResource, revalidations []Revalidation, opts ...LockOption) (time.Duration, error) {
+	due, next := obj.Conditions().DueForRevalidation(now().Time, revalidations...)
+
+	for _, ct := range due {
+		lock := NewLock(obj, c, ct, opts...)
+		err := lock.Execute(ctx, func(condition Condition) (Condition, error) {
+			completedFor := now().Sub(condition.LastTransitionTime.Time).Round(time.Second)
+
+			condition.Status = ConditionRevalidating
+			condition.Reason = fmt.Sprintf("Completed for %s, revalidating", completedFor)
+			return condition, nil
+		})
+
+		// Paused conditions, and the ones whose dependency is unavailable, are revalidated once they can be.
+		if errors.Is(err, PausedConditionErr) || errors.Is(err, DependencyUnavailableErr) {
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return next, nil
+}
@@ -0,0 +1,83 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDueForRevalidation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	conditions := Conditions{
+		{Type: ConditionType("DNSRecord"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(now.Add(-time.Hour))},
+		{Type: ConditionType("Certificate"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(now.Add(-20 * time.Minute))},
+		{Type: ConditionType("Bucket"), Status: ConditionCreated, LastTransitionTime: meta.NewTime(now.Add(-time.Hour))},
+	}
+
+	due, next := conditions.DueForRevalidation(now,
+		Revalidation{Type: ConditionType("DNSRecord"), Every: 30 * time.Minute},
+		Revalidation{Type: ConditionType("Certificate"), Every: 30 * time.Minute},
+		Revalidation{Type: ConditionType("Bucket"), Every: 30 * time.Minute},
+	)
+
+	if len(due) != 1 || due[0] != ConditionType("DNSRecord") {
+		t.Error("Expected only the completed condition past its period to be due, got: ", due)
+	}
+
+	if next != 10*time.Minute {
+		t.Error("Expected the certificate to be due in 10 minutes, got: ", next)
+	}
+}
+
+func TestReconcilerWithRevalidation(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("revalidation")
+	res.Conditions().SetCondition(Condition{
+		Type:               ConditionType("DNSRecord"),
+		Status:             ConditionCompleted,
+		LastTransitionTime: meta.NewTime(time.Now().Add(-time.Hour)),
+	})
+	c := newTestClient(res)
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+
+	var statuses []ConditionStatus
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("DNSRecord"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			statuses = append(statuses, condition.Status)
+			condition.Status = ConditionCompleted
+			condition.Reason = "Record in sync"
+			return condition, nil
+		}).
+		WithRevalidation(Revalidation{Type: ConditionType("DNSRecord"), Every: 30 * time.Minute}).
+		Build()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 1 || statuses[0] != ConditionRevalidating {
+		t.Fatal("Expected the handler to revalidate the condition, got: ", statuses)
+	}
+
+	if result.RequeueAfter <= 29*time.Minute || result.RequeueAfter > 30*time.Minute {
+		t.Error("Expected the resource to be requeued for the next revalidation, got: ", result)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("DNSRecord"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed again, got: ", stored.Conditions())
+	}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil || len(statuses) != 1 {
+		t.Error("Expected the condition not to be revalidated before it is due, got: ", statuses, err)
+	}
+}
@@ -0,0 +1,90 @@
+package konditions
+
+import (
+	"regexp"
+)
+
+// Sanitizer removes the sensitive data from the values Konditionner stores or emits: the reason and the attributes of
+// conditions before they are persisted by a Lock, and the messages of the events recorded by the EventAggregator
+// and the DegradedDetector.
+//
+// Operators routinely leak presigned URLs and tokens into conditions by storing err.Error() as the reason, and
+// the status of a resource is readable by anyone who can read the resource.
+type Sanitizer interface {
+	Sanitize(value string) string
+}
+
+// SanitizerFunc is a function that implements Sanitizer.
+type SanitizerFunc func(value string) string
+
+func (f SanitizerFunc) Sanitize(value string) string {
+	return f(value)
+}
+
+// CloudCredentialPatterns match the credentials of cloud providers and SaaS: AWS access keys and the signatures
+// of presigned URLs, Azure account keys and SAS tokens, Google Cloud private keys, JWTs, GitHub and Slack tokens.
+var CloudCredentialPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`(?i)(aws_secret_access_key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`),
+	regexp.MustCompile(`(?i)([?&](?:X-Amz-Signature|X-Amz-Credential|X-Amz-Security-Token|X-Goog-Signature|X-Goog-Credential|sig|Signature)=)[^&\s"']+`),
+	regexp.MustCompile(`(?i)((?:AccountKey|SharedAccessKey|SharedAccessSignature)=)[^;\s"']+`),
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+}
+
+// SecretSanitizer is a Sanitizer that replaces the matches of its patterns with Redacted, the same way RedactReason
+// does. When no patterns are set, it uses DefaultSecretPatterns and CloudCredentialPatterns.
+type SecretSanitizer struct {
+	Patterns []*regexp.Regexp
+}
+
+func (s SecretSanitizer) Sanitize(value string) string {
+	patterns := s.Patterns
+	if len(patterns) == 0 {
+		patterns = append(append([]*regexp.Regexp{}, DefaultSecretPatterns...), CloudCredentialPatterns...)
+	}
+
+	return RedactReason(patterns...)(value)
+}
+
+// DefaultSanitizer is used by the locks that aren't configured with WithSanitizer, and by the events Konditionner
+// records. Set it to nil to disable the sanitization.
+var DefaultSanitizer Sanitizer = SecretSanitizer{}
+
+// SanitizeCondition returns a copy of the condition with its reason and the values of its attributes sanitized.
+func SanitizeCondition(s Sanitizer, condition Condition) Condition {
+	sanitized := *condition.DeepCopy()
+	if s == nil {
+		return sanitized
+	}
+
+	sanitized.Reason = s.Sanitize(sanitized.Reason)
+	for key, value := range sanitized.Attributes {
+		sanitized.Attributes[key] = s.Sanitize(value)
+	}
+
+	return sanitized
+}
+
+// WithSanitizer configures the Sanitizer applied to the condition of the lock every time it is persisted,
+// DefaultSanitizer is used otherwise. Passing nil disables the sanitization.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithSanitizer(
+//		konditions.SecretSanitizer{Patterns: append(konditions.CloudCredentialPatterns, regexp.MustCompile(`sk_live_\w+`))},
+//	))
+func WithSanitizer(s Sanitizer) LockOption {
+	return func(l *Lock) {
+		l.sanitizer = s
+	}
+}
+
+// Sanitizes the message of an event with the DefaultSanitizer.
+func sanitizeMessage(message string) string {
+	if DefaultSanitizer == nil {
+		return message
+	}
+
+	return DefaultSanitizer.Sanitize(message)
+}
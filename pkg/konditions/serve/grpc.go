@@ -0,0 +1,173 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// GRPCServiceName is the name of the gRPC service registered by Server.RegisterGRPC.
+const GRPCServiceName = "konditionner.serve.v1.Conditions"
+
+// RegisterGRPC registers the gRPC service of the server on the gRPC server given. The service has the same
+// operations as the HTTP routes:
+//
+//	grpcServer := grpc.NewServer()
+//	server.RegisterGRPC(grpcServer)
+//
+//	err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+//		listener, err := net.Listen("tcp", ":8091")
+//		if err != nil {
+//			return err
+//		}
+//		return grpcServer.Serve(listener)
+//	}))
+//
+// The messages are google.protobuf.Struct, so clients don't need generated code. The requests have the fields
+// `resource`, the name a registry is registered with, and `namespace` and `name` for Get. The responses have the
+// same fields as the JSON of the HTTP routes:
+//   - List returns the resources in the `resources` field.
+//   - Get returns a Resource, or the code NotFound.
+//   - Watch streams a TransitionEvent for each transition of the resources, until the client cancels the call.
+//
+// An unknown resource is answered with the code NotFound.
+func (s *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&grpcServiceDesc, s)
+}
+
+// grpcService is implemented by the Server, the descriptor of the service needs an interface.
+type grpcService interface {
+	grpcList(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+	grpcGet(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+	grpcWatch(request *structpb.Struct, stream grpc.ServerStream) error
+}
+
+var grpcServiceDesc = grpc.ServiceDesc{
+	ServiceName: GRPCServiceName,
+	HandlerType: (*grpcService)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "List", Handler: unaryHandler("List", grpcService.grpcList)},
+		{MethodName: "Get", Handler: unaryHandler("Get", grpcService.grpcGet)},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				request := &structpb.Struct{}
+				if err := stream.RecvMsg(request); err != nil {
+					return err
+				}
+
+				return srv.(grpcService).grpcWatch(request, stream)
+			},
+		},
+	},
+}
+
+// Returns the handler of a unary method, decoding the request and going through the interceptor of the gRPC server.
+func unaryHandler(method string, fn func(grpcService, context.Context, *structpb.Struct) (*structpb.Struct, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		request := &structpb.Struct{}
+		if err := dec(request); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, request any) (any, error) {
+			return fn(srv.(grpcService), ctx, request.(*structpb.Struct))
+		}
+
+		if interceptor == nil {
+			return handler(ctx, request)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + GRPCServiceName + "/" + method}
+		return interceptor(ctx, request, info, handler)
+	}
+}
+
+func (s *Server) grpcEndpoint(request *structpb.Struct) (*endpoint, error) {
+	name := request.GetFields()["resource"].GetStringValue()
+	e, ok := s.lookup(name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown resource %q", name)
+	}
+
+	return e, nil
+}
+
+func (s *Server) grpcList(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	e, err := s.grpcEndpoint(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStruct(map[string]any{"resources": e.resources()})
+}
+
+func (s *Server) grpcGet(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	e, err := s.grpcEndpoint(request)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := request.GetFields()
+	key := types.NamespacedName{Namespace: fields["namespace"].GetStringValue(), Name: fields["name"].GetStringValue()}
+	resource, ok := e.resource(key)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%s not found", key)
+	}
+
+	return toStruct(resource)
+}
+
+func (s *Server) grpcWatch(request *structpb.Struct, stream grpc.ServerStream) error {
+	e, err := s.grpcEndpoint(request)
+	if err != nil {
+		return err
+	}
+
+	watcher := e.watch()
+	defer e.unwatch(watcher)
+
+	// The headers are sent right away, the client knows the watch started before the first transition.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-watcher:
+			message, err := toStruct(event)
+			if err != nil {
+				continue
+			}
+
+			if err := stream.SendMsg(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Converts the value to a Struct through its JSON, so the messages have the same fields as the HTTP responses.
+func toStruct(value any) (*structpb.Struct, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	message := &structpb.Struct{}
+	if err := message.UnmarshalJSON(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
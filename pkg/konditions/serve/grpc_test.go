@@ -0,0 +1,149 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTestGRPCClient(t *testing.T, server *Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	server.RegisterGRPC(grpcServer)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newTestRequest(t *testing.T, fields map[string]any) *structpb.Struct {
+	t.Helper()
+
+	request, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return request
+}
+
+// Decodes the Struct into the value, through its JSON.
+func fromStruct(t *testing.T, message *structpb.Struct, value any) {
+	t.Helper()
+
+	data, err := message.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerGRPCListAndGet(t *testing.T) {
+	ctx := context.Background()
+	server, informer := newTestServer(t)
+	informer.Add(newSample("bucket", konditions.ConditionCompleted))
+	informer.Add(newSample("other", konditions.ConditionError))
+	conn := newTestGRPCClient(t, server)
+
+	response := &structpb.Struct{}
+	if err := conn.Invoke(ctx, "/"+GRPCServiceName+"/List", newTestRequest(t, map[string]any{"resource": "samples"}), response); err != nil {
+		t.Fatal(err)
+	}
+
+	var list struct {
+		Resources []Resource `json:"resources"`
+	}
+	fromStruct(t, response, &list)
+
+	if len(list.Resources) != 2 || list.Resources[0].Name != "bucket" || !list.Resources[0].Conditions.TypeHasStatus("Bucket", konditions.ConditionCompleted) {
+		t.Error("Unexpected resources: ", list.Resources)
+	}
+
+	request := newTestRequest(t, map[string]any{"resource": "samples", "namespace": "default", "name": "other"})
+	if err := conn.Invoke(ctx, "/"+GRPCServiceName+"/Get", request, response); err != nil {
+		t.Fatal(err)
+	}
+
+	var resource Resource
+	fromStruct(t, response, &resource)
+
+	if resource.Name != "other" || !resource.Conditions.TypeHasStatus("Bucket", konditions.ConditionError) {
+		t.Error("Unexpected resource: ", resource)
+	}
+
+	for _, fields := range []map[string]any{
+		{"resource": "samples", "namespace": "default", "name": "missing"},
+		{"resource": "unknown", "namespace": "default", "name": "other"},
+	} {
+		err := conn.Invoke(ctx, "/"+GRPCServiceName+"/Get", newTestRequest(t, fields), response)
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected %v to be not found, got: %v", fields, err)
+		}
+	}
+}
+
+func TestServerGRPCWatch(t *testing.T) {
+	server, informer := newTestServer(t)
+	conn := newTestGRPCClient(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/"+GRPCServiceName+"/Watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stream.SendMsg(newTestRequest(t, map[string]any{"resource": "samples"})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The watch started once the headers are received.
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	informer.Add(newSample("bucket", konditions.ConditionLocked))
+
+	message := &structpb.Struct{}
+	if err := stream.RecvMsg(message); err != nil {
+		t.Fatal(err)
+	}
+
+	var event TransitionEvent
+	fromStruct(t, message, &event)
+
+	if event.Name != "bucket" || event.Type != "Bucket" || event.Old != nil || event.New.Status != konditions.ConditionLocked {
+		t.Error("Unexpected event: ", event)
+	}
+}
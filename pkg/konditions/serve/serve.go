@@ -0,0 +1,249 @@
+// Package serve exposes the conditions of resources over HTTP and gRPC, so consumers that don't have access to the
+// Kubernetes API, dashboards or ticketing automation for instance, can read them.
+//
+// The server is backed by registries, see konditions.Registry, which means it doesn't issue any call to the
+// Kubernetes API. Each registry is exposed under the name it is registered with:
+//
+//	registry := konditions.NewRegistry(&Bucket{})
+//	if err := registry.Register(ctx, mgr.GetCache()); err != nil {
+//		return err
+//	}
+//
+//	server := serve.NewServer()
+//	server.Register("buckets", registry)
+//
+//	err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+//		return http.ListenAndServe(":8090", server)
+//	}))
+//
+// The server responds to the following routes, with JSON:
+//   - GET /{resource} lists the resources and their conditions.
+//   - GET /{resource}/{namespace}/{name} returns the conditions of a single resource.
+//   - GET /{resource}/watch streams the transitions of the resources, as Server-Sent Events.
+//
+// The same operations are available over gRPC, see Server.RegisterGRPC.
+//
+// The server doesn't authenticate the requests, it should be wrapped by a handler that does if the conditions
+// aren't meant to be public.
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WatchBufferSize is the number of transitions buffered for each watcher. Transitions are dropped for watchers
+// that fall behind, rather than slowing down the registry.
+const WatchBufferSize = 64
+
+// Resource is the representation of a resource in the responses of the server.
+type Resource struct {
+	Namespace  string                `json:"namespace"`
+	Name       string                `json:"name"`
+	Conditions konditions.Conditions `json:"conditions"`
+}
+
+// TransitionEvent is the data of the events streamed by the watch route.
+type TransitionEvent struct {
+	Namespace string                   `json:"namespace"`
+	Name      string                   `json:"name"`
+	Type      konditions.ConditionType `json:"type"`
+	Old       *konditions.Condition    `json:"old,omitempty"`
+	New       *konditions.Condition    `json:"new,omitempty"`
+}
+
+type endpoint struct {
+	registry *konditions.Registry
+
+	mu       sync.Mutex
+	watchers map[chan TransitionEvent]struct{}
+}
+
+func (e *endpoint) broadcast(event konditions.RegistryEvent) {
+	transition := TransitionEvent{
+		Namespace: event.Key.Namespace,
+		Name:      event.Key.Name,
+		Type:      event.Type,
+		Old:       event.Old,
+		New:       event.New,
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for watcher := range e.watchers {
+		select {
+		case watcher <- transition:
+		default:
+		}
+	}
+}
+
+func (e *endpoint) watch() chan TransitionEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	watcher := make(chan TransitionEvent, WatchBufferSize)
+	e.watchers[watcher] = struct{}{}
+	return watcher
+}
+
+func (e *endpoint) unwatch(watcher chan TransitionEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.watchers, watcher)
+}
+
+// Server is an http.Handler that serves the conditions of the registries registered with it.
+type Server struct {
+	mux *http.ServeMux
+
+	mu        sync.RWMutex
+	endpoints map[string]*endpoint
+}
+
+// NewServer returns a server without any registry.
+func NewServer() *Server {
+	s := &Server{
+		mux:       http.NewServeMux(),
+		endpoints: map[string]*endpoint{},
+	}
+
+	s.mux.HandleFunc("GET /{resource}", s.list)
+	s.mux.HandleFunc("GET /{resource}/watch", s.watch)
+	s.mux.HandleFunc("GET /{resource}/{namespace}/{name}", s.get)
+
+	return s
+}
+
+// Register exposes the registry under the name given. Registering a name twice replaces the previous registry.
+func (s *Server) Register(name string, registry *konditions.Registry) {
+	e := &endpoint{
+		registry: registry,
+		watchers: map[chan TransitionEvent]struct{}{},
+	}
+
+	registry.Subscribe(e.broadcast)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.endpoints[name] = e
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) lookup(name string) (*endpoint, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.endpoints[name]
+	return e, ok
+}
+
+func (s *Server) endpoint(w http.ResponseWriter, r *http.Request) (*endpoint, bool) {
+	e, ok := s.lookup(r.PathValue("resource"))
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown resource %q", r.PathValue("resource")), http.StatusNotFound)
+	}
+
+	return e, ok
+}
+
+func (e *endpoint) resources() []Resource {
+	resources := []Resource{}
+	for _, key := range e.registry.List() {
+		if conditions, ok := e.registry.Get(key); ok {
+			resources = append(resources, Resource{Namespace: key.Namespace, Name: key.Name, Conditions: conditions})
+		}
+	}
+
+	return resources
+}
+
+func (e *endpoint) resource(key types.NamespacedName) (Resource, bool) {
+	conditions, ok := e.registry.Get(key)
+	if !ok {
+		return Resource{}, false
+	}
+
+	return Resource{Namespace: key.Namespace, Name: key.Name, Conditions: conditions}, true
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, e.resources())
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+	resource, ok := e.resource(key)
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s not found", key), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, resource)
+}
+
+func (s *Server) watch(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	watcher := e.watch()
+	defer e.unwatch(watcher)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-watcher:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "event: transition\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,136 @@
+package serve
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pier-oliviert/konditionner/pkg/konditions"
+	"github.com/pier-oliviert/konditionner/pkg/konditions/konditionstest/envtest"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+func newTestServer(t *testing.T) (*Server, *controllertest.FakeInformer) {
+	t.Helper()
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	if err := envtest.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	registry := konditions.NewRegistry(&envtest.Sample{})
+	if err := registry.Register(ctx, informers); err != nil {
+		t.Fatal(err)
+	}
+
+	informer, err := informers.FakeInformerFor(ctx, &envtest.Sample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer()
+	server.Register("samples", registry)
+
+	return server, informer
+}
+
+func newSample(name string, status konditions.ConditionStatus) *envtest.Sample {
+	sample := &envtest.Sample{ObjectMeta: meta.ObjectMeta{Namespace: "default", Name: name}}
+	sample.Conditions().SetCondition(konditions.Condition{Type: "Bucket", Status: status})
+	return sample
+}
+
+func TestServerListAndGet(t *testing.T) {
+	server, informer := newTestServer(t)
+	informer.Add(newSample("bucket", konditions.ConditionCompleted))
+	informer.Add(newSample("other", konditions.ConditionError))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest("GET", "/samples", nil))
+
+	var resources []Resource
+	if err := json.NewDecoder(rec.Body).Decode(&resources); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resources) != 2 || resources[0].Name != "bucket" || !resources[0].Conditions.TypeHasStatus("Bucket", konditions.ConditionCompleted) {
+		t.Error("Unexpected resources: ", resources)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest("GET", "/samples/default/other", nil))
+
+	var resource Resource
+	if err := json.NewDecoder(rec.Body).Decode(&resource); err != nil {
+		t.Fatal(err)
+	}
+
+	if resource.Name != "other" || !resource.Conditions.TypeHasStatus("Bucket", konditions.ConditionError) {
+		t.Error("Unexpected resource: ", resource)
+	}
+
+	for _, path := range []string{"/samples/default/missing", "/unknown"} {
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected %s to be not found, got: %d", path, rec.Code)
+		}
+	}
+}
+
+func TestServerWatch(t *testing.T) {
+	server, informer := newTestServer(t)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", httpServer.URL+"/samples/watch", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatal("Unexpected content type: ", resp.Header.Get("Content-Type"))
+	}
+
+	informer.Add(newSample("bucket", konditions.ConditionLocked))
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var event TransitionEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Name != "bucket" || event.Type != "Bucket" || event.Old != nil || event.New.Status != konditions.ConditionLocked {
+			t.Error("Unexpected event: ", event)
+		}
+		return
+	}
+
+	t.Fatal("Expected a transition to be streamed: ", scanner.Err())
+}
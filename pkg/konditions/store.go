@@ -0,0 +1,175 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultStoreKey is the key used by stores when no key is provided.
+const DefaultStoreKey = "conditions"
+
+// Store persists a Conditions set in a ConfigMap, or a Secret, so work that isn't tied to a
+// custom resource can still be coordinated with conditions and locks. Think of it as a checklist
+// shared by every replica of your operator.
+//
+// The ConfigMap is created when it doesn't exist. Every write to the ConfigMap is done with the
+// resourceVersion of the ConfigMap that was loaded, as such, two writers racing on the same
+// store will see one of them fail with a Conflict error, the same way a lock on a custom resource would.
+//
+//	store := konditions.NewConfigMapStore(reconciler.Client, types.NamespacedName{Namespace: "operator", Name: "migrations"}, "")
+//	lock, err := store.Lock(ctx, ConditionType("Migration v2"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	err = lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		// ... Migrate ...
+//		condition.Status = konditions.ConditionCompleted
+//		return condition, nil
+//	})
+type Store struct {
+	client client.Client
+	name   types.NamespacedName
+	key    string
+	secret bool
+}
+
+// NewConfigMapStore returns a Store backed by the ConfigMap with the given name. The conditions are
+// stored as JSON under `key` in the ConfigMap's data. If the key is empty, DefaultStoreKey is used.
+func NewConfigMapStore(c client.Client, name types.NamespacedName, key string) *Store {
+	if key == "" {
+		key = DefaultStoreKey
+	}
+
+	return &Store{client: c, name: name, key: key}
+}
+
+// NewSecretStore returns a Store backed by the Secret with the given name. It behaves the same way as
+// the ConfigMap store and is useful when the reasons stored in the conditions shouldn't be readable by
+// everyone that can read ConfigMaps in the namespace.
+func NewSecretStore(c client.Client, name types.NamespacedName, key string) *Store {
+	store := NewConfigMapStore(c, name, key)
+	store.secret = true
+
+	return store
+}
+
+// Load fetches the underlying ConfigMap, or Secret, creating it if it doesn't exist yet, and decodes
+// the conditions stored in it. Conditions with anomalies are normalized, see StoredConditions.Anomalies.
+func (s *Store) Load(ctx context.Context) (*StoredConditions, error) {
+	obj := s.newObject()
+
+	err := s.client.Get(ctx, s.name, obj)
+	if apierrors.IsNotFound(err) {
+		obj = s.newObject()
+		obj.SetName(s.name.Name)
+		obj.SetNamespace(s.name.Namespace)
+
+		err = s.client.Create(ctx, obj)
+		if apierrors.IsAlreadyExists(err) {
+			err = s.client.Get(ctx, s.name, obj)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &StoredConditions{
+		Object:     obj,
+		key:        s.key,
+		conditions: Conditions{},
+	}
+
+	if data := stored.data(); len(data) != 0 {
+		if err := json.Unmarshal(data, &stored.conditions); err != nil {
+			return nil, fmt.Errorf("could not decode conditions stored in %s: %w", s.name, err)
+		}
+
+		stored.anomalies = normalizeDecoded(&stored.conditions)
+	}
+
+	return stored, nil
+}
+
+// Lock loads the store and returns a Lock for the condition type given. The lock is configured to
+// persist the store with the ObjectPersister, additional options can be passed and will be applied after.
+func (s *Store) Lock(ctx context.Context, ct ConditionType, opts ...LockOption) (*Lock, error) {
+	stored, err := s.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]LockOption{WithPersister(ObjectPersister{Client: s.client})}, opts...)
+
+	return NewLock(stored, s.client, ct, opts...), nil
+}
+
+func (s *Store) newObject() client.Object {
+	if s.secret {
+		return &corev1.Secret{}
+	}
+
+	return &corev1.ConfigMap{}
+}
+
+// StoredConditions is the ConditionalResource returned by a Store. It holds the ConfigMap, or
+// Secret, and the conditions that were decoded from it. The conditions are only encoded back in
+// the ConfigMap when it is persisted.
+type StoredConditions struct {
+	client.Object
+
+	key        string
+	conditions Conditions
+	anomalies  error
+}
+
+// Conditions returns the conditions decoded from the store.
+func (s *StoredConditions) Conditions() *Conditions {
+	return &s.conditions
+}
+
+// Anomalies returns the *NormalizedError of the conditions decoded from the store, nil if they were valid.
+func (s *StoredConditions) Anomalies() error {
+	return s.anomalies
+}
+
+// Encode serializes the conditions into the ConfigMap, or Secret, and returns it.
+func (s *StoredConditions) Encode() (client.Object, error) {
+	data, err := json.Marshal(s.conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch obj := s.Object.(type) {
+	case *corev1.ConfigMap:
+		if obj.Data == nil {
+			obj.Data = map[string]string{}
+		}
+		obj.Data[s.key] = string(data)
+	case *corev1.Secret:
+		if obj.Data == nil {
+			obj.Data = map[string][]byte{}
+		}
+		obj.Data[s.key] = data
+	}
+
+	return s.Object, nil
+}
+
+func (s *StoredConditions) data() []byte {
+	switch obj := s.Object.(type) {
+	case *corev1.ConfigMap:
+		return []byte(obj.Data[s.key])
+	case *corev1.Secret:
+		return obj.Data[s.key]
+	}
+
+	return nil
+}
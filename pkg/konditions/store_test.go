@@ -0,0 +1,80 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	name := types.NamespacedName{Namespace: "operator", Name: "migrations"}
+
+	store := NewConfigMapStore(c, name, "")
+	lock, err := store.Lock(ctx, ConditionType("Migration"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var configMap corev1.ConfigMap
+	if err := c.Get(ctx, name, &configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	if configMap.Data[DefaultStoreKey] == "" {
+		t.Error("Expected the conditions to be stored in the ConfigMap")
+	}
+
+	stored, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Migration"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed, got: ", stored.Conditions())
+	}
+}
+
+func TestStoreOptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	store := NewSecretStore(c, types.NamespacedName{Namespace: "operator", Name: "checklist"}, "")
+
+	first, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.Conditions().SetCondition(Condition{Type: ConditionType("Step"), Status: ConditionCreated})
+	if err := (ObjectPersister{Client: c}).Persist(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	second.Conditions().SetCondition(Condition{Type: ConditionType("Step"), Status: ConditionCompleted})
+	if err := (ObjectPersister{Client: c}).Persist(ctx, second); !apierrors.IsConflict(err) {
+		t.Error("Expected a conflict, got: ", err)
+	}
+
+	if _, ok := first.Object.(*corev1.Secret); !ok {
+		t.Error("Expected the store to be backed by a Secret")
+	}
+}
@@ -0,0 +1,100 @@
+package konditions
+
+import (
+	"fmt"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReconcilingCondition is the type of the metav1.Condition that is True while the controller is
+	// working on the resource, as described by the Kubernetes API conventions.
+	ReconcilingCondition = "Reconciling"
+
+	// StalledCondition is the type of the metav1.Condition that is True when the controller
+	// can't make progress on the resource without an intervention, as described by the Kubernetes API conventions.
+	StalledCondition = "Stalled"
+)
+
+// Summarize the conditions into the Reconciling and Stalled metav1.Condition pair of the Kubernetes
+// API conventions. Tools like kstatus, Flux and Argo CD understand those two conditions and use them
+// to report the progress of any resource, without knowing about Konditionner.
+//
+//   - Reconciling is True while any condition is in progress, that is, any condition that isn't
+//     completed, errored, terminated or suspended. Locked conditions are in progress.
+//   - Stalled is True when any condition is in ConditionError or ConditionExhausted. Retries happen before a condition is
+//     marked as errored, as such, a condition in error is one that exhausted its backoff.
+//
+// Both conditions are always set, with a False status when they don't apply. The other conditions of
+// the list are left untouched, the generation is stored as the ObservedGeneration of both conditions.
+//
+//	res.Status.Conditions.SummarizeInto(&res.Status.MetaConditions, res.GetGeneration())
+//
+// The lock can keep the summary up to date every time it writes to the Kubernetes API, see
+// WithReconcilingStatus.
+func (c Conditions) SummarizeInto(conditions *[]meta.Condition, generation int64) {
+	if conditions == nil {
+		return
+	}
+
+	var progressing, errored []string
+	for _, condition := range c {
+		switch condition.Class() {
+		case ClassCompleted, ClassTerminal, ClassSuspended:
+		case ClassError:
+			errored = append(errored, fmt.Sprintf("%s: %s", condition.Type, condition.Reason))
+		default:
+			progressing = append(progressing, fmt.Sprintf("%s is %s", condition.Type, condition.Status))
+		}
+	}
+
+	reconciling := meta.Condition{
+		Type:               ReconcilingCondition,
+		Status:             meta.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "Idle",
+		Message:            "No condition in progress",
+	}
+
+	if len(progressing) > 0 {
+		reconciling.Status = meta.ConditionTrue
+		reconciling.Reason = "Progressing"
+		reconciling.Message = strings.Join(progressing, ", ")
+	}
+
+	stalled := meta.Condition{
+		Type:               StalledCondition,
+		Status:             meta.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoError",
+		Message:            "No condition in error",
+	}
+
+	if len(errored) > 0 {
+		stalled.Status = meta.ConditionTrue
+		stalled.Reason = "Error"
+		stalled.Message = strings.Join(errored, ", ")
+	}
+
+	apimeta.SetStatusCondition(conditions, reconciling)
+	apimeta.SetStatusCondition(conditions, stalled)
+}
+
+// WithReconcilingStatus configures the lock to keep the Reconciling and Stalled conditions of the
+// resource up to date, every time the lock writes to the Kubernetes API. See SummarizeInto.
+//
+// Like MirrorToMeta, the resource needs to implement MetaConditionalResource, Execute returns
+// MetaConditionsNotSupportedErr otherwise. Both options can be used together.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithReconcilingStatus())
+func WithReconcilingStatus() LockOption {
+	return func(l *Lock) {
+		l.summarize = true
+	}
+}
+
+func isSummaryType(t string) bool {
+	return t == ReconcilingCondition || t == StalledCondition
+}
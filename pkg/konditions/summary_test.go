@@ -0,0 +1,87 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSummarizeInto(t *testing.T) {
+	conditions := Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+		{Type: ConditionType("DNS"), Status: ConditionLocked},
+	}
+
+	metaConditions := []meta.Condition{}
+	conditions.SummarizeInto(&metaConditions, 2)
+
+	if !apimeta.IsStatusConditionTrue(metaConditions, ReconcilingCondition) {
+		t.Error("Expected the resource to be reconciling")
+	}
+
+	if !apimeta.IsStatusConditionFalse(metaConditions, StalledCondition) {
+		t.Error("Expected the resource to not be stalled")
+	}
+
+	conditions = Conditions{
+		{Type: ConditionType("Bucket"), Status: ConditionCompleted},
+		{Type: ConditionType("DNS"), Status: ConditionError, Reason: "Zone not found"},
+	}
+	conditions.SummarizeInto(&metaConditions, 3)
+
+	if !apimeta.IsStatusConditionFalse(metaConditions, ReconcilingCondition) {
+		t.Error("Expected the resource to not be reconciling")
+	}
+
+	stalled := apimeta.FindStatusCondition(metaConditions, StalledCondition)
+	if stalled.Status != meta.ConditionTrue || stalled.Message != "DNS: Zone not found" || stalled.ObservedGeneration != 3 {
+		t.Error("Unexpected stalled condition: ", stalled)
+	}
+}
+
+func TestMirrorIntoKeepsSummary(t *testing.T) {
+	conditions := Conditions{{Type: ConditionType("Bucket"), Status: ConditionCompleted}}
+
+	metaConditions := []meta.Condition{}
+	conditions.SummarizeInto(&metaConditions, 1)
+	conditions.MirrorInto(&metaConditions, 1)
+
+	if len(metaConditions) != 3 {
+		t.Error("Expected the summary to be kept, got: ", metaConditions)
+	}
+}
+
+func TestLockWithReconcilingStatus(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("summary")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithReconcilingStatus()).Execute(ctx, func(condition Condition) (Condition, error) {
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		if !apimeta.IsStatusConditionTrue(stored.Status.MetaConditions, ReconcilingCondition) {
+			t.Error("Expected the resource to be reconciling while the lock is held")
+		}
+
+		return condition, errors.New("Access denied")
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	if !apimeta.IsStatusConditionTrue(res.Status.MetaConditions, StalledCondition) {
+		t.Error("Expected the resource to be stalled")
+	}
+
+	if !apimeta.IsStatusConditionFalse(res.Status.MetaConditions, ReconcilingCondition) {
+		t.Error("Expected the resource to not be reconciling")
+	}
+}
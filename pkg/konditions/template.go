@@ -0,0 +1,126 @@
+package konditions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ReasonTemplateNotFoundErr = errors.New("Reason template not found")
+var InvalidReasonTemplateErr = errors.New("Invalid reason template")
+
+type reasonTemplateKey struct {
+	conditionType ConditionType
+	status        ConditionStatus
+}
+
+var reasonTemplates = struct {
+	sync.RWMutex
+	templates map[reasonTemplateKey]*template.Template
+}{templates: map[reasonTemplateKey]*template.Template{}}
+
+// RegisterReasonTemplate registers the template of the reason of the conditions of the type given, when they
+// reach the status given. The template uses the syntax of text/template and is rendered with the data passed
+// to SetConditionTemplated, or Condition.SetTemplated.
+//
+//	func init() {
+//		konditions.MustRegisterReasonTemplate(ConditionType("Bucket"), konditions.ConditionCompleted, "Bucket {{.Name}} created in {{.Region}}")
+//		konditions.MustRegisterReasonTemplate(ConditionType("Bucket"), konditions.ConditionError, "Bucket {{.Name}} could not be created: {{.Err}}")
+//	}
+//
+// Registering the reasons in one place keeps them consistent across reconcilers, makes them easy to translate
+// and to query, instead of ad-hoc fmt.Sprintf everywhere. Fields missing from the data are an error, the template
+// isn't rendered with placeholders.
+//
+// Registering a template for a type and status that already has one replaces it.
+func RegisterReasonTemplate(ct ConditionType, status ConditionStatus, text string) error {
+	tmpl, err := template.New(fmt.Sprintf("%s/%s", ct, status)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("%w: %w", InvalidReasonTemplateErr, err)
+	}
+
+	reasonTemplates.Lock()
+	defer reasonTemplates.Unlock()
+
+	reasonTemplates.templates[reasonTemplateKey{conditionType: ct, status: status}] = tmpl
+	return nil
+}
+
+// MustRegisterReasonTemplate is like RegisterReasonTemplate but panics if the template can't be parsed.
+func MustRegisterReasonTemplate(ct ConditionType, status ConditionStatus, text string) {
+	if err := RegisterReasonTemplate(ct, status, text); err != nil {
+		panic(err)
+	}
+}
+
+// RenderReason renders the template registered for the type and status with the data given. An error wrapping
+// ReasonTemplateNotFoundErr is returned if no template is registered.
+func RenderReason(ct ConditionType, status ConditionStatus, data any) (string, error) {
+	reasonTemplates.RLock()
+	tmpl, ok := reasonTemplates.templates[reasonTemplateKey{conditionType: ct, status: status}]
+	reasonTemplates.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s/%s", ReasonTemplateNotFoundErr, ct, status)
+	}
+
+	var reason strings.Builder
+	if err := tmpl.Execute(&reason, data); err != nil {
+		return "", fmt.Errorf("%w: %w", InvalidReasonTemplateErr, err)
+	}
+
+	return reason.String(), nil
+}
+
+// SetTemplated sets the status of the condition and renders its reason with the template registered for its type
+// and the status, see RegisterReasonTemplate. The condition is left untouched if the reason can't be rendered.
+//
+//	lock.Execute(ctx, func(condition konditions.Condition) (konditions.Condition, error) {
+//		bucket, err := createBucketForResource(ctx, &res)
+//		if err != nil {
+//			return condition, err
+//		}
+//
+//		err = condition.SetTemplated(konditions.ConditionCompleted, bucket)
+//		return condition, err
+//	})
+func (c *Condition) SetTemplated(status ConditionStatus, data any) error {
+	reason, err := RenderReason(c.Type, status, data)
+	if err != nil {
+		return err
+	}
+
+	c.Status = status
+	c.Reason = reason
+	return nil
+}
+
+// SetConditionTemplated sets the condition of the type given to the status given, with its reason rendered by the
+// template registered for the type and status, see RegisterReasonTemplate. The other fields of the condition, its
+// attributes for instance, are kept. It behaves like SetCondition otherwise.
+//
+//	err := res.Status.Conditions.SetConditionTemplated(ConditionType("Bucket"), konditions.ConditionCompleted, map[string]string{
+//		"Name":   bucket.Name,
+//		"Region": bucket.Region,
+//	})
+func (c *Conditions) SetConditionTemplated(ct ConditionType, status ConditionStatus, data any) error {
+	if c == nil {
+		return NotInitializedConditionsErr
+	}
+
+	condition := c.FindOrInitializeFor(ct)
+	if err := condition.SetTemplated(status, data); err != nil {
+		return err
+	}
+
+	// SetCondition sets the transition time when it's missing.
+	if existing, ok := c.GetType(ct); ok && existing.Status != status {
+		condition.LastTransitionTime = meta.Time{}
+	}
+
+	return c.SetCondition(condition)
+}
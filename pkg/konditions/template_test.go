@@ -0,0 +1,65 @@
+package konditions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetConditionTemplated(t *testing.T) {
+	MustRegisterReasonTemplate(ConditionType("TemplatedBucket"), ConditionCompleted, "Bucket {{.Name}} created in {{.Region}}")
+
+	transitioned := meta.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	conditions := Conditions{
+		{Type: ConditionType("TemplatedBucket"), Status: ConditionCreated, LastTransitionTime: transitioned, Attributes: map[string]string{"konditionner.io/etag": "abc"}},
+	}
+
+	err := conditions.SetConditionTemplated(ConditionType("TemplatedBucket"), ConditionCompleted, map[string]string{"Name": "assets", "Region": "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := conditions.FindType(ConditionType("TemplatedBucket"))
+	if condition.Status != ConditionCompleted || condition.Reason != "Bucket assets created in us-east-1" {
+		t.Error("Unexpected condition: ", condition)
+	}
+
+	if condition.LastTransitionTime.Equal(&transitioned) {
+		t.Error("Expected the transition to be recorded")
+	}
+
+	if value, _ := condition.GetAttr("konditionner.io/etag"); value != "abc" {
+		t.Error("Expected the attributes to be kept, got: ", condition.Attributes)
+	}
+
+	if err := conditions.SetConditionTemplated(ConditionType("TemplatedBucket"), ConditionCompleted, map[string]string{"Name": "assets"}); !errors.Is(err, InvalidReasonTemplateErr) {
+		t.Error("Expected missing fields to be an error, got: ", err)
+	}
+
+	if err := conditions.SetConditionTemplated(ConditionType("TemplatedBucket"), ConditionError, nil); !errors.Is(err, ReasonTemplateNotFoundErr) {
+		t.Error("Expected an error when no template is registered, got: ", err)
+	}
+
+	if condition := conditions.FindType(ConditionType("TemplatedBucket")); condition.Reason != "Bucket assets created in us-east-1" {
+		t.Error("Expected the condition to be left untouched on errors, got: ", condition)
+	}
+}
+
+func TestConditionSetTemplated(t *testing.T) {
+	MustRegisterReasonTemplate(ConditionType("TemplatedDNS"), ConditionError, "Zone {{.Zone}} not found")
+
+	condition := Condition{Type: ConditionType("TemplatedDNS"), Status: ConditionInitialized}
+	if err := condition.SetTemplated(ConditionError, struct{ Zone string }{Zone: "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if condition.Status != ConditionError || condition.Reason != "Zone example.com not found" {
+		t.Error("Unexpected condition: ", condition)
+	}
+
+	if err := RegisterReasonTemplate(ConditionType("TemplatedDNS"), ConditionCompleted, "{{.Zone"); !errors.Is(err, InvalidReasonTemplateErr) {
+		t.Error("Expected an invalid template to be rejected, got: ", err)
+	}
+}
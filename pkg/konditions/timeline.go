@@ -0,0 +1,161 @@
+package konditions
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TimelineEntry is an entry of the timeline of a resource, reconstructed from an event the library emitted for it.
+//
+// Entries reconstructed from the events of an EventAggregator are transitions, with a From and a To status. The
+// aggregator emits the transitions of a window in a single event: they happened within the Window before Time, in
+// the order of the timeline, their exact time isn't known. The other events, a degraded condition or a lock that
+// couldn't be refreshed for instance, are entries without statuses.
+type TimelineEntry struct {
+	// Time the event was emitted, or last emitted when the event was repeated, see Count.
+	Time time.Time
+
+	// Type of the condition, empty when the event doesn't name it.
+	Type ConditionType
+
+	// From is empty when the condition was added by the transition.
+	From ConditionStatus
+	To   ConditionStatus
+
+	// Window is the time the transitions of the event happened within, before Time.
+	Window time.Duration
+
+	// Count is the number of times the event was emitted. Identical events are deduplicated by the recorder, a
+	// condition that went through the same transitions twice only has one event.
+	Count int32
+
+	// EventReason and Message are the reason and the message of the event.
+	EventReason string
+	Message     string
+}
+
+// The reasons of the events the library emits, which are the events the timeline is reconstructed from.
+var timelineEventReasons = []string{TransitionsEventReason, DegradedEventReason, LockHeartbeatFailedEventReason, DeadlineExceededReason}
+
+// Timeline lists the events of the object and reconstructs the timeline of its conditions. The conditions of a
+// resource only hold their latest status, the events an EventAggregator emits hold the transitions that led to it:
+//
+//	timeline, err := konditions.Timeline(ctx, reconciler.Client, &res)
+//	for _, entry := range timeline {
+//		fmt.Println(entry.Time, entry.Type, entry.From, "→", entry.To)
+//	}
+//
+// The events are listed with the involvedObject.uid field selector, caching clients need an index on that field.
+// Kubernetes only keeps events for a while, an hour by default, the timeline doesn't go further back than that.
+func Timeline(ctx context.Context, c client.Reader, obj client.Object) ([]TimelineEntry, error) {
+	var events corev1.EventList
+	err := c.List(ctx, &events, client.InNamespace(obj.GetNamespace()), client.MatchingFields{"involvedObject.uid": string(obj.GetUID())})
+	if err != nil {
+		return nil, err
+	}
+
+	return TimelineFromEvents(events.Items), nil
+}
+
+// TimelineFromEvents reconstructs the timeline from the events given, oldest first. Events that weren't emitted by
+// the library are skipped.
+func TimelineFromEvents(events []corev1.Event) []TimelineEntry {
+	timeline := []TimelineEntry{}
+
+	for _, event := range events {
+		if !slices.Contains(timelineEventReasons, event.Reason) {
+			continue
+		}
+
+		entry := TimelineEntry{
+			Time:        eventTime(event),
+			Count:       event.Count,
+			EventReason: event.Reason,
+			Message:     event.Message,
+		}
+
+		if entry.Count == 0 {
+			entry.Count = 1
+		}
+
+		if event.Reason != TransitionsEventReason {
+			timeline = append(timeline, entry)
+			continue
+		}
+
+		ct, statuses, window, ok := parseTransitionsMessage(event.Message)
+		if !ok {
+			timeline = append(timeline, entry)
+			continue
+		}
+
+		entry.Type = ct
+		entry.Window = window
+		if len(statuses) == 1 {
+			entry.To = statuses[0]
+			timeline = append(timeline, entry)
+			continue
+		}
+
+		for i := 1; i < len(statuses); i++ {
+			entry.From = statuses[i-1]
+			entry.To = statuses[i]
+			timeline = append(timeline, entry)
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(timeline[j].Time)
+	})
+
+	return timeline
+}
+
+// Returns the time the event was last emitted, falling back on the older fields for the recorders that don't set
+// the newer ones.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// Parses the message of an event emitted by an EventAggregator: `Bucket: Initialized→Locked→Completed over 3s`.
+func parseTransitionsMessage(message string) (ConditionType, []ConditionStatus, time.Duration, bool) {
+	i := strings.LastIndex(message, " over ")
+	if i < 0 {
+		return "", nil, 0, false
+	}
+
+	window, err := time.ParseDuration(message[i+len(" over "):])
+	if err != nil {
+		return "", nil, 0, false
+	}
+
+	j := strings.LastIndex(message[:i], ": ")
+	if j <= 0 {
+		return "", nil, 0, false
+	}
+
+	var statuses []ConditionStatus
+	for _, status := range strings.Split(message[j+2:i], "→") {
+		if status == "" {
+			return "", nil, 0, false
+		}
+		statuses = append(statuses, ConditionStatus(status))
+	}
+
+	return ConditionType(message[:j]), statuses, window, true
+}
@@ -0,0 +1,105 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestEvent(name string, uid types.UID, reason, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     meta.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Namespace: "default", Name: "logs", UID: uid},
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  meta.NewTime(at),
+		Count:          1,
+	}
+}
+
+func TestTimelineFromEvents(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []corev1.Event{
+		*newTestEvent("b", "", TransitionsEventReason, "Bucket: Locked→Error over 2s", at.Add(time.Minute)),
+		*newTestEvent("a", "", TransitionsEventReason, "Bucket: Initialized→Locked over 0s", at),
+		*newTestEvent("c", "", DegradedEventReason, "Bucket failed 3 times in a row: Access denied", at.Add(2*time.Minute)),
+		*newTestEvent("d", "", "Scheduled", "Not an event of the library", at),
+		*newTestEvent("e", "", TransitionsEventReason, "DNS: Initialized over 0s", at.Add(-time.Minute)),
+	}
+
+	timeline := TimelineFromEvents(events)
+	if len(timeline) != 4 {
+		t.Fatal("Expected 4 entries, got: ", timeline)
+	}
+
+	expected := []struct {
+		ct       ConditionType
+		from, to ConditionStatus
+	}{
+		{"DNS", "", ConditionInitialized},
+		{"Bucket", ConditionInitialized, ConditionLocked},
+		{"Bucket", ConditionLocked, ConditionError},
+		{"", "", ""},
+	}
+
+	for i, e := range expected {
+		entry := timeline[i]
+		if entry.Type != e.ct || entry.From != e.from || entry.To != e.to {
+			t.Errorf("Expected entry %d to be %s %s→%s, got %s %s→%s", i, e.ct, e.from, e.to, entry.Type, entry.From, entry.To)
+		}
+	}
+
+	if timeline[2].Window != 2*time.Second || !timeline[2].Time.Equal(at.Add(time.Minute)) {
+		t.Error("Expected the transition to have happened within the window of the event, got: ", timeline[2])
+	}
+
+	if timeline[3].EventReason != DegradedEventReason || timeline[3].Message == "" {
+		t.Error("Expected the degraded event to be an entry without statuses, got: ", timeline[3])
+	}
+}
+
+func TestParseTransitionsMessage(t *testing.T) {
+	ct, statuses, window, ok := parseTransitionsMessage("Bucket: Initialized→Locked→Completed over 3s")
+	if !ok || ct != "Bucket" || len(statuses) != 3 || statuses[2] != ConditionCompleted || window != 3*time.Second {
+		t.Errorf("Unexpected result: %s, %v, %s, %t", ct, statuses, window, ok)
+	}
+
+	for _, message := range []string{"Bucket: Locked", "Locked over 3s", "Bucket: Locked over soon", ": Locked over 3s", "Bucket: Locked→ over 3s"} {
+		if _, _, _, ok := parseTransitionsMessage(message); ok {
+			t.Errorf("Expected %q not to be parsed", message)
+		}
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	res := newTestResource("logs")
+	res.SetUID("1234")
+
+	c := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithIndex(&corev1.Event{}, "involvedObject.uid", func(obj client.Object) []string {
+			return []string{string(obj.(*corev1.Event).InvolvedObject.UID)}
+		}).
+		WithObjects(
+			newTestEvent("a", "1234", TransitionsEventReason, "Bucket: Initialized→Locked over 0s", at),
+			newTestEvent("b", "5678", TransitionsEventReason, "Bucket: Locked→Error over 2s", at),
+		).
+		Build()
+
+	timeline, err := Timeline(context.Background(), c, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(timeline) != 1 || timeline[0].To != ConditionLocked {
+		t.Error("Expected the events of the resource only, got: ", timeline)
+	}
+}
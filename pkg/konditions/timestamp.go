@@ -0,0 +1,77 @@
+package konditions
+
+import (
+	"maps"
+	"reflect"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TimestampPrecision is the precision of the LastTransitionTime set by SetCondition. The time is truncated to
+// a multiple of it, seconds by default, the same way metav1.Condition does.
+//
+// The API server serializes timestamps with a precision of a second: a condition with a nanosecond
+// precision doesn't round-trip and compares unequal to itself once read back. Set it to time.Nanosecond, or
+// any value lower or equal to 0, to keep the full precision.
+var TimestampPrecision = time.Second
+
+// TimestampTolerance is the difference between two timestamps that Condition.Equal still considers equal.
+var TimestampTolerance = time.Second
+
+// Returns the current time, truncated to TimestampPrecision.
+func now() meta.Time {
+	return meta.NewTime(time.Now().Truncate(TimestampPrecision))
+}
+
+// Returns true if both conditions are the same, field by field. Their LastTransitionTime, and LastHeartbeatTime,
+// are considered equal if they are within TimestampTolerance of each other, which makes it possible to compare a condition
+// held in memory with the same condition read back from the API server.
+func (c Condition) Equal(other Condition) bool {
+	if !timesEqual(c.LastTransitionTime, other.LastTransitionTime) {
+		return false
+	}
+
+	if (c.LastHeartbeatTime == nil) != (other.LastHeartbeatTime == nil) {
+		return false
+	}
+
+	if c.LastHeartbeatTime != nil && !timesEqual(*c.LastHeartbeatTime, *other.LastHeartbeatTime) {
+		return false
+	}
+
+	if !maps.Equal(c.Attributes, other.Attributes) {
+		return false
+	}
+
+	c.LastTransitionTime, other.LastTransitionTime = meta.Time{}, meta.Time{}
+	c.LastHeartbeatTime, other.LastHeartbeatTime = nil, nil
+	c.Attributes, other.Attributes = nil, nil
+	return reflect.DeepEqual(c, other)
+}
+
+// Returns true if both sets hold equal conditions, see Condition.Equal. The order of the conditions
+// doesn't matter.
+func (c Conditions) Equal(other Conditions) bool {
+	if len(c) != len(other) {
+		return false
+	}
+
+	for _, condition := range c {
+		o := other.FindType(condition.Type)
+		if o == nil || !condition.Equal(*o) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func timesEqual(a, b meta.Time) bool {
+	diff := a.Sub(b.Time)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= TimestampTolerance
+}
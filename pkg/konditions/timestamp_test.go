@@ -0,0 +1,89 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetConditionTruncatesTimestamp(t *testing.T) {
+	conditions := Conditions{}
+	conditions.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+
+	if conditions[0].LastTransitionTime.Nanosecond() != 0 {
+		t.Error("Expected the timestamp to be truncated to the second, got: ", conditions[0].LastTransitionTime)
+	}
+
+	TimestampPrecision = time.Nanosecond
+	defer func() { TimestampPrecision = time.Second }()
+
+	conditions.Reset(ConditionType("Bucket"), "Full precision")
+	if conditions[0].LastTransitionTime.Nanosecond() == 0 {
+		t.Error("Expected the full precision to be kept")
+	}
+}
+
+func TestConditionEqual(t *testing.T) {
+	now := time.Now()
+	condition := Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, LastTransitionTime: meta.NewTime(now)}
+	condition.SetAttr("etag", "abc")
+
+	other := *condition.DeepCopy()
+	other.LastTransitionTime = meta.NewTime(now.Truncate(time.Second))
+	if !condition.Equal(other) {
+		t.Error("Expected the conditions to be equal")
+	}
+
+	other.LastTransitionTime = meta.NewTime(now.Add(time.Minute))
+	if condition.Equal(other) {
+		t.Error("Expected the timestamps to differ")
+	}
+
+	other = *condition.DeepCopy()
+	other.SetAttr("etag", "def")
+	if condition.Equal(other) {
+		t.Error("Expected the attributes to differ")
+	}
+
+	if !(Conditions{condition}).Equal(Conditions{*condition.DeepCopy()}) {
+		t.Error("Expected the sets to be equal")
+	}
+}
+
+func TestConditionsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("timestamp")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+	c := newTestClient(res)
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().Equal(*res.Conditions()) {
+		t.Error("Expected the conditions to round-trip")
+	}
+}
+
+func TestLockSetsReleaseTransitionTime(t *testing.T) {
+	res := newTestResource("timestamp")
+	res.Status.Conditions = Conditions{{Type: ConditionType("Bucket"), Status: ConditionCreated, LastTransitionTime: meta.NewTime(time.Now().Add(-time.Hour))}}
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket")).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if condition := res.Conditions().FindType(ConditionType("Bucket")); time.Since(condition.LastTransitionTime.Time) > time.Minute {
+		t.Error("Expected the release to be a transition, got: ", condition.LastTransitionTime)
+	}
+}
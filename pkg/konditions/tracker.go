@@ -0,0 +1,192 @@
+package konditions
+
+import "context"
+
+// Listener is called for every Transition of a condition of the resource. Listeners are the single
+// extension point to react to transitions in-process: recording events, updating metrics, logging,
+// invalidating a cache, etc.
+//
+//	func recordTransition(obj konditions.ConditionalResource, transition konditions.Transition) {
+//		if transition.New != nil && transition.New.Status == konditions.ConditionError {
+//			recorder.Event(obj, corev1.EventTypeWarning, string(transition.Type), transition.New.Reason)
+//		}
+//	}
+//
+// Listeners are called synchronously, in the order they were registered, and should return quickly.
+type Listener func(obj ConditionalResource, transition Transition)
+
+// Tracker wraps the conditions of a resource and notifies its listeners of every transition made
+// through it. It exposes the same mutations as Conditions.
+//
+//	tracker := konditions.NewTracker(&res, recordTransition)
+//	tracker.SetCondition(konditions.Condition{Type: ConditionType("Bucket"), Status: konditions.ConditionCompleted})
+//
+// Changes made to the conditions directly, without going through the tracker, aren't observed.
+//
+// The tracker also knows whether the conditions need to be persisted: they're dirty once they differ from the
+// conditions the resource had when the tracker was created, or when MarkClean was last called. Reconcilers can
+// skip the writes that wouldn't change anything, and the watch events they would trigger downstream:
+//
+//	tracker := konditions.NewTracker(&res)
+//	// ... set and remove conditions through the tracker ...
+//	if _, err := tracker.PersistIfDirty(ctx, konditions.StatusPersister{Client: reconciler.Client}); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+// The dirty state lives on the tracker, not on Conditions: Conditions is the slice stored in the status of the
+// resource and can't hold anything that isn't serialized with it. Locks track the conditions of their resource with a
+// tracker, and don't write a release that leaves the conditions as they were last read or written: a condition
+// committed twice with CommitCondition, for instance. Like PersistIfDirty, only the conditions are compared, the
+// other changes made to the resource aren't written by a release that is skipped.
+type Tracker struct {
+	obj       ConditionalResource
+	listeners []Listener
+	clean     Conditions
+}
+
+// NewTracker returns a tracker for the conditions of the resource, with the listeners given. The conditions
+// the resource has are considered clean, the resource is expected to be fresh from the Kubernetes API.
+func NewTracker(obj ConditionalResource, listeners ...Listener) *Tracker {
+	return &Tracker{
+		obj:       obj,
+		listeners: listeners,
+		clean:     obj.Conditions().DeepCopy(),
+	}
+}
+
+// Listen registers an additional listener.
+func (t *Tracker) Listen(listener Listener) {
+	t.listeners = append(t.listeners, listener)
+}
+
+// Conditions returns the conditions tracked.
+func (t *Tracker) Conditions() *Conditions {
+	return t.obj.Conditions()
+}
+
+// IsDirty returns true if the conditions changed since they were last clean, see MarkClean. The conditions are
+// compared with Conditions.Equal, changes made without going through the tracker count too.
+func (t *Tracker) IsDirty() bool {
+	return !t.obj.Conditions().Equal(t.clean)
+}
+
+// MarkClean records that the conditions, as they are, are persisted. It's called by PersistIfDirty, and
+// should be called after the resource is written some other way.
+func (t *Tracker) MarkClean() {
+	t.clean = t.obj.Conditions().DeepCopy()
+}
+
+// PersistIfDirty writes the resource with the persister if its conditions are dirty, and marks them clean once
+// they're written. The return value indicates whether the resource was written or not.
+func (t *Tracker) PersistIfDirty(ctx context.Context, persister Persister) (bool, error) {
+	if !t.IsDirty() {
+		return false, nil
+	}
+
+	if err := persister.Persist(ctx, t.obj); err != nil {
+		return false, err
+	}
+
+	t.MarkClean()
+	return true, nil
+}
+
+// See Conditions.SetCondition
+func (t *Tracker) SetCondition(condition Condition) error {
+	return t.track(func(c *Conditions) error {
+		return c.SetCondition(condition)
+	})
+}
+
+// See Conditions.SetConditionForce
+func (t *Tracker) SetConditionForce(condition Condition) error {
+	return t.track(func(c *Conditions) error {
+		return c.SetConditionForce(condition)
+	})
+}
+
+// See Conditions.Reset
+func (t *Tracker) Reset(ct ConditionType, reason string) error {
+	return t.track(func(c *Conditions) error {
+		return c.Reset(ct, reason)
+	})
+}
+
+// See Conditions.RenameType
+func (t *Tracker) RenameType(oldType, newType ConditionType) (renamed bool, err error) {
+	err = t.track(func(c *Conditions) (err error) {
+		renamed, err = c.RenameType(oldType, newType)
+		return err
+	})
+
+	return renamed, err
+}
+
+// See Conditions.RemoveConditionWith
+func (t *Tracker) RemoveConditionWith(ct ConditionType) (removed bool) {
+	t.track(func(c *Conditions) error {
+		removed = c.RemoveConditionWith(ct)
+		return nil
+	})
+
+	return removed
+}
+
+// See Conditions.RemoveAllWithStatus
+func (t *Tracker) RemoveAllWithStatus(status ConditionStatus) (removed int) {
+	t.track(func(c *Conditions) error {
+		removed = c.RemoveAllWithStatus(status)
+		return nil
+	})
+
+	return removed
+}
+
+// See Conditions.RemoveWhere
+func (t *Tracker) RemoveWhere(predicate func(Condition) bool) (removed int) {
+	t.track(func(c *Conditions) error {
+		removed = c.RemoveWhere(predicate)
+		return nil
+	})
+
+	return removed
+}
+
+// See Conditions.Clear
+func (t *Tracker) Clear() (removed int) {
+	t.track(func(c *Conditions) error {
+		removed = c.Clear()
+		return nil
+	})
+
+	return removed
+}
+
+func (t *Tracker) track(mutation func(*Conditions) error) error {
+	previous := t.obj.Conditions().DeepCopy()
+	if err := mutation(t.obj.Conditions()); err != nil {
+		return err
+	}
+
+	notify(t.obj, t.listeners, t.obj.Conditions().Diff(previous))
+	return nil
+}
+
+// WithListener configures the lock to notify the listener of the transitions it persists. The listener is
+// called after each successful write to the Kubernetes API: once when the condition is locked and once when
+// it is released.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithListener(recordTransition))
+func WithListener(listener Listener) LockOption {
+	return func(l *Lock) {
+		l.listeners = append(l.listeners, listener)
+	}
+}
+
+func notify(obj ConditionalResource, listeners []Listener, transitions []Transition) {
+	for _, transition := range transitions {
+		for _, listener := range listeners {
+			listener(obj, transition)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	res := newTestResource("tracker")
+
+	var transitions []Transition
+	tracker := NewTracker(res, func(obj ConditionalResource, transition Transition) {
+		transitions = append(transitions, transition)
+	})
+
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated})
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCreated, Reason: "Same status"})
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	tracker.RemoveConditionWith(ConditionType("Bucket"))
+
+	if len(transitions) != 3 {
+		t.Fatal("Expected 3 transitions, got: ", transitions)
+	}
+
+	if transitions[0].Old != nil || transitions[0].New.Status != ConditionCreated {
+		t.Error("Unexpected transition: ", transitions[0])
+	}
+
+	if transitions[1].Old.Status != ConditionCreated || transitions[1].New.Status != ConditionCompleted {
+		t.Error("Unexpected transition: ", transitions[1])
+	}
+
+	if transitions[2].Old.Status != ConditionCompleted || transitions[2].New != nil {
+		t.Error("Unexpected transition: ", transitions[2])
+	}
+}
+
+func TestTrackerBulkRemoval(t *testing.T) {
+	res := newTestResource("tracker")
+
+	var removed []ConditionType
+	tracker := NewTracker(res, func(obj ConditionalResource, transition Transition) {
+		if transition.New == nil {
+			removed = append(removed, transition.Type)
+		}
+	})
+
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionTerminated})
+	tracker.SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionTerminated})
+	tracker.SetCondition(Condition{Type: ConditionType("Certificate"), Status: ConditionCompleted})
+
+	if count := tracker.RemoveAllWithStatus(ConditionTerminated); count != 2 || len(removed) != 2 {
+		t.Error("Expected the 2 terminated conditions to be removed, got: ", count, removed)
+	}
+
+	if count := tracker.Clear(); count != 1 || len(removed) != 3 {
+		t.Error("Expected the last condition to be removed, got: ", count, removed)
+	}
+}
+
+func TestLockWithListener(t *testing.T) {
+	res := newTestResource("tracker")
+	c := newTestClient(res)
+
+	var statuses []ConditionStatus
+	listener := func(obj ConditionalResource, transition Transition) {
+		statuses = append(statuses, transition.New.Status)
+	}
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithListener(listener)).Execute(context.Background(), func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 2 || statuses[0] != ConditionLocked || statuses[1] != ConditionCompleted {
+		t.Error("Unexpected transitions: ", statuses)
+	}
+}
+
+func TestTrackerDirty(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("tracker")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	c := newTestClient(res)
+
+	tracker := NewTracker(res)
+	if tracker.IsDirty() {
+		t.Error("Expected the conditions to be clean")
+	}
+
+	tracker.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted})
+	written, err := tracker.PersistIfDirty(ctx, StatusPersister{Client: c})
+	if err != nil || written {
+		t.Error("Expected setting the same condition to not need a write, got: ", written, err)
+	}
+
+	tracker.SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionCreated})
+	if !tracker.IsDirty() {
+		t.Fatal("Expected the conditions to be dirty")
+	}
+
+	written, err = tracker.PersistIfDirty(ctx, StatusPersister{Client: c})
+	if err != nil || !written {
+		t.Fatal("Expected the resource to be written, got: ", written, err)
+	}
+
+	if tracker.IsDirty() {
+		t.Error("Expected the conditions to be clean once written")
+	}
+
+	tracker.RemoveConditionWith(ConditionType("DNS"))
+	if !tracker.IsDirty() {
+		t.Error("Expected a removal to make the conditions dirty")
+	}
+
+	tracker.MarkClean()
+	if tracker.IsDirty() {
+		t.Error("Expected the conditions to be clean")
+	}
+}
@@ -0,0 +1,47 @@
+package konditions
+
+// Transition represents a change of a condition from one status to another. Old is nil when the
+// condition didn't exist before the transition, and New is nil when the condition was removed.
+type Transition struct {
+	Type ConditionType
+	Old  *Condition
+	New  *Condition
+}
+
+// Returns the transitions that happened between the `previous` conditions and these conditions.
+// A condition is said to have transitioned when its status changed, when it was added or when it was
+// removed. Changes that didn't affect the status (a different Reason, for instance) aren't transitions.
+//
+// The transitions are returned in the order the conditions appear in the set, followed
+// by the removed conditions, in the order they appeared in `previous`.
+//
+//	for _, transition := range newConditions.Diff(oldConditions) {
+//		log.Info("Transition", "type", transition.Type)
+//	}
+func (c Conditions) Diff(previous Conditions) []Transition {
+	var transitions []Transition
+
+	for i := range c {
+		old := previous.FindType(c[i].Type)
+		if old != nil && old.Status == c[i].Status {
+			continue
+		}
+
+		transitions = append(transitions, Transition{
+			Type: c[i].Type,
+			Old:  old,
+			New:  c[i].DeepCopy(),
+		})
+	}
+
+	for i := range previous {
+		if c.FindType(previous[i].Type) == nil {
+			transitions = append(transitions, Transition{
+				Type: previous[i].Type,
+				Old:  previous[i].DeepCopy(),
+			})
+		}
+	}
+
+	return transitions
+}
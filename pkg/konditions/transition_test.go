@@ -0,0 +1,42 @@
+package konditions
+
+import (
+	"testing"
+)
+
+func TestConditionsDiff(t *testing.T) {
+	previous := Conditions{
+		{Type: ConditionType("Unchanged"), Status: ConditionCompleted},
+		{Type: ConditionType("Reason"), Status: ConditionCreated, Reason: "Before"},
+		{Type: ConditionType("Changed"), Status: ConditionLocked},
+		{Type: ConditionType("Removed"), Status: ConditionTerminated},
+	}
+
+	current := Conditions{
+		{Type: ConditionType("Unchanged"), Status: ConditionCompleted},
+		{Type: ConditionType("Reason"), Status: ConditionCreated, Reason: "After"},
+		{Type: ConditionType("Changed"), Status: ConditionCreated},
+		{Type: ConditionType("Added"), Status: ConditionInitialized},
+	}
+
+	transitions := current.Diff(previous)
+	if len(transitions) != 3 {
+		t.Fatal("Expected 3 transitions, got: ", transitions)
+	}
+
+	if changed := transitions[0]; changed.Type != ConditionType("Changed") || changed.Old.Status != ConditionLocked || changed.New.Status != ConditionCreated {
+		t.Error("Unexpected transition: ", changed)
+	}
+
+	if added := transitions[1]; added.Type != ConditionType("Added") || added.Old != nil || added.New.Status != ConditionInitialized {
+		t.Error("Unexpected transition: ", added)
+	}
+
+	if removed := transitions[2]; removed.Type != ConditionType("Removed") || removed.New != nil || removed.Old.Status != ConditionTerminated {
+		t.Error("Unexpected transition: ", removed)
+	}
+
+	if transitions := (Conditions{}).Diff(nil); len(transitions) != 0 {
+		t.Error("Expected no transitions, got: ", transitions)
+	}
+}
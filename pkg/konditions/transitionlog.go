@@ -0,0 +1,104 @@
+package konditions
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TransitionLogMessage is the message of the lines logged by a TransitionLogger.
+const TransitionLogMessage = "Condition transitioned"
+
+// TransitionLogger logs a single, canonical, structured line for every transition of a condition. With a logger
+// that writes JSON, zap as configured by controller-runtime for instance, the lines form an event stream that can be
+// parsed by the tools that already process the logs of the operator:
+//
+//	{"msg":"Condition transitioned","resource":"default/logs","type":"Bucket","old":"Locked","new":"Completed","reason":"Bucket created","durationSeconds":12.5}
+//
+// The values logged are:
+//
+//	resource         namespace/name of the resource, or its name for cluster scoped resources
+//	uid              UID of the resource, when it's known
+//	type             type of the condition
+//	old              status before the transition, empty if the condition was added
+//	new              status after the transition, empty if the condition was removed
+//	reason           reason of the new condition, or of the old one if the condition was removed
+//	durationSeconds  time spent in the old status, only when the condition existed before the transition
+//
+// The logger records the transitions persisted by a lock with its Listener, or every transition of a kind observed
+// by a Registry with its Subscriber:
+//
+//	transitions := konditions.NewTransitionLogger(mgr.GetLogger().WithName("transitions"))
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithListener(transitions.Listener()))
+type TransitionLogger struct {
+	logger logr.Logger
+
+	// now returns the current time. It defaults to time.Now and exists for tests.
+	now func() time.Time
+}
+
+// NewTransitionLogger returns a TransitionLogger that logs through the logger given.
+func NewTransitionLogger(logger logr.Logger) *TransitionLogger {
+	return &TransitionLogger{
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Listener returns the Listener that logs the transitions of the resource of a lock, or of a Tracker.
+func (t *TransitionLogger) Listener() Listener {
+	return func(obj ConditionalResource, transition Transition) {
+		values := []interface{}{}
+		if uid := obj.GetUID(); uid != "" {
+			values = append(values, "uid", string(uid))
+		}
+
+		t.log(types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, transition, values...)
+	}
+}
+
+// Subscriber returns the function that logs the transitions observed by a Registry, see Registry.Subscribe.
+func (t *TransitionLogger) Subscriber() func(RegistryEvent) {
+	return func(event RegistryEvent) {
+		t.log(event.Key, event.Transition)
+	}
+}
+
+func (t *TransitionLogger) log(key types.NamespacedName, transition Transition, values ...interface{}) {
+	resource := key.Name
+	if key.Namespace != "" {
+		resource = key.String()
+	}
+
+	var from, to ConditionStatus
+	var reason string
+	if transition.Old != nil {
+		from = transition.Old.Status
+		reason = transition.Old.Reason
+	}
+
+	if transition.New != nil {
+		to = transition.New.Status
+		reason = transition.New.Reason
+	}
+
+	values = append([]interface{}{
+		"resource", resource,
+		"type", string(transition.Type),
+		"old", string(from),
+		"new", string(to),
+		"reason", reason,
+	}, values...)
+
+	if transition.Old != nil && !transition.Old.LastTransitionTime.IsZero() {
+		end := t.now()
+		if transition.New != nil && !transition.New.LastTransitionTime.IsZero() {
+			end = transition.New.LastTransitionTime.Time
+		}
+
+		values = append(values, "durationSeconds", end.Sub(transition.Old.LastTransitionTime.Time).Seconds())
+	}
+
+	t.logger.Info(TransitionLogMessage, values...)
+}
@@ -0,0 +1,96 @@
+package konditions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newJSONLogger returns a TransitionLogger that decodes the JSON lines it logs into the slice returned.
+func newJSONLogger(t *testing.T) (*TransitionLogger, *[]map[string]interface{}) {
+	lines := &[]map[string]interface{}{}
+	logger := funcr.NewJSON(func(obj string) {
+		line := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(obj), &line); err != nil {
+			t.Fatal(err)
+		}
+		*lines = append(*lines, line)
+	}, funcr.Options{})
+
+	return NewTransitionLogger(logger), lines
+}
+
+func TestTransitionLoggerListener(t *testing.T) {
+	transitions, lines := newJSONLogger(t)
+	locked := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	transitions.now = func() time.Time { return locked.Add(time.Minute) }
+
+	res := newTestResource("logs")
+	res.SetUID(types.UID("1234"))
+
+	listener := transitions.Listener()
+	listener(res, Transition{
+		Type: ConditionType("Bucket"),
+		Old:  &Condition{Type: ConditionType("Bucket"), Status: ConditionLocked, LastTransitionTime: meta.NewTime(locked)},
+		New:  &Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created", LastTransitionTime: meta.NewTime(locked.Add(12500 * time.Millisecond))},
+	})
+	listener(res, Transition{
+		Type: ConditionType("Bucket"),
+		Old:  &Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created", LastTransitionTime: meta.NewTime(locked)},
+	})
+
+	if len(*lines) != 2 {
+		t.Fatal("Expected a line per transition, got: ", *lines)
+	}
+
+	line := (*lines)[0]
+	expected := map[string]interface{}{
+		"msg":             TransitionLogMessage,
+		"resource":        "default/logs",
+		"uid":             "1234",
+		"type":            "Bucket",
+		"old":             "Locked",
+		"new":             "Completed",
+		"reason":          "Bucket created",
+		"durationSeconds": 12.5,
+	}
+	for key, value := range expected {
+		if line[key] != value {
+			t.Errorf("Expected %s to be %v, got %v", key, value, line[key])
+		}
+	}
+
+	removed := (*lines)[1]
+	if removed["new"] != "" || removed["reason"] != "Bucket created" || removed["durationSeconds"] != 60.0 {
+		t.Error("Expected the removal to be measured until now, got: ", removed)
+	}
+}
+
+func TestTransitionLoggerSubscriber(t *testing.T) {
+	transitions, lines := newJSONLogger(t)
+
+	transitions.Subscriber()(RegistryEvent{
+		Key: types.NamespacedName{Name: "cluster-wide"},
+		Transition: Transition{
+			Type: ConditionType("Quota"),
+			New:  &Condition{Type: ConditionType("Quota"), Status: ConditionInitialized},
+		},
+	})
+
+	if len(*lines) != 1 {
+		t.Fatal("Expected a line, got: ", *lines)
+	}
+
+	line := (*lines)[0]
+	if line["resource"] != "cluster-wide" || line["old"] != "" || line["new"] != "Initialized" {
+		t.Error("Unexpected line: ", line)
+	}
+
+	if _, ok := line["durationSeconds"]; ok {
+		t.Error("Expected no duration for a new condition, got: ", line["durationSeconds"])
+	}
+}
@@ -0,0 +1,211 @@
+package konditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultConditionsFields is the path of the conditions in an unstructured object when no
+// path is provided to NewUnstructuredConditions.
+var DefaultConditionsFields = []string{"status", "conditions"}
+
+// Returns the conditions stored at the path given by fields in the unstructured object. The boolean is
+// false if the field doesn't exist, in which case the conditions returned are empty.
+//
+//	conditions, found, err := konditions.NestedConditions(obj, "status", "conditions")
+//
+// An error is returned if the field exists but doesn't hold a valid list of conditions. The conditions
+// decoded are normalized, see Conditions.Normalize: when anomalies were fixed, the conditions are returned
+// along with a *NormalizedError.
+func NestedConditions(obj *unstructured.Unstructured, fields ...string) (Conditions, bool, error) {
+	conditions := Conditions{}
+
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if err != nil || !found || value == nil {
+		return conditions, found, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return conditions, true, err
+	}
+
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return Conditions{}, true, fmt.Errorf("could not decode conditions at %s: %w", strings.Join(fields, "."), err)
+	}
+
+	return conditions, true, normalizeDecoded(&conditions)
+}
+
+// Stores the conditions at the path given by fields in the unstructured object, creating the
+// intermediate maps if needed.
+//
+//	err := konditions.SetNestedConditions(obj, conditions, "status", "conditions")
+func SetNestedConditions(obj *unstructured.Unstructured, conditions Conditions, fields ...string) error {
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+
+	var value []interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value == nil {
+		value = []interface{}{}
+	}
+
+	return unstructured.SetNestedField(obj.Object, value, fields...)
+}
+
+// UnstructuredConditions is a ConditionalResource backed by an unstructured object. Meta-controllers, and
+// controllers that operate on arbitrary kinds, can use it to work with Locks and the finders without a Go type
+// for the resource.
+//
+//	obj := &unstructured.Unstructured{}
+//	obj.SetGroupVersionKind(gvk)
+//	if err := reconciler.Get(ctx, req.NamespacedName, obj); err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	res, err := konditions.NewUnstructuredConditions(obj)
+//	if err != nil {
+//		return ctrl.Result{}, err
+//	}
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"))
+//
+// The conditions are decoded once, when UnstructuredConditions is created, and encoded back into the
+// unstructured object when it is persisted, or when Encode() is called.
+type UnstructuredConditions struct {
+	*unstructured.Unstructured
+
+	gvk        schema.GroupVersionKind
+	fields     []string
+	conditions Conditions
+	anomalies  error
+}
+
+// NewUnstructuredConditions decodes the conditions stored at the path given by fields in the object.
+// If no fields are given, DefaultConditionsFields is used. An object without conditions starts with
+// an empty Conditions set.
+//
+// Conditions with anomalies are normalized, the anomalies are available with Anomalies.
+func NewUnstructuredConditions(obj *unstructured.Unstructured, fields ...string) (*UnstructuredConditions, error) {
+	if len(fields) == 0 {
+		fields = DefaultConditionsFields
+	}
+
+	conditions, _, err := NestedConditions(obj, fields...)
+	anomalies, err := splitNormalized(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnstructuredConditions{
+		Unstructured: obj,
+		gvk:          obj.GroupVersionKind(),
+		fields:       fields,
+		conditions:   conditions,
+		anomalies:    anomalies,
+	}, nil
+}
+
+// Anomalies returns the *NormalizedError of the conditions decoded from the object, nil if they were valid.
+func (u *UnstructuredConditions) Anomalies() error {
+	return u.anomalies
+}
+
+// Conditions returns the conditions decoded from the unstructured object. Changes made to the conditions
+// are kept in memory until the object is persisted.
+func (u *UnstructuredConditions) Conditions() *Conditions {
+	return &u.conditions
+}
+
+// Encode stores the conditions in the unstructured object and returns that object.
+func (u *UnstructuredConditions) Encode() (client.Object, error) {
+	// Unstructured objects need their kind to be sent to the API. Some clients clear it when they
+	// write the response back into the object, it's restored so the object can be written again.
+	if u.GetKind() == "" {
+		u.SetGroupVersionKind(u.gvk)
+	}
+
+	if err := SetNestedConditions(u.Unstructured, u.conditions, u.fields...); err != nil {
+		return nil, err
+	}
+
+	return u.Unstructured, nil
+}
+
+// See Conditions.FindOrInitializeFor
+func (u *UnstructuredConditions) FindOrInitializeFor(ct ConditionType) Condition {
+	return u.conditions.FindOrInitializeFor(ct)
+}
+
+// See Conditions.FindStatus
+func (u *UnstructuredConditions) FindStatus(status ConditionStatus) *Condition {
+	return u.conditions.FindStatus(status)
+}
+
+// See Conditions.FindType
+//
+// Deprecated: Use GetType.
+func (u *UnstructuredConditions) FindType(ct ConditionType) *Condition {
+	return u.conditions.FindType(ct)
+}
+
+// See Conditions.GetType
+func (u *UnstructuredConditions) GetType(ct ConditionType) (Condition, bool) {
+	return u.conditions.GetType(ct)
+}
+
+// See Conditions.MustType
+func (u *UnstructuredConditions) MustType(ct ConditionType) Condition {
+	return u.conditions.MustType(ct)
+}
+
+// See Conditions.TypeHasStatus
+func (u *UnstructuredConditions) TypeHasStatus(ct ConditionType, status ConditionStatus) bool {
+	return u.conditions.TypeHasStatus(ct, status)
+}
+
+// See Conditions.AnyWithStatus
+func (u *UnstructuredConditions) AnyWithStatus(status ConditionStatus) bool {
+	return u.conditions.AnyWithStatus(status)
+}
+
+// See Conditions.SetCondition
+func (u *UnstructuredConditions) SetCondition(condition Condition) error {
+	return u.conditions.SetCondition(condition)
+}
+
+// See Conditions.RenameType
+func (u *UnstructuredConditions) RenameType(oldType, newType ConditionType) (bool, error) {
+	return u.conditions.RenameType(oldType, newType)
+}
+
+// See Conditions.RemoveConditionWith
+func (u *UnstructuredConditions) RemoveConditionWith(ct ConditionType) bool {
+	return u.conditions.RemoveConditionWith(ct)
+}
+
+// See Conditions.RemoveAllWithStatus
+func (u *UnstructuredConditions) RemoveAllWithStatus(status ConditionStatus) int {
+	return u.conditions.RemoveAllWithStatus(status)
+}
+
+// See Conditions.RemoveWhere
+func (u *UnstructuredConditions) RemoveWhere(predicate func(Condition) bool) int {
+	return u.conditions.RemoveWhere(predicate)
+}
+
+// See Conditions.Clear
+func (u *UnstructuredConditions) Clear() int {
+	return u.conditions.Clear()
+}
@@ -0,0 +1,75 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNestedConditions(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	conditions, found, err := NestedConditions(obj, "status", "conditions")
+	if err != nil || found || len(conditions) != 0 {
+		t.Error("Expected no conditions, got: ", conditions, found, err)
+	}
+
+	conditions.SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created"})
+	if err := SetNestedConditions(obj, conditions, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+
+	conditions, found, err = NestedConditions(obj, "status", "conditions")
+	if err != nil || !found {
+		t.Fatal("Expected the conditions to be found: ", err)
+	}
+
+	if !conditions.TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) || conditions[0].LastTransitionTime.IsZero() {
+		t.Error("Unexpected conditions: ", conditions)
+	}
+
+	unstructured.SetNestedField(obj.Object, "invalid", "status", "conditions")
+	if _, _, err := NestedConditions(obj, "status", "conditions"); err == nil {
+		t.Error("Expected an error decoding invalid conditions")
+	}
+}
+
+func TestUnstructuredConditionsLock(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("unstructured")
+	c := newTestClient(res)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(testGroupVersion.WithKind("TestResource"))
+
+	u, err := NewUnstructuredConditions(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLock(u, c, ConditionType("Bucket")).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Unexpected conditions stored: ", stored.Status.Conditions)
+	}
+}
@@ -0,0 +1,108 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var CommitNotVerifiedErr = errors.New("Condition released by the lock is not the one stored by the Kubernetes API")
+
+// VerificationError is returned by Execute when the condition stored by the Kubernetes API isn't the one the lock
+// wrote, see WithVerify. It wraps CommitNotVerifiedErr.
+type VerificationError struct {
+	Type ConditionType
+
+	// Written is the condition the lock wrote, nil if the lock removed it, see RemoveOnRelease.
+	Written *Condition
+
+	// Stored are the conditions of the type stored by the Kubernetes API: none if the condition is missing, more than
+	// one if the conditions were merged into duplicates.
+	Stored []Condition
+}
+
+func (e *VerificationError) Error() string {
+	switch {
+	case len(e.Stored) > 1:
+		return fmt.Sprintf("%s: %s is stored %d times", CommitNotVerifiedErr, e.Type, len(e.Stored))
+	case e.Written == nil:
+		return fmt.Sprintf("%s: %s was removed, the Kubernetes API still has it as %s", CommitNotVerifiedErr, e.Type, e.Stored[0].Status)
+	case len(e.Stored) == 0:
+		return fmt.Sprintf("%s: %s was written as %s, the Kubernetes API doesn't have it", CommitNotVerifiedErr, e.Type, e.Written.Status)
+	default:
+		return fmt.Sprintf("%s: %s was written as %s (%s), the Kubernetes API has %s (%s)", CommitNotVerifiedErr, e.Type, e.Written.Status, e.Written.Reason, e.Stored[0].Status, e.Stored[0].Reason)
+	}
+}
+
+func (e *VerificationError) Unwrap() error {
+	return CommitNotVerifiedErr
+}
+
+// WithVerify configures the lock to read the resource once the condition is released, and to compare the condition
+// stored by the Kubernetes API with the one it wrote. A mutating webhook that changed it, fields pruned by the API
+// server because the CRD doesn't declare them or a list merged into duplicates, for instance, are caught by the
+// verification and Execute returns a *VerificationError:
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithVerify(mgr.GetAPIReader()))
+//	err := lock.Execute(ctx, createBucket)
+//
+//	var verification *konditions.VerificationError
+//	if errors.As(err, &verification) {
+//		log.Error(err, "Bucket condition wasn't stored as written", "stored", verification.Stored)
+//	}
+//
+// The conditions are compared with Condition.Equal. The resource is read with the reader given, which needs to bypass
+// the cache of the manager, mgr.GetAPIReader() for instance: a cache that didn't catch up with the release reports
+// mismatches that aren't real. A nil reader disables the verification.
+//
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithVerify(mgr.GetAPIReader()))
+//
+// Verifying costs a read on every execution, and only works with resources that hold their conditions in a Go field:
+// it returns an error wrapping UnsupportedResourceErr for the others, like AnnotationConditions.
+func WithVerify(reader client.Reader) LockOption {
+	return func(l *Lock) {
+		l.verifier = reader
+	}
+}
+
+// Reads the resource with the reader of the verification, when the lock verifies its commits, and compares the
+// condition stored with the condition the lock left on the resource.
+func (l *Lock) verifyCommit(ctx context.Context) error {
+	if l.verifier == nil {
+		return nil
+	}
+
+	if _, encoded := l.obj.(EncodedResource); encoded {
+		return fmt.Errorf("%w: %T can't be verified", UnsupportedResourceErr, l.obj)
+	}
+
+	live := emptyCopy(l.obj).(ConditionalResource)
+	if err := l.verifier.Get(ctx, client.ObjectKeyFromObject(l.obj), live); err != nil {
+		return err
+	}
+
+	ct := l.condition.Type
+	verification := &VerificationError{Type: ct}
+	if written, ok := l.obj.Conditions().GetType(ct); ok {
+		verification.Written = &written
+	}
+
+	for _, condition := range *live.Conditions() {
+		if condition.Type == ct {
+			verification.Stored = append(verification.Stored, condition)
+		}
+	}
+
+	switch {
+	case len(verification.Stored) > 1:
+		return verification
+	case verification.Written == nil && len(verification.Stored) == 0:
+		return nil
+	case verification.Written != nil && len(verification.Stored) == 1 && verification.Written.Equal(verification.Stored[0]):
+		return nil
+	default:
+		return verification
+	}
+}
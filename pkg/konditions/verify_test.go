@@ -0,0 +1,152 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLockWithVerify(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("verify")
+	c := newTestClient(res)
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithVerify(c)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		condition.Reason = "Bucket created"
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Error("Expected the condition to be verified, got: ", err)
+	}
+
+	lock := NewLock(res, c, ConditionType("DNS"), WithVerify(c))
+	lock.RemoveOnRelease()
+	err = lock.Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Error("Expected the removal to be verified, got: ", err)
+	}
+}
+
+func TestLockWithVerifyMismatch(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(conditions *Conditions)
+		stored int
+	}{
+		"pruned": {
+			mutate: func(conditions *Conditions) {
+				(*conditions)[0].Reason = ""
+			},
+			stored: 1,
+		},
+		"missing": {
+			mutate: func(conditions *Conditions) {
+				*conditions = Conditions{}
+			},
+			stored: 0,
+		},
+		"duplicated": {
+			mutate: func(conditions *Conditions) {
+				*conditions = append(*conditions, (*conditions)[0])
+			},
+			stored: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			res := newTestResource("verify")
+			c := newTestClient(res)
+
+			reader := &countingReader{Reader: c, mutate: func(obj client.Object) {
+				tc.mutate(obj.(*testResource).Conditions())
+			}}
+
+			err := NewLock(res, c, ConditionType("Bucket"), WithVerify(reader)).Execute(ctx, func(condition Condition) (Condition, error) {
+				condition.Status = ConditionCompleted
+				condition.Reason = "Bucket created"
+				return condition, nil
+			})
+
+			var verification *VerificationError
+			if !errors.As(err, &verification) || !errors.Is(err, CommitNotVerifiedErr) {
+				t.Fatal("Expected a VerificationError, got: ", err)
+			}
+
+			if verification.Written == nil || verification.Written.Status != ConditionCompleted || len(verification.Stored) != tc.stored {
+				t.Error("Unexpected verification: ", verification)
+			}
+		})
+	}
+}
+
+func TestLockWithVerifyEncodedResource(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("verify")
+	c := newTestClient(res)
+
+	annotated, err := NewAnnotationConditions(res, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewLock(annotated, c, ConditionType("Bucket"), WithStatusSubresource(false), WithVerify(c)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if !errors.Is(err, UnsupportedResourceErr) {
+		t.Error("Expected UnsupportedResourceErr, got: ", err)
+	}
+}
+
+// Decodes the resource into the object it's given without zeroing it first, like the Kubernetes API client does.
+type decodingReader struct {
+	client.Reader
+
+	mutate func(obj client.Object)
+}
+
+func (r *decodingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	stored := &testResource{}
+	if err := r.Reader.Get(ctx, key, stored, opts...); err != nil {
+		return err
+	}
+	r.mutate(stored)
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, obj)
+}
+
+func TestLockWithVerifyPrunedField(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("verify")
+	c := newTestClient(res)
+
+	reader := &decodingReader{Reader: c, mutate: func(obj client.Object) {
+		(*obj.(*testResource).Conditions())[0].Reason = ""
+	}}
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithVerify(reader)).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		condition.Reason = "Bucket created"
+		return condition, nil
+	})
+
+	var verification *VerificationError
+	if !errors.As(err, &verification) || verification.Stored[0].Reason != "" {
+		t.Error("Expected the pruned reason to be reported, got: ", err)
+	}
+}
@@ -0,0 +1,156 @@
+package konditions
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// DefaultWaitQueueTTL is how long a resource stays parked in a WaitQueue returned by NewWaitQueue.
+const DefaultWaitQueueTTL = 5 * time.Minute
+
+type waitKey struct {
+	key           types.NamespacedName
+	conditionType ConditionType
+}
+
+type waiter struct {
+	obj    client.Object
+	parked time.Time
+}
+
+// WaitQueue parks the reconciliations blocked on a locked condition and wakes them up when the lock is
+// released, within the same process. Without it, a reconciliation that finds a condition locked fails
+// and is requeued by the rate limiter of the controller, at an arbitrary time, while the lock may still be held.
+//
+// The queue is wired to the controller through a source, and to the locks through a lock option:
+//
+//	queue := konditions.NewWaitQueue()
+//
+//	reconciler := konditions.NewReconciler[MyCRD](mgr.GetClient()).
+//		On(ConditionType("Bucket"), bucketHandler).
+//		WithWaitQueue(queue).
+//		Build()
+//
+//	err := ctrl.NewControllerManagedBy(mgr).
+//		For(&MyCRD{}).
+//		WatchesRawSource(queue.Source()).
+//		Complete(reconciler)
+//
+// Locks held by another process are released through an update of the resource, which triggers a
+// reconciliation through the watch of the controller. That update never comes if the holder crashed, so a
+// resource is only parked for the TTL of the queue: the ConditionReconciler requeues the parked resources
+// after the TTL, and the queue forgets the entries older than the TTL. A TTL of zero parks the resources until
+// they are released or forgotten, without requeueing them.
+//
+// The ConditionReconciler forgets the resources it can't find anymore, see Forget.
+type WaitQueue struct {
+	TTL time.Duration
+
+	mu      sync.Mutex
+	waiters map[waitKey]waiter
+	events  chan event.GenericEvent
+
+	// now returns the current time. It defaults to time.Now and exists for tests.
+	now func() time.Time
+}
+
+// NewWaitQueue returns an empty wait queue, with a TTL of DefaultWaitQueueTTL.
+func NewWaitQueue() *WaitQueue {
+	return &WaitQueue{
+		TTL:     DefaultWaitQueueTTL,
+		waiters: map[waitKey]waiter{},
+		events:  make(chan event.GenericEvent, 1024),
+		now:     time.Now,
+	}
+}
+
+// Park registers the object as waiting for the condition type to be released. Parking the same object
+// multiple times only wakes it up once, and restarts its TTL.
+func (q *WaitQueue) Park(obj client.Object, ct ConditionType) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+	q.waiters[waitKey{key: client.ObjectKeyFromObject(obj), conditionType: ct}] = waiter{obj: obj, parked: q.now()}
+}
+
+// Forget removes the entries of the resource from the queue, for all condition types, without waking it up.
+// It's meant for resources that were deleted.
+func (q *WaitQueue) Forget(key types.NamespacedName) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for k := range q.waiters {
+		if k.key == key {
+			delete(q.waiters, k)
+		}
+	}
+}
+
+// Removes the entries parked for longer than the TTL. The caller holds the mutex.
+func (q *WaitQueue) expire() {
+	if q.TTL <= 0 {
+		return
+	}
+
+	now := q.now()
+	for k, w := range q.waiters {
+		if now.Sub(w.parked) >= q.TTL {
+			delete(q.waiters, k)
+		}
+	}
+}
+
+// Release wakes up the object if it is waiting on the condition type. The object is sent to the source
+// of the queue, which enqueues a reconciliation for it.
+func (q *WaitQueue) Release(obj client.Object, ct ConditionType) {
+	key := waitKey{key: client.ObjectKeyFromObject(obj), conditionType: ct}
+
+	q.mu.Lock()
+	waiter, ok := q.waiters[key]
+	delete(q.waiters, key)
+	q.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	ev := event.GenericEvent{Object: waiter.obj}
+	select {
+	case q.events <- ev:
+	default:
+		// The buffer is full, the controller isn't keeping up. The event is sent as soon as it can be
+		// so the wake up isn't lost.
+		go func() { q.events <- ev }()
+	}
+}
+
+// Waiting returns the number of objects parked in the queue, the entries that expired aren't counted.
+func (q *WaitQueue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.expire()
+
+	return len(q.waiters)
+}
+
+// Source returns the source that enqueues the objects woken up by the queue. It needs to be watched by the
+// controller reconciling the objects, see WatchesRawSource.
+func (q *WaitQueue) Source() source.Source {
+	return source.Channel(q.events, &handler.EnqueueRequestForObject{})
+}
+
+// WithWaitQueue configures the lock to park the resource in the queue when its condition is already locked,
+// and to wake up the resources parked on the condition when the lock is released.
+func WithWaitQueue(q *WaitQueue) LockOption {
+	return func(l *Lock) {
+		l.waitQueue = q
+	}
+}
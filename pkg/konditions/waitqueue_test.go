@@ -0,0 +1,123 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWaitQueue(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("waitqueue")
+	res.Status.Conditions = Conditions{{Type: ConditionType("Bucket"), Status: ConditionLocked}}
+	c := newTestClient(res)
+	queue := NewWaitQueue()
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithWaitQueue(queue)).Execute(ctx, func(condition Condition) (Condition, error) {
+		return condition, nil
+	})
+
+	if !errors.Is(err, LockNotReleasedErr) || queue.Waiting() != 1 {
+		t.Fatal("Expected the resource to be parked, got: ", err, queue.Waiting())
+	}
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if err := queue.Source().Start(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CommitCondition(ctx, c, res, Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted}, WithWaitQueue(queue)); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := q.Get()
+	if req.Name != res.Name || queue.Waiting() != 0 {
+		t.Error("Expected the resource to be woken up, got: ", req)
+	}
+}
+
+func TestReconcilerWithWaitQueue(t *testing.T) {
+	res := newTestResource("waitqueue")
+	res.Status.Conditions = Conditions{{Type: ConditionType("Bucket"), Status: ConditionLocked}}
+	c := newTestClient(res)
+	queue := NewWaitQueue()
+
+	reconciler := NewReconciler[testResource](c).
+		On(ConditionType("Bucket"), func(ctx context.Context, obj *testResource, condition Condition) (Condition, error) {
+			condition.Status = ConditionCompleted
+			return condition, nil
+		}).
+		WithWaitQueue(queue).
+		Build()
+
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(res)}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil || result.Requeue || result.RequeueAfter != DefaultWaitQueueTTL {
+		t.Error("Expected the reconciliation to be parked until the TTL, got: ", result, err)
+	}
+
+	if queue.Waiting() != 1 {
+		t.Error("Expected the resource to be parked")
+	}
+
+	if err := c.Delete(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if queue.Waiting() != 0 {
+		t.Error("Expected the deleted resource to be forgotten")
+	}
+}
+
+func TestWaitQueueTTL(t *testing.T) {
+	now := time.Now()
+	queue := NewWaitQueue()
+	queue.now = func() time.Time { return now }
+
+	queue.Park(newTestResource("first"), ConditionType("Bucket"))
+	now = now.Add(DefaultWaitQueueTTL / 2)
+	queue.Park(newTestResource("second"), ConditionType("Bucket"))
+
+	if queue.Waiting() != 2 {
+		t.Fatal("Expected both resources to be parked, got: ", queue.Waiting())
+	}
+
+	now = now.Add(DefaultWaitQueueTTL / 2)
+	if queue.Waiting() != 1 {
+		t.Error("Expected the first resource to expire, got: ", queue.Waiting())
+	}
+
+	queue.TTL = 0
+	now = now.Add(time.Hour)
+	if queue.Waiting() != 1 {
+		t.Error("Expected the resources to stay parked without a TTL, got: ", queue.Waiting())
+	}
+}
+
+func TestWaitQueueForget(t *testing.T) {
+	queue := NewWaitQueue()
+	res := newTestResource("forget")
+
+	queue.Park(res, ConditionType("Bucket"))
+	queue.Park(res, ConditionType("Queue"))
+	queue.Park(newTestResource("other"), ConditionType("Bucket"))
+
+	queue.Forget(client.ObjectKeyFromObject(res))
+	if queue.Waiting() != 1 {
+		t.Error("Expected the entries of the resource to be forgotten, got: ", queue.Waiting())
+	}
+}
@@ -0,0 +1,154 @@
+package konditions
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultWatchBufferSize is the size of the channel returned by Watch, unless configured otherwise.
+const DefaultWatchBufferSize = 100
+
+// TransitionEvent is a transition of a condition of a resource, as streamed by Watch. The Reason is the reason
+// of the new condition, or of the old one if the condition was removed.
+type TransitionEvent struct {
+	Key    types.NamespacedName
+	Type   ConditionType
+	Old    *Condition
+	New    *Condition
+	Reason string
+}
+
+type watchOptions struct {
+	types      []ConditionType
+	bufferSize int
+}
+
+// WatchOption configures Watch.
+type WatchOption func(*watchOptions)
+
+// WithWatchTypes only streams the transitions of the condition types given.
+func WithWatchTypes(types ...ConditionType) WatchOption {
+	return func(o *watchOptions) {
+		o.types = append(o.types, types...)
+	}
+}
+
+// WithWatchBuffer sets the size of the channel returned by Watch.
+func WithWatchBuffer(size int) WatchOption {
+	return func(o *watchOptions) {
+		o.bufferSize = size
+	}
+}
+
+// Watch streams the transitions of the conditions of every resource of the kind of the object given. It is built
+// on the informers of controller-runtime, which means auxiliary goroutines, notifiers, billing, audit, etc.
+// can react to transitions without writing their own controller, nor issuing any call to the Kubernetes API:
+//
+//	events, err := konditions.Watch(ctx, mgr.GetCache(), &MyCRD{}, konditions.WithWatchTypes(ConditionType("Bucket")))
+//	if err != nil {
+//		return err
+//	}
+//
+//	go func() {
+//		for event := range events {
+//			log.Info("Transition", "resource", event.Key, "type", event.Type, "reason", event.Reason)
+//		}
+//	}()
+//
+// The resources that exist when the watch starts produce a transition for each of their conditions, with a nil Old.
+// Deleting a resource produces a transition for each of its conditions, with a nil New.
+//
+// The channel is closed once the context is done. Events are delivered in order, a consumer that doesn't keep up
+// slows down the delivery of the events to the watch, but not to the other handlers of the informer.
+func Watch(ctx context.Context, informers cache.Informers, obj ConditionalResource, opts ...WatchOption) (<-chan TransitionEvent, error) {
+	options := watchOptions{bufferSize: DefaultWatchBufferSize}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	informer, err := informers.GetInformer(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan TransitionEvent, options.bufferSize)
+	var mu sync.Mutex
+	closed := false
+
+	send := func(key types.NamespacedName, transitions []Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, transition := range transitions {
+			if closed {
+				return
+			}
+
+			if len(options.types) > 0 && !slices.Contains(options.types, transition.Type) {
+				continue
+			}
+
+			event := TransitionEvent{Key: key, Type: transition.Type, Old: transition.Old, New: transition.New}
+			if transition.New != nil {
+				event.Reason = transition.New.Reason
+			} else if transition.Old != nil {
+				event.Reason = transition.Old.Reason
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(o interface{}) {
+			if res, ok := o.(ConditionalResource); ok {
+				send(client.ObjectKeyFromObject(res), res.Conditions().Diff(Conditions{}))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			previous, ok := oldObj.(ConditionalResource)
+			if !ok {
+				return
+			}
+
+			if res, ok := newObj.(ConditionalResource); ok {
+				send(client.ObjectKeyFromObject(res), res.Conditions().Diff(*previous.Conditions()))
+			}
+		},
+		DeleteFunc: func(o interface{}) {
+			if tombstone, ok := o.(toolscache.DeletedFinalStateUnknown); ok {
+				o = tombstone.Obj
+			}
+
+			if res, ok := o.(ConditionalResource); ok {
+				send(client.ObjectKeyFromObject(res), Conditions{}.Diff(*res.Conditions()))
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = informer.RemoveEventHandler(registration)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+		close(events)
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,74 @@
+package konditions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	informers := &informertest.FakeInformers{Scheme: newTestScheme()}
+	events, err := Watch(ctx, informers, &testResource{}, WithWatchTypes(ConditionType("Bucket")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	informer, err := informers.FakeInformerFor(ctx, &testResource{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := newTestResource("watch")
+	res.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionLocked, Reason: "Resource locked"})
+	res.Conditions().SetCondition(Condition{Type: ConditionType("DNS"), Status: ConditionLocked})
+	informer.Add(res)
+
+	updated := res.DeepCopyObject().(*testResource)
+	updated.Conditions().SetCondition(Condition{Type: ConditionType("Bucket"), Status: ConditionCompleted, Reason: "Bucket created"})
+	informer.Update(res, updated)
+	informer.Delete(updated)
+
+	expected := []struct {
+		from, to ConditionStatus
+		reason   string
+	}{
+		{"", ConditionLocked, "Resource locked"},
+		{ConditionLocked, ConditionCompleted, "Bucket created"},
+		{ConditionCompleted, "", "Bucket created"},
+	}
+
+	for _, e := range expected {
+		select {
+		case event := <-events:
+			var from, to ConditionStatus
+			if event.Old != nil {
+				from = event.Old.Status
+			}
+			if event.New != nil {
+				to = event.New.Status
+			}
+
+			if event.Key.Name != "watch" || event.Type != ConditionType("Bucket") || from != e.from || to != e.to || event.Reason != e.reason {
+				t.Errorf("Unexpected event: %+v", event)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected an event")
+		}
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected no more events")
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the channel to be closed once the context is done")
+	}
+}
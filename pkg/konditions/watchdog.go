@@ -0,0 +1,163 @@
+package konditions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LockHeartbeatFailedEventReason is the reason of the Warning event emitted when a Watchdog can't refresh a lock.
+const LockHeartbeatFailedEventReason = "LockHeartbeatFailed"
+
+// Watchdog refreshes the LastHeartbeatTime of a locked condition while its task runs for longer than the Threshold,
+// every Interval, so that the logic elsewhere that considers a lock abandoned after some time doesn't take over a lock
+// that is legitimately held by a long task. That logic reads the condition with Condition.SilentFor, or IsStuck: the
+// heartbeats aren't transitions, the LastTransitionTime is left untouched.
+//
+//	watchdog := konditions.NewWatchdog(time.Minute, mgr.GetEventRecorderFor("my-operator"))
+//	lock := konditions.NewLock(res, reconciler.Client, ConditionType("Bucket"), konditions.WithWatchdog(watchdog))
+//
+// Each heartbeat is a JSON patch of the condition, stored in `status.conditions`, that only applies if the condition
+// is still locked. A Warning event is emitted on the resource when a heartbeat fails. Heartbeats change the
+// resourceVersion of the resource: the lock accounts for it when it releases the condition, but a task that writes
+// the resource itself needs to fetch it again first.
+type Watchdog struct {
+	// Threshold is how long the task runs before the first heartbeat.
+	Threshold time.Duration
+
+	// Interval is the time between heartbeats, Threshold if it's 0.
+	Interval time.Duration
+
+	// Recorder receives the Warning events, events aren't emitted if it is nil.
+	Recorder record.EventRecorder
+}
+
+// NewWatchdog returns a watchdog that sends a heartbeat every threshold, once the task ran for the threshold. The
+// recorder can be nil.
+func NewWatchdog(threshold time.Duration, recorder record.EventRecorder) *Watchdog {
+	return &Watchdog{
+		Threshold: threshold,
+		Interval:  threshold,
+		Recorder:  recorder,
+	}
+}
+
+// WithWatchdog configures the lock to run the watchdog while its task runs, see Watchdog.
+func WithWatchdog(watchdog *Watchdog) LockOption {
+	return func(l *Lock) {
+		l.watchdog = watchdog
+	}
+}
+
+func (w *Watchdog) interval() time.Duration {
+	if w.Interval <= 0 {
+		return w.Threshold
+	}
+
+	return w.Interval
+}
+
+// Starts the watchdog of the lock, if it has one. The function returned stops it and must be called once the task
+// returns, before the lock is released.
+func (l *Lock) startWatchdog(ctx context.Context) (stop func()) {
+	if l.watchdog == nil || l.watchdog.Threshold <= 0 {
+		return func() {}
+	}
+
+	// The task may modify the resource while it runs, the heartbeats are sent with a copy.
+	obj := l.obj.DeepCopyObject().(client.Object)
+	acquired := l.obj.GetResourceVersion()
+	ct := l.condition.Type
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	var heartbeat string
+	var heartbeats int
+
+	go func() {
+		defer close(done)
+
+		timer := time.NewTimer(l.watchdog.Threshold)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if err := l.heartbeat(ctx, obj, ct); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				if l.watchdog.Recorder != nil {
+					l.watchdog.Recorder.Event(obj, corev1.EventTypeWarning, LockHeartbeatFailedEventReason, sanitizeMessage(fmt.Sprintf("Could not refresh the lock of %s: %s", ct, err)))
+				}
+			} else {
+				heartbeat = obj.GetResourceVersion()
+				heartbeats++
+			}
+
+			timer.Reset(l.watchdog.interval())
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+
+		l.result.Writes += heartbeats
+		if heartbeat == "" {
+			return
+		}
+
+		// The heartbeats are writes of the lock, the task didn't see them.
+		if l.obj.GetResourceVersion() == acquired {
+			l.obj.SetResourceVersion(heartbeat)
+		}
+
+		if l.resourceVersion != "" {
+			l.resourceVersion = heartbeat
+		}
+	}
+}
+
+// Refreshes the LastHeartbeatTime of the locked condition, if it's still locked by this lock. A heartbeat isn't a
+// transition, the LastTransitionTime is left untouched.
+func (l *Lock) heartbeat(ctx context.Context, obj client.Object, ct ConditionType) error {
+	path := fmt.Sprintf("/status/conditions/%d", l.index)
+
+	return l.patchLocked(ctx, obj, ct, jsonPatchOperation{Op: "add", Path: path + "/lastHeartbeatTime", Value: now()})
+}
+
+// Sends the operations as a JSON patch of the resource that only applies if the condition is still locked by this lock.
+func (l *Lock) patchLocked(ctx context.Context, obj client.Object, ct ConditionType, operations ...jsonPatchOperation) error {
+	path := fmt.Sprintf("/status/conditions/%d", l.index)
+	tests := []jsonPatchOperation{
+		{Op: "test", Path: path + "/type", Value: ct},
+		{Op: "test", Path: path + "/status", Value: ConditionLocked},
+	}
+
+	if l.token != "" {
+		tests = append(tests, jsonPatchOperation{Op: "test", Path: path + "/attributes/" + escapeJSONPointer(FencingTokenAttribute), Value: l.token})
+	}
+
+	patch, err := json.Marshal(append(tests, operations...))
+	if err != nil {
+		return err
+	}
+
+	if _, object := l.persister.(ObjectPersister); object {
+		return l.client.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+	}
+
+	return l.client.Status().Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch))
+}
@@ -0,0 +1,132 @@
+package konditions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newWatchdogTestClient(res *testResource, patch func() error) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(newTestScheme()).
+		WithStatusSubresource(&testResource{}).
+		WithObjects(res).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, p client.Patch, opts ...client.SubResourcePatchOption) error {
+				if err := patch(); err != nil {
+					return err
+				}
+				return c.SubResource(subResourceName).Patch(ctx, obj, p, opts...)
+			},
+		}).
+		Build()
+}
+
+func TestLockWithWatchdog(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("watchdog")
+
+	var heartbeats atomic.Int32
+	c := newWatchdogTestClient(res, func() error {
+		heartbeats.Add(1)
+		return nil
+	})
+
+	watchdog := NewWatchdog(20*time.Millisecond, nil)
+	err := NewLock(res, c, ConditionType("Bucket"), WithWatchdog(watchdog), CommitWithResourceVersion()).Execute(ctx, func(condition Condition) (Condition, error) {
+		locked := res.Conditions().MustType(ConditionType("Bucket"))
+		time.Sleep(150 * time.Millisecond)
+
+		var stored testResource
+		if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+			t.Fatal(err)
+		}
+
+		heartbeat := stored.Conditions().MustType(ConditionType("Bucket"))
+		if heartbeat.LastHeartbeatTime == nil || !heartbeat.LastTransitionTime.Equal(&locked.LastTransitionTime) {
+			t.Error("Expected the heartbeats to leave the LastTransitionTime untouched, got: ", heartbeat)
+		}
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal("Expected the lock to be released after the heartbeats, got: ", err)
+	}
+
+	if heartbeats.Load() == 0 {
+		t.Error("Expected the watchdog to send heartbeats")
+	}
+
+	var stored testResource
+	if err := c.Get(ctx, client.ObjectKeyFromObject(res), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !stored.Conditions().TypeHasStatus(ConditionType("Bucket"), ConditionCompleted) {
+		t.Error("Expected the condition to be completed, got: ", stored.Conditions())
+	}
+}
+
+func TestLockWithWatchdogFastTask(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("watchdog")
+
+	var heartbeats atomic.Int32
+	c := newWatchdogTestClient(res, func() error {
+		heartbeats.Add(1)
+		return nil
+	})
+
+	err := NewLock(res, c, ConditionType("Bucket"), WithWatchdog(NewWatchdog(time.Minute, nil))).Execute(ctx, func(condition Condition) (Condition, error) {
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if heartbeats.Load() != 0 {
+		t.Error("Expected no heartbeat for a task shorter than the threshold")
+	}
+}
+
+func TestLockWithWatchdogHeartbeatFailure(t *testing.T) {
+	ctx := context.Background()
+	res := newTestResource("watchdog")
+	c := newWatchdogTestClient(res, func() error {
+		return errors.New("API server unavailable")
+	})
+
+	recorder := record.NewFakeRecorder(10)
+	watchdog := NewWatchdog(20*time.Millisecond, recorder)
+	err := NewLock(res, c, ConditionType("Bucket"), WithWatchdog(watchdog)).Execute(ctx, func(condition Condition) (Condition, error) {
+		time.Sleep(50 * time.Millisecond)
+
+		condition.Status = ConditionCompleted
+		return condition, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, LockHeartbeatFailedEventReason) {
+			t.Error("Unexpected event: ", event)
+		}
+	default:
+		t.Error("Expected a warning event for the failed heartbeat")
+	}
+}